@@ -17,8 +17,10 @@
 package builder
 
 import (
+	"archive/tar"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -369,6 +371,166 @@ COPY %s /`, testFileName)
 	testCase.Run(t)
 }
 
+func TestBuildOutputTar(t *testing.T) {
+	nerdtest.Setup()
+
+	const testFileName = "nerdctl-build-test"
+	const testContent = "nerdctl"
+
+	dockerfile := fmt.Sprintf(`FROM scratch
+COPY %s /`, testFileName)
+
+	testCase := &test.Case{
+		Require: nerdtest.Build,
+		Setup: func(data test.Data, helpers test.Helpers) {
+			data.Temp().Save(dockerfile, "Dockerfile")
+			data.Temp().Save(testContent, testFileName)
+			data.Labels().Set("buildCtx", data.Temp().Path())
+		},
+		SubTests: []*test.Case{
+			{
+				// GOTCHA: avoid comma and = in the test name, or buildctl will misparse the destination direction
+				Description: "-o type tar destination FILE: verify the file copied from context is in the tarball",
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("build", "-o", fmt.Sprintf("type=tar,dest=%s", data.Temp().Path("out.tar")), data.Labels().Get("buildCtx"))
+				},
+				Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+					return &test.Expected{
+						Output: func(stdout string, t tig.T) {
+							f, err := os.Open(data.Temp().Path("out.tar"))
+							assert.NilError(t, err)
+							defer f.Close()
+							tr := tar.NewReader(f)
+							for {
+								hdr, err := tr.Next()
+								assert.NilError(t, err, "expected %s in the tarball", testFileName)
+								if hdr.Name != testFileName {
+									continue
+								}
+								content, err := io.ReadAll(tr)
+								assert.NilError(t, err)
+								assert.Equal(t, string(content), testContent, "file content is identical")
+								return
+							}
+						},
+					}
+				},
+			},
+			{
+				Description: "-o type tar without destination: the dest option is required",
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("build", "-o", "type=tar", data.Labels().Get("buildCtx"))
+				},
+				Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("requires the dest option")}, nil),
+			},
+			{
+				Description: "-o type local with push: the push option is not supported",
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("build", "-o", "type=local,dest=/tmp/out,push=true", data.Labels().Get("buildCtx"))
+				},
+				Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("does not support the \"push\" option")}, nil),
+			},
+			{
+				Description: "-o dash: the tarball is written to stdout",
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("build", "-o", "-", data.Labels().Get("buildCtx"))
+				},
+				Expected: test.Expects(expect.ExitCodeSuccess, nil, func(stdout string, t tig.T) {
+					tr := tar.NewReader(strings.NewReader(stdout))
+					for {
+						hdr, err := tr.Next()
+						assert.NilError(t, err, "expected %s in the tarball", testFileName)
+						if hdr.Name == testFileName {
+							return
+						}
+					}
+				}),
+			},
+			{
+				Description: "-o with a bare exporter type: rejected as ambiguous",
+				Require:     require.Not(nerdtest.Docker),
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("build", "-o", "tar", data.Labels().Get("buildCtx"))
+				},
+				Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("ambiguous output")}, nil),
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestBuildCacheLocal(t *testing.T) {
+	nerdtest.Setup()
+
+	dockerfile := fmt.Sprintf(`FROM %s
+RUN echo nerdctl-build-cache > /cached`, testutil.CommonImage)
+
+	testCase := &test.Case{
+		Require: require.All(
+			nerdtest.Build,
+			// The docker driver of buildx does not support the local cache exporter
+			require.Not(nerdtest.Docker),
+		),
+		// Pruning the build cache affects the other builds
+		NoParallel: true,
+		Setup: func(data test.Data, helpers test.Helpers) {
+			data.Temp().Save(dockerfile, "Dockerfile")
+			data.Temp().Dir("cache")
+			helpers.Ensure("build", "-t", data.Identifier(),
+				"--cache-to", "type=local,dest="+data.Temp().Path("cache")+",mode=max",
+				data.Temp().Path())
+			helpers.Ensure("rmi", "-f", data.Identifier())
+			helpers.Ensure("builder", "prune", "--force", "--all")
+		},
+		Cleanup: func(data test.Data, helpers test.Helpers) {
+			helpers.Anyhow("rmi", "-f", data.Identifier())
+		},
+		Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+			return helpers.Command("build", "-t", data.Identifier(), "--progress=plain",
+				"--cache-from", "type=local,src="+data.Temp().Path("cache"),
+				data.Temp().Path())
+		},
+		// The RUN step is imported from the exported cache instead of being executed again
+		Expected: test.Expects(expect.ExitCodeSuccess, []error{errors.New("CACHED")}, nil),
+	}
+
+	testCase.Run(t)
+}
+
+func TestBuildCacheInvalid(t *testing.T) {
+	nerdtest.Setup()
+
+	testCase := &test.Case{
+		Require: require.All(
+			nerdtest.Build,
+			require.Not(nerdtest.Docker),
+		),
+		Setup: func(data test.Data, helpers test.Helpers) {
+			data.Temp().Save(fmt.Sprintf("FROM %s", testutil.CommonImage), "Dockerfile")
+			data.Labels().Set("buildCtx", data.Temp().Path())
+		},
+		SubTests: []*test.Case{
+			{
+				Description: "cache-from type=inline",
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("build", "--cache-from", "type=inline", data.Labels().Get("buildCtx"))
+				},
+				Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("does not support type=inline")}, nil),
+			},
+			{
+				Description: "cache-to type=local without dest",
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("build", "--cache-to", "type=local", data.Labels().Get("buildCtx"))
+				},
+				Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("requires the dest option")}, nil),
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
+
 func TestBuildWithBuildArg(t *testing.T) {
 	nerdtest.Setup()
 
@@ -58,7 +58,13 @@ func NetworkOptions(cmd *cobra.Command, args []string, toComplete string) ([]str
 			"mtu=",
 			"com.docker.network.driver.mtu=",
 			"mode=bridge",
+			"mode=private",
+			"mode=vepa",
+			"mode=passthru",
 			"macvlan_mode=bridge",
+			"macvlan_mode=private",
+			"macvlan_mode=vepa",
+			"macvlan_mode=passthru",
 			"parent=",
 		}
 	case "ipvlan":
@@ -67,8 +73,10 @@ func NetworkOptions(cmd *cobra.Command, args []string, toComplete string) ([]str
 			"com.docker.network.driver.mtu=",
 			"mode=l2",
 			"mode=l3",
+			"mode=l3s",
 			"ipvlan_mode=l2",
 			"ipvlan_mode=l3",
+			"ipvlan_mode=l3s",
 			"parent=",
 		}
 	default:
@@ -67,7 +67,7 @@ func attachOptions(cmd *cobra.Command) (types.ContainerAttachOptions, error) {
 	if err != nil {
 		return types.ContainerAttachOptions{}, err
 	}
-	detachKeys, err := cmd.Flags().GetString("detach-keys")
+	detachKeys, err := helpers.DetachKeys(cmd)
 	if err != nil {
 		return types.ContainerAttachOptions{}, err
 	}
@@ -82,10 +82,27 @@ func TestCommit(t *testing.T) {
 					"-c", `ENTRYPOINT ["cat"]`,
 					"--pause=false",
 					identifier, identifier)
+				// the container must have been kept running
+				assert.Equal(helpers.T(), helpers.Capture("inspect", "--format", "{{.State.Status}}", identifier), "running\n")
 				return helpers.Command("run", "--rm", identifier)
 			},
 			Expected: test.Expects(0, nil, expect.Equals("hello-test-commit\n")),
 		},
+		{
+			Description: "never started container with pause",
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				identifier := data.Identifier()
+				helpers.Anyhow("rm", "-f", identifier)
+				helpers.Anyhow("rmi", "-f", identifier)
+			},
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("create", "--name", data.Identifier(), testutil.CommonImage, "true")
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("commit", "--pause=true", data.Identifier(), data.Identifier())
+			},
+			Expected: test.Expects(0, nil, nil),
+		},
 	}
 
 	testCase.Run(t)
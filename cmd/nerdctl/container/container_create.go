@@ -28,6 +28,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/clientutil"
 	"github.com/containerd/nerdctl/v2/pkg/cmd/container"
 	"github.com/containerd/nerdctl/v2/pkg/containerutil"
+	"github.com/containerd/nerdctl/v2/pkg/flagutil"
 )
 
 func CreateCommand() *cobra.Command {
@@ -85,6 +86,10 @@ func createOptions(cmd *cobra.Command) (types.ContainerCreateOptions, error) {
 	if err != nil {
 		return opt, err
 	}
+	opt.RestartMinUptime, err = cmd.Flags().GetDuration("restart-min-uptime")
+	if err != nil {
+		return opt, err
+	}
 	opt.Rm, err = cmd.Flags().GetBool("rm")
 	if err != nil {
 		return opt, err
@@ -105,6 +110,7 @@ func createOptions(cmd *cobra.Command) (types.ContainerCreateOptions, error) {
 	if err != nil {
 		return opt, err
 	}
+	opt.StopTimeoutChanged = cmd.Flags().Changed("stop-timeout")
 	// #endregion
 
 	// #region for platform flags
@@ -127,6 +133,10 @@ func createOptions(cmd *cobra.Command) (types.ContainerCreateOptions, error) {
 		}
 		opt.InitBinary = &initBinary
 	}
+	opt.InitArgs, err = cmd.Flags().GetStringArray("init-args")
+	if err != nil {
+		return opt, err
+	}
 	// #endregion
 
 	// #region for isolation flags
@@ -149,6 +159,10 @@ func createOptions(cmd *cobra.Command) (types.ContainerCreateOptions, error) {
 	if err != nil {
 		return opt, err
 	}
+	opt.CPUBurst, err = cmd.Flags().GetUint64("cpu-burst")
+	if err != nil {
+		return opt, err
+	}
 	opt.CPUShares, err = cmd.Flags().GetUint64("cpu-shares")
 	if err != nil {
 		return opt, err
@@ -223,7 +237,7 @@ func createOptions(cmd *cobra.Command) (types.ContainerCreateOptions, error) {
 		return opt, err
 	}
 	for _, device := range allDevices {
-		if cdiparser.IsQualifiedName(device) {
+		if cdiparser.IsQualifiedName(device) || container.IsCDIDeviceClass(device) {
 			opt.CDIDevices = append(opt.CDIDevices, device)
 		} else {
 			opt.Device = append(opt.Device, device)
@@ -283,6 +297,10 @@ func createOptions(cmd *cobra.Command) (types.ContainerCreateOptions, error) {
 	if err != nil {
 		return opt, err
 	}
+	opt.HealthEnv, err = cmd.Flags().GetStringArray("health-env")
+	if err != nil {
+		return opt, err
+	}
 	opt.NoHealthcheck, err = cmd.Flags().GetBool("no-healthcheck")
 	if err != nil {
 		return opt, err
@@ -290,6 +308,8 @@ func createOptions(cmd *cobra.Command) (types.ContainerCreateOptions, error) {
 	if err := helpers.ValidateHealthcheckFlags(opt); err != nil {
 		return opt, err
 	}
+	// As with --env, --health-env KEY takes the value of KEY from the environment of nerdctl
+	opt.HealthEnv = flagutil.LookupOSEnv(opt.HealthEnv)
 	// #endregion
 
 	// #region for intel RDT flags
@@ -402,6 +422,10 @@ func createOptions(cmd *cobra.Command) (types.ContainerCreateOptions, error) {
 	if err != nil {
 		return opt, err
 	}
+	opt.RedactEnv, err = cmd.Flags().GetStringSlice("redact-env")
+	if err != nil {
+		return opt, err
+	}
 	// #endregion
 
 	// #region for metadata flags
@@ -487,13 +511,24 @@ func createOptions(cmd *cobra.Command) (types.ContainerCreateOptions, error) {
 	if err != nil {
 		return opt, err
 	}
+	registryMirrors, err := cmd.Flags().GetStringSlice("registry-mirror")
+	if err != nil {
+		return opt, err
+	}
+	pullRetry, pullRetryDelay, err := helpers.PullRetryOptions(cmd, "pull-retry", "pull-retry-delay")
+	if err != nil {
+		return opt, err
+	}
 	opt.ImagePullOpt = types.ImagePullOptions{
-		GOptions:      opt.GOptions,
-		VerifyOptions: imageVerifyOpt,
-		IPFSAddress:   opt.IPFSAddress,
-		Stdout:        opt.Stdout,
-		Stderr:        opt.Stderr,
-		Quiet:         quiet,
+		GOptions:        opt.GOptions,
+		VerifyOptions:   imageVerifyOpt,
+		IPFSAddress:     opt.IPFSAddress,
+		Stdout:          opt.Stdout,
+		Stderr:          opt.Stderr,
+		Quiet:           quiet,
+		RegistryMirrors: registryMirrors,
+		Retry:           pullRetry,
+		RetryDelay:      pullRetryDelay,
 	}
 	// #endregion
 
@@ -508,10 +543,12 @@ func createOptions(cmd *cobra.Command) (types.ContainerCreateOptions, error) {
 		return opt, err
 	}
 
+	// --userns=host disables the remapping, and any other value overrides --userns-remap for this container,
+	// e.g., --userns=auto or --userns=<name|uid>[:<group|gid>]
 	if userns == "host" {
 		opt.UserNS = ""
 	} else if userns != "" {
-		return opt, fmt.Errorf("invalid user mode")
+		opt.UserNS = userns
 	}
 
 	if opt.Privileged && opt.UserNS != "" {
@@ -18,6 +18,7 @@ package container
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -25,6 +26,7 @@ import (
 	"gotest.tools/v3/assert"
 
 	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
 	"github.com/containerd/nerdctl/mod/tigron/test"
 	"github.com/containerd/nerdctl/mod/tigron/tig"
 
@@ -120,3 +122,32 @@ func TestCreateHyperVContainer(t *testing.T) {
 
 	testCase.Run(t)
 }
+
+func TestCreateNameNotDNSLabel(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	// Docker accepts these names silently
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", "Nerdctl_"+data.Identifier())
+	}
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		return helpers.Command("create", "--name", "Nerdctl_"+data.Identifier(), testutil.CommonImage)
+	}
+
+	// The name is still accepted, with a warning telling its normalized name that is resolved too
+	testCase.Expected = func(data test.Data, helpers test.Helpers) *test.Expected {
+		return &test.Expected{
+			ExitCode: expect.ExitCodeSuccess,
+			Errors: []error{
+				errors.New("is not a valid DNS label"),
+				errors.New("it is also resolved as \"nerdctl-"),
+				errors.New("--name=nerdctl-"),
+			},
+		}
+	}
+
+	testCase.Run(t)
+}
@@ -22,7 +22,11 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+
 	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
 )
 
 func TestExec(t *testing.T) {
@@ -66,6 +70,52 @@ func TestExecStdin(t *testing.T) {
 	base.Cmd("exec", "-i", testContainer, "cat").CmdOption(opts...).AssertOutExactly(testStr)
 }
 
+func TestExecInteractiveWithoutTTY(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("run", "-d", "--name", data.Identifier(), testutil.CommonImage, "sleep", nerdtest.Infinity)
+		nerdtest.EnsureContainerStarted(helpers, data.Identifier())
+		data.Labels().Set("container", data.Identifier())
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "piped input is passed through and its EOF ends the process",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				cmd := helpers.Command("exec", "-i", data.Labels().Get("container"), "sh", "-c", "cat; echo done")
+				cmd.Feed(strings.NewReader("line1\nline2\n"))
+				return cmd
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Equals("line1\nline2\ndone\n")),
+		},
+		{
+			Description: "empty input",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				cmd := helpers.Command("exec", "-i", data.Labels().Get("container"), "sh", "-c", "cat; echo done")
+				cmd.Feed(strings.NewReader(""))
+				return cmd
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Equals("done\n")),
+		},
+		{
+			Description: "no pseudo-terminal is allocated",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				cmd := helpers.Command("exec", "-i", data.Labels().Get("container"), "sh", "-c", "test -t 0 || echo notty")
+				cmd.Feed(strings.NewReader(""))
+				return cmd
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Equals("notty\n")),
+		},
+	}
+
+	testCase.Run(t)
+}
+
 // FYI: https://github.com/containerd/nerdctl/blob/e4b2b6da56555dc29ed66d0fd8e7094ff2bc002d/cmd/nerdctl/run_test.go#L177
 func TestExecEnv(t *testing.T) {
 	t.Parallel()
@@ -358,6 +358,80 @@ func TestContainerHealthCheckAdvance(t *testing.T) {
 				}
 			},
 		},
+		{
+			Description: "Health check uses --health-env on top of the container environment",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("run", "-d", "--name", data.Identifier(),
+					"--env", "MYVAR=container-value",
+					"--health-env", "MYVAR=probe-value",
+					"--health-env", "SECRET=probe-secret",
+					"--health-cmd", "echo $MYVAR $SECRET",
+					"--health-interval", "1s",
+					"--health-timeout", "1s",
+					testutil.CommonImage, "sleep", nerdtest.Infinity)
+				nerdtest.EnsureContainerStarted(helpers, data.Identifier())
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("container", "healthcheck", data.Identifier())
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					ExitCode: 0,
+					Output: expect.All(func(stdout string, t tig.T) {
+						inspect := nerdtest.InspectContainer(helpers, data.Identifier())
+						h := inspect.State.Health
+						assert.Assert(t, h != nil, "expected health state")
+						assert.Equal(t, h.Status, healthcheck.Healthy)
+						assert.Assert(t, strings.Contains(h.Log[0].Output, "probe-value probe-secret"), "expected health log output to contain the probe env")
+						// the probe env is not set in the container, and its values are not displayed
+						assert.Assert(t, !strings.Contains(strings.Join(inspect.Config.Env, " "), "probe-"))
+						assert.DeepEqual(t, inspect.Config.Healthcheck.Env, []string{"MYVAR=<redacted>", "SECRET=<redacted>"})
+					}),
+				}
+			},
+		},
+		{
+			Description: "Health check takes --health-env KEY from the environment of nerdctl",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				cmd := helpers.Command("run", "-d", "--name", data.Identifier(),
+					"--health-env", "HEALTH_TOKEN",
+					"--health-env", "NERDCTL_TEST_UNSET_HEALTH_VAR",
+					"--health-cmd", "echo token=$HEALTH_TOKEN unset=${NERDCTL_TEST_UNSET_HEALTH_VAR-none}",
+					"--health-interval", "1s",
+					"--health-timeout", "1s",
+					testutil.CommonImage, "sleep", nerdtest.Infinity)
+				cmd.Setenv("HEALTH_TOKEN", "probe-token")
+				cmd.Run(&test.Expected{ExitCode: 0})
+				nerdtest.EnsureContainerStarted(helpers, data.Identifier())
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("container", "healthcheck", data.Identifier())
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					ExitCode: 0,
+					Output: expect.All(func(stdout string, t tig.T) {
+						inspect := nerdtest.InspectContainer(helpers, data.Identifier())
+						h := inspect.State.Health
+						assert.Assert(t, h != nil, "expected health state")
+						assert.Assert(t, strings.Contains(h.Log[0].Output, "token=probe-token unset=none"), "expected health log output to contain the probe env")
+						assert.Assert(t, !strings.Contains(strings.Join(inspect.Config.Env, " "), "probe-token"))
+						assert.DeepEqual(t, inspect.Config.Healthcheck.Env, []string{"HEALTH_TOKEN=<redacted>"})
+					}),
+				}
+			},
+		},
+		{
+			Description: "Invalid --health-env",
+			Command:     test.Command("run", "--rm", "--health-cmd", "true", "--health-env", "=value", testutil.CommonImage, "true"),
+			Expected:    test.Expects(1, []error{errors.New("must be in the KEY=VALUE format")}, nil),
+		},
 		{
 			Description: "Health check respects container WorkingDir",
 			Setup: func(data test.Data, helpers test.Helpers) {
@@ -602,3 +676,97 @@ func TestContainerHealthCheckAdvance(t *testing.T) {
 
 	testCase.Run(t)
 }
+
+func TestContainerHealthCheckBuiltinProbe(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	// Docker CLI does not provide a standalone healthcheck command, nor built-in probes.
+	testCase.Require = require.All(
+		require.Not(nerdtest.Docker),
+		require.Linux,
+	)
+
+	healthyExpected := func(data test.Data, helpers test.Helpers) *test.Expected {
+		return &test.Expected{
+			ExitCode: 0,
+			Output: expect.All(func(stdout string, t tig.T) {
+				inspect := nerdtest.InspectContainer(helpers, data.Identifier())
+				h := inspect.State.Health
+				assert.Assert(t, h != nil, "expected health state to be present")
+				assert.Equal(t, healthcheck.Healthy, h.Status)
+			}),
+		}
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "Invalid probe scheme",
+			Command: test.Command("run", "-d", "--health-cmd", "udp://localhost:53",
+				testutil.CommonImage, "sleep", nerdtest.Infinity),
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("unsupported scheme")}, nil),
+		},
+		{
+			Description: "TCP probe success",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("run", "-d", "--name", data.Identifier(),
+					"--health-cmd", "tcp://localhost:80",
+					testutil.NginxAlpineImage)
+				nerdtest.EnsureContainerStarted(helpers, data.Identifier())
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("container", "healthcheck", data.Identifier())
+			},
+			Expected: healthyExpected,
+		},
+		{
+			Description: "HTTP probe success",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("run", "-d", "--name", data.Identifier(),
+					"--health-cmd", "http://localhost/",
+					testutil.NginxAlpineImage)
+				nerdtest.EnsureContainerStarted(helpers, data.Identifier())
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("container", "healthcheck", data.Identifier())
+			},
+			Expected: healthyExpected,
+		},
+		{
+			Description: "HTTP probe failure",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("run", "-d", "--name", data.Identifier(),
+					"--health-cmd", "http://localhost/does-not-exist",
+					"--health-retries", "1",
+					testutil.NginxAlpineImage)
+				nerdtest.EnsureContainerStarted(helpers, data.Identifier())
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("container", "healthcheck", data.Identifier())
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					ExitCode: 0,
+					Output: expect.All(func(stdout string, t tig.T) {
+						inspect := nerdtest.InspectContainer(helpers, data.Identifier())
+						h := inspect.State.Health
+						assert.Assert(t, h != nil, "expected health state to be present")
+						assert.Equal(t, healthcheck.Unhealthy, h.Status)
+						assert.Assert(t, len(h.Log) > 0, "expected at least one health check log entry")
+						assert.Assert(t, strings.Contains(h.Log[len(h.Log)-1].Output, "404"))
+					}),
+				}
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
@@ -28,7 +28,9 @@ import (
 	"gotest.tools/v3/assert"
 
 	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
 	"github.com/containerd/nerdctl/mod/tigron/test"
+	"github.com/containerd/nerdctl/mod/tigron/tig"
 
 	"github.com/containerd/nerdctl/v2/pkg/infoutil"
 	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/dockercompat"
@@ -54,6 +56,43 @@ func TestContainerInspectContainsPortConfig(t *testing.T) {
 	assert.Equal(base.T, expected, inspect80TCP[0])
 }
 
+func TestContainerInspectContainsEphemeralPort(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	// Automatic host port allocation is not supported in rootless mode
+	testCase.Require = require.Not(nerdtest.Rootless)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("run", "-d", "--name", data.Identifier(), "-p", "127.0.0.1::80", testutil.NginxAlpineImage)
+		nerdtest.EnsureContainerStarted(helpers, data.Identifier())
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+	}
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		return helpers.Command("inspect", "--format",
+			`{{range (index .NetworkSettings.Ports "80/tcp")}}{{.HostIP}}:{{.HostPort}}{{"\n"}}{{end}}`, data.Identifier())
+	}
+
+	testCase.Expected = func(data test.Data, helpers test.Helpers) *test.Expected {
+		// `nerdctl port` reports the host port that was actually allocated
+		allocated := helpers.Capture("port", data.Identifier(), "80/tcp")
+		return &test.Expected{
+			Output: expect.All(
+				expect.Equals(allocated),
+				func(stdout string, t tig.T) {
+					assert.Assert(t, strings.HasPrefix(stdout, "127.0.0.1:"), stdout)
+					assert.Assert(t, !strings.HasPrefix(stdout, "127.0.0.1:0\n"), "the ephemeral host port was not resolved")
+				},
+			),
+		}
+	}
+
+	testCase.Run(t)
+}
+
 func TestContainerInspectContainsMounts(t *testing.T) {
 	testContainer := testutil.Identifier(t)
 
@@ -185,6 +224,27 @@ func TestContainerInspectContainsInternalLabel(t *testing.T) {
 	assert.Equal(base.T, expectedLabelMount, labelMount)
 }
 
+func TestContainerInspectLogPath(t *testing.T) {
+	t.Parallel()
+	testContainer := testutil.Identifier(t)
+	noneContainer := testContainer + "-none"
+
+	base := testutil.NewBase(t)
+	defer base.Cmd("rm", "-f", testContainer, noneContainer).Run()
+
+	base.Cmd("run", "-d", "--name", testContainer, "--log-driver", "json-file", testutil.CommonImage, "sleep", nerdtest.Infinity).AssertOK()
+	base.EnsureContainerStarted(testContainer)
+	inspect := base.InspectContainer(testContainer)
+	assert.Assert(base.T, inspect.LogPath != "")
+	_, err := os.Stat(inspect.LogPath)
+	assert.NilError(base.T, err)
+
+	base.Cmd("run", "-d", "--name", noneContainer, "--log-driver", "none", testutil.CommonImage, "sleep", nerdtest.Infinity).AssertOK()
+	base.EnsureContainerStarted(noneContainer)
+	inspect = base.InspectContainer(noneContainer)
+	assert.Equal(base.T, "", inspect.LogPath)
+}
+
 func TestContainerInspectState(t *testing.T) {
 	t.Parallel()
 	testContainer := testutil.Identifier(t)
@@ -283,6 +343,80 @@ func TestContainerInspectHostConfig(t *testing.T) {
 	assert.Equal(t, int64(268435456), inspect.HostConfig.ShmSize)
 }
 
+func TestContainerInspectHostConfigNanoCPUs(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = nerdtest.CgroupsAccessible
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("run", "-d", "--name", data.Identifier(), "--cpus", "0.5",
+			testutil.AlpineImage, "sleep", nerdtest.Infinity)
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "NanoCpus is set from --cpus",
+			NoParallel:  true,
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("inspect", "--format", "{{.HostConfig.NanoCpus}} {{.HostConfig.CpuQuota}} {{.HostConfig.CpuPeriod}}", data.Identifier())
+			},
+			Expected: test.Expects(0, nil, expect.Equals("500000000 50000 100000\n")),
+		},
+		{
+			Description: "NanoCpus is updated with update --cpus",
+			NoParallel:  true,
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("update", "--cpus", "1.5", data.Identifier())
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("inspect", "--format", "{{.HostConfig.NanoCpus}} {{.HostConfig.CpuQuota}}", data.Identifier())
+			},
+			Expected: test.Expects(0, nil, expect.Equals("1500000000 150000\n")),
+		},
+		{
+			Description: "NanoCpus is reset with update --cpu-quota",
+			NoParallel:  true,
+			Require:     require.Not(nerdtest.Docker),
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("update", "--cpu-quota", "20000", data.Identifier())
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("inspect", "--format", "{{.HostConfig.NanoCpus}} {{.HostConfig.CpuQuota}}", data.Identifier())
+			},
+			Expected: test.Expects(0, nil, expect.Equals("0 20000\n")),
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestContainerInspectHostConfigUlimits(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("create", "--name", data.Identifier(),
+			"--ulimit", "nofile=1024:2048", "--ulimit", "nproc=512",
+			testutil.AlpineImage)
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+	}
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		return helpers.Command("inspect", "--format", "{{json .HostConfig.Ulimits}}", data.Identifier())
+	}
+
+	testCase.Expected = test.Expects(0, nil,
+		expect.Equals(`[{"Name":"nofile","Hard":2048,"Soft":1024},{"Name":"nproc","Hard":512,"Soft":512}]`+"\n"))
+
+	testCase.Run(t)
+}
+
 func TestContainerInspectHostConfigDefaults(t *testing.T) {
 	testContainer := testutil.Identifier(t)
 
@@ -511,15 +645,22 @@ func TestContainerInspectBlkioSettings(t *testing.T) {
 	inspect := base.InspectContainer(testContainer)
 	assert.Equal(t, uint16(500), inspect.HostConfig.BlkioWeight)
 	assert.Equal(t, 1, len(inspect.HostConfig.BlkioWeightDevice))
-	assert.Equal(t, uint16(500), *inspect.HostConfig.BlkioWeightDevice[0].Weight)
+	assert.Equal(t, uint16(500), inspect.HostConfig.BlkioWeightDevice[0].Weight)
+	assert.Equal(t, devPath, inspect.HostConfig.BlkioWeightDevice[0].Path)
 	assert.Equal(t, 1, len(inspect.HostConfig.BlkioDeviceReadBps))
 	assert.Equal(t, uint64(1048576), inspect.HostConfig.BlkioDeviceReadBps[0].Rate)
+	assert.Equal(t, devPath, inspect.HostConfig.BlkioDeviceReadBps[0].Path)
 	assert.Equal(t, 1, len(inspect.HostConfig.BlkioDeviceWriteBps))
 	assert.Equal(t, uint64(2097152), inspect.HostConfig.BlkioDeviceWriteBps[0].Rate)
 	assert.Equal(t, 1, len(inspect.HostConfig.BlkioDeviceReadIOps))
 	assert.Equal(t, uint64(1000), inspect.HostConfig.BlkioDeviceReadIOps[0].Rate)
 	assert.Equal(t, 1, len(inspect.HostConfig.BlkioDeviceWriteIOps))
 	assert.Equal(t, uint64(2000), inspect.HostConfig.BlkioDeviceWriteIOps[0].Rate)
+	assert.Equal(t, devPath, inspect.HostConfig.BlkioDeviceWriteIOps[0].Path)
+
+	// The throttle devices are reported in the format of Docker, as `[{"Path": ..., "Rate": ...}]`
+	out := base.Cmd("inspect", "--format", "{{json .HostConfig.BlkioDeviceReadBps}}", testContainer).Out()
+	assert.Equal(t, `[{"Path":"/dev/dummy-zero","Rate":1048576}]`, strings.TrimSpace(out))
 }
 
 func TestContainerInspectUser(t *testing.T) {
@@ -551,6 +692,104 @@ USER test
 	testCase.Run(t)
 }
 
+func TestContainerInspectOOMKilled(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.All(
+		nerdtest.CGroup,
+		require.Not(nerdtest.Rootless),
+	)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("run", "--name", data.Identifier(), "--memory", "32m", "--memory-swap", "32m", testutil.CommonImage,
+			"sh", "-c", "x=a; while true; do x=$x$x; done")
+		data.Labels().Set("oomKilled", data.Identifier())
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "State.OOMKilled is true",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("inspect", "--format", "{{.State.OOMKilled}}", data.Labels().Get("oomKilled"))
+			},
+			Expected: test.Expects(0, nil, expect.Equals("true\n")),
+		},
+		{
+			Description: "OOMKilled is recorded in a label on exit",
+			Require:     require.Not(nerdtest.Docker),
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("inspect", "--mode", "native", "--format",
+					"{{index .Labels \""+labels.OOMKilled+"\"}}", data.Labels().Get("oomKilled"))
+			},
+			Expected: test.Expects(0, nil, expect.Equals("true\n")),
+		},
+		{
+			Description: "State.OOMKilled is false for a container that exited normally",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("run", "--name", data.Identifier(), "--memory", "32m", testutil.CommonImage, "true")
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("inspect", "--format", "{{.State.OOMKilled}}", data.Identifier())
+			},
+			Expected: test.Expects(0, nil, expect.Equals("false\n")),
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestContainerInspectRedactEnv(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("run", "-d", "--name", data.Identifier(), "-e", "API_TOKEN=s3cr3t", "-e", "FOO=bar",
+			"--redact-env", "API_TOKEN", testutil.CommonImage, "sleep", nerdtest.Infinity)
+		nerdtest.EnsureContainerStarted(helpers, data.Identifier())
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "inspect redacts the value",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("inspect", "--format", "{{json .Config.Env}}", data.Identifier())
+			},
+			Expected: test.Expects(0, nil, expect.All(
+				expect.Contains("API_TOKEN=<redacted>", "FOO=bar"),
+				expect.DoesNotContain("s3cr3t"),
+			)),
+		},
+		{
+			Description: "native inspect redacts the value",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("inspect", "--mode=native", data.Identifier())
+			},
+			Expected: test.Expects(0, nil, expect.DoesNotContain("s3cr3t")),
+		},
+		{
+			Description: "the container receives the real value",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("exec", data.Identifier(), "sh", "-c", "echo $API_TOKEN")
+			},
+			Expected: test.Expects(0, nil, expect.Equals("s3cr3t\n")),
+		},
+	}
+
+	testCase.Run(t)
+}
+
 type hostConfigValues struct {
 	Driver       string
 	ShmSize      int64
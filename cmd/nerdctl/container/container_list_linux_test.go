@@ -26,6 +26,7 @@ import (
 
 	"gotest.tools/v3/assert"
 
+	"github.com/containerd/nerdctl/mod/tigron/require"
 	"github.com/containerd/nerdctl/mod/tigron/test"
 	"github.com/containerd/nerdctl/mod/tigron/tig"
 
@@ -699,3 +700,36 @@ func TestContainerListStatusFilter(t *testing.T) {
 
 	testCase.Run(t)
 }
+
+func TestContainerListSizeStopped(t *testing.T) {
+	testCase := nerdtest.Setup()
+	testCase.Require = require.Not(nerdtest.Docker)
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		// let the writable layer of the stopped container occupy 5MiB
+		helpers.Ensure("run", "--name", data.Identifier(), testutil.CommonImage,
+			"dd", "if=/dev/zero", "of=/test_file", "bs=1M", "count=5")
+	}
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+	}
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		return helpers.Command("ps", "-a", "-s", "--filter", "name="+data.Identifier())
+	}
+	testCase.Expected = func(data test.Data, helpers test.Helpers) *test.Expected {
+		return &test.Expected{
+			Output: func(stdout string, t tig.T) {
+				lines := strings.Split(strings.TrimSpace(stdout), "\n")
+				assert.Equal(t, len(lines), 2, stdout)
+
+				tab := tabutil.NewReader("CONTAINER ID\tIMAGE\tCOMMAND\tCREATED\tSTATUS\tPORTS\tNAMES\tSIZE")
+				assert.NilError(t, tab.ParseHeader(lines[0]))
+				name, _ := tab.ReadRow(lines[1], "NAMES")
+				assert.Equal(t, name, data.Identifier())
+				size, _ := tab.ReadRow(lines[1], "SIZE")
+				assert.Assert(t, strings.HasPrefix(size, "5.0 MiB (virtual "), "unexpected size %q", size)
+			},
+		}
+	}
+
+	testCase.Run(t)
+}
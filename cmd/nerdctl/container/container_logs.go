@@ -70,12 +70,9 @@ func logsOptions(cmd *cobra.Command) (types.ContainerLogsOptions, error) {
 	if err != nil {
 		return types.ContainerLogsOptions{}, err
 	}
-	var tail uint
-	if tailArg != "" {
-		tail, err = getTailArgAsUint(tailArg)
-		if err != nil {
-			return types.ContainerLogsOptions{}, err
-		}
+	tail, err := parseTailArg(tailArg)
+	if err != nil {
+		return types.ContainerLogsOptions{}, err
 	}
 	timestamps, err := cmd.Flags().GetBool("timestamps")
 	if err != nil {
@@ -126,17 +123,19 @@ func logsShellComplete(cmd *cobra.Command, args []string, toComplete string) ([]
 	return completion.ContainerNames(cmd, nil)
 }
 
-// Attempts to parse the argument given to `-n/--tail` as a uint.
-func getTailArgAsUint(arg string) (uint, error) {
-	if arg == "all" {
-		return 0, nil
+// parseTailArg parses the argument given to `-n/--tail`.
+// It returns nil for "all" (or an empty or negative number, as Docker does), meaning that all the lines are shown.
+func parseTailArg(arg string) (*uint, error) {
+	if arg == "" || arg == "all" {
+		return nil, nil
 	}
 	num, err := strconv.Atoi(arg)
 	if err != nil {
-		return 0, fmt.Errorf("failed to parse `-n/--tail` argument %q: %w", arg, err)
+		return nil, fmt.Errorf("failed to parse `-n/--tail` argument %q: %w", arg, err)
 	}
 	if num < 0 {
-		return 0, fmt.Errorf("`-n/--tail` argument must be positive, got: %d", num)
+		return nil, nil
 	}
-	return uint(num), nil
+	tail := uint(num)
+	return &tail, nil
 }
@@ -120,6 +120,62 @@ bar
 	testCase.Run(t)
 }
 
+func TestLogsTail(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	if runtime.GOOS == "windows" {
+		testCase.Require = nerdtest.NerdctlNeedsFixing("https://github.com/containerd/nerdctl/issues/4237")
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+	}
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("run", "--quiet", "--name", data.Identifier(), testutil.CommonImage, "sh", "-euc", "for i in 1 2 3 4 5; do echo line$i; done")
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "all",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("logs", "--tail", "all", data.Identifier())
+			},
+			Expected: test.Expects(0, nil, expect.Equals("line1\nline2\nline3\nline4\nline5\n")),
+		},
+		{
+			Description: "negative number shows all",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("logs", "--tail", "-1", data.Identifier())
+			},
+			Expected: test.Expects(0, nil, expect.Equals("line1\nline2\nline3\nline4\nline5\n")),
+		},
+		{
+			Description: "0 shows nothing",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("logs", "--tail", "0", data.Identifier())
+			},
+			Expected: test.Expects(0, nil, expect.Equals("")),
+		},
+		{
+			Description: "positive number shows the last lines",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("logs", "--tail", "2", data.Identifier())
+			},
+			Expected: test.Expects(0, nil, expect.Equals("line4\nline5\n")),
+		},
+		{
+			Description: "invalid number",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("logs", "--tail", "foo", data.Identifier())
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, nil, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
+
 // Tests whether `nerdctl logs` properly separates stdout/stderr output
 // streams for containers using the jsonfile logging driver:
 func TestLogsOutStreamsSeparated(t *testing.T) {
@@ -525,6 +581,36 @@ func TestLogsWithDetails(t *testing.T) {
 	testCase.Run(t)
 }
 
+func TestLogsWithDetailsTag(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.All(
+		require.Not(nerdtest.Docker),
+		require.Not(require.Windows),
+	)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("run", "--log-driver", "json-file",
+			"--log-opt", "tag={{.Name}}/{{.DaemonName}}",
+			"--name", data.Identifier(), testutil.CommonImage,
+			"sh", "-ec", "echo baz")
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+	}
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		return helpers.Command("logs", "--details", data.Identifier())
+	}
+
+	testCase.Expected = func(data test.Data, helpers test.Helpers) *test.Expected {
+		return test.Expects(0, nil, expect.Contains("tag="+data.Identifier()+"/nerdctl", "baz"))(data, helpers)
+	}
+
+	testCase.Run(t)
+}
+
 func TestLogsFollowNoExtraneousLineFeed(t *testing.T) {
 	testCase := nerdtest.Setup()
 	// This test verifies that `nerdctl logs -f` does not add extraneous line feeds
@@ -17,9 +17,16 @@
 package container
 
 import (
+	"encoding/json"
 	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -167,3 +174,105 @@ func TestRestartWithSignal(t *testing.T) {
 
 	testCase.Run(t)
 }
+
+// countingVolumePlugin serves the Docker volume plugin protocol, mounting the volumes in a directory of the host,
+// and counting the calls to /VolumeDriver.Unmount.
+type countingVolumePlugin struct {
+	mu       sync.Mutex
+	root     string
+	mounts   map[string]string
+	unmounts int
+}
+
+func (p *countingVolumePlugin) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name string
+		ID   string
+	}
+	_ = json.NewDecoder(r.Body).Decode(&req)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	res := map[string]any{}
+	switch r.URL.Path {
+	case "/Plugin.Activate":
+		res["Implements"] = []string{"VolumeDriver"}
+	case "/VolumeDriver.Create", "/VolumeDriver.Remove":
+	case "/VolumeDriver.Mount":
+		mountpoint := filepath.Join(p.root, req.Name)
+		if err := os.MkdirAll(mountpoint, 0o755); err != nil {
+			res["Err"] = err.Error()
+			break
+		}
+		p.mounts[req.ID] = req.Name
+		res["Mountpoint"] = mountpoint
+	case "/VolumeDriver.Unmount":
+		delete(p.mounts, req.ID)
+		p.unmounts++
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+func (p *countingVolumePlugin) counts() (int, int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.mounts), p.unmounts
+}
+
+func TestRestartVolumeDriverMounts(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	// The volume plugins are discovered in /run/docker/plugins
+	testCase.Require = require.All(nerdtest.Rootful, require.Not(nerdtest.Docker))
+
+	plugin := &countingVolumePlugin{root: t.TempDir(), mounts: map[string]string{}}
+	var srv *httptest.Server
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		pluginDir := "/run/docker/plugins"
+		assert.NilError(helpers.T(), os.MkdirAll(pluginDir, 0o755))
+		l, err := net.Listen("unix", filepath.Join(pluginDir, data.Identifier()+".sock"))
+		assert.NilError(helpers.T(), err)
+		srv = httptest.NewUnstartedServer(plugin)
+		srv.Listener = l
+		srv.Start()
+
+		helpers.Ensure("volume", "create", "--driver", data.Identifier(), data.Identifier())
+		helpers.Ensure("run", "-d", "--name", data.Identifier(), "-v", data.Identifier()+":/data",
+			testutil.AlpineImage, "sleep", nerdtest.Infinity)
+		helpers.Ensure("exec", data.Identifier(), "sh", "-c", "echo hello > /data/file")
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+		helpers.Anyhow("volume", "rm", data.Identifier())
+		if srv != nil {
+			srv.Close()
+		}
+	}
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		// Starting the running container must not release the mount of its volume
+		helpers.Ensure("start", data.Identifier())
+		mounts, unmounts := plugin.counts()
+		assert.Equal(helpers.T(), mounts, 1)
+		assert.Equal(helpers.T(), unmounts, 0)
+
+		helpers.Ensure("restart", data.Identifier())
+		return helpers.Command("exec", data.Identifier(), "cat", "/data/file")
+	}
+
+	testCase.Expected = test.Expects(expect.ExitCodeSuccess, nil, expect.All(
+		expect.Equals("hello\n"),
+		func(stdout string, t tig.T) {
+			// The volume is remounted once on restart, keeping a single mount
+			mounts, unmounts := plugin.counts()
+			assert.Equal(t, mounts, 1)
+			assert.Equal(t, unmounts, 1)
+		},
+	))
+
+	testCase.Run(t)
+}
@@ -19,16 +19,21 @@ package container
 import (
 	"errors"
 	"fmt"
+	"os"
 	"runtime"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
 
 	"github.com/containerd/console"
+	"github.com/containerd/errdefs"
 	"github.com/containerd/log"
 
 	"github.com/containerd/nerdctl/v2/cmd/nerdctl/completion"
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
 	"github.com/containerd/nerdctl/v2/pkg/annotations"
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/clientutil"
@@ -87,16 +92,20 @@ func setCreateFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolP("tty", "t", false, "Allocate a pseudo-TTY")
 	cmd.Flags().Bool("sig-proxy", true, "Proxy received signals to the process (default true)")
 	cmd.Flags().BoolP("interactive", "i", false, "Keep STDIN open even if not attached")
-	cmd.Flags().String("restart", "no", `Restart policy to apply when a container exits (implemented values: "no"|"always|on-failure:n|unless-stopped")`)
+	cmd.Flags().String("restart", "no", `Restart policy to apply when a container exits (implemented values: "no"|"always|on-failure:n[:codes=c1,c2]|unless-stopped")`)
 	cmd.RegisterFlagCompletionFunc("restart", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{"no", "always", "on-failure", "unless-stopped"}, cobra.ShellCompDirectiveNoFileComp
 	})
+	cmd.Flags().Duration("restart-min-uptime", 0, "Minimum uptime of a restarted container, earlier exits are restarted with a backoff (0 to disable)")
 	cmd.Flags().Bool("rm", false, "Automatically remove the container when it exits")
 	cmd.Flags().String("pull", "missing", `Pull image before running ("always"|"missing"|"never")`)
 	cmd.Flags().BoolP("quiet", "q", false, "Suppress the pull output")
 	cmd.RegisterFlagCompletionFunc("pull", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{"always", "missing", "never"}, cobra.ShellCompDirectiveNoFileComp
 	})
+	cmd.Flags().StringSlice("registry-mirror", nil, "Registry mirror to try before Docker Hub when pulling from Docker Hub (can be specified multiple times)")
+	cmd.Flags().Int("pull-retry", 0, "Number of times to retry a failed pull (0 disables retries)")
+	cmd.Flags().Duration("pull-retry-delay", time.Second, "Delay between the pull retries")
 	cmd.Flags().String("stop-signal", "SIGTERM", "Signal to stop a container")
 	cmd.Flags().Int("stop-timeout", 0, "Timeout (in seconds) to stop a container")
 	cmd.Flags().String("detach-keys", consoleutil.DefaultDetachKeys, "Override the default detach keys")
@@ -104,6 +113,7 @@ func setCreateFlags(cmd *cobra.Command) {
 	// #region for init process
 	cmd.Flags().Bool("init", false, "Run an init process inside the container, Default to use tini")
 	cmd.Flags().String("init-binary", tiniInitBinary, "The custom binary to use as the init process")
+	cmd.Flags().StringArray("init-args", nil, "Additional argument to pass to the init process, e.g., --init-args=-v (can be specified multiple times)")
 	// #endregion
 
 	// #region platform flags
@@ -127,13 +137,18 @@ func setCreateFlags(cmd *cobra.Command) {
 	// We allow for both "--dns-opt" and "--dns-option", although the latter is the recommended way.
 	cmd.Flags().StringSlice("dns-opt", nil, "Set DNS options")
 	cmd.Flags().StringSlice("dns-option", nil, "Set DNS options")
+	cmd.Flags().Bool("no-resolv", false, "Do not manage /etc/resolv.conf, keep the one of the image (same as --dns=none)")
+	cmd.Flags().Bool("no-hosts", false, "Do not manage /etc/hosts, keep the one of the image")
 	// publish is defined as StringSlice, not StringArray, to allow specifying "--publish=80:80,443:443" (compatible with Podman)
 	cmd.Flags().StringSliceP("publish", "p", nil, "Publish a container's port(s) to the host")
+	cmd.Flags().BoolP("publish-all", "P", false, "Publish all exposed ports to random host ports")
+	cmd.Flags().StringSlice("expose", nil, "Expose a port or a range of ports")
 	cmd.Flags().String("ip", "", "IPv4 address to assign to the container")
 	cmd.Flags().String("ip6", "", "IPv6 address to assign to the container")
 	cmd.Flags().StringP("hostname", "h", "", "Container host name")
 	cmd.Flags().String("domainname", "", "Container domain name")
 	cmd.Flags().String("mac-address", "", "MAC address to assign to the container")
+	cmd.Flags().Int("network-mtu", 0, "MTU of the container network interfaces (default: the MTU of the network)")
 	// #endregion
 
 	cmd.Flags().String("ipc", "", `IPC namespace to use ("host"|"private")`)
@@ -166,6 +181,7 @@ func setCreateFlags(cmd *cobra.Command) {
 	cmd.Flags().Uint64("cpu-shares", 0, "CPU shares (relative weight)")
 	cmd.Flags().Int64("cpu-quota", -1, "Limit CPU CFS (Completely Fair Scheduler) quota")
 	cmd.Flags().Uint64("cpu-period", 0, "Limit CPU CFS (Completely Fair Scheduler) period")
+	cmd.Flags().Uint64("cpu-burst", 0, "CPU time in microseconds that can be used beyond the quota of --cpus or --cpu-quota (cgroup v2 only)")
 	cmd.Flags().Uint64("cpu-rt-period", 0, "Limit CPU real-time period in microseconds")
 	cmd.Flags().Uint64("cpu-rt-runtime", 0, "Limit CPU real-time runtime in microseconds")
 	// device is defined as StringSlice, not StringArray, to allow specifying "--device=DEV1,DEV2" (compatible with Podman)
@@ -241,6 +257,7 @@ func setCreateFlags(cmd *cobra.Command) {
 	cmd.Flags().Int("health-retries", 0, "Consecutive failures needed to report unhealthy (default: 3)")
 	cmd.Flags().Duration("health-start-period", 0, "Start period for the container to initialize before starting health-retries countdown")
 	cmd.Flags().Duration("health-start-interval", 0, "Time between running the checks during the start period")
+	cmd.Flags().StringArray("health-env", nil, "Set environment variables (KEY=VALUE) for the health check command only")
 	cmd.Flags().Bool("no-healthcheck", false, "Disable any container-specified HEALTHCHECK")
 
 	// #region env flags
@@ -255,6 +272,8 @@ func setCreateFlags(cmd *cobra.Command) {
 	cmd.Flags().StringSlice("add-host", nil, "Add a custom host-to-IP mapping (host:ip)")
 	// env-file is defined as StringSlice, not StringArray, to allow specifying "--env-file=FILE1,FILE2" (compatible with Podman)
 	cmd.Flags().StringSlice("env-file", nil, "Set environment variables from file")
+	// redact-env is defined as StringSlice, not StringArray, to allow specifying "--redact-env=PASSWORD,TOKEN"
+	cmd.Flags().StringSlice("redact-env", nil, "Redact the values of the specified environment variables in inspect")
 
 	// #region metadata flags
 	cmd.Flags().String("name", "", "Assign a name to the container")
@@ -304,7 +323,7 @@ func setCreateFlags(cmd *cobra.Command) {
 		}
 		return []string{"default"}, cobra.ShellCompDirectiveNoFileComp
 	})
-	cmd.Flags().String("userns", "", "Specify host to disable userns-remap")
+	cmd.Flags().String("userns", "", "User namespace to use: 'host' to disable userns-remap, 'auto[:size=N]' to allocate a free ID range, or '<name|uid>[:<group|gid>]' to use its /etc/subuid and /etc/subgid ranges")
 
 }
 
@@ -328,7 +347,7 @@ func processCreateCommandFlagsInRun(cmd *cobra.Command) (types.ContainerCreateOp
 	if err != nil {
 		return opt, err
 	}
-	opt.DetachKeys, err = cmd.Flags().GetString("detach-keys")
+	opt.DetachKeys, err = helpers.DetachKeys(cmd)
 	if err != nil {
 		return opt, err
 	}
@@ -443,14 +462,20 @@ func runAction(cmd *cobra.Command, args []string) error {
 		fmt.Fprintln(createOpt.Stdout, id)
 		return nil
 	}
+	var hangupC, interruptC chan os.Signal
 	if createOpt.TTY {
 		if err := consoleutil.HandleConsoleResize(ctx, task, con); err != nil {
 			log.L.WithError(err).Error("console resize")
 		}
+		hangupC = signalutil.NotifyHangup()
+		defer signalutil.StopCatch(hangupC)
 	} else {
 		if createOpt.SigProxy {
 			sigC := signalutil.ForwardAllSignals(ctx, task)
 			defer signalutil.StopCatch(sigC)
+		} else {
+			interruptC = signalutil.NotifyInterrupt()
+			defer signalutil.StopCatch(interruptC)
 		}
 	}
 
@@ -458,36 +483,66 @@ func runAction(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	select {
-	// io.Wait() would return when either 1) the user detaches from the container OR 2) the container is about to exit.
-	//
-	// If we replace the `select` block with io.Wait() and
-	// directly use task.Status() to check the status of the container after io.Wait() returns,
-	// it can still be running even though the container is about to exit (somehow especially for Windows).
-	//
-	// As a result, we need a separate detachC to distinguish from the 2 cases mentioned above.
-	case <-detachC:
-		io := task.IO()
-		if io == nil {
-			return errors.New("got a nil IO from the task")
-		}
-		io.Wait()
-		isDetached = true
-	case status := <-statusC:
-		if createOpt.Rm {
-			if _, taskDeleteErr := task.Delete(ctx); taskDeleteErr != nil {
-				log.L.Error(taskDeleteErr)
+	for {
+		select {
+		// io.Wait() would return when either 1) the user detaches from the container OR 2) the container is about to exit.
+		//
+		// If we replace the `select` block with io.Wait() and
+		// directly use task.Status() to check the status of the container after io.Wait() returns,
+		// it can still be running even though the container is about to exit (somehow especially for Windows).
+		//
+		// As a result, we need a separate detachC to distinguish from the 2 cases mentioned above.
+		case <-detachC:
+			io := task.IO()
+			if io == nil {
+				return errors.New("got a nil IO from the task")
 			}
-		}
-		code, _, err := status.Result()
-		if err != nil {
-			return err
-		}
-		if code != 0 {
-			return errutil.NewExitCoderErr(int(code))
+			io.Wait()
+			isDetached = true
+			return nil
+		case <-hangupC:
+			// The terminal was closed, e.g., the SSH session was dropped.
+			if !createOpt.SigProxy {
+				// The container keeps running as if the user had detached from it.
+				log.L.Warnf("terminal hung up, detaching from container %s", id)
+				if io := task.IO(); io != nil {
+					io.Cancel()
+				}
+				isDetached = true
+				return nil
+			}
+			log.L.Debugf("terminal hung up, sending SIGHUP to container %s", id)
+			if err := task.Kill(ctx, syscall.SIGHUP); err != nil && !errdefs.IsNotFound(err) {
+				log.L.WithError(err).Error("forward signal SIGHUP")
+			}
+			// Keep waiting for the container to exit, so that --rm and the console
+			// reset are still handled.
+			hangupC = nil
+		case sig := <-interruptC:
+			// With --sig-proxy=false, the signal is not forwarded to the container.
+			// nerdctl stops waiting and exits, leaving the container running.
+			log.L.Warnf("received %s, detaching from container %s without forwarding the signal", sig, id)
+			if io := task.IO(); io != nil {
+				io.Cancel()
+			}
+			isDetached = true
+			return errutil.NewExitCoderErr(128 + int(sig.(syscall.Signal)))
+		case status := <-statusC:
+			if createOpt.Rm {
+				if _, taskDeleteErr := task.Delete(ctx); taskDeleteErr != nil {
+					log.L.Error(taskDeleteErr)
+				}
+			}
+			code, _, err := status.Result()
+			if err != nil {
+				return err
+			}
+			if code != 0 {
+				return errutil.NewExitCoderErr(int(code))
+			}
+			return nil
 		}
 	}
-	return nil
 }
 
 func runShellComplete(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -19,6 +19,7 @@ package container
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -184,6 +185,35 @@ func TestRunCgroupV1(t *testing.T) {
 	base.Cmd("run", "--rm", testutil.AlpineImage, "mkdir", "/sys/fs/cgroup/pids/foo").AssertFail()
 }
 
+func TestRunCgroupnsPrivate(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = &test.Requirement{
+		Check: func(data test.Data, helpers test.Helpers) (bool, string) {
+			_, err := os.Stat("/proc/self/ns/cgroup")
+			return err == nil, "the kernel does not support cgroup namespaces"
+		},
+	}
+
+	// With a private cgroup namespace, the cgroup of the container is the root of every hierarchy,
+	// on cgroup v1 as well as on v2.
+	testCase.Command = test.Command("run", "--rm", "--cgroupns=private", testutil.AlpineImage, "cat", "/proc/self/cgroup")
+
+	testCase.Expected = func(data test.Data, helpers test.Helpers) *test.Expected {
+		return &test.Expected{
+			Output: func(stdout string, t tig.T) {
+				lines := strings.Split(strings.TrimSpace(stdout), "\n")
+				assert.Assert(t, len(lines) > 0)
+				for _, line := range lines {
+					assert.Assert(t, strings.HasSuffix(line, ":/"), "expected a private cgroup view, got %q", line)
+				}
+			},
+		}
+	}
+
+	testCase.Run(t)
+}
+
 // TestIssue3781 tests https://github.com/containerd/nerdctl/issues/3781
 func TestIssue3781(t *testing.T) {
 	t.Parallel()
@@ -463,6 +493,43 @@ func TestRunCgroupParent(t *testing.T) {
 	base.Cmd("exec", containerName, "cat", "/proc/self/cgroup").AssertOutContains(expected)
 }
 
+func TestRunCgroupParentSystemdSlice(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.All(
+		nerdtest.SystemdCgroup,
+		// Stopping the slice in Cleanup is only done for the system instance of systemd
+		nerdtest.Rootful,
+		// Docker leaves the creation of the slice to the OCI runtime
+		require.Not(nerdtest.Docker),
+	)
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "nested slice without a unit file is created",
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+				helpers.Custom("systemctl", "stop", "nerdctltest.slice").Run(&test.Expected{ExitCode: expect.ExitCodeNoCheck})
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "--name", data.Identifier(), "--cgroupns=host",
+					"--cgroup-parent", "nerdctltest-batch.slice",
+					testutil.AlpineImage, "cat", "/proc/self/cgroup")
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Contains("/nerdctltest.slice/nerdctltest-batch.slice/nerdctl-")),
+		},
+		{
+			Description: "slice with an empty level is refused",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "--cgroup-parent", "nerdctltest--batch.slice", testutil.AlpineImage, "true")
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("has an empty level in its hierarchy")}, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
+
 func TestRunBlkioWeightCgroupV2(t *testing.T) {
 	t.Parallel()
 	if cgroups.Mode() != cgroups.Unified {
@@ -675,6 +742,43 @@ func TestRunBlkioSettingCgroupV2(t *testing.T) {
 				}
 			},
 		},
+		{
+			Description: "device-read-iops with k suffix, last one wins",
+			Require: require.All(
+				nerdtest.CGroupV2,
+				require.Not(nerdtest.Docker),
+			),
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "-d", "--name", data.Identifier(),
+					"--device-read-iops", dummyDev+":1000",
+					"--device-read-iops", dummyDev+":3k",
+					testutil.AlpineImage, "sleep", "infinity")
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					ExitCode: 0,
+					Output: func(stdout string, t tig.T) {
+						inspectOut := helpers.Capture("inspect", "--format", "{{range .HostConfig.BlkioDeviceReadIOps}}{{.Rate}} {{end}}", data.Identifier())
+						assert.Equal(t, strings.TrimSpace(inspectOut), "3000")
+					},
+				}
+			},
+		},
+		{
+			Description: "device-write-iops with byte units",
+			Require: require.All(
+				nerdtest.CGroupV2,
+				require.Not(nerdtest.Docker),
+			),
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "--device-write-iops", dummyDev+":1kb",
+					testutil.AlpineImage, "true")
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("invalid write iops device")}, nil),
+		},
 	}
 
 	testCase.Run(t)
@@ -715,3 +819,254 @@ func TestRunCPURealTimeSettingCgroupV1(t *testing.T) {
 
 	testCase.Run(t)
 }
+
+func TestRunCPURealTimeSettingUnsupported(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.All(
+		require.Not(nerdtest.Docker),
+		nerdtest.CGroupV2,
+	)
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+	}
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		return helpers.Command("create", "--name", data.Identifier(),
+			"--cpu-rt-runtime", "950000",
+			"--cpu-rt-period", "1000000",
+			testutil.AlpineImage, "sleep", "infinity")
+	}
+
+	testCase.Expected = test.Expects(expect.ExitCodeGenericFail, []error{errors.New("cpu real-time scheduler")}, nil)
+
+	testCase.Run(t)
+}
+
+func TestRunCPURealTimeRuntimeHigherThanPeriod(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+	}
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		return helpers.Command("create", "--name", data.Identifier(),
+			"--cpu-rt-runtime", "2000000",
+			"--cpu-rt-period", "1000000",
+			testutil.AlpineImage, "sleep", "infinity")
+	}
+
+	testCase.Expected = test.Expects(expect.ExitCodeGenericFail, []error{errors.New("cannot be higher than cpu real-time period")}, nil)
+
+	testCase.Run(t)
+}
+
+func TestRunDeviceTun(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = nerdtest.Rootful
+
+	// /dev/net/tun is created in the container, even if it does not exist on the host
+	testCase.Command = test.Command("run", "--rm", "--device", "/dev/net/tun", testutil.AlpineImage, "test", "-c", "/dev/net/tun")
+
+	testCase.Expected = test.Expects(expect.ExitCodeSuccess, nil, nil)
+
+	testCase.Run(t)
+}
+
+func TestRunDeviceFuse(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = nerdtest.Rootful
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "usable with CAP_SYS_ADMIN",
+			// Opening /dev/fuse read-write requires the device cgroup rule to allow it
+			Command: test.Command("run", "--rm", "--device", "/dev/fuse", "--cap-add", "SYS_ADMIN",
+				"--security-opt", "apparmor=unconfined",
+				testutil.AlpineImage, "sh", "-euc", "test -c /dev/fuse; exec 3<>/dev/fuse"),
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, nil),
+		},
+		{
+			Description: "warns without CAP_SYS_ADMIN",
+			Require:     require.Not(nerdtest.Docker),
+			Command: test.Command("run", "--rm", "--device", "/dev/fuse",
+				testutil.AlpineImage, "test", "-c", "/dev/fuse"),
+			Expected: test.Expects(expect.ExitCodeSuccess, []error{errors.New("--cap-add=SYS_ADMIN")}, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestRunDeviceGlob(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = nerdtest.Rootful
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "adds all the matching device nodes",
+			Require: &test.Requirement{
+				Check: func(data test.Data, helpers test.Helpers) (bool, string) {
+					matches, _ := filepath.Glob("/dev/snd/*")
+					for _, match := range matches {
+						if fi, err := os.Lstat(match); err == nil && fi.Mode()&os.ModeCharDevice != 0 {
+							return true, ""
+						}
+					}
+					return false, "the host has no sound device"
+				},
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				var checks []string
+				matches, _ := filepath.Glob("/dev/snd/*")
+				for _, match := range matches {
+					if fi, err := os.Lstat(match); err == nil && fi.Mode()&os.ModeCharDevice != 0 {
+						checks = append(checks, "test -c "+match)
+					}
+				}
+				return helpers.Command("run", "--rm", "--device", "/dev/snd/*",
+					testutil.AlpineImage, "sh", "-euc", strings.Join(checks, "; "))
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, nil),
+		},
+		{
+			Description: "fails without a matching device node",
+			Require:     require.Not(nerdtest.Docker),
+			Command: test.Command("run", "--rm", "--device", "/dev/nerdctl-test-nonexistent*",
+				testutil.AlpineImage, "true"),
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("no device node matches")}, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestRunCPUSetCPUsValidation(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "out of range",
+			// Docker fails later, from the OCI runtime
+			Require: require.Not(nerdtest.Docker),
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "--cpuset-cpus", "0,4096-4097", testutil.CommonImage, "true")
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("CPU 4096 is not available")}, nil),
+		},
+		{
+			Description: "mixed syntax",
+			Require:     nerdtest.CgroupsAccessible,
+			NoParallel:  true,
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "--cpuset-cpus", "0-0,0", testutil.CommonImage, "true")
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, nil),
+		},
+		{
+			Description: "invalid syntax",
+			Require:     require.Not(nerdtest.Docker),
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "--cpuset-cpus", "1-0", testutil.CommonImage, "true")
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("invalid --cpuset-cpus")}, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestRunMemoryReservation(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "reservation higher than the limit",
+			// Docker fails later, from the OCI runtime
+			Require: require.Not(nerdtest.Docker),
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "--memory", "6m", "--memory-reservation", "42m", testutil.AlpineImage, "true")
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("--memory-reservation (42MiB) must be less than or equal to --memory (6MiB)")}, nil),
+		},
+		{
+			Description: "reservation equal to the limit",
+			Require:     require.All(nerdtest.CGroupV2, nerdtest.CgroupsAccessible),
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "-w", "/sys/fs/cgroup", "--memory", "42m", "--memory-reservation", "42m",
+					testutil.AlpineImage, "cat", "memory.max", "memory.low")
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Equals("44040192\n44040192\n")),
+		},
+		{
+			Description: "reservation without limit",
+			Require:     require.All(nerdtest.CGroupV2, nerdtest.CgroupsAccessible),
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "-w", "/sys/fs/cgroup", "--memory-reservation", "6m",
+					testutil.AlpineImage, "cat", "memory.max", "memory.low")
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Equals("max\n6291456\n")),
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestRunCPUBurst(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.All(
+		require.Not(nerdtest.Docker),
+		nerdtest.CGroupV2,
+		&test.Requirement{
+			Check: func(data test.Data, helpers test.Helpers) (bool, string) {
+				matches, _ := filepath.Glob("/sys/fs/cgroup/*/cpu.max.burst")
+				return len(matches) > 0, "the kernel does not support cpu.max.burst"
+			},
+		},
+	)
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "burst within the quota",
+			Require:     nerdtest.CgroupsAccessible,
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--name", data.Identifier(), "--cpus", "0.5", "--cpu-burst", "20000",
+					testutil.AlpineImage, "cat", "/sys/fs/cgroup/cpu.max", "/sys/fs/cgroup/cpu.max.burst")
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.All(
+						expect.Equals("50000 100000\n20000\n"),
+						func(stdout string, t tig.T) {
+							inspect := nerdtest.InspectContainer(helpers, data.Identifier())
+							assert.Equal(t, inspect.HostConfig.CPUBurst, uint64(20000))
+						},
+					),
+				}
+			},
+		},
+		{
+			Description: "burst higher than the quota",
+			Command:     test.Command("run", "--rm", "--cpu-quota", "10000", "--cpu-burst", "20000", testutil.AlpineImage, "true"),
+			Expected:    test.Expects(expect.ExitCodeGenericFail, []error{errors.New("cannot be higher than the CPU quota")}, nil),
+		},
+		{
+			Description: "burst without quota",
+			Command:     test.Command("run", "--rm", "--cpu-burst", "20000", testutil.AlpineImage, "true"),
+			Expected:    test.Expects(expect.ExitCodeGenericFail, []error{errors.New("requires a CPU quota")}, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
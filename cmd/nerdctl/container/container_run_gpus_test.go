@@ -57,3 +57,28 @@ func TestParseGpusOpts(t *testing.T) {
 		assert.Check(t, is.DeepEqual(req.Capabilities, []string{"compute", "utility"}))
 	}
 }
+
+func TestParseGpusOptFraction(t *testing.T) {
+	t.Parallel()
+	req, err := container.ParseGPUOptCSV("device=0,fraction=0.25")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(req.DeviceIDs, []string{"0"}))
+	assert.Equal(t, req.Fraction, 0.25)
+
+	req, err = container.ParseGPUOptCSV("count=1,fraction=1")
+	assert.NilError(t, err)
+	assert.Equal(t, req.Count, 1)
+	assert.Equal(t, req.Fraction, 1.0)
+
+	for _, testcase := range []string{
+		"device=0,fraction=0",
+		"device=0,fraction=1.5",
+		"device=0,fraction=-0.5",
+		"device=0,fraction=quarter",
+		"device=MIG-3a23c669-1f69-c64e-cf85-44e9b07e7a2a,fraction=0.5",
+		"device=0:1,fraction=0.5",
+	} {
+		_, err := container.ParseGPUOptCSV(testcase)
+		assert.Check(t, err != nil, testcase)
+	}
+}
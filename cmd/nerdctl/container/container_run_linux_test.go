@@ -28,6 +28,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"syscall"
 	"testing"
 	"time"
 
@@ -279,6 +280,38 @@ func TestRunUlimit(t *testing.T) {
 	base.Cmd("run", "--rm", "--ulimit", ulimit2, testutil.AlpineImage, "sh", "-c", "ulimit -Hn").AssertOutExactly("722\n")
 }
 
+func TestRunUlimitCapabilityHint(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	// Raising the hard limit of memlock to unlimited is not permitted in rootless mode
+	testCase.Require = require.All(nerdtest.Rootful, require.Not(nerdtest.Docker))
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "memlock without CAP_IPC_LOCK",
+			Command:     test.Command("run", "--rm", "--ulimit", "memlock=-1", testutil.AlpineImage, "true"),
+			Expected:    test.Expects(0, []error{errors.New("has no effect beyond the ulimit"), errors.New("--cap-add=IPC_LOCK")}, nil),
+		},
+		{
+			Description: "rtprio without CAP_SYS_NICE",
+			Command:     test.Command("run", "--rm", "--ulimit", "rtprio=99", testutil.AlpineImage, "true"),
+			Expected:    test.Expects(0, []error{errors.New("--cap-add=SYS_NICE")}, nil),
+		},
+		{
+			Description: "the limit is applied with the capability",
+			Command: test.Command("run", "--rm", "--ulimit", "memlock=-1", "--cap-add=IPC_LOCK",
+				testutil.AlpineImage, "sh", "-c", "ulimit -l"),
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.Equals("unlimited\n"),
+				}
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
+
 func TestRunWithInit(t *testing.T) {
 	t.Parallel()
 	testutil.DockerIncompatible(t)
@@ -312,6 +345,79 @@ func TestRunWithInit(t *testing.T) {
 	assert.Equal(t, base.InspectContainer(container2).State.ExitCode, 143)
 }
 
+func TestRunWithInitExitCode(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.Binary("tini")
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "the exit code of the child is propagated",
+			Command:     test.Command("run", "--rm", "--init", testutil.AlpineImage, "sh", "-c", "exit 42"),
+			Expected:    test.Expects(42, nil, nil),
+		},
+		{
+			Description: "the exit code of the child is propagated when tini is not PID 1",
+			Command:     test.Command("run", "--rm", "--init", "--pid=host", testutil.AlpineImage, "sh", "-c", "exit 42"),
+			Expected:    test.Expects(42, nil, nil),
+		},
+		{
+			Description: "a child killed by a signal exits with 128+signal",
+			Command:     test.Command("run", "--rm", "--init", testutil.AlpineImage, "sh", "-c", "kill -KILL $$"),
+			Expected:    test.Expects(137, nil, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestRunWithInitArgs(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.All(
+		require.Not(nerdtest.Docker),
+		require.Binary("tini"),
+	)
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "the arguments are passed to tini (-e remaps the exit code to 0)",
+			Command: test.Command("run", "--rm", "--init", "--init-args=-e", "--init-args=42",
+				testutil.AlpineImage, "sh", "-c", "exit 42"),
+			Expected: test.Expects(0, nil, nil),
+		},
+		{
+			Description: "the arguments are preserved across restarts",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("run", "-d", "--name", data.Identifier(), "--init", "--init-args=-v",
+					testutil.AlpineImage, "sleep", nerdtest.Infinity)
+				nerdtest.EnsureContainerStarted(helpers, data.Identifier())
+				helpers.Ensure("restart", "--time=1", data.Identifier())
+				nerdtest.EnsureContainerStarted(helpers, data.Identifier())
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("exec", data.Identifier(), "sh", "-c", "tr '\\0' ' ' < /proc/1/cmdline")
+			},
+			Expected: test.Expects(0, nil, expect.Contains("/sbin/tini -v -- sleep")),
+		},
+		{
+			Description: "an empty argument is rejected",
+			Command:     test.Command("run", "--rm", "--init", "--init-args=", testutil.AlpineImage, "true"),
+			Expected:    test.Expects(1, []error{errors.New("must not be empty")}, nil),
+		},
+		{
+			Description: "--init is required",
+			Command:     test.Command("run", "--rm", "--init-args=-v", testutil.AlpineImage, "true"),
+			Expected:    test.Expects(1, []error{errors.New("requires --init")}, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
+
 func TestRunTTY(t *testing.T) {
 	const sttyPartialOutput = "speed 38400 baud"
 
@@ -439,7 +545,21 @@ func TestRunSigProxy(t *testing.T) {
 				return cmd
 			},
 
-			Expected: test.Expects(expect.ExitCodeSignaled, nil, expect.DoesNotContain(nerdtest.SignalCaught)),
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					// nerdctl exits on its own on SIGINT, without forwarding it to the container
+					ExitCode: 128 + int(syscall.SIGINT),
+					Output: expect.All(
+						expect.DoesNotContain(nerdtest.SignalCaught),
+						func(stdout string, t tig.T) {
+							container := nerdtest.InspectContainer(helpers, data.Identifier())
+							assert.Assert(t, container.State.Running, "the container must keep running")
+							logs := helpers.Capture("logs", data.Identifier())
+							assert.Assert(t, !strings.Contains(logs, nerdtest.SignalCaught), "the container must not be signaled")
+						},
+					),
+				}
+			},
 		},
 	}
 
@@ -515,6 +635,23 @@ func TestRunWithOOMScoreAdj(t *testing.T) {
 	base.Cmd("run", "--rm", "--oom-score-adj", score, testutil.AlpineImage, "cat", "/proc/self/oom_score_adj").AssertOutContains(score)
 }
 
+func TestRunWithOOMScoreAdjAndInit(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.All(
+		nerdtest.Rootful,
+		require.Binary("tini"),
+	)
+
+	// The score is set on the init process, and inherited by the workload, which can still raise it.
+	testCase.Command = test.Command("run", "--rm", "--init", "--oom-score-adj", "-42", testutil.AlpineImage,
+		"sh", "-c", "cat /proc/1/oom_score_adj /proc/self/oom_score_adj; echo 500 >/proc/self/oom_score_adj; cat /proc/self/oom_score_adj")
+
+	testCase.Expected = test.Expects(expect.ExitCodeSuccess, nil, expect.Equals("-42\n-42\n500\n"))
+
+	testCase.Run(t)
+}
+
 func TestRunWithDetachKeys(t *testing.T) {
 	testCase := nerdtest.Setup()
 
@@ -559,6 +696,43 @@ func TestRunWithDetachKeys(t *testing.T) {
 	testCase.Run(t)
 }
 
+func TestRunWithDetachKeysNone(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+	}
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		cmd := helpers.Command("run", "-it", "--detach-keys=none", "--name", data.Identifier(), testutil.CommonImage)
+		cmd.WithPseudoTTY()
+		cmd.Feed(strings.NewReader("echo mark${NON}mark\n"))
+		cmd.WithFeeder(func() io.Reader {
+			nerdtest.EnsureContainerStarted(helpers, data.Identifier())
+			// ctrl+p and ctrl+q must be passed to the container instead of detaching
+			return io.MultiReader(bytes.NewReader([]byte{16, 17}), strings.NewReader("\nexit\n"))
+		})
+
+		return cmd
+	}
+
+	testCase.Expected = func(data test.Data, helpers test.Helpers) *test.Expected {
+		return &test.Expected{
+			ExitCode: 0,
+			Output: expect.All(
+				expect.Contains("markmark"),
+				func(stdout string, t tig.T) {
+					assert.Assert(t, strings.Contains(helpers.Capture("inspect", "--format", "json", data.Identifier()), "\"Running\":false"))
+				},
+			),
+		}
+	}
+
+	testCase.Run(t)
+}
+
 func TestRunWithTtyAndDetached(t *testing.T) {
 	base := testutil.NewBase(t)
 	imageName := testutil.CommonImage
@@ -17,6 +17,7 @@
 package container
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -24,10 +25,12 @@ import (
 	"testing"
 
 	mobymount "github.com/moby/sys/mount"
+	"golang.org/x/sys/unix"
 	"gotest.tools/v3/assert"
 
 	"github.com/containerd/containerd/v2/core/mount"
 	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
 	"github.com/containerd/nerdctl/mod/tigron/test"
 	"github.com/containerd/nerdctl/mod/tigron/tig"
 
@@ -111,6 +114,40 @@ func TestRunVolumeRelativePath(t *testing.T) {
 	base.Cmd("run", "--rm", "-v", "./foo:./foo", testutil.AlpineImage).AssertFail()
 }
 
+func TestRunVolumeRelativePathResolved(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		data.Temp().Save("hello", "data", "hello.txt")
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+	}
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		cmd := helpers.Command("run", "--name", data.Identifier(), "-v", "./data:/mnt/data", testutil.CommonImage, "cat", "/mnt/data/hello.txt")
+		cmd.WithCwd(data.Temp().Path())
+		return cmd
+	}
+
+	testCase.Expected = func(data test.Data, helpers test.Helpers) *test.Expected {
+		return &test.Expected{
+			Output: expect.All(
+				expect.Equals("hello"),
+				func(stdout string, t tig.T) {
+					inspect := nerdtest.InspectContainer(helpers, data.Identifier())
+					assert.Equal(t, len(inspect.Mounts), 1)
+					assert.Equal(t, inspect.Mounts[0].Type, "bind")
+					assert.Equal(t, inspect.Mounts[0].Source, data.Temp().Path("data"))
+				},
+			),
+		}
+	}
+
+	testCase.Run(t)
+}
+
 func TestRunAnonymousVolumeWithTypeMountFlag(t *testing.T) {
 	t.Parallel()
 	base := testutil.NewBase(t)
@@ -408,6 +445,54 @@ func TestRunBindMountBind(t *testing.T) {
 	testCase.Run(t)
 }
 
+func TestRunBindMountRecursiveReadOnly(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.All(
+		nerdtest.Rootful,
+		&test.Requirement{
+			Check: func(data test.Data, helpers test.Helpers) (bool, string) {
+				err := unix.MountSetattr(-1, "", unix.AT_RECURSIVE, &unix.MountAttr{})
+				return !errors.Is(err, unix.ENOSYS), "recursive read-only mounts require kernel >= 5.12"
+			},
+		},
+	)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		// A writable tmpfs is mounted under the bind source on the host
+		sub := data.Temp().Dir("src", "sub")
+		assert.NilError(helpers.T(), mobymount.Mount("tmpfs", sub, "tmpfs", ""))
+		data.Labels().Set("src", data.Temp().Path("src"))
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		_ = mobymount.Unmount(data.Temp().Path("src", "sub"))
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "submounts of a readonly bind are read-only",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm",
+					"--mount", fmt.Sprintf("type=bind,src=%s,dst=/mnt,readonly,bind-recursive=readonly", data.Labels().Get("src")),
+					testutil.AlpineImage, "touch", "/mnt/sub/file")
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("Read-only file system")}, nil),
+		},
+		{
+			Description: "submounts of a readonly bind remain writable with bind-recursive=writable",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm",
+					"--mount", fmt.Sprintf("type=bind,src=%s,dst=/mnt,readonly,bind-recursive=writable", data.Labels().Get("src")),
+					testutil.AlpineImage, "touch", "/mnt/sub/file")
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
+
 func TestRunMountBindMode(t *testing.T) {
 	if rootlessutil.IsRootless() {
 		t.Skip("must be superuser to use mount")
@@ -539,6 +624,48 @@ func TestRunVolumeBindMode(t *testing.T) {
 	})
 }
 
+func TestRunBindMountDefaultPropagation(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	// Like Docker, a bind mount without a propagation option is rprivate,
+	// so it neither receives nor propagates submounts
+	bindTest := func(description string, args ...string) *test.Case {
+		return &test.Case{
+			Description: description,
+			Setup: func(data test.Data, helpers test.Helpers) {
+				runArgs := append([]string{"run", "-d", "--name", data.Identifier()}, args...)
+				helpers.Ensure(append(runArgs, testutil.CommonImage, "sleep", nerdtest.Infinity)...)
+				nerdtest.EnsureContainerStarted(helpers, data.Identifier())
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("exec", data.Identifier(), "grep", " /mnt1 ", "/proc/self/mountinfo")
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.All(
+						expect.DoesNotContain("shared:", "master:"),
+						func(stdout string, t tig.T) {
+							container := nerdtest.InspectContainer(helpers, data.Identifier())
+							assert.Equal(t, len(container.Mounts), 1)
+							assert.Equal(t, container.Mounts[0].Propagation, "rprivate")
+						},
+					),
+				}
+			},
+		}
+	}
+
+	testCase.SubTests = []*test.Case{
+		bindTest("volume flag", "-v", "/tmp:/mnt1"),
+		bindTest("mount flag", "--mount", "type=bind,src=/tmp,dst=/mnt1"),
+	}
+
+	testCase.Run(t)
+}
+
 func TestRunBindMountPropagation(t *testing.T) {
 	t.Skip("This test is currently broken. See https://github.com/containerd/nerdctl/issues/3404")
 
@@ -736,6 +863,120 @@ func TestRunVolumesFrom(t *testing.T) {
 	).AssertOutExactly("str1str3")
 }
 
+func TestRunVolumesFromMode(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("volume", "create", data.Identifier())
+		helpers.Ensure("create", "--name", data.Identifier(), "-v", data.Identifier()+":/mnt", testutil.CommonImage)
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+		helpers.Anyhow("volume", "rm", "-f", data.Identifier())
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "inherit as ro",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "--volumes-from", data.Identifier()+":ro",
+					testutil.CommonImage, "sh", "-c", "echo foo > /mnt/file")
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("Read-only file system")}, nil),
+		},
+		{
+			Description: "inherit as-is",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "--volumes-from", data.Identifier(),
+					testutil.CommonImage, "sh", "-c", "echo foo > /mnt/file")
+			},
+			Expected: test.Expects(0, nil, nil),
+		},
+		{
+			Description: "invalid mode",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "--volumes-from", data.Identifier()+":rx",
+					testutil.CommonImage, "true")
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, nil, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestRunDuplicateMountPoint(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		data.Labels().Set("src", data.Temp().Path())
+		helpers.Ensure("volume", "create", data.Identifier())
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("volume", "rm", "-f", data.Identifier())
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "volume and bind mount",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm",
+					"-v", data.Identifier()+":/mnt",
+					"--mount", "type=bind,src="+data.Labels().Get("src")+",dst=/mnt",
+					testutil.CommonImage, "true")
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("mount point")}, nil),
+		},
+		{
+			Description: "bind mount and tmpfs",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm",
+					"--mount", "type=bind,src="+data.Labels().Get("src")+",dst=/mnt",
+					"--tmpfs", "/mnt",
+					testutil.CommonImage, "true")
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("mount point")}, nil),
+		},
+		{
+			Description: "volumes-from and volume",
+			// Docker lets user-specified mounts override the ones from --volumes-from
+			Require: require.Not(nerdtest.Docker),
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("create", "--name", data.Identifier(), "-v", data.Labels().Get("src")+":/mnt", testutil.CommonImage)
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm",
+					"--volumes-from", data.Identifier(),
+					"-v", data.Identifier()+":/mnt",
+					testutil.CommonImage, "true")
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("duplicate mount point")}, nil),
+		},
+		{
+			Description: "--mount with replace overrides volume",
+			// "replace" is specific to nerdctl
+			Require: require.Not(nerdtest.Docker),
+			Setup: func(data test.Data, helpers test.Helpers) {
+				data.Temp().Save("replaced", "marker")
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm",
+					"-v", data.Identifier()+":/mnt",
+					"--mount", "type=bind,src="+data.Temp().Path()+",dst=/mnt,replace",
+					testutil.CommonImage, "cat", "/mnt/marker")
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Equals("replaced")),
+		},
+	}
+
+	testCase.Run(t)
+}
+
 func TestBindMountWhenHostFolderDoesNotExist(t *testing.T) {
 	t.Parallel()
 	base := testutil.NewBase(t)
@@ -762,3 +1003,226 @@ func TestBindMountWhenHostFolderDoesNotExist(t *testing.T) {
 	_, err = os.Stat(hp)
 	assert.ErrorIs(t, err, os.ErrNotExist)
 }
+
+func TestRunBindMountPseudoFilesystem(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "read-only bind mount of a /sys subpath",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm",
+					"--mount", "type=bind,src=/sys/kernel/mm,dst=/mnt,readonly",
+					testutil.CommonImage, "sh", "-euc", "ls /mnt >/dev/null; grep -w /mnt /proc/mounts | grep -qw ro")
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, nil),
+		},
+		{
+			Description: "non-existent /sys subpath",
+			// Docker fails later, from the OCI runtime
+			Require: require.Not(nerdtest.Docker),
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm",
+					"-v", "/sys/"+data.Identifier()+":/mnt",
+					testutil.CommonImage, "true")
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("does not exist")}, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestRunBindMountFile(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		data.Temp().Save("hello-from-file", "app.conf")
+		data.Labels().Set("file", data.Temp().Path("app.conf"))
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "single regular file",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm",
+					"--mount", "type=bind,src="+data.Labels().Get("file")+",dst=/etc/app.conf,readonly",
+					testutil.CommonImage, "sh", "-euc", "test -f /etc/app.conf; cat /etc/app.conf")
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Equals("hello-from-file")),
+		},
+		{
+			Description: "device node",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm",
+					"--mount", "type=bind,src=/dev/null,dst=/mnt/null",
+					testutil.CommonImage, "sh", "-euc", "test -c /mnt/null; echo foo > /mnt/null")
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, nil),
+		},
+		{
+			Description: "file onto a directory of the image",
+			// Docker fails later, from the OCI runtime
+			Require: require.Not(nerdtest.Docker),
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm",
+					"--mount", "type=bind,src="+data.Labels().Get("file")+",dst=/etc",
+					testutil.CommonImage, "true")
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New(`onto directory "/etc" of the image`)}, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestRunVolumeSubpathReadonly(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	// Docker fails later, from the OCI runtime, on a missing subpath
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("volume", "create", data.Identifier())
+		helpers.Ensure("run", "--rm", "-v", data.Identifier()+":/mnt", testutil.CommonImage,
+			"sh", "-euc", "mkdir /mnt/conf; echo hello-from-subpath > /mnt/conf/app.conf")
+		data.Labels().Set("volume", data.Identifier())
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("volume", "rm", "-f", data.Identifier())
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "an existing subpath is mounted read-only",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm",
+					"--mount", "type=volume,src="+data.Labels().Get("volume")+",dst=/data,subpath=conf,readonly",
+					testutil.CommonImage, "sh", "-euc", "cat /data/app.conf; if touch /data/foo; then exit 42; fi")
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Contains("hello-from-subpath")),
+		},
+		{
+			Description: "a missing subpath is refused for a read-only mount",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm",
+					"--mount", "type=volume,src="+data.Labels().Get("volume")+",dst=/data,subpath=missing,readonly",
+					testutil.CommonImage, "true")
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("does not exist in volume")}, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestRunBindMountGlobSource(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		return helpers.Command("run", "--rm", "--mount", "type=bind,src=/etc/*.conf,dst=/mnt", testutil.CommonImage, "true")
+	}
+
+	testCase.Expected = test.Expects(1, []error{errors.New("must be a concrete path")}, nil)
+
+	testCase.Run(t)
+}
+
+func TestRunMountImage(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("pull", "--quiet", testutil.CommonImage)
+		digestRef := strings.TrimSpace(helpers.Capture("image", "inspect", "--format", "{{index .RepoDigests 0}}", testutil.CommonImage))
+		assert.Assert(helpers.T(), strings.Contains(digestRef, "@sha256:"), digestRef)
+		data.Labels().Set("digestRef", digestRef)
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "the rootfs of the image is mounted",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "--mount", "type=image,src="+testutil.CommonImage+",dst=/mnt",
+					testutil.CommonImage, "cat", "/mnt/etc/alpine-release")
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, func(stdout string, t tig.T) {
+				assert.Assert(t, strings.TrimSpace(stdout) != "")
+			}),
+		},
+		{
+			Description: "a subpath of an image pinned by digest is mounted",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "--mount", "type=image,src="+data.Labels().Get("digestRef")+",dst=/mnt,subpath=/bin",
+					testutil.CommonImage, "sh", "-euc", "test -x /mnt/busybox; test ! -e /mnt/etc")
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, nil),
+		},
+		{
+			Description: "the mount is read-only",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "--mount", "type=image,src="+testutil.CommonImage+",dst=/mnt",
+					testutil.CommonImage, "touch", "/mnt/foo")
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("Read-only file system")}, nil),
+		},
+		{
+			Description: "the mount is pinned to the digest of the image, and kept on restart",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("run", "-d", "--name", data.Identifier(), "--mount", "type=image,src="+testutil.CommonImage+",dst=/mnt,subpath=/etc",
+					testutil.CommonImage, "sleep", nerdtest.Infinity)
+				nerdtest.EnsureContainerStarted(helpers, data.Identifier())
+				helpers.Ensure("restart", data.Identifier())
+				nerdtest.EnsureContainerStarted(helpers, data.Identifier())
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("exec", data.Identifier(), "cat", "/mnt/alpine-release")
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: func(stdout string, t tig.T) {
+						assert.Assert(t, strings.TrimSpace(stdout) != "")
+						mounts := nerdtest.InspectContainer(helpers, data.Identifier()).Mounts
+						assert.Equal(t, len(mounts), 1)
+						assert.Equal(t, mounts[0].Type, "image")
+						assert.Assert(t, strings.Contains(mounts[0].Name, "@sha256:"), mounts[0].Name)
+						assert.Equal(t, mounts[0].RW, false)
+					},
+				}
+			},
+		},
+		{
+			Description: "a subpath missing in the image is rejected",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "--mount", "type=image,src="+testutil.CommonImage+",dst=/mnt,subpath=/no-such-dir",
+					testutil.CommonImage, "true")
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("does not exist in image")}, nil),
+		},
+		{
+			Description: "a subpath escaping the rootfs is rejected",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "--mount", "type=image,src="+testutil.CommonImage+",dst=/mnt,subpath=../etc",
+					testutil.CommonImage, "true")
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("must not escape the rootfs")}, nil),
+		},
+		{
+			Description: "rw is rejected",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "--mount", "type=image,src="+testutil.CommonImage+",dst=/mnt,rw",
+					testutil.CommonImage, "true")
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("always read-only")}, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
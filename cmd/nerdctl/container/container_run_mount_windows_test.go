@@ -19,6 +19,7 @@ package container
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"gotest.tools/v3/assert"
@@ -204,6 +205,32 @@ func TestRunMountNamedPipeVolume(t *testing.T) {
 	base.Cmd("run", "--rm", "-v", `\\.\pipe\containerd-containerd`, testutil.CommonImage).AssertFail()
 }
 
+func TestRunMountNamedPipeMountFlag(t *testing.T) {
+	t.Parallel()
+	base := testutil.NewBase(t)
+	testContainer := testutil.Identifier(t)
+	defer base.Cmd("rm", "-f", testContainer).Run()
+
+	base.Cmd("run", "-d", "--name", testContainer,
+		"--mount", `type=npipe,source=\\.\pipe\containerd-containerd,target=\\.\pipe\containerd-containerd`,
+		testutil.CommonImage,
+	).AssertOK()
+
+	inspect := base.InspectContainer(testContainer)
+	found := false
+	for _, m := range inspect.Mounts {
+		if m.Destination == `\\.\pipe\containerd-containerd` {
+			found = true
+			assert.Equal(base.T, m.Type, "npipe")
+			assert.Equal(base.T, m.Source, `\\.\pipe\containerd-containerd`)
+		}
+	}
+	assert.Assert(base.T, found, "expected the named pipe to be mounted")
+
+	// The source and the target of type=npipe must be named pipes
+	base.Cmd("run", "--rm", "--mount", `type=npipe,source=C:\mnt,target=\\.\pipe\containerd-containerd`, testutil.CommonImage).AssertFail()
+}
+
 func TestRunMountVolumeSpec(t *testing.T) {
 	t.Parallel()
 	base := testutil.NewBase(t)
@@ -213,3 +240,30 @@ func TestRunMountVolumeSpec(t *testing.T) {
 	// If -v is an empty string, it will be ignored
 	base.Cmd("run", "--rm", "-v", "", testutil.CommonImage).AssertOK()
 }
+
+func TestRunMountBindDriveLetter(t *testing.T) {
+	t.Parallel()
+	base := testutil.NewBase(t)
+	srcDir := t.TempDir()
+	assert.NilError(t, os.WriteFile(filepath.Join(srcDir, "file1"), []byte("str1"), 0o644))
+
+	// The source and the target both contain the ":" of the drive letter
+	base.Cmd("run", "--rm",
+		"--mount", fmt.Sprintf("type=bind,source=%s,target=C:\\mnt1,readonly", srcDir),
+		testutil.CommonImage,
+		"cmd", "/c", "type", "C:\\mnt1\\file1",
+	).AssertOutContains("str1")
+
+	// Forward slashes are accepted as well
+	base.Cmd("run", "--rm",
+		"--mount", fmt.Sprintf("type=bind,src=%s,dst=C:/mnt1", filepath.ToSlash(srcDir)),
+		testutil.CommonImage,
+		"cmd", "/c", "type", "C:\\mnt1\\file1",
+	).AssertOutContains("str1")
+
+	// The root of a drive cannot be a mount target
+	base.Cmd("run", "--rm",
+		"--mount", fmt.Sprintf("type=bind,src=%s,dst=C:\\", srcDir),
+		testutil.CommonImage,
+	).AssertFail()
+}
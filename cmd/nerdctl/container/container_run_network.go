@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"slices"
 
 	"github.com/spf13/cobra"
 
@@ -31,6 +32,13 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/strutil"
 )
 
+const (
+	// minNetworkMTU is the smallest MTU accepted by the Linux kernel for IPv4 interfaces.
+	minNetworkMTU = 68
+	// maxNetworkMTU is the largest MTU that fits in the 16-bit IPv4 total length field.
+	maxNetworkMTU = 65535
+)
+
 func loadNetworkFlags(cmd *cobra.Command, globalOpts types.GlobalCommandOptions) (types.NetworkOptions, error) {
 	netOpts := types.NetworkOptions{}
 
@@ -75,6 +83,16 @@ func loadNetworkFlags(cmd *cobra.Command, globalOpts types.GlobalCommandOptions)
 	}
 	netOpts.MACAddress = macAddress
 
+	// --network-mtu=<MTU>
+	mtu, err := cmd.Flags().GetInt("network-mtu")
+	if err != nil {
+		return netOpts, err
+	}
+	if mtu != 0 && (mtu < minNetworkMTU || mtu > maxNetworkMTU) {
+		return netOpts, fmt.Errorf("invalid --network-mtu %d: must be between %d and %d", mtu, minNetworkMTU, maxNetworkMTU)
+	}
+	netOpts.MTU = mtu
+
 	// --ip=<container static IP>
 	ipAddress, err := cmd.Flags().GetString("ip")
 	if err != nil {
@@ -103,6 +121,17 @@ func loadNetworkFlags(cmd *cobra.Command, globalOpts types.GlobalCommandOptions)
 	}
 	netOpts.Domainname = domainname
 
+	// --no-resolv, --no-hosts
+	noResolv, err := cmd.Flags().GetBool("no-resolv")
+	if err != nil {
+		return netOpts, err
+	}
+	noHosts, err := cmd.Flags().GetBool("no-hosts")
+	if err != nil {
+		return netOpts, err
+	}
+	netOpts.NoHosts = noHosts
+
 	// --dns=<DNS host> ...
 	// Use command flags if set, otherwise use global config is set
 	var dnsSlice []string
@@ -115,15 +144,26 @@ func loadNetworkFlags(cmd *cobra.Command, globalOpts types.GlobalCommandOptions)
 		if len(dnsSlice) == 0 {
 			return netOpts, errors.New("--dns flag was specified but no DNS server was provided")
 		}
+		// --dns=none is an alias of --no-resolv
+		if slices.Contains(dnsSlice, "none") {
+			if len(dnsSlice) > 1 {
+				return netOpts, errors.New("--dns=none cannot be combined with other DNS servers")
+			}
+			noResolv = true
+			dnsSlice = nil
+		} else if noResolv {
+			return netOpts, errors.New("--dns cannot be combined with --no-resolv")
+		}
 		for _, dns := range dnsSlice {
 			if _, err := dnsutil.ValidateIPAddress(dns); err != nil {
 				return netOpts, fmt.Errorf("%w with --dns flag", err)
 			}
 		}
-	} else {
+	} else if !noResolv {
 		dnsSlice = globalOpts.DNS
 	}
 	netOpts.DNSServers = strutil.DedupeStrSlice(dnsSlice)
+	netOpts.NoResolv = noResolv
 
 	// --dns-search=<domain name> ...
 	// Use command flags if set, otherwise use global config is set
@@ -134,7 +174,10 @@ func loadNetworkFlags(cmd *cobra.Command, globalOpts types.GlobalCommandOptions)
 		if err != nil {
 			return netOpts, err
 		}
-	} else {
+		if noResolv {
+			return netOpts, errors.New("--dns-search cannot be combined with --no-resolv or --dns=none")
+		}
+	} else if !noResolv {
 		dnsSearchSlice = globalOpts.DNSSearch
 	}
 	netOpts.DNSSearchDomains = strutil.DedupeStrSlice(dnsSearchSlice)
@@ -160,7 +203,10 @@ func loadNetworkFlags(cmd *cobra.Command, globalOpts types.GlobalCommandOptions)
 			return netOpts, err
 		}
 		dnsOptions = append(dnsOptions, dnsOptionFlags...)
-	} else {
+		if noResolv {
+			return netOpts, errors.New("--dns-option cannot be combined with --no-resolv or --dns=none")
+		}
+	} else if !noResolv {
 		// Use global config defaults
 		dnsOptions = append(dnsOptions, globalOpts.DNSOpts...)
 	}
@@ -172,6 +218,9 @@ func loadNetworkFlags(cmd *cobra.Command, globalOpts types.GlobalCommandOptions)
 	if err != nil {
 		return netOpts, err
 	}
+	if noHosts && len(addHostFlags) > 0 {
+		return netOpts, errors.New("--add-host cannot be combined with --no-hosts")
+	}
 	netOpts.AddHost = addHostFlags
 
 	// --uts=<Unix Time Sharing namespace>
@@ -197,5 +246,19 @@ func loadNetworkFlags(cmd *cobra.Command, globalOpts types.GlobalCommandOptions)
 	}
 	netOpts.PortMappings = portMappings
 
+	// -P/--publish-all
+	publishAll, err := cmd.Flags().GetBool("publish-all")
+	if err != nil {
+		return netOpts, err
+	}
+	netOpts.PublishAll = publishAll
+
+	// --expose=80,8000-8010/udp ...
+	expose, err := cmd.Flags().GetStringSlice("expose")
+	if err != nil {
+		return netOpts, err
+	}
+	netOpts.Expose = strutil.DedupeStrSlice(expose)
+
 	return netOpts, nil
 }
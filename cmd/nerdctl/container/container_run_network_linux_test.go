@@ -17,6 +17,7 @@
 package container
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -547,6 +548,87 @@ func TestRunDNS(t *testing.T) {
 	cmd.AssertOutContains("options attempts:10\n")
 }
 
+func TestRunDNSNetworkHost(t *testing.T) {
+	base := testutil.NewBase(t)
+
+	cmd := base.Cmd("run", "--rm", "--network", "host",
+		"--dns", "8.8.8.8", "--dns-search", "test", "--dns-option", "attempts:10", testutil.CommonImage,
+		"cat", "/etc/resolv.conf")
+	cmd.AssertOutContains("nameserver 8.8.8.8\n")
+	cmd.AssertOutContains("search test\n")
+	cmd.AssertOutContains("options attempts:10\n")
+
+	// The DNS settings are stored in the container
+	testContainer := testutil.Identifier(t)
+	defer base.Cmd("rm", "-f", testContainer).Run()
+	base.Cmd("create", "--name", testContainer, "--network", "host", "--dns", "8.8.8.8", testutil.CommonImage).AssertOK()
+	inspect := base.InspectContainer(testContainer)
+	assert.DeepEqual(t, inspect.HostConfig.DNS, []string{"8.8.8.8"})
+}
+
+func TestRunNoResolvNoHosts(t *testing.T) {
+	nerdtest.Setup()
+
+	testCase := &test.Case{
+		SubTests: []*test.Case{
+			{
+				Description: "--no-resolv and --no-hosts leave the files of the image",
+				Command: test.Command("run", "--rm", "--no-resolv", "--no-hosts", testutil.CommonImage,
+					"cat", "/proc/self/mountinfo"),
+				Expected: test.Expects(expect.ExitCodeSuccess, nil,
+					expect.DoesNotContain(" /etc/resolv.conf ", " /etc/hosts ")),
+			},
+			{
+				Description: "--dns=none is the same as --no-resolv",
+				Require:     require.Not(nerdtest.Docker),
+				Command: test.Command("run", "--rm", "--network", "host", "--dns", "none", testutil.CommonImage,
+					"cat", "/proc/self/mountinfo"),
+				Expected: test.Expects(expect.ExitCodeSuccess, nil,
+					expect.All(
+						expect.DoesNotContain(" /etc/resolv.conf "),
+						expect.Contains(" /etc/hosts "),
+					)),
+			},
+			{
+				Description: "the toggles are preserved on restart",
+				Require:     require.Not(nerdtest.Docker),
+				NoParallel:  true,
+				Setup: func(data test.Data, helpers test.Helpers) {
+					helpers.Ensure("create", "--name", data.Identifier(), "--no-resolv", "--no-hosts",
+						testutil.CommonImage, "cat", "/proc/self/mountinfo")
+					helpers.Ensure("start", data.Identifier())
+				},
+				Cleanup: func(data test.Data, helpers test.Helpers) {
+					helpers.Anyhow("rm", "-f", data.Identifier())
+				},
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("start", "--attach", data.Identifier())
+				},
+				Expected: test.Expects(expect.ExitCodeSuccess, nil,
+					expect.DoesNotContain(" /etc/resolv.conf ", " /etc/hosts ")),
+			},
+			{
+				Description: "--add-host cannot be combined with --no-hosts",
+				Require:     require.Not(nerdtest.Docker),
+				Command: test.Command("run", "--rm", "--no-hosts", "--add-host", "foo:1.2.3.4", testutil.CommonImage,
+					"true"),
+				Expected: test.Expects(expect.ExitCodeGenericFail,
+					[]error{errors.New("--add-host cannot be combined with --no-hosts")}, nil),
+			},
+			{
+				Description: "--dns cannot be combined with --no-resolv",
+				Require:     require.Not(nerdtest.Docker),
+				Command: test.Command("run", "--rm", "--no-resolv", "--dns", "8.8.8.8", testutil.CommonImage,
+					"true"),
+				Expected: test.Expects(expect.ExitCodeGenericFail,
+					[]error{errors.New("--dns cannot be combined with --no-resolv")}, nil),
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
+
 func TestRunNetworkHostHostname(t *testing.T) {
 	base := testutil.NewBase(t)
 
@@ -932,6 +1014,90 @@ func TestNoneNetworkHostName(t *testing.T) {
 	testCase.Run(t)
 }
 
+func TestRunDefaultHostnameResolves(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.Not(require.Windows)
+
+	// The default hostname is the short container ID, it matches the hostname reported by inspect
+	// and resolves through /etc/hosts, with or without a network
+	hostnameTest := func(network string) *test.Case {
+		return &test.Case{
+			Description: network,
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+			},
+			Setup: func(data test.Data, helpers test.Helpers) {
+				id := strings.TrimSpace(helpers.Capture("run", "-d", "--name", data.Identifier(), "--network", network,
+					testutil.CommonImage, "sleep", nerdtest.Infinity))
+				assert.Assert(helpers.T(), len(id) > 12, id)
+				data.Labels().Set("hostname", id[:12])
+				nerdtest.EnsureContainerStarted(helpers, data.Identifier())
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("exec", data.Identifier(), "sh", "-euc",
+					`hostname; grep -qw "$(hostname)" /etc/hosts; ping -c 1 -W 1 "$(hostname)" >/dev/null`)
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.All(
+						expect.Equals(data.Labels().Get("hostname")+"\n"),
+						func(stdout string, t tig.T) {
+							container := nerdtest.InspectContainer(helpers, data.Identifier())
+							assert.Equal(t, container.Config.Hostname, data.Labels().Get("hostname"))
+						},
+					),
+				}
+			},
+		}
+	}
+
+	testCase.SubTests = []*test.Case{
+		hostnameTest("bridge"),
+		hostnameTest("none"),
+	}
+
+	testCase.Run(t)
+}
+
+func TestRunDomainnameHostsAndSearch(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.Not(require.Windows)
+
+	// --hostname and --domainname make up the FQDN of the container, resolved through /etc/hosts
+	fqdnTest := func(network string) *test.Case {
+		return &test.Case{
+			Description: "fqdn with network " + network,
+			Command: test.Command("run", "--rm", "--network", network, "--hostname", "foo", "--domainname", "example.com",
+				testutil.CommonImage, "sh", "-euc", "hostname; hostname -f; grep -w foo.example.com /etc/hosts >/dev/null"),
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Equals("foo\nfoo.example.com\n")),
+		}
+	}
+
+	testCase.SubTests = []*test.Case{
+		fqdnTest("bridge"),
+		fqdnTest("none"),
+		{
+			Description: "domain in resolv.conf search",
+			// Docker does not add the domain to the search domains
+			Require: require.Not(nerdtest.Docker),
+			Command: test.Command("run", "--rm", "--domainname", "example.com",
+				testutil.CommonImage, "grep", "^search", "/etc/resolv.conf"),
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Match(regexp.MustCompile(`^search example\.com( |\n)`))),
+		},
+		{
+			Description: "explicit search domains are kept",
+			Require:     require.Not(nerdtest.Docker),
+			Command: test.Command("run", "--rm", "--domainname", "example.com", "--dns-search", "example.org",
+				testutil.CommonImage, "grep", "^search", "/etc/resolv.conf"),
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Equals("search example.org\n")),
+		},
+	}
+
+	testCase.Run(t)
+}
+
 func TestHostNetworkHostName(t *testing.T) {
 	nerdtest.Setup()
 	testCase := &test.Case{
@@ -1080,3 +1246,236 @@ dns_search = ["example.com", "test.local"]`
 	}
 	testCase.Run(t)
 }
+
+func TestRunNetworkMTU(t *testing.T) {
+	nerdtest.Setup()
+
+	testCase := &test.Case{
+		// --network-mtu is not supported by Docker
+		Require: require.Not(nerdtest.Docker),
+		SubTests: []*test.Case{
+			{
+				Description: "MTU is applied to the container interface",
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("run", "--rm", "--network-mtu", "1400",
+						testutil.CommonImage, "cat", "/sys/class/net/eth0/mtu")
+				},
+				Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Equals("1400\n")),
+			},
+			{
+				Description: "MTU is kept across restarts",
+				NoParallel:  true,
+				Setup: func(data test.Data, helpers test.Helpers) {
+					helpers.Ensure("run", "-d", "--name", data.Identifier(), "--network-mtu", "1280",
+						testutil.CommonImage, "sleep", nerdtest.Infinity)
+					helpers.Ensure("stop", data.Identifier())
+					helpers.Ensure("start", data.Identifier())
+				},
+				Cleanup: func(data test.Data, helpers test.Helpers) {
+					helpers.Anyhow("rm", "-f", data.Identifier())
+				},
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("exec", data.Identifier(), "cat", "/sys/class/net/eth0/mtu")
+				},
+				Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Equals("1280\n")),
+			},
+			{
+				Description: "MTU out of range is rejected",
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("run", "--rm", "--network-mtu", "10", testutil.CommonImage, "true")
+				},
+				Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("invalid --network-mtu")}, nil),
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestRunStaticIPReservation(t *testing.T) {
+	nerdtest.Setup()
+
+	testCase := &test.Case{
+		// Docker does not keep the address of a stopped container out of IPAM
+		Require:    require.Not(nerdtest.Docker),
+		NoParallel: true,
+		Setup: func(data test.Data, helpers test.Helpers) {
+			helpers.Ensure("network", "create", data.Identifier(), "--subnet", "10.78.0.0/24")
+			// The first address handed out by IPAM, reserved by a container that is never started
+			helpers.Ensure("create", "--name", data.Identifier("static"), "--network", data.Identifier(),
+				"--ip", "10.78.0.2", testutil.CommonImage, "sleep", nerdtest.Infinity)
+			data.Labels().Set("network", data.Identifier())
+			data.Labels().Set("static", data.Identifier("static"))
+			data.Labels().Set("reuse", data.Identifier("reuse"))
+		},
+		Cleanup: func(data test.Data, helpers test.Helpers) {
+			helpers.Anyhow("rm", "-f", data.Identifier("static"))
+			helpers.Anyhow("rm", "-f", data.Identifier("reuse"))
+			helpers.Anyhow("network", "rm", data.Identifier())
+		},
+		SubTests: []*test.Case{
+			{
+				Description: "a reserved address cannot be requested by another container",
+				NoParallel:  true,
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("create", "--network", data.Labels().Get("network"),
+						"--ip", "10.78.0.2", testutil.CommonImage)
+				},
+				Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("already reserved")}, nil),
+			},
+			{
+				Description: "a reserved address is not allocated dynamically",
+				NoParallel:  true,
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("run", "--rm", "--network", data.Labels().Get("network"),
+						testutil.CommonImage, "ip", "-4", "-o", "addr", "show", "eth0")
+				},
+				Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.All(
+					expect.Contains("10.78.0."),
+					expect.DoesNotContain("10.78.0.2/"),
+				)),
+			},
+			{
+				Description: "the reservation is released on removal",
+				NoParallel:  true,
+				Setup: func(data test.Data, helpers test.Helpers) {
+					helpers.Ensure("rm", data.Labels().Get("static"))
+				},
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("create", "--name", data.Labels().Get("reuse"), "--network", data.Labels().Get("network"),
+						"--ip", "10.78.0.2", testutil.CommonImage)
+				},
+				Expected: test.Expects(expect.ExitCodeSuccess, nil, nil),
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestRunPublishAll(t *testing.T) {
+	nerdtest.Setup()
+
+	dockerfile := fmt.Sprintf(`FROM %s
+EXPOSE 8080 9090/tcp 5353/udp
+`, testutil.CommonImage)
+
+	testCase := &test.Case{
+		Require: require.All(
+			nerdtest.Build,
+			// Automatic host port allocation is not supported in rootless mode
+			require.Not(nerdtest.Rootless),
+		),
+		Setup: func(data test.Data, helpers test.Helpers) {
+			data.Temp().Save(dockerfile, "Dockerfile")
+			data.Labels().Set("image", data.Identifier())
+			helpers.Ensure("build", "-t", data.Labels().Get("image"), data.Temp().Path())
+		},
+		Cleanup: func(data test.Data, helpers test.Helpers) {
+			helpers.Anyhow("rmi", "-f", data.Identifier())
+		},
+		SubTests: []*test.Case{
+			{
+				Description: "all the exposed ports are published to ephemeral host ports",
+				Setup: func(data test.Data, helpers test.Helpers) {
+					helpers.Ensure("run", "-d", "--name", data.Identifier(), "-P",
+						data.Labels().Get("image"), "sleep", nerdtest.Infinity)
+				},
+				Cleanup: func(data test.Data, helpers test.Helpers) {
+					helpers.Anyhow("rm", "-f", data.Identifier())
+				},
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("ps", "--filter", "name="+data.Identifier(), "--format", "{{.Ports}}")
+				},
+				Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+					return &test.Expected{
+						Output: expect.All(
+							expect.Contains("->8080/tcp", "->9090/tcp", "->5353/udp"),
+							func(stdout string, t tig.T) {
+								ports := *nerdtest.InspectContainer(helpers, data.Identifier()).NetworkSettings.Ports
+								assert.Equal(t, len(ports), 3, "expected 3 published ports")
+								for port, bindings := range ports {
+									assert.Equal(t, len(bindings), 1, "expected a single binding for %s", port)
+									assert.Assert(t, bindings[0].HostPort != "" && bindings[0].HostPort != "0",
+										"the host port of %s was not allocated", port)
+								}
+							},
+						),
+					}
+				},
+			},
+			{
+				Description: "explicitly published ports are kept",
+				Setup: func(data test.Data, helpers test.Helpers) {
+					helpers.Ensure("run", "-d", "--name", data.Identifier(), "-P", "-p", "127.0.0.1:18080:8080",
+						data.Labels().Get("image"), "sleep", nerdtest.Infinity)
+				},
+				Cleanup: func(data test.Data, helpers test.Helpers) {
+					helpers.Anyhow("rm", "-f", data.Identifier())
+				},
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("port", data.Identifier(), "8080/tcp")
+				},
+				Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Equals("127.0.0.1:18080\n")),
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestRunExpose(t *testing.T) {
+	nerdtest.Setup()
+
+	testCase := &test.Case{
+		SubTests: []*test.Case{
+			{
+				Description: "exposed ranges appear in Config.ExposedPorts",
+				Setup: func(data test.Data, helpers test.Helpers) {
+					helpers.Ensure("create", "--name", data.Identifier(), "--expose", "8000-8002/udp", "--expose", "9000",
+						testutil.CommonImage)
+				},
+				Cleanup: func(data test.Data, helpers test.Helpers) {
+					helpers.Anyhow("rm", "-f", data.Identifier())
+				},
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("inspect", "--format", "{{json .Config.ExposedPorts}}", data.Identifier())
+				},
+				Expected: test.Expects(expect.ExitCodeSuccess, nil,
+					expect.Contains(`"8000/udp":{}`, `"8001/udp":{}`, `"8002/udp":{}`, `"9000/tcp":{}`)),
+			},
+			{
+				Description: "exposed ports are not published without -P",
+				Setup: func(data test.Data, helpers test.Helpers) {
+					helpers.Ensure("run", "-d", "--name", data.Identifier(), "--expose", "9000",
+						testutil.CommonImage, "sleep", nerdtest.Infinity)
+				},
+				Cleanup: func(data test.Data, helpers test.Helpers) {
+					helpers.Anyhow("rm", "-f", data.Identifier())
+				},
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("port", data.Identifier())
+				},
+				Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Equals("")),
+			},
+			{
+				Description: "exposed ports are published with -P",
+				// Automatic host port allocation is not supported in rootless mode
+				Require: require.Not(nerdtest.Rootless),
+				Setup: func(data test.Data, helpers test.Helpers) {
+					helpers.Ensure("run", "-d", "--name", data.Identifier(), "--expose", "9000-9001", "-P",
+						testutil.CommonImage, "sleep", nerdtest.Infinity)
+				},
+				Cleanup: func(data test.Data, helpers test.Helpers) {
+					helpers.Anyhow("rm", "-f", data.Identifier())
+				},
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("port", data.Identifier())
+				},
+				Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Contains("9000/tcp -> ", "9001/tcp -> ")),
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
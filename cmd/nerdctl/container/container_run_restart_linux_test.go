@@ -17,6 +17,7 @@
 package container
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os/exec"
@@ -27,6 +28,10 @@ import (
 	"gotest.tools/v3/assert"
 	"gotest.tools/v3/poll"
 
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+
 	"github.com/containerd/nerdctl/v2/pkg/testutil"
 	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
 	"github.com/containerd/nerdctl/v2/pkg/testutil/nettestutil"
@@ -112,6 +117,83 @@ func TestRunRestartWithOnFailure(t *testing.T) {
 	assert.Equal(t, inspect.RestartCount, 2)
 }
 
+func TestRunRestartWithOnFailureExitCodes(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.All(
+		// Restart exit codes are a nerdctl extension
+		require.Not(nerdtest.Docker),
+		nerdtest.ContainerdPlugin("io.containerd.internal.v1", "restart", "on-failure"),
+	)
+
+	// waitFor polls the container until cond returns true. The restart monitor of containerd
+	// reconciles the containers every 10 seconds, so a few restarts take a while.
+	waitFor := func(helpers test.Helpers, description string, cond func() bool) {
+		helpers.T().Helper()
+		deadline := time.Now().Add(60 * time.Second)
+		for !cond() {
+			if time.Now().After(deadline) {
+				helpers.T().Log(fmt.Sprintf("timed out waiting for %s", description))
+				helpers.T().FailNow()
+			}
+			time.Sleep(time.Second)
+		}
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "restarted on a listed exit code",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("run", "-d", "--restart=on-failure:2:codes=3,4", "--name", data.Identifier(),
+					testutil.AlpineImage, "sh", "-c", "exit 3")
+				waitFor(helpers, "the container to be restarted twice", func() bool {
+					inspect := nerdtest.InspectContainer(helpers, data.Identifier())
+					return inspect.RestartCount == 2 && inspect.State.Status == "exited"
+				})
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("inspect", "--format", "{{.State.ExitCode}}", data.Identifier())
+			},
+			Expected: test.Expects(0, nil, expect.Equals("3\n")),
+		},
+		{
+			Description: "not restarted on another exit code",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("run", "-d", "--restart=on-failure:2:codes=3,4", "--name", data.Identifier(),
+					testutil.AlpineImage, "sh", "-c", "exit 1")
+				// The desired status is set to stopped as soon as the container exits, so that the monitor does not restart it
+				waitFor(helpers, "the restart policy to be disabled", func() bool {
+					status := helpers.Capture("container", "inspect", "--mode=native",
+						"--format", "{{index .Labels \"containerd.io/restart.status\"}}", data.Identifier())
+					return strings.TrimSpace(status) == "stopped"
+				})
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("inspect", "--format", "{{.RestartCount}} {{.State.Status}}", data.Identifier())
+			},
+			Expected: test.Expects(0, nil, expect.Equals("0 exited\n")),
+		},
+		{
+			Description: "exit codes require the on-failure policy",
+			Command:     test.Command("run", "--restart=always:codes=1", testutil.AlpineImage, "true"),
+			Expected:    test.Expects(expect.ExitCodeGenericFail, []error{errors.New("only supported by the \"on-failure\" policy")}, nil),
+		},
+		{
+			Description: "exit code 0 is rejected",
+			Command:     test.Command("run", "--restart=on-failure:codes=0", testutil.AlpineImage, "true"),
+			Expected:    test.Expects(expect.ExitCodeGenericFail, []error{errors.New("must be between 1 and 255")}, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
+
 func TestRunRestartWithUnlessStopped(t *testing.T) {
 	base := testutil.NewBase(t)
 	if !nerdtest.IsDocker() {
@@ -136,6 +218,30 @@ func TestRunRestartWithUnlessStopped(t *testing.T) {
 	assert.Equal(t, inspect.RestartCount, 2)
 }
 
+func TestRunRestartAlwaysStop(t *testing.T) {
+	if testing.Short() {
+		t.Skipf("test is long")
+	}
+	base := testutil.NewBase(t)
+	tID := testutil.Identifier(t)
+	defer base.Cmd("rm", "-f", tID).Run()
+	base.Cmd("run", "-d", "--restart=always", "--stop-signal", "SIGINT", "--name", tID,
+		testutil.AlpineImage, "sh", "-c", "trap 'exit 0' INT; while true; do sleep 1; done").AssertOK()
+	base.Cmd("stop", tID).AssertOK()
+
+	// The restart monitor of containerd polls every 10 seconds by default.
+	for i := 0; i < 15; i++ {
+		inspect := base.InspectContainer(tID)
+		assert.Equal(t, inspect.State.Status, "exited", "the container was restarted after %d seconds", i)
+		assert.Equal(t, inspect.State.ExitCode, 0)
+		time.Sleep(time.Second)
+	}
+	assert.Equal(t, base.InspectContainer(tID).RestartCount, 0)
+
+	base.Cmd("start", tID).AssertOK()
+	assert.Equal(t, base.InspectContainer(tID).State.Status, "running")
+}
+
 func TestUpdateRestartPolicy(t *testing.T) {
 	base := testutil.NewBase(t)
 	if !nerdtest.IsDocker() {
@@ -183,3 +289,62 @@ func TestAddRestartPolicy(t *testing.T) {
 	inspect = base.InspectContainer(tID)
 	assert.Equal(t, inspect.RestartCount, 1)
 }
+
+func TestRunRestartWithRm(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "run",
+			Command:     test.Command("run", "--rm", "--restart=always", testutil.CommonImage, "true"),
+			Expected:    test.Expects(expect.ExitCodeGenericFail, []error{errors.New("flags --restart and --rm cannot be specified together")}, nil),
+		},
+		{
+			Description: "create",
+			Command:     test.Command("create", "--rm", "--restart=always", testutil.CommonImage, "true"),
+			Expected:    test.Expects(expect.ExitCodeGenericFail, []error{errors.New("flags --restart and --rm cannot be specified together")}, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestRunRestartMinUptime(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "requires a restart policy",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "--restart-min-uptime=10s", testutil.CommonImage, "true")
+			},
+			Expected: test.Expects(1, []error{errors.New("requires a restart policy")}, nil),
+		},
+		{
+			Description: "rejects negative values",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "--restart=always", "--restart-min-uptime=-1s", testutil.CommonImage, "true")
+			},
+			Expected: test.Expects(1, []error{errors.New("must not be negative")}, nil),
+		},
+		{
+			Description: "is stored in the container labels",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("create", "--name", data.Identifier(), "--restart=always", "--restart-min-uptime=30s", testutil.CommonImage, "true")
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("inspect", "--format", "{{index .Config.Labels \"nerdctl/restart-min-uptime\"}}", data.Identifier())
+			},
+			Expected: test.Expects(0, nil, expect.Equals("30s\n")),
+		},
+	}
+
+	testCase.Run(t)
+}
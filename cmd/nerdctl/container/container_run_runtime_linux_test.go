@@ -17,9 +17,15 @@
 package container
 
 import (
+	"errors"
 	"testing"
 
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+
 	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
 )
 
 func TestRunSysctl(t *testing.T) {
@@ -27,3 +33,27 @@ func TestRunSysctl(t *testing.T) {
 	base := testutil.NewBase(t)
 	base.Cmd("run", "--rm", "--sysctl", "net.ipv4.ip_forward=1", testutil.AlpineImage, "cat", "/proc/sys/net/ipv4/ip_forward").AssertOutExactly("1\n")
 }
+
+func TestRunSysctlValidation(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	// The error messages are specific to nerdctl
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "net.* sysctls are refused with --network=host",
+			Command: test.Command("run", "--rm", "--network=host", "--sysctl", "net.ipv4.ip_forward=1",
+				testutil.AlpineImage, "true"),
+			Expected: test.Expects(expect.ExitCodeGenericFail,
+				[]error{errors.New("would modify the network parameters of the host kernel")}, nil),
+		},
+		{
+			Description: "sysctls that are not namespaced are refused",
+			Command:     test.Command("run", "--rm", "--sysctl", "vm.swappiness=10", testutil.AlpineImage, "true"),
+			Expected:    test.Expects(expect.ExitCodeGenericFail, []error{errors.New("is not namespaced")}, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
@@ -82,6 +82,20 @@ CMD ["echo", "bar"]
 				},
 				Expected: test.Expects(expect.ExitCodeGenericFail, nil, nil),
 			},
+			{
+				Description: "Run image echo entrypoint clears image CMD",
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("run", "--rm", "--entrypoint", "echo", data.Labels().Get("image"))
+				},
+				Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Equals("\n")),
+			},
+			{
+				Description: "Run image echo entrypoint custom command",
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("run", "--rm", "--entrypoint", "echo", data.Labels().Get("image"), "blah")
+				},
+				Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Equals("blah\n")),
+			},
 			{
 				Description: "Run image empty entrypoint custom command",
 				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
@@ -187,6 +201,134 @@ func TestRunCIDFile(t *testing.T) {
 	testCase.Run(t)
 }
 
+func TestRunAnnotationReserved(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "nerdctl prefix",
+			Command:     test.Command("run", "--rm", "--annotation", "nerdctl/name=foo", testutil.CommonImage, "true"),
+			Expected:    test.Expects(expect.ExitCodeGenericFail, []error{errors.New("is reserved for internal use")}, nil),
+		},
+		{
+			Description: "compose prefix",
+			Command:     test.Command("run", "--rm", "--annotation", "com.docker.compose.project=foo", testutil.CommonImage, "true"),
+			Expected:    test.Expects(expect.ExitCodeGenericFail, []error{errors.New("is reserved for compose")}, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestRunLabelSizeLimit(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	large := strings.Repeat("x", 5000)
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "label",
+			Command:     test.Command("run", "--rm", "--label", "foo="+large, testutil.CommonImage, "true"),
+			Expected:    test.Expects(expect.ExitCodeGenericFail, []error{errors.New(`label "foo" is too large`)}, nil),
+		},
+		{
+			Description: "label within a raised limit",
+			Command:     test.Command("--max-label-size=8192", "run", "--rm", "--label", "foo="+large, testutil.CommonImage, "true"),
+			Expected:    test.Expects(expect.ExitCodeSuccess, nil, nil),
+		},
+		{
+			Description: "annotations are not limited",
+			Command:     test.Command("run", "--rm", "--annotation", "foo="+large, testutil.CommonImage, "true"),
+			Expected:    test.Expects(expect.ExitCodeSuccess, nil, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestRunProvenanceAnnotations(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	const format = `{{index .Spec.Annotations "nerdctl/created-by-version"}},{{index .Spec.Annotations "nerdctl/created-at"}}`
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "enabled",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("--provenance-annotations", "create", "--name", data.Identifier(), testutil.CommonImage, "true")
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("container", "inspect", "--mode=native", "--format", format, data.Identifier())
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, func(stdout string, t tig.T) {
+				createdByVersion, createdAt, _ := strings.Cut(strings.TrimSpace(stdout), ",")
+				assert.Assert(t, createdByVersion != "", "expected the nerdctl version, got %q", stdout)
+				_, err := time.Parse(time.RFC3339, createdAt)
+				assert.NilError(t, err, "expected an RFC 3339 creation time, got %q", stdout)
+			}),
+		},
+		{
+			Description: "disabled by default",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("create", "--name", data.Identifier(), testutil.CommonImage, "true")
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("container", "inspect", "--mode=native", "--format", format, data.Identifier())
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Equals(",\n")),
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestRunPullDigestPinned(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	// Docker contacts the registry with --pull=always
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		// The registry of the tag does not resolve, so that any pull attempt fails
+		tagged := "nerdctl-test.invalid/" + data.Identifier() + ":latest"
+		helpers.Ensure("pull", "--quiet", testutil.CommonImage)
+		helpers.Ensure("tag", testutil.CommonImage, tagged)
+		digest := strings.TrimSpace(helpers.Capture("image", "inspect", "--mode=native", "--format", "{{.Image.Target.Digest}}", tagged))
+		data.Labels().Set("tagged", tagged)
+		data.Labels().Set("pinned", "nerdctl-test.invalid/"+data.Identifier()+"@"+digest)
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rmi", "-f", data.Labels().Get("pinned"))
+		helpers.Anyhow("rmi", "-f", data.Labels().Get("tagged"))
+	}
+
+	testCase.SubTests = []*test.Case{}
+	for _, pull := range []string{"missing", "always", "never"} {
+		testCase.SubTests = append(testCase.SubTests, &test.Case{
+			Description: "--pull=" + pull,
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "--pull="+pull, data.Labels().Get("pinned"), "echo", "pinned")
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Equals("pinned\n")),
+		})
+	}
+
+	testCase.Run(t)
+}
+
 func TestRunEnvFile(t *testing.T) {
 	testCase := nerdtest.Setup()
 
@@ -266,6 +408,71 @@ func TestRunEnv(t *testing.T) {
 	testCase.Run(t)
 }
 
+func TestRunEnvPrecedence(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.Not(require.Windows)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		data.Temp().Save("FOO=env-file\nBAR=env-file-1\nBAR=env-file-2\nPATH=/env-file:/usr/sbin:/usr/bin:/sbin:/bin", "env-file")
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "--env overrides --env-file, which overrides the image",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm",
+					"--env-file", data.Temp().Path("env-file"),
+					"--env", "FOO=env-1",
+					"--env", "FOO=env-2",
+					testutil.CommonImage, "env")
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.All(
+				expect.Contains(
+					"\nFOO=env-2\n",
+					"\nBAR=env-file-2\n",
+					"\nPATH=/env-file:/usr/sbin:/usr/bin:/sbin:/bin\n",
+				),
+				expect.DoesNotContain("env-1", "env-file-1", "FOO=env-file"),
+			)),
+		},
+		{
+			Description: "the environment is ordered as the image, --env-file, then --env",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm",
+					"--env", "ZZZ=env",
+					"--env-file", data.Temp().Path("env-file"),
+					"--env", "AAA=env",
+					testutil.CommonImage, "env")
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, func(stdout string, t tig.T) {
+				// PATH is set by the image, and keeps its position when overridden by --env-file
+				ordered := []string{"\nPATH=/env-file:", "\nFOO=env-file\n", "\nBAR=env-file-2\n", "\nZZZ=env\n", "\nAAA=env\n"}
+				// env does not print a newline before the first variable
+				stdout = "\n" + stdout
+				last := -1
+				for i, e := range ordered {
+					idx := strings.Index(stdout, e)
+					assert.Assert(t, idx > last, "expected %q to be present, after the variables %q", e, ordered[:i])
+					last = idx
+				}
+			}),
+		},
+		{
+			Description: "--env can override HOSTNAME",
+			Command:     test.Command("run", "--rm", "--env", "HOSTNAME=foo", testutil.CommonImage, "env"),
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.All(
+				expect.Contains("\nHOSTNAME=foo\n"),
+				func(stdout string, t tig.T) {
+					assert.Equal(t, strings.Count(stdout, "HOSTNAME="), 1)
+				},
+			)),
+		},
+	}
+
+	testCase.Run(t)
+}
+
 func TestRunHostnameEnv(t *testing.T) {
 	testCase := nerdtest.Setup()
 
@@ -390,8 +597,10 @@ func TestRunWithJournaldLogDriver(t *testing.T) {
 	containerName := testutil.Identifier(t)
 
 	defer base.Cmd("rm", "-f", containerName).AssertOK()
-	base.Cmd("run", "-d", "--log-driver", "journald", "--name", containerName, testutil.CommonImage,
-		"sh", "-euxc", "echo foo; echo bar").AssertOK()
+	base.Cmd("run", "-d", "--log-driver", "journald", "--name", containerName,
+		"--label", "com.example.team=infra", "--log-opt", "labels=com.example.team",
+		"-e", "APP_VERSION=1.2", "--log-opt", "env=APP_VERSION,UNSET_VAR",
+		testutil.CommonImage, "sh", "-euxc", "echo foo; echo bar").AssertOK()
 
 	time.Sleep(3 * time.Second)
 
@@ -414,6 +623,18 @@ func TestRunWithJournaldLogDriver(t *testing.T) {
 			name:   "filter journald logs using IMAGE_NAME field",
 			filter: fmt.Sprintf("IMAGE_NAME=%s", testutil.CommonImage),
 		},
+		{
+			name:   "filter journald logs using CONTAINER_ID field",
+			filter: fmt.Sprintf("CONTAINER_ID=%s", inspectedContainer.ID[:12]),
+		},
+		{
+			name:   "filter journald logs using the field of the labels log-opt",
+			filter: "COM_EXAMPLE_TEAM=infra",
+		},
+		{
+			name:   "filter journald logs using the field of the env log-opt",
+			filter: "APP_VERSION=1.2",
+		},
 	}
 	for _, tc := range testCases {
 		tc := tc
@@ -989,6 +1210,59 @@ func TestRunHealthcheckFlags(t *testing.T) {
 	testCase.Run(t)
 }
 
+func TestRunHealthcheckMinInterval(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	// The minimum interval is specific to nerdctl
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "interval below the minimum is clamped",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "-d", "--name", data.Identifier(),
+					"--health-cmd", "true", "--health-interval", "100ms",
+					testutil.CommonImage, "sleep", "infinity")
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Errors: []error{errors.New("shorter than the minimum of 1s")},
+					Output: func(stdout string, t tig.T) {
+						hc := nerdtest.InspectContainer(helpers, data.Identifier()).Config.Healthcheck
+						assert.Assert(t, hc != nil)
+						assert.Equal(t, hc.Interval, time.Second)
+					},
+				}
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+			},
+		},
+		{
+			Description: "minimum of 0s disables the clamp",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("--min-health-interval=0s", "run", "-d", "--name", data.Identifier(),
+					"--health-cmd", "true", "--health-interval", "100ms",
+					testutil.CommonImage, "sleep", "infinity")
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: func(stdout string, t tig.T) {
+						hc := nerdtest.InspectContainer(helpers, data.Identifier()).Config.Healthcheck
+						assert.Assert(t, hc != nil)
+						assert.Equal(t, hc.Interval, 100*time.Millisecond)
+					},
+				}
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
+
 func TestRunHealthcheckFromImage(t *testing.T) {
 	nerdtest.Setup()
 
@@ -1103,3 +1377,16 @@ func TestCleanupFIFOs(t *testing.T) {
 	}
 	testCase.Run(t)
 }
+
+func TestRunPullNever(t *testing.T) {
+	testCase := nerdtest.Setup()
+	testCase.Require = require.Not(nerdtest.Docker)
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		return helpers.Command("run", "--rm", "--pull=never", "example.com/nonexistent/"+data.Identifier())
+	}
+	testCase.Expected = test.Expects(expect.ExitCodeGenericFail, []error{
+		errors.New("was not found locally, and --pull=never prevents pulling it"),
+	}, nil)
+
+	testCase.Run(t)
+}
@@ -399,6 +399,62 @@ func TestUsernsMappingRunCmd(t *testing.T) {
 				},
 				Expected: test.Expects(1, nil, nil),
 			},
+			{
+				Description: "Test container run with --userns <username> without userns-remap",
+				NoParallel:  true, // Changes system config so running in non parallel mode
+				Setup: func(data test.Data, helpers test.Helpers) {
+					err := appendUsernsConfig(data.Labels().Get("validUserns"), data.Labels().Get("expectedHostUID"), helpers)
+					assert.NilError(t, err, "Failed to append Userns config")
+				},
+				Cleanup: func(data test.Data, helpers test.Helpers) {
+					helpers.Anyhow("rm", "-f", data.Identifier())
+					removeUsernsConfig(t, data.Labels().Get("validUserns"), helpers)
+				},
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("run", "--tty", "-d", "--userns", data.Labels().Get("validUserns"), "--name", data.Identifier(), testutil.CommonImage, "sleep", "inf")
+				},
+				Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+					return &test.Expected{
+						ExitCode: 0,
+						Output: func(stdout string, t tig.T) {
+							actualHostUID, err := getContainerHostUID(helpers, data.Identifier())
+							assert.NilError(t, err)
+							assert.Equal(t, actualHostUID, data.Labels().Get("expectedHostUID"))
+						},
+					}
+				},
+			},
+			{
+				Description: "Test container run with --userns=auto allocates distinct ranges",
+				NoParallel:  true, // Changes system config so running in non parallel mode
+				Setup: func(data test.Data, helpers test.Helpers) {
+					// --userns=auto allocates from the ranges of the "containers" user
+					err := appendUsernsConfig("containers", data.Labels().Get("expectedHostUID"), helpers)
+					assert.NilError(t, err, "Failed to append Userns config")
+					helpers.Ensure("run", "--tty", "-d", "--userns", "auto:size=1024", "--name", data.Identifier("first"), testutil.CommonImage, "sleep", "inf")
+				},
+				Cleanup: func(data test.Data, helpers test.Helpers) {
+					helpers.Anyhow("rm", "-f", data.Identifier("first"), data.Identifier())
+					removeUsernsConfig(t, "containers", helpers)
+				},
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("run", "--tty", "-d", "--userns", "auto:size=1024", "--name", data.Identifier(), testutil.CommonImage, "sleep", "inf")
+				},
+				Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+					return &test.Expected{
+						ExitCode: 0,
+						Output: func(stdout string, t tig.T) {
+							firstHostUID, err := getContainerHostUID(helpers, data.Identifier("first"))
+							assert.NilError(t, err)
+							secondHostUID, err := getContainerHostUID(helpers, data.Identifier())
+							assert.NilError(t, err)
+							// The root of each container is mapped to a non-root host UID of its own range
+							assert.Assert(t, firstHostUID != "0" && secondHostUID != "0")
+							assert.Assert(t, firstHostUID != secondHostUID)
+						},
+					}
+				},
+			},
 			{
 				Description: "Test container run with invalid Userns",
 				Cleanup: func(data test.Data, helpers test.Helpers) {
@@ -92,6 +92,29 @@ func TestRunHyperVContainer(t *testing.T) {
 	assert.Assert(t, strings.Contains(inspectOutput, "hyperv"))
 }
 
+func TestRunHyperVContainerResources(t *testing.T) {
+	testutil.DockerIncompatible(t)
+	base := testutil.NewBase(t)
+
+	if !testutil.HyperVSupported() {
+		t.Skip("HyperV is not enabled, skipping test")
+	}
+
+	containerName := testutil.Identifier(t)
+	defer base.Cmd("rm", "-f", containerName).Run()
+	base.Cmd("run", "-d", "--isolation", "hyperv", "--memory", "1g", "--cpus", "1.5", "--name", containerName,
+		testutil.WindowsNano, "ping", "-t", "localhost").AssertOK()
+
+	inspectOutput := base.Cmd("container", "inspect", "--mode", "native", containerName).Out()
+	// --memory and --cpus size the utility VM
+	assert.Assert(t, strings.Contains(inspectOutput, `"io.microsoft.virtualmachine.computetopology.memory.sizeinmb": "1024"`), inspectOutput)
+	assert.Assert(t, strings.Contains(inspectOutput, `"io.microsoft.virtualmachine.computetopology.processor.count": "2"`), inspectOutput)
+
+	// Hyper-V isolation is only available with runhcs
+	base.Cmd("run", "--rm", "--isolation", "hyperv", "--runtime", "io.containerd.runc.v2", testutil.WindowsNano).
+		AssertFail()
+}
+
 func TestRunProcessContainer(t *testing.T) {
 	testutil.DockerIncompatible(t)
 	base := testutil.NewBase(t)
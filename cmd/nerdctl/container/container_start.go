@@ -56,7 +56,7 @@ func startOptions(cmd *cobra.Command) (types.ContainerStartOptions, error) {
 	if err != nil {
 		return types.ContainerStartOptions{}, err
 	}
-	detachKeys, err := cmd.Flags().GetString("detach-keys")
+	detachKeys, err := helpers.DetachKeys(cmd)
 	if err != nil {
 		return types.ContainerStartOptions{}, err
 	}
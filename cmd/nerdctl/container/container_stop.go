@@ -41,7 +41,7 @@ func StopCommand() *cobra.Command {
 		SilenceErrors:     true,
 	}
 	cmd.Flags().IntP("time", "t", 10, "Seconds to wait before sending a SIGKILL")
-	cmd.Flags().StringP("signal", "s", "SIGTERM", "Signal to send to the container")
+	cmd.Flags().StringP("signal", "s", "", "Signal to send to the container (default: the stop signal of the container, or SIGTERM)")
 	return cmd
 }
 
@@ -28,6 +28,7 @@ import (
 
 	"github.com/containerd/nerdctl/mod/tigron/expect"
 	"github.com/containerd/nerdctl/mod/tigron/test"
+	"github.com/containerd/nerdctl/mod/tigron/tig"
 
 	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
 	"github.com/containerd/nerdctl/v2/pkg/testutil"
@@ -96,6 +97,54 @@ func TestStopWithStopSignal(t *testing.T) {
 	testCase.Run(t)
 }
 
+func TestStopWithSignalOverridingStopSignal(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+	}
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		cmd := nerdtest.RunSigProxyContainer(nerdtest.SigUsr1, true,
+			[]string{"--stop-signal", nerdtest.SigQuit.String()}, data, helpers)
+		helpers.Ensure("stop", "--signal", "SIGUSR1", "--time", "30", data.Identifier())
+		return cmd
+	}
+
+	// Verify that SIGUSR1 was sent to the container instead of the stored SIGQUIT, and that the container exited gracefully
+	testCase.Expected = test.Expects(0, nil, expect.Contains(nerdtest.SignalCaught))
+
+	testCase.Run(t)
+}
+
+func TestStopWithInvalidSignal(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("run", "-d", "--name", data.Identifier(), testutil.CommonImage, "sleep", nerdtest.Infinity)
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+	}
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		return helpers.Command("stop", "--signal", "SIGFOO", data.Identifier())
+	}
+
+	testCase.Expected = func(data test.Data, helpers test.Helpers) *test.Expected {
+		return &test.Expected{
+			ExitCode: expect.ExitCodeGenericFail,
+			Output: func(stdout string, t tig.T) {
+				status := helpers.Capture("inspect", "--format", "{{.State.Status}}", data.Identifier())
+				assert.Equal(t, strings.TrimSpace(status), "running")
+			},
+		}
+	}
+
+	testCase.Run(t)
+}
+
 func TestStopCleanupForwards(t *testing.T) {
 	const (
 		hostPort          = 9999
@@ -199,3 +248,21 @@ func TestStopWithTimeout(t *testing.T) {
 	// The container should get the SIGKILL before the 10s default timeout
 	assert.Assert(t, elapsed < 10*time.Second, "Container did not respect --timeout flag")
 }
+
+func TestStopWithStopTimeoutZero(t *testing.T) {
+	t.Parallel()
+	base := testutil.NewBase(t)
+	testContainerName := testutil.Identifier(t)
+	defer base.Cmd("rm", "-f", testContainerName).Run()
+
+	// Start a container that ignores SIGTERM (PID 1 without a handler), with a stop timeout of 0
+	base.Cmd("run", "-d", "--name", testContainerName, "--stop-timeout", "0", testutil.CommonImage, "sleep", "Inf").AssertOK()
+	base.Cmd("inspect", "--format", "{{.Config.StopTimeout}}", testContainerName).AssertOutExactly("0\n")
+
+	start := time.Now()
+	base.Cmd("stop", testContainerName).AssertOK()
+	elapsed := time.Since(start)
+
+	// The container should be killed without waiting for the 10s default timeout
+	assert.Assert(t, elapsed < 5*time.Second, "Container with --stop-timeout 0 was not killed immediately")
+}
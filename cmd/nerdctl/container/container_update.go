@@ -21,6 +21,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"reflect"
 	"runtime"
 	"time"
 
@@ -42,12 +44,15 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/formatter"
 	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
 	"github.com/containerd/nerdctl/v2/pkg/infoutil"
+	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/dockercompat"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
 )
 
 type updateResourceOptions struct {
 	CPUPeriod          uint64
 	CPUQuota           int64
 	CPUShares          uint64
+	NanoCPUs           int64
 	MemoryLimitInBytes int64
 	MemoryReservation  int64
 	MemorySwapInBytes  int64
@@ -85,7 +90,7 @@ func setUpdateFlags(cmd *cobra.Command) {
 	cmd.Flags().String("cpuset-mems", "", "MEMs in which to allow execution (0-3, 0,1)")
 	cmd.Flags().Int64("pids-limit", -1, "Tune container pids limit (set -1 for unlimited)")
 	cmd.Flags().Uint16("blkio-weight", 0, "Block IO (relative weight), between 10 and 1000, or 0 to disable (default 0)")
-	cmd.Flags().String("restart", "no", `Restart policy to apply when a container exits (implemented values: "no"|"always|on-failure:n|unless-stopped")`)
+	cmd.Flags().String("restart", "no", `Restart policy to apply when a container exits (implemented values: "no"|"always|on-failure:n[:codes=c1,c2]|unless-stopped")`)
 	cmd.RegisterFlagCompletionFunc("restart", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{"no", "always", "on-failure", "unless-stopped"}, cobra.ShellCompDirectiveNoFileComp
 	})
@@ -138,9 +143,11 @@ func getUpdateOption(cmd *cobra.Command, globalOptions types.GlobalCommandOption
 			return options, errors.New("cpus and quota/period should be used separately")
 		}
 	}
+	var nanoCPUs int64
 	if cpus > 0.0 {
 		cpuPeriod = uint64(100000)
 		cpuQuota = int64(cpus * 100000.0)
+		nanoCPUs = int64(math.Round(cpus * 1e9))
 	}
 	shares, err := cmd.Flags().GetUint64("cpu-shares")
 	if err != nil {
@@ -230,6 +237,7 @@ func getUpdateOption(cmd *cobra.Command, globalOptions types.GlobalCommandOption
 			CPUPeriod:          cpuPeriod,
 			CPUQuota:           cpuQuota,
 			CPUShares:          shares,
+			NanoCPUs:           nanoCPUs,
 			CpusetCpus:         cpuset,
 			CpusetMems:         cpusetMems,
 			MemoryLimitInBytes: mem64,
@@ -296,9 +304,8 @@ func updateContainer(ctx context.Context, client *containerd.Client, id string,
 			}
 		}
 		if cmd.Flags().Changed("cpus") {
-			if spec.Linux.Resources.CPU.Cpus != opts.CpusetCpus {
-				spec.Linux.Resources.CPU.Cpus = opts.CpusetCpus
-			}
+			spec.Linux.Resources.CPU.Quota = &opts.CPUQuota
+			spec.Linux.Resources.CPU.Period = &opts.CPUPeriod
 		}
 		if cmd.Flags().Changed("cpuset-mems") {
 			if spec.Linux.Resources.CPU.Mems != opts.CpusetMems {
@@ -353,6 +360,24 @@ func updateContainer(ctx context.Context, client *containerd.Client, id string,
 		}
 	}()
 
+	if cmd.Flags().Changed("cpus") || cmd.Flags().Changed("cpu-quota") || cmd.Flags().Changed("cpu-period") ||
+		cmd.Flags().Changed("cpu-shares") || cmd.Flags().Changed("cpuset-cpus") {
+		if err := updateContainerHostConfigLabel(ctx, container, func(hostConfigLabel *dockercompat.HostConfigLabel) {
+			if cmd.Flags().Changed("cpus") || cmd.Flags().Changed("cpu-quota") || cmd.Flags().Changed("cpu-period") {
+				// NanoCPUs is reset when the quota or the period is updated directly
+				hostConfigLabel.NanoCPUs = opts.NanoCPUs
+			}
+			if cmd.Flags().Changed("cpu-shares") {
+				hostConfigLabel.CPUShares = opts.CPUShares
+			}
+			if cmd.Flags().Changed("cpuset-cpus") {
+				hostConfigLabel.CPUSetCPUs = opts.CpusetCpus
+			}
+		}); err != nil {
+			return err
+		}
+	}
+
 	restart, err := cmd.Flags().GetString("restart")
 	if err != nil {
 		return err
@@ -393,6 +418,31 @@ func updateContainerSpec(ctx context.Context, container containerd.Container, sp
 	return nil
 }
 
+// updateContainerHostConfigLabel updates the host config label, which is shown in inspect, with update.
+func updateContainerHostConfigLabel(ctx context.Context, container containerd.Container, update func(*dockercompat.HostConfigLabel)) error {
+	containerLabels, err := container.Labels(ctx)
+	if err != nil {
+		return err
+	}
+	var hostConfigLabel dockercompat.HostConfigLabel
+	if hostConfigJSON, ok := containerLabels[labels.HostConfigLabel]; ok {
+		if err := json.Unmarshal([]byte(hostConfigJSON), &hostConfigLabel); err != nil {
+			return err
+		}
+	}
+	oldHostConfigLabel := hostConfigLabel
+	update(&hostConfigLabel)
+	if reflect.DeepEqual(hostConfigLabel, oldHostConfigLabel) {
+		return nil
+	}
+	hostConfigJSON, err := json.Marshal(hostConfigLabel)
+	if err != nil {
+		return err
+	}
+	_, err = container.SetLabels(ctx, map[string]string{labels.HostConfigLabel: string(hostConfigJSON)})
+	return err
+}
+
 func copySpec(spec *runtimespec.Spec) (*runtimespec.Spec, error) {
 	var copySpec runtimespec.Spec
 	if spec == nil {
@@ -290,3 +290,10 @@ func HiddenPersistentStringArrayFlag(cmd *cobra.Command, name string, value []st
 	cmd.PersistentFlags().StringSlice(name, value, usage)
 	cmd.PersistentFlags().MarkHidden(name)
 }
+
+// HiddenPersistentStringFlag creates a persistent string flag and hides it.
+// Used mainly to pass global config values to individual commands.
+func HiddenPersistentStringFlag(cmd *cobra.Command, name string, value string, usage string) {
+	cmd.PersistentFlags().String(name, value, usage)
+	cmd.PersistentFlags().MarkHidden(name)
+}
@@ -18,42 +18,98 @@ package helpers
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/consoleutil"
 	"github.com/containerd/nerdctl/v2/pkg/fs"
+	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
 )
 
+// VerifyOptions returns the image verification options.
+// A flag that is not specified on the command line defaults to the corresponding
+// property of nerdctl.toml (e.g., `verify` for `--verify`), if set.
 func VerifyOptions(cmd *cobra.Command) (opt types.ImageVerifyOptions, err error) {
-	if opt.Provider, err = cmd.Flags().GetString("verify"); err != nil {
+	if opt.Provider, err = flagOrGlobalDefault(cmd, "verify"); err != nil {
 		return
 	}
-	if opt.CosignKey, err = cmd.Flags().GetString("cosign-key"); err != nil {
+	if opt.CosignKey, err = flagOrGlobalDefault(cmd, "cosign-key"); err != nil {
 		return
 	}
-	if opt.CosignCertificateIdentity, err = cmd.Flags().GetString("cosign-certificate-identity"); err != nil {
+	if opt.CosignCertificateIdentity, err = flagOrGlobalDefault(cmd, "cosign-certificate-identity"); err != nil {
 		return
 	}
-	if opt.CosignCertificateIdentityRegexp, err = cmd.Flags().GetString("cosign-certificate-identity-regexp"); err != nil {
+	if opt.CosignCertificateIdentityRegexp, err = flagOrGlobalDefault(cmd, "cosign-certificate-identity-regexp"); err != nil {
 		return
 	}
-	if opt.CosignCertificateOidcIssuer, err = cmd.Flags().GetString("cosign-certificate-oidc-issuer"); err != nil {
+	if opt.CosignCertificateOidcIssuer, err = flagOrGlobalDefault(cmd, "cosign-certificate-oidc-issuer"); err != nil {
 		return
 	}
-	if opt.CosignCertificateOidcIssuerRegexp, err = cmd.Flags().GetString("cosign-certificate-oidc-issuer-regexp"); err != nil {
+	if opt.CosignCertificateOidcIssuerRegexp, err = flagOrGlobalDefault(cmd, "cosign-certificate-oidc-issuer-regexp"); err != nil {
 		return
 	}
 	return
 }
 
+// DetachKeys returns the value of the `--detach-keys` flag.
+// When the flag is not specified, it defaults to the `detach_keys` property of nerdctl.toml, if set.
+func DetachKeys(cmd *cobra.Command) (string, error) {
+	keys, err := flagOrGlobalDefault(cmd, "detach-keys")
+	if err != nil {
+		return "", err
+	}
+	if err := consoleutil.ValidateDetachKeys(keys); err != nil {
+		return "", err
+	}
+	return keys, nil
+}
+
+// flagOrGlobalDefault returns the value of the flag `name`, falling back to the hidden global flag
+// `global-<name>` populated from nerdctl.toml when the flag is not specified.
+func flagOrGlobalDefault(cmd *cobra.Command, name string) (string, error) {
+	if !cmd.Flags().Changed(name) && cmd.Flags().Lookup("global-"+name) != nil {
+		v, err := cmd.Flags().GetString("global-" + name)
+		if err != nil {
+			return "", err
+		}
+		if v != "" {
+			return v, nil
+		}
+	}
+	return cmd.Flags().GetString(name)
+}
+
+// PullRetryOptions returns the number of pull retries and the delay between them,
+// from the flags `retryFlag` and `delayFlag` (e.g., "retry" and "retry-delay").
+func PullRetryOptions(cmd *cobra.Command, retryFlag, delayFlag string) (int, time.Duration, error) {
+	retry, err := cmd.Flags().GetInt(retryFlag)
+	if err != nil {
+		return 0, 0, err
+	}
+	if retry < 0 {
+		return 0, 0, fmt.Errorf("--%s must not be negative, got %d", retryFlag, retry)
+	}
+	delay, err := cmd.Flags().GetDuration(delayFlag)
+	if err != nil {
+		return 0, 0, err
+	}
+	if delay < 0 {
+		return 0, 0, fmt.Errorf("--%s must not be negative, got %s", delayFlag, delay)
+	}
+	return retry, delay, nil
+}
+
 func ValidateHealthcheckFlags(options types.ContainerCreateOptions) error {
 	healthFlagsSet :=
 		options.HealthInterval != 0 ||
 			options.HealthTimeout != 0 ||
 			options.HealthRetries != 0 ||
 			options.HealthStartPeriod != 0 ||
-			options.HealthStartInterval != 0
+			options.HealthStartInterval != 0 ||
+			len(options.HealthEnv) > 0
 
 	if options.NoHealthcheck {
 		if options.HealthCmd != "" || healthFlagsSet {
@@ -62,6 +118,9 @@ func ValidateHealthcheckFlags(options types.ContainerCreateOptions) error {
 	}
 
 	// Note: HealthCmd can be empty with other healthcheck flags set cause healthCmd could be coming from image.
+	if _, err := healthcheck.ParseProbeURL(options.HealthCmd); err != nil {
+		return err
+	}
 	if options.HealthInterval < 0 {
 		return fmt.Errorf("--health-interval cannot be negative")
 	}
@@ -77,6 +136,11 @@ func ValidateHealthcheckFlags(options types.ContainerCreateOptions) error {
 	if options.HealthStartInterval < 0 {
 		return fmt.Errorf("--health-start-interval cannot be negative")
 	}
+	for _, env := range options.HealthEnv {
+		if k, _, _ := strings.Cut(env, "="); k == "" {
+			return fmt.Errorf("invalid --health-env %q: must be in the KEY=VALUE format, or KEY to take the value from the environment", env)
+		}
+	}
 	return nil
 }
 
@@ -157,6 +221,21 @@ func ProcessRootCmdFlags(cmd *cobra.Command) (types.GlobalCommandOptions, error)
 	if err != nil {
 		return types.GlobalCommandOptions{}, err
 	}
+	maxLabelSize, err := cmd.Flags().GetInt("max-label-size")
+	if err != nil {
+		return types.GlobalCommandOptions{}, err
+	}
+	minHealthInterval, err := cmd.Flags().GetString("min-health-interval")
+	if err != nil {
+		return types.GlobalCommandOptions{}, err
+	}
+	if _, err := healthcheck.ParseMinInterval(minHealthInterval); err != nil {
+		return types.GlobalCommandOptions{}, fmt.Errorf("invalid --min-health-interval: %w", err)
+	}
+	provenanceAnnotations, err := cmd.Flags().GetBool("provenance-annotations")
+	if err != nil {
+		return types.GlobalCommandOptions{}, err
+	}
 
 	// Point to dataRoot for filesystem-helpers implementing rollback / backups.
 	err = fs.InitFS(dataRoot)
@@ -165,25 +244,28 @@ func ProcessRootCmdFlags(cmd *cobra.Command) (types.GlobalCommandOptions, error)
 	}
 
 	return types.GlobalCommandOptions{
-		Debug:            debug,
-		DebugFull:        debugFull,
-		Address:          address,
-		Namespace:        namespace,
-		Snapshotter:      snapshotter,
-		CNIPath:          cniPath,
-		CNINetConfPath:   cniConfigPath,
-		DataRoot:         dataRoot,
-		CgroupManager:    cgroupManager,
-		InsecureRegistry: insecureRegistry,
-		HostsDir:         hostsDir,
-		Experimental:     experimental,
-		HostGatewayIP:    hostGatewayIP,
-		BridgeIP:         bridgeIP,
-		KubeHideDupe:     kubeHideDupe,
-		CDISpecDirs:      cdiSpecDirs,
-		DNS:              dns,
-		DNSOpts:          dnsOpts,
-		DNSSearch:        dnsSearch,
+		Debug:                 debug,
+		DebugFull:             debugFull,
+		Address:               address,
+		Namespace:             namespace,
+		Snapshotter:           snapshotter,
+		CNIPath:               cniPath,
+		CNINetConfPath:        cniConfigPath,
+		DataRoot:              dataRoot,
+		CgroupManager:         cgroupManager,
+		InsecureRegistry:      insecureRegistry,
+		HostsDir:              hostsDir,
+		Experimental:          experimental,
+		HostGatewayIP:         hostGatewayIP,
+		BridgeIP:              bridgeIP,
+		KubeHideDupe:          kubeHideDupe,
+		CDISpecDirs:           cdiSpecDirs,
+		DNS:                   dns,
+		DNSOpts:               dnsOpts,
+		DNSSearch:             dnsSearch,
+		MaxLabelSize:          maxLabelSize,
+		MinHealthInterval:     minHealthInterval,
+		ProvenanceAnnotations: provenanceAnnotations,
 	}, nil
 }
 
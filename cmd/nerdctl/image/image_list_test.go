@@ -22,9 +22,11 @@ import (
 	"regexp"
 	"runtime"
 	"slices"
+	"strconv"
 	"strings"
 	"testing"
 
+	"github.com/docker/go-units"
 	"gotest.tools/v3/assert"
 
 	"github.com/containerd/nerdctl/mod/tigron/expect"
@@ -107,6 +109,38 @@ func TestImages(t *testing.T) {
 					}
 				},
 			},
+			{
+				Description: "Size in human-readable form and in bytes",
+				Command:     test.Command("images", "--format", "{{.Size}}\t{{.SizeBytes}}", commonImage.String()),
+				Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+					return &test.Expected{
+						Output: func(stdout string, t tig.T) {
+							human, raw, ok := strings.Cut(strings.Split(strings.TrimSpace(stdout), "\n")[0], "\t")
+							assert.Assert(t, ok, "unexpected output %q", stdout)
+							sizeBytes, err := strconv.ParseInt(raw, 10, 64)
+							assert.NilError(t, err)
+							assert.Assert(t, sizeBytes > 0)
+							// the human-readable size is rounded to 4 significant digits
+							assert.Equal(t, human, units.HumanSize(float64(sizeBytes)))
+						},
+					}
+				},
+			},
+			{
+				Description: "Size with --no-trunc is not rounded",
+				Command:     test.Command("images", "--no-trunc", "--format", "{{.Size}}\t{{.SizeBytes}}", commonImage.String()),
+				Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+					return &test.Expected{
+						Output: func(stdout string, t tig.T) {
+							human, raw, ok := strings.Cut(strings.Split(strings.TrimSpace(stdout), "\n")[0], "\t")
+							assert.Assert(t, ok, "unexpected output %q", stdout)
+							sizeBytes, err := strconv.ParseInt(raw, 10, 64)
+							assert.NilError(t, err)
+							assert.Equal(t, human, units.HumanSizeWithPrecision(float64(sizeBytes), 15))
+						},
+					}
+				},
+			},
 			{
 				Description: "CheckCreatedTime",
 				Command:     test.Command("images", "--format", "'{{json .CreatedAt}}'"),
@@ -328,6 +362,115 @@ CMD ["echo", "nerdctl-build-notag-string"]
 	testCase.Run(t)
 }
 
+func TestImagesDigests(t *testing.T) {
+	nerdtest.Setup()
+
+	const header = "REPOSITORY\tTAG\tDIGEST\tIMAGE ID\tCREATED\tPLATFORM\tSIZE\tBLOB SIZE"
+
+	// readRows returns the REPOSITORY, TAG, and DIGEST columns of the rows
+	readRows := func(t tig.T, stdout string) [][3]string {
+		lines := strings.Split(strings.TrimSpace(stdout), "\n")
+		tab := tabutil.NewReader(header)
+		assert.NilError(t, tab.ParseHeader(lines[0]))
+		var rows [][3]string
+		for _, line := range lines[1:] {
+			repo, _ := tab.ReadRow(line, "REPOSITORY")
+			tag, _ := tab.ReadRow(line, "TAG")
+			dgst, _ := tab.ReadRow(line, "DIGEST")
+			rows = append(rows, [3]string{repo, tag, dgst})
+		}
+		return rows
+	}
+
+	testCase := &test.Case{
+		Require: require.Not(nerdtest.Docker),
+		Setup: func(data test.Data, helpers test.Helpers) {
+			helpers.Ensure("pull", "--quiet", testutil.CommonImage)
+			dgst := helpers.Capture("images", "--format", "{{.Digest}}", testutil.CommonImage)
+			data.Labels().Set("digest", strings.TrimSpace(dgst))
+		},
+		SubTests: []*test.Case{
+			{
+				Description: "tagged image shows the short digest",
+				Command:     test.Command("images", "--digests", testutil.CommonImage),
+				Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+					return &test.Expected{
+						Output: func(stdout string, t tig.T) {
+							rows := readRows(t, stdout)
+							assert.Equal(t, len(rows), 1, stdout)
+							assert.Equal(t, rows[0][2], data.Labels().Get("digest")[:len("sha256:")+12])
+						},
+					}
+				},
+			},
+			{
+				Description: "tagged image shows the full digest with --no-trunc",
+				Command:     test.Command("images", "--digests", "--no-trunc", testutil.CommonImage),
+				Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+					return &test.Expected{
+						Output: func(stdout string, t tig.T) {
+							rows := readRows(t, stdout)
+							assert.Equal(t, len(rows), 1, stdout)
+							assert.Equal(t, rows[0][2], data.Labels().Get("digest"))
+						},
+					}
+				},
+			},
+			{
+				Description: "digest-only image shows <none> tag",
+				Setup: func(data test.Data, helpers test.Helpers) {
+					commonImage, _ := referenceutil.Parse(testutil.CommonImage)
+					data.Labels().Set("digestRef", commonImage.FamiliarName()+"@"+data.Labels().Get("digest"))
+					helpers.Ensure("pull", "--quiet", data.Labels().Get("digestRef"))
+				},
+				Cleanup: func(data test.Data, helpers test.Helpers) {
+					if data.Labels().Get("digestRef") != "" {
+						helpers.Anyhow("rmi", data.Labels().Get("digestRef"))
+					}
+				},
+				Command: test.Command("images", "--digests", "--no-trunc"),
+				Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+					return &test.Expected{
+						Output: func(stdout string, t tig.T) {
+							commonImage, _ := referenceutil.Parse(testutil.CommonImage)
+							assert.Assert(t, slices.Contains(readRows(t, stdout),
+								[3]string{commonImage.FamiliarName(), "<none>", data.Labels().Get("digest")}), stdout)
+						},
+					}
+				},
+			},
+			{
+				Description: "dangling image shows <none> repository and tag",
+				NoParallel:  true,
+				Require:     nerdtest.Build,
+				Setup: func(data test.Data, helpers test.Helpers) {
+					data.Temp().Save(fmt.Sprintf("FROM %s\nCMD [\"echo\", \"nerdctl-images-digests-dangling\"]", testutil.CommonImage), "Dockerfile")
+					helpers.Ensure("build", data.Temp().Path())
+				},
+				Cleanup: func(data test.Data, helpers test.Helpers) {
+					helpers.Anyhow("image", "prune", "-f")
+				},
+				Command: test.Command("images", "--digests", "--filter", "dangling=true"),
+				Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+					return &test.Expected{
+						Output: func(stdout string, t tig.T) {
+							rows := readRows(t, stdout)
+							assert.Assert(t, len(rows) > 0, stdout)
+							for _, row := range rows {
+								assert.Equal(t, row[0], "<none>")
+								assert.Equal(t, row[1], "<none>")
+								assert.Assert(t, regexp.MustCompile(`^sha256:[0-9a-f]{12}$`).MatchString(row[2]), row[2])
+							}
+						},
+					}
+				},
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
+
 func TestImagesKubeWithKubeHideDupe(t *testing.T) {
 	nerdtest.Setup()
 
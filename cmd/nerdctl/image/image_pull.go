@@ -17,6 +17,8 @@
 package image
 
 import (
+	"time"
+
 	"github.com/spf13/cobra"
 
 	"github.com/containerd/nerdctl/v2/cmd/nerdctl/completion"
@@ -66,6 +68,8 @@ func PullCommand() *cobra.Command {
 	// #endregion
 
 	cmd.Flags().BoolP("quiet", "q", false, "Suppress verbose output")
+	cmd.Flags().Int("retry", 0, "Number of times to retry a failed pull (0 disables retries)")
+	cmd.Flags().Duration("retry-delay", time.Second, "Delay between the pull retries")
 
 	cmd.Flags().String("ipfs-address", "", "multiaddr of IPFS API (default uses $IPFS_PATH env variable if defined or local directory ~/.ipfs)")
 
@@ -118,6 +122,10 @@ func processPullCommandFlags(cmd *cobra.Command) (types.ImagePullOptions, error)
 	if err != nil {
 		return types.ImagePullOptions{}, err
 	}
+	retry, retryDelay, err := helpers.PullRetryOptions(cmd, "retry", "retry-delay")
+	if err != nil {
+		return types.ImagePullOptions{}, err
+	}
 	return types.ImagePullOptions{
 		GOptions:        globalOptions,
 		VerifyOptions:   verifyOptions,
@@ -126,6 +134,8 @@ func processPullCommandFlags(cmd *cobra.Command) (types.ImagePullOptions, error)
 		Mode:            "always",
 		Quiet:           quiet,
 		IPFSAddress:     ipfsAddressStr,
+		Retry:           retry,
+		RetryDelay:      retryDelay,
 		RFlags: types.RemoteSnapshotterFlags{
 			SociIndexDigest: sociIndexDigest,
 		},
@@ -17,9 +17,13 @@
 package image
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
 
 	"gotest.tools/v3/assert"
@@ -282,3 +286,76 @@ func TestImagePullProcessOutput(t *testing.T) {
 
 	testCase.Run(t)
 }
+
+func TestImagePullRetry(t *testing.T) {
+	nerdtest.Setup()
+
+	var (
+		server *httptest.Server
+		// manifestRequests counts the requests of the fake registry, which fails all of them
+		manifestRequests atomic.Int64
+		// requestsPerAttempt is the number of requests made by a single pull attempt,
+		// which depends on the retries done internally by the containerd resolver
+		requestsPerAttempt int64
+	)
+
+	testCase := &test.Case{
+		// --retry is not supported by Docker
+		Require:    require.Not(nerdtest.Docker),
+		NoParallel: true,
+		Setup: func(data test.Data, helpers test.Helpers) {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.Contains(r.URL.Path, "/manifests/") {
+					manifestRequests.Add(1)
+				}
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}))
+			data.Labels().Set("ref", strings.TrimPrefix(server.URL, "http://")+"/"+data.Identifier()+":latest")
+		},
+		Cleanup: func(data test.Data, helpers test.Helpers) {
+			if server != nil {
+				server.Close()
+			}
+		},
+		SubTests: []*test.Case{
+			{
+				Description: "--retry 0 disables retries",
+				NoParallel:  true,
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("pull", "--insecure-registry", "--retry", "0", data.Labels().Get("ref"))
+				},
+				Expected: test.Expects(expect.ExitCodeGenericFail, nil, func(stdout string, t tig.T) {
+					requestsPerAttempt = manifestRequests.Swap(0)
+					assert.Assert(t, requestsPerAttempt > 0, "the fake registry should have been contacted")
+				}),
+			},
+			{
+				Description: "--retry 2 makes 3 attempts",
+				NoParallel:  true,
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("pull", "--insecure-registry", "--retry", "2", "--retry-delay", "100ms", data.Labels().Get("ref"))
+				},
+				Expected: test.Expects(expect.ExitCodeGenericFail, nil, func(stdout string, t tig.T) {
+					assert.Equal(t, manifestRequests.Swap(0), 3*requestsPerAttempt)
+				}),
+			},
+			{
+				Description: "run --pull-retry 1 makes 2 attempts",
+				NoParallel:  true,
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("run", "--rm", "--insecure-registry", "--pull-retry", "1", "--pull-retry-delay", "100ms", data.Labels().Get("ref"))
+				},
+				Expected: test.Expects(expect.ExitCodeGenericFail, nil, func(stdout string, t tig.T) {
+					assert.Equal(t, manifestRequests.Swap(0), 2*requestsPerAttempt)
+				}),
+			},
+			{
+				Description: "negative --retry",
+				Command:     test.Command("pull", "--retry", "-1", testutil.CommonImage),
+				Expected:    test.Expects(expect.ExitCodeGenericFail, []error{errors.New("must not be negative")}, nil),
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
@@ -32,6 +32,8 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/clientutil"
 	"github.com/containerd/nerdctl/v2/pkg/cmd/container"
 	"github.com/containerd/nerdctl/v2/pkg/cmd/image"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/network"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/volume"
 	"github.com/containerd/nerdctl/v2/pkg/formatter"
 	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
 	"github.com/containerd/nerdctl/v2/pkg/idutil/imagewalker"
@@ -56,6 +58,8 @@ func Command() *cobra.Command {
 var validInspectType = map[string]bool{
 	"container": true,
 	"image":     true,
+	"network":   true,
+	"volume":    true,
 }
 
 func addInspectFlags(cmd *cobra.Command) {
@@ -67,7 +71,7 @@ func addInspectFlags(cmd *cobra.Command) {
 	})
 	cmd.Flags().String("type", "", "Return JSON for specified type")
 	cmd.RegisterFlagCompletionFunc("type", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		return []string{"image", "container", ""}, cobra.ShellCompDirectiveNoFileComp
+		return []string{"image", "container", "network", "volume", ""}, cobra.ShellCompDirectiveNoFileComp
 	})
 	cmd.Flags().String("mode", "dockercompat", `Inspect mode, "dockercompat" for Docker-compatible output, "native" for containerd-native output`)
 	cmd.RegisterFlagCompletionFunc("mode", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -119,6 +123,8 @@ func inspectAction(cmd *cobra.Command, args []string) error {
 
 	inspectImage := len(inspectType) == 0 || inspectType == "image"
 	inspectContainer := len(inspectType) == 0 || inspectType == "container"
+	inspectNetwork := len(inspectType) == 0 || inspectType == "network"
+	inspectVolume := len(inspectType) == 0 || inspectType == "volume"
 
 	var imageInspectOptions types.ImageInspectOptions
 	var containerInspectOptions types.ContainerInspectOptions
@@ -135,6 +141,17 @@ func inspectAction(cmd *cobra.Command, args []string) error {
 			return err
 		}
 	}
+	mode, err := cmd.Flags().GetString("mode")
+	if err != nil {
+		return err
+	}
+	networkInspectOptions := types.NetworkInspectOptions{
+		GOptions: globalOptions,
+		Mode:     mode,
+	}
+	volumeInspectOptions := types.VolumeInspectOptions{
+		GOptions: globalOptions,
+	}
 
 	var errs []error
 	var entries []interface{}
@@ -156,6 +173,28 @@ func inspectAction(cmd *cobra.Command, args []string) error {
 		}
 
 		if ni == 0 && nc == 0 {
+			// Networks and volumes are only looked up when the request matches no image or container.
+			// Their lookup errors are only logged (e.g. when CNI is not configured), as the request may be
+			// for another type of object, and the request is then reported as "no such object".
+			if inspectNetwork {
+				networkInspectOptions.Networks = []string{req}
+				networkEntries, _, err := network.InspectEntries(ctx, client, networkInspectOptions)
+				if err != nil {
+					log.G(ctx).WithError(err).Debugf("failed to inspect %q as a network", req)
+				} else if len(networkEntries) > 0 {
+					entries = append(entries, networkEntries...)
+					continue
+				}
+			}
+			if inspectVolume {
+				volumeEntries, _, err := volume.InspectEntries([]string{req}, volumeInspectOptions)
+				if err != nil {
+					log.G(ctx).WithError(err).Debugf("failed to inspect %q as a volume", req)
+				} else if len(volumeEntries) > 0 {
+					entries = append(entries, volumeEntries...)
+					continue
+				}
+			}
 			errs = append(errs, fmt.Errorf("no such object %s", req))
 		} else if ni > 0 {
 			if imageEntries, err := image.Inspect(ctx, client, []string{req}, imageInspectOptions); err != nil {
@@ -188,5 +227,9 @@ func inspectShellComplete(cmd *cobra.Command, args []string, toComplete string)
 	containers, _ := completion.ContainerNames(cmd, nil)
 	// show image names
 	images, _ := completion.ImageNames(cmd)
-	return append(containers, images...), cobra.ShellCompDirectiveNoFileComp
+	// show network names
+	networks, _ := completion.NetworkNames(cmd, nil)
+	// show volume names
+	volumes, _ := completion.VolumeNames(cmd)
+	return append(append(append(containers, images...), networks...), volumes...), cobra.ShellCompDirectiveNoFileComp
 }
@@ -18,14 +18,17 @@ package inspect
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
 
 	"gotest.tools/v3/assert"
 
+	"github.com/containerd/nerdctl/mod/tigron/expect"
 	"github.com/containerd/nerdctl/mod/tigron/test"
 	"github.com/containerd/nerdctl/mod/tigron/tig"
 
 	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/dockercompat"
+	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/native"
 	"github.com/containerd/nerdctl/v2/pkg/testutil"
 	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
 )
@@ -75,3 +78,79 @@ func TestInspectSimpleCase(t *testing.T) {
 
 	testCase.Run(t)
 }
+
+func TestInspectNetworkAndVolume(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("network", "create", data.Identifier("net"))
+		helpers.Ensure("volume", "create", data.Identifier("vol"))
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("network", "rm", data.Identifier("net"))
+		helpers.Anyhow("volume", "rm", data.Identifier("vol"))
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "inspect network and volume without type",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("inspect", data.Identifier("net"), data.Identifier("vol"))
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: func(stdout string, t tig.T) {
+						var inspectResult []json.RawMessage
+						err := json.Unmarshal([]byte(stdout), &inspectResult)
+						assert.NilError(t, err, "Unable to unmarshal output\n")
+						assert.Equal(t, len(inspectResult), 2, "Unexpected number of results\n")
+
+						var dcn dockercompat.Network
+						err = json.Unmarshal(inspectResult[0], &dcn)
+						assert.NilError(t, err, "Unable to unmarshal output\n")
+						assert.Equal(t, dcn.Name, data.Identifier("net"), "network name should match\n")
+						assert.Equal(t, dcn.ID, nerdtest.InspectNetwork(helpers, data.Identifier("net")).ID, "id should match\n")
+
+						var vol native.Volume
+						err = json.Unmarshal(inspectResult[1], &vol)
+						assert.NilError(t, err, "Unable to unmarshal output\n")
+						assert.Equal(t, vol.Name, data.Identifier("vol"), "volume name should match\n")
+						assert.Equal(t, vol.Mountpoint, nerdtest.InspectVolume(helpers, data.Identifier("vol")).Mountpoint, "mountpoint should match\n")
+					},
+				}
+			},
+		},
+		{
+			Description: "inspect network with type",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("inspect", "--type", "network", "--format", "{{.Name}}", data.Identifier("net"))
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.Equals(data.Identifier("net") + "\n"),
+				}
+			},
+		},
+		{
+			Description: "inspect volume with type",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("inspect", "--type", "volume", "--format", "{{.Name}}", data.Identifier("vol"))
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.Equals(data.Identifier("vol") + "\n"),
+				}
+			},
+		},
+		{
+			Description: "inspect volume with network type fails",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("inspect", "--type", "network", data.Identifier("vol"))
+			},
+			Expected: test.Expects(1, []error{errors.New("no such object")}, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
@@ -62,5 +62,6 @@ func internalOCIHookAction(cmd *cobra.Command, args []string) error {
 		cniPath,
 		cniNetconfpath,
 		bridgeIP,
+		globalOptions.Address,
 	)
 }
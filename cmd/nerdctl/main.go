@@ -46,8 +46,10 @@ import (
 	"github.com/containerd/nerdctl/v2/cmd/nerdctl/system"
 	"github.com/containerd/nerdctl/v2/cmd/nerdctl/volume"
 	"github.com/containerd/nerdctl/v2/pkg/config"
+	"github.com/containerd/nerdctl/v2/pkg/consoleutil"
 	ncdefaults "github.com/containerd/nerdctl/v2/pkg/defaults"
 	"github.com/containerd/nerdctl/v2/pkg/errutil"
+	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
 	"github.com/containerd/nerdctl/v2/pkg/logging"
 	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
 	"github.com/containerd/nerdctl/v2/pkg/store"
@@ -155,6 +157,12 @@ func initRootCmdFlags(rootCmd *cobra.Command, tomlPath string) (*pflag.FlagSet,
 		if err := dec.Decode(cfg); err != nil {
 			return nil, fmt.Errorf("failed to load nerdctl config (not daemon config) from %q (Hint: don't mix up daemon's `config.toml` with `nerdctl.toml`): %w", tomlPath, err)
 		}
+		if err := consoleutil.ValidateDetachKeys(cfg.DetachKeys); err != nil {
+			return nil, fmt.Errorf("failed to load nerdctl config from %q: detach_keys: %w", tomlPath, err)
+		}
+		if _, err := healthcheck.ParseMinInterval(cfg.MinHealthInterval); err != nil {
+			return nil, fmt.Errorf("failed to load nerdctl config from %q: min_health_interval: %w", tomlPath, err)
+		}
 		log.L.Debugf("Loaded config %+v", cfg)
 	} else {
 		log.L.WithError(err).Debugf("Not loading config from %q", tomlPath)
@@ -192,6 +200,20 @@ func initRootCmdFlags(rootCmd *cobra.Command, tomlPath string) (*pflag.FlagSet,
 	helpers.HiddenPersistentStringArrayFlag(rootCmd, "global-dns", cfg.DNS, "Global DNS servers for containers")
 	helpers.HiddenPersistentStringArrayFlag(rootCmd, "global-dns-opts", cfg.DNSOpts, "Global DNS options for containers")
 	helpers.HiddenPersistentStringArrayFlag(rootCmd, "global-dns-search", cfg.DNSSearch, "Global DNS search domains for containers")
+	rootCmd.PersistentFlags().Int("max-label-size", cfg.MaxLabelSize, "Maximum size of the key and the value of a container label, in bytes (0 to disable the check)")
+	rootCmd.PersistentFlags().MarkHidden("max-label-size")
+	// The defaults of the verification flags of pull, run, and create. See helpers.VerifyOptions.
+	helpers.HiddenPersistentStringFlag(rootCmd, "global-verify", cfg.Verify, "Default value of --verify")
+	helpers.HiddenPersistentStringFlag(rootCmd, "global-cosign-key", cfg.CosignKey, "Default value of --cosign-key")
+	helpers.HiddenPersistentStringFlag(rootCmd, "global-cosign-certificate-identity", cfg.CosignCertificateIdentity, "Default value of --cosign-certificate-identity")
+	helpers.HiddenPersistentStringFlag(rootCmd, "global-cosign-certificate-identity-regexp", cfg.CosignCertificateIdentityRegexp, "Default value of --cosign-certificate-identity-regexp")
+	helpers.HiddenPersistentStringFlag(rootCmd, "global-cosign-certificate-oidc-issuer", cfg.CosignCertificateOidcIssuer, "Default value of --cosign-certificate-oidc-issuer")
+	helpers.HiddenPersistentStringFlag(rootCmd, "global-cosign-certificate-oidc-issuer-regexp", cfg.CosignCertificateOidcIssuerRegexp, "Default value of --cosign-certificate-oidc-issuer-regexp")
+	// The default of the --detach-keys flag of run, start, and attach. See helpers.DetachKeys.
+	helpers.HiddenPersistentStringFlag(rootCmd, "global-detach-keys", cfg.DetachKeys, "Default value of --detach-keys")
+	helpers.HiddenPersistentStringFlag(rootCmd, "min-health-interval", cfg.MinHealthInterval, "Minimum interval between health check probes (0s to disable the check)")
+	rootCmd.PersistentFlags().Bool("provenance-annotations", cfg.ProvenanceAnnotations, "Annotate the containers with the nerdctl version, the creation time, and the invoking user")
+	rootCmd.PersistentFlags().MarkHidden("provenance-annotations")
 	return aliasToBeInherited, nil
 }
 
@@ -130,3 +130,65 @@ version = 2`),
 
 	testCase.Run(t)
 }
+
+func TestNerdctlConfigVerify(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("pull", "--quiet", testutil.CommonImage)
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "TOML applies to run",
+			Command:     test.Command("run", "--rm", testutil.CommonImage, "true"),
+			Expected:    test.Expects(1, []error{errors.New("no verifiers found: dummy-verifier")}, nil),
+			Config:      test.WithConfig(nerdtest.NerdctlToml, `verify = "dummy-verifier"`),
+		},
+		{
+			Description: "TOML applies to pull",
+			Command:     test.Command("pull", "--quiet", testutil.CommonImage),
+			Expected:    test.Expects(1, []error{errors.New("no verifiers found: dummy-verifier")}, nil),
+			Config:      test.WithConfig(nerdtest.NerdctlToml, `verify = "dummy-verifier"`),
+		},
+		{
+			Description: "Cli > TOML",
+			Command:     test.Command("run", "--rm", "--verify=none", testutil.CommonImage, "true"),
+			Expected:    test.Expects(0, nil, nil),
+			Config:      test.WithConfig(nerdtest.NerdctlToml, `verify = "dummy-verifier"`),
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestNerdctlConfigDetachKeys(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "Invalid TOML is rejected at load time",
+			Command:     test.Command("info"),
+			Expected:    test.Expects(1, []error{errors.New("detach_keys"), errors.New("invalid detach keys")}, nil),
+			Config:      test.WithConfig(nerdtest.NerdctlToml, `detach_keys = "ctrl-p,not-a-key"`),
+		},
+		{
+			Description: "Valid TOML",
+			Command:     test.Command("info", "-f", "{{.Driver}}"),
+			Expected:    test.Expects(0, nil, nil),
+			Config:      test.WithConfig(nerdtest.NerdctlToml, `detach_keys = "ctrl-a,x"`),
+		},
+		{
+			Description: "Invalid Cli is rejected",
+			Command:     test.Command("run", "--rm", "--detach-keys=not-a-key", testutil.CommonImage, "true"),
+			Expected:    test.Expects(1, []error{errors.New("invalid detach keys")}, nil),
+			Config:      test.WithConfig(nerdtest.NerdctlToml, `detach_keys = "ctrl-a,x"`),
+		},
+	}
+
+	testCase.Run(t)
+}
@@ -107,6 +107,30 @@ func TestNetworkCreate(t *testing.T) {
 				}
 			},
 		},
+		{
+			Description: "macvlan with subnet and gateway",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("network", "create", data.Identifier(), "--driver", "macvlan",
+					"--opt", "macvlan_mode=bridge", "--subnet", "10.5.123.0/24", "--gateway", "10.5.123.1")
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("network", "rm", data.Identifier())
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "--net", data.Identifier(), testutil.CommonImage, "ip", "addr", "show", "dev", "eth0")
+			},
+			Expected: test.Expects(0, nil, expect.Contains("inet 10.5.123.")),
+		},
+		{
+			Description: "ipvlan with non-existent parent",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("network", "create", data.Identifier(), "--driver", "ipvlan", "--opt", "parent=nonexistent0")
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("network", "rm", data.Identifier())
+			},
+			Expected: test.Expects(1, nil, nil),
+		},
 	}
 
 	testCase.Run(t)
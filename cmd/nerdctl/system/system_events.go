@@ -17,6 +17,8 @@
 package system
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 
 	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
@@ -39,9 +41,10 @@ func EventsCommand() *cobra.Command {
 	}
 	cmd.Flags().String("format", "", "Format the output using the given Go template, e.g, '{{json .}}'")
 	cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		return []string{"json"}, cobra.ShellCompDirectiveNoFileComp
+		return []string{"json", "{{.Timestamp}} {{.Topic}} {{.ID}}"}, cobra.ShellCompDirectiveNoFileComp
 	})
 	cmd.Flags().StringSliceP("filter", "f", []string{}, "Filter matches containers based on given conditions")
+	cmd.Flags().Duration("heartbeat", 0, "Emit a heartbeat event when no event has been emitted for the given duration, e.g., '30s' (0 to disable)")
 	return cmd
 }
 
@@ -58,11 +61,19 @@ func eventsOptions(cmd *cobra.Command) (types.SystemEventsOptions, error) {
 	if err != nil {
 		return types.SystemEventsOptions{}, err
 	}
+	heartbeat, err := cmd.Flags().GetDuration("heartbeat")
+	if err != nil {
+		return types.SystemEventsOptions{}, err
+	}
+	if heartbeat < 0 {
+		return types.SystemEventsOptions{}, fmt.Errorf("invalid --heartbeat %q: must not be negative", heartbeat)
+	}
 	return types.SystemEventsOptions{
-		Stdout:   cmd.OutOrStdout(),
-		GOptions: globalOptions,
-		Format:   format,
-		Filters:  filters,
+		Stdout:    cmd.OutOrStdout(),
+		GOptions:  globalOptions,
+		Format:    format,
+		Filters:   filters,
+		Heartbeat: heartbeat,
 	}, nil
 }
 
@@ -119,3 +119,19 @@ func TestEventFilters(t *testing.T) {
 
 	testCase.Run(t)
 }
+
+func TestEventsHeartbeat(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		cmd := helpers.Command("events", "--heartbeat", "1s", "--filter", "event=start", "--format", "{{.Status}} {{.Topic}}")
+		cmd.WithTimeout(5 * time.Second)
+		return cmd
+	}
+
+	testCase.Expected = test.Expects(expect.ExitCodeTimeout, nil, expect.Contains("heartbeat /nerdctl/heartbeat\n"))
+
+	testCase.Run(t)
+}
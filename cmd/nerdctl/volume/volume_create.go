@@ -18,6 +18,7 @@ package volume
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -26,6 +27,7 @@ import (
 	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/cmd/volume"
+	"github.com/containerd/nerdctl/v2/pkg/volumedriver"
 )
 
 func createCommand() *cobra.Command {
@@ -38,6 +40,8 @@ func createCommand() *cobra.Command {
 		SilenceErrors: true,
 	}
 	cmd.Flags().StringArray("label", nil, "Set a label on the volume")
+	cmd.Flags().StringP("driver", "d", "local", "Specify volume driver name")
+	cmd.Flags().StringArrayP("opt", "o", nil, "Set driver specific options")
 	return cmd
 }
 
@@ -56,9 +60,31 @@ func createOptions(cmd *cobra.Command) (types.VolumeCreateOptions, error) {
 		}
 	}
 
+	driver, err := cmd.Flags().GetString("driver")
+	if err != nil {
+		return types.VolumeCreateOptions{}, err
+	}
+	opts, err := cmd.Flags().GetStringArray("opt")
+	if err != nil {
+		return types.VolumeCreateOptions{}, err
+	}
+	if len(opts) > 0 && volumedriver.IsLocal(driver) {
+		return types.VolumeCreateOptions{}, fmt.Errorf("options are not supported by the %q volume driver (%w)", volumedriver.LocalDriverName, errdefs.ErrInvalidArgument)
+	}
+	options := make(map[string]string, len(opts))
+	for _, opt := range opts {
+		k, v, ok := strings.Cut(opt, "=")
+		if !ok || k == "" {
+			return types.VolumeCreateOptions{}, fmt.Errorf("invalid option %q: must be key=value (%w)", opt, errdefs.ErrInvalidArgument)
+		}
+		options[k] = v
+	}
+
 	return types.VolumeCreateOptions{
 		GOptions: globalOptions,
 		Labels:   labels,
+		Driver:   driver,
+		Options:  options,
 		Stdout:   cmd.OutOrStdout(),
 	}, nil
 }
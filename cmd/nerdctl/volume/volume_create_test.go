@@ -23,6 +23,7 @@ import (
 
 	"github.com/containerd/errdefs"
 	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
 	"github.com/containerd/nerdctl/mod/tigron/test"
 
 	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
@@ -87,6 +88,27 @@ func TestVolumeCreate(t *testing.T) {
 			// NOTE: docker returns 125 on this
 			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errdefs.ErrInvalidArgument}, nil),
 		},
+		{
+			Description: "options with the local driver should fail",
+			Require:     require.Not(nerdtest.Docker),
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("volume", "create", "--driver", "local", "-o", "foo=bar", data.Identifier())
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("volume", "rm", "-f", data.Identifier())
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errdefs.ErrInvalidArgument}, nil),
+		},
+		{
+			Description: "unknown driver should fail",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("volume", "create", "--driver", "nonexistent", data.Identifier())
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("volume", "rm", "-f", data.Identifier())
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, nil, nil),
+		},
 		{
 			Description: "creating already existing volume should succeed",
 			Setup: func(data test.Data, helpers test.Helpers) {
@@ -20,7 +20,11 @@ import (
 	"strings"
 	"testing"
 
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/errdefs"
 	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
 	"github.com/containerd/nerdctl/mod/tigron/test"
 	"github.com/containerd/nerdctl/mod/tigron/tig"
 
@@ -107,6 +111,98 @@ func TestVolumePrune(t *testing.T) {
 				}
 			},
 		},
+		{
+			Description: "prune auto-created volumes only",
+			NoParallel:  true,
+			Require:     require.Not(nerdtest.Docker),
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("volume", "create", data.Identifier("explicit"))
+				helpers.Ensure("run", "--rm", "-v", data.Identifier("auto")+":/auto", "-v", data.Identifier("explicit")+":/explicit", testutil.CommonImage)
+				// only the auto-created volume has the label
+				helpers.Command("volume", "ls", "-q", "--filter", "label=nerdctl/auto-created=true").Run(&test.Expected{
+					Output: expect.All(
+						expect.Contains(data.Identifier("auto")),
+						expect.DoesNotContain(data.Identifier("explicit")),
+					),
+				})
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("volume", "rm", "-f", data.Identifier("auto"))
+				helpers.Anyhow("volume", "rm", "-f", data.Identifier("explicit"))
+			},
+			Command: test.Command("volume", "prune", "-f", "--all", "--filter", "label=nerdctl/auto-created=true"),
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.All(
+						expect.Contains(data.Identifier("auto")),
+						expect.DoesNotContain(data.Identifier("explicit")),
+						func(stdout string, t tig.T) {
+							helpers.Fail("volume", "inspect", data.Identifier("auto"))
+							helpers.Ensure("volume", "inspect", data.Identifier("explicit"))
+						},
+					),
+				}
+			},
+		},
+		{
+			Description: "invalid filter should fail",
+			Require:     require.Not(nerdtest.Docker),
+			Command:     test.Command("volume", "prune", "-f", "--filter", "dangling=true"),
+			Expected:    test.Expects(expect.ExitCodeGenericFail, []error{errdefs.ErrInvalidArgument}, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestVolumeAutoCreatedLabels(t *testing.T) {
+	testCase := nerdtest.Setup()
+	testCase.Require = require.Not(nerdtest.Docker)
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("create", "--name", data.Identifier(), "-v", data.Identifier()+":/data", testutil.CommonImage)
+		data.Labels().Set("cID", strings.TrimSpace(helpers.Capture("inspect", "--format", "{{.ID}}", data.Identifier())))
+	}
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+		helpers.Anyhow("volume", "rm", "-f", data.Identifier())
+	}
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		return helpers.Command("volume", "inspect", "--format", "{{json .Labels}}", data.Identifier())
+	}
+	testCase.Expected = func(data test.Data, helpers test.Helpers) *test.Expected {
+		return &test.Expected{
+			Output: expect.JSON(map[string]string{}, func(volLabels map[string]string, t tig.T) {
+				assert.Equal(t, volLabels["nerdctl/auto-created"], "true")
+				assert.Equal(t, volLabels["nerdctl/created-by"], data.Labels().Get("cID"))
+			}),
+		}
+	}
+
+	testCase.Run(t)
+}
+
+func TestVolumeMountLabels(t *testing.T) {
+	testCase := nerdtest.Setup()
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("run", "--rm",
+			"--mount", "type=volume,src="+data.Identifier("labeled")+",dst=/labeled,volume-label=project="+data.Identifier(),
+			"--mount", "type=volume,src="+data.Identifier("other")+",dst=/other",
+			testutil.CommonImage)
+	}
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("volume", "rm", "-f", data.Identifier("labeled"))
+		helpers.Anyhow("volume", "rm", "-f", data.Identifier("other"))
+	}
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		return helpers.Command("volume", "ls", "-q", "--filter", "label=project="+data.Identifier())
+	}
+	testCase.Expected = func(data test.Data, helpers test.Helpers) *test.Expected {
+		return &test.Expected{
+			Output: expect.All(
+				expect.Contains(data.Identifier("labeled")),
+				expect.DoesNotContain(data.Identifier("other")),
+			),
+		}
 	}
 
 	testCase.Run(t)
@@ -33,6 +33,22 @@ const (
 	// Bypass4netnsIgnoreBind disables acceleration for bind.
 	// Boolean value which can be parsed with strconv.ParseBool() is required.
 	Bypass4netnsIgnoreBind = Bypass4netns + "-ignore-bind"
+
+	// GPUFraction is the fraction of the GPU time that a time-slicing-aware device plugin or runtime
+	// should allocate to the container (like "nerdctl/gpu-fraction=0.25").
+	// Set by `nerdctl run --gpus fraction=<fraction>`; the kernel itself does not partition the GPU.
+	GPUFraction = Prefix + "gpu-fraction"
+
+	// CreatedByVersion is the version of nerdctl that created the container (like "nerdctl/created-by-version=v2.2.0").
+	// CreatedByVersion, CreatedAt, and CreatedByUser are only set when `provenance_annotations` is enabled in nerdctl.toml.
+	CreatedByVersion = Prefix + "created-by-version"
+
+	// CreatedAt is the creation time of the container, in RFC 3339 format.
+	CreatedAt = Prefix + "created-at"
+
+	// CreatedByUser is the name of the user who invoked nerdctl to create the container.
+	// In rootless mode, it is the user running rootlesskit, not the root user of the user namespace.
+	CreatedByUser = Prefix + "created-by-user"
 )
 
 var ShellCompletions = []string{
@@ -26,6 +26,8 @@ type NetworkOptions struct {
 	NetworkSlice []string
 	// MACAddress set container MAC address (e.g., 92:d0:c6:0a:29:33)
 	MACAddress string
+	// MTU sets the MTU of the container network interfaces. 0 keeps the MTU configured by the CNI plugins.
+	MTU int
 	// IPAddress set specific static IP address(es) to use
 	IPAddress string
 	// IP6Address set specific static IP6 address(es) to use
@@ -42,8 +44,16 @@ type NetworkOptions struct {
 	DNSSearchDomains []string
 	// AddHost add a custom host-to-IP mapping (host:ip)
 	AddHost []string
+	// NoResolv disables the management of /etc/resolv.conf (--no-resolv, --dns=none)
+	NoResolv bool
+	// NoHosts disables the management of /etc/hosts
+	NoHosts bool
 	// UTS namespace to use
 	UTSNamespace string
 	// PortMappings specifies a list of ports to publish from the container to the host
 	PortMappings []cni.PortMapping
+	// PublishAll publishes all the ports exposed by the image and by Expose to ephemeral host ports
+	PublishAll bool
+	// Expose specifies ports (or ranges) to expose without publishing them, like "80" or "8000-8010/udp"
+	Expose []string
 }
@@ -74,6 +74,8 @@ type ContainerCreateOptions struct {
 	Attach []string
 	// Restart specifies the policy to apply when a container exits
 	Restart string
+	// RestartMinUptime is the minimum uptime for a restarted container; earlier exits are restarted with a backoff
+	RestartMinUptime time.Duration
 	// Rm specifies whether to remove the container automatically when it exits
 	Rm bool
 	// Pull image before running, default is missing
@@ -84,6 +86,8 @@ type ContainerCreateOptions struct {
 	StopSignal string
 	// StopTimeout specifies the timeout (in seconds) to stop a container
 	StopTimeout int
+	// StopTimeoutChanged specifies whether the stop timeout has been set, as 0 means killing the container immediately
+	StopTimeoutChanged bool
 	// #endregion
 
 	// #region for platform flags
@@ -96,6 +100,8 @@ type ContainerCreateOptions struct {
 	InitProcessFlag bool
 	// InitBinary specifies the custom init binary to use, default is tini
 	InitBinary *string
+	// InitArgs specifies the additional arguments of the init process, placed after the init binary
+	InitArgs []string
 	// #endregion
 
 	// #region for isolation flags
@@ -110,6 +116,8 @@ type ContainerCreateOptions struct {
 	CPUQuota int64
 	// CPUPeriod limits the CPU CFS (Completely Fair Scheduler) period
 	CPUPeriod uint64
+	// CPUBurst is the CPU time in microseconds that the container can use beyond its quota (cgroup v2 cpu.max.burst)
+	CPUBurst uint64
 	// CPUShares specifies the CPU shares (relative weight)
 	CPUShares uint64
 	// CPUSetCPUs specifies the CPUs in which to allow execution (0-3, 0,1)
@@ -234,6 +242,8 @@ type ContainerCreateOptions struct {
 	Env []string
 	// EnvFile set environment variables from file
 	EnvFile []string
+	// RedactEnv specifies the environment variables whose values are redacted in inspect
+	RedactEnv []string
 	// #endregion
 
 	// #region for metadata flags
@@ -291,6 +301,7 @@ type ContainerCreateOptions struct {
 	HealthRetries       int
 	HealthStartPeriod   time.Duration
 	HealthStartInterval time.Duration
+	HealthEnv           []string
 	NoHealthcheck       bool
 
 	// UserNS name for user namespace mapping of container
@@ -436,8 +447,8 @@ type ContainerLogsOptions struct {
 	// Timestamps specifies whether to show the timestamps of the logs.
 	Timestamps bool
 	// Tail specifies the number of lines to show from the end of the logs.
-	// Specify 0 to show all logs.
-	Tail uint
+	// nil shows all the logs, and 0 shows none of them (only the new ones with Follow).
+	Tail *uint
 	// Show logs since timestamp (e.g., 2013-01-02T13:23:37Z) or relative (e.g., 42m for 42 minutes).
 	Since string
 	// Show logs before a timestamp (e.g., 2013-01-02T13:23:37Z) or relative (e.g., 42m for 42 minutes).
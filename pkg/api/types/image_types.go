@@ -18,6 +18,7 @@ package types
 
 import (
 	"io"
+	"time"
 
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 )
@@ -230,6 +231,12 @@ type ImagePullOptions struct {
 	IPFSAddress string
 	// Flags to pass into remote snapshotters
 	RFlags RemoteSnapshotterFlags
+	// RegistryMirrors are tried, in order, before the registry of the image
+	RegistryMirrors []string
+	// Retry is the number of times a failed pull is retried. 0 disables retries.
+	Retry int
+	// RetryDelay is the delay between the pull attempts
+	RetryDelay time.Duration
 }
 
 // ImageTagOptions specifies options for `nerdctl (image) tag`.
@@ -16,7 +16,10 @@
 
 package types
 
-import "io"
+import (
+	"io"
+	"time"
+)
 
 // SystemInfoOptions specifies options for `nerdctl (system) info`.
 type SystemInfoOptions struct {
@@ -39,6 +42,8 @@ type SystemEventsOptions struct {
 	Format string
 	// Filter events based on given conditions
 	Filters []string
+	// Heartbeat is the idle period after which a heartbeat event is emitted. Disabled when zero.
+	Heartbeat time.Duration
 }
 
 // SystemPruneOptions specifies options for `nerdctl system prune`.
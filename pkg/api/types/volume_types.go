@@ -24,6 +24,10 @@ type VolumeCreateOptions struct {
 	GOptions GlobalCommandOptions
 	// Labels are the volume labels
 	Labels []string
+	// Driver is the volume driver, "local" by default
+	Driver string
+	// Options are the driver specific options
+	Options map[string]string
 }
 
 // VolumeInspectOptions specifies options for `nerdctl volume inspect`.
@@ -58,6 +62,8 @@ type VolumePruneOptions struct {
 	All bool
 	// Do not prompt for confirmation
 	Force bool
+	// Filters matches the labels of the volumes to remove
+	Filters []string
 }
 
 // VolumeRemoveOptions specifies options for `nerdctl volume rm`.
@@ -68,7 +68,7 @@ func NewClientWithPlatform(ctx context.Context, namespace, address, platform str
 				log.L.Warn(warn)
 			}
 		}
-		platformParsed, err := platforms.Parse(platform)
+		platformParsed, err := platformutil.Parse(platform)
 		if err != nil {
 			return nil, nil, nil, err
 		}
@@ -18,6 +18,7 @@ package builder
 
 import (
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -25,6 +26,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -224,24 +226,31 @@ func generateBuildctlArgs(ctx context.Context, client *containerd.Client, option
 			needsLoading = true
 		}
 	} else {
-		if !strings.Contains(output, "type=") {
-			// should accept --output <DIR> as an alias of --output
-			// type=local,dest=<DIR>
-			output = fmt.Sprintf("type=local,dest=%s", output)
-		}
-		if strings.Contains(output, "type=docker") || strings.Contains(output, "type=oci") {
-			if !strings.Contains(output, "dest=") {
-				needsLoading = true
-			}
+		output, err = normalizeBuildOutput(output)
+		if err != nil {
+			return "", nil, false, "", nil, nil, err
 		}
 	}
+	exporter, attrs, err := parseBuildOutput(output)
+	if err != nil {
+		return "", nil, false, "", nil, nil, err
+	}
+	if err = validateBuildOutput(exporter, attrs, len(options.Tag) > 0); err != nil {
+		return "", nil, false, "", nil, nil, err
+	}
+	if (exporter == "docker" || exporter == "oci") && attrs["dest"] == "" {
+		needsLoading = true
+	}
+	namedExporter := exporter != "local" && exporter != "tar"
 	if tags = strutil.DedupeStrSlice(options.Tag); len(tags) > 0 {
 		ref := tags[0]
 		parsedReference, err := referenceutil.Parse(ref)
 		if err != nil {
 			return "", nil, false, "", nil, nil, err
 		}
-		output += ",name=" + parsedReference.String()
+		if namedExporter {
+			output += ",name=" + parsedReference.String()
+		}
 
 		// pick the first tag and add it to output
 		for idx, tag := range tags {
@@ -251,9 +260,13 @@ func generateBuildctlArgs(ctx context.Context, client *containerd.Client, option
 			}
 			tags[idx] = parsedReference.String()
 		}
-	} else if len(tags) == 0 {
+	} else if namedExporter {
 		output = output + ",dangling-name-prefix=<none>"
 	}
+	if exporter != "image" && exporter != "registry" && !needsLoading {
+		// the result is not stored in the image store, so there is nothing to tag
+		tags = nil
+	}
 
 	buildctlArgs = buildkitutil.BuildctlBaseArgs(options.BuildKitHost)
 
@@ -425,15 +438,15 @@ func generateBuildctlArgs(ctx context.Context, client *containerd.Client, option
 	}
 
 	for _, s := range strutil.DedupeStrSlice(options.CacheFrom) {
-		if !strings.Contains(s, "type=") {
-			s = "type=registry,ref=" + s
+		if s, err = normalizeBuildCache(s, false); err != nil {
+			return "", nil, false, "", nil, nil, err
 		}
 		buildctlArgs = append(buildctlArgs, "--import-cache="+s)
 	}
 
 	for _, s := range strutil.DedupeStrSlice(options.CacheTo) {
-		if !strings.Contains(s, "type=") {
-			s = "type=registry,ref=" + s
+		if s, err = normalizeBuildCache(s, true); err != nil {
+			return "", nil, false, "", nil, nil, err
 		}
 		buildctlArgs = append(buildctlArgs, "--export-cache="+s)
 	}
@@ -567,6 +580,181 @@ func parseContextNames(values []string) (map[string]string, error) {
 	return result, nil
 }
 
+// buildOutputTypes are the exporter types accepted by --output.
+var buildOutputTypes = []string{"local", "tar", "oci", "docker", "image", "registry"}
+
+// buildOutputKeys are the keys recognizing an --output value as a list of exporter options, rather than a directory.
+var buildOutputKeys = []string{"type", "dest"}
+
+// normalizeBuildOutput expands the shorthand forms accepted by --output:
+// "-" for a tarball written to stdout, and <DIR> for type=local,dest=<DIR>.
+// A value is only taken as a list of options when it has a type= or dest= option, so that a directory
+// containing "=" is not misparsed.
+// A bare exporter type (e.g. "tar") is rejected, as it is more likely a missing "type=" than a directory.
+func normalizeBuildOutput(output string) (string, error) {
+	switch {
+	case output == "-":
+		return "type=tar,dest=-", nil
+	case isBuildOutputAttrs(output):
+		return output, nil
+	case slices.Contains(buildOutputTypes, output):
+		return "", fmt.Errorf("ambiguous output %q: specify type=%s for the exporter, or ./%s for a local directory", output, output, output)
+	}
+	// should accept --output <DIR> as an alias of --output
+	// type=local,dest=<DIR>
+	dest := "dest=" + output
+	if strings.ContainsAny(dest, ",\"\n") {
+		// the options are comma-separated values, quoted as in CSV
+		dest = `"` + strings.ReplaceAll(dest, `"`, `""`) + `"`
+	}
+	return "type=local," + dest, nil
+}
+
+// isBuildOutputAttrs returns whether an --output value is a list of exporter options, i.e., whether one of its
+// comma-separated fields has one of buildOutputKeys.
+func isBuildOutputAttrs(output string) bool {
+	fields, err := csv.NewReader(strings.NewReader(output)).Read()
+	if err != nil {
+		return false
+	}
+	return slices.ContainsFunc(fields, func(field string) bool {
+		key, _, ok := strings.Cut(field, "=")
+		return ok && slices.Contains(buildOutputKeys, strings.ToLower(strings.TrimSpace(key)))
+	})
+}
+
+// parseAttrs parses a comma-separated list of key=value pairs, as accepted by --output and --cache-*.
+// Values containing commas can be quoted.
+func parseAttrs(s string) (map[string]string, error) {
+	fields, err := csv.NewReader(strings.NewReader(s)).Read()
+	if err != nil {
+		return nil, err
+	}
+	attrs := make(map[string]string, len(fields))
+	for _, field := range fields {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid option %q, expected key=value", field)
+		}
+		attrs[strings.ToLower(strings.TrimSpace(key))] = value
+	}
+	return attrs, nil
+}
+
+// parseBuildOutput splits an --output value into the exporter type and its attributes.
+func parseBuildOutput(output string) (string, map[string]string, error) {
+	attrs, err := parseAttrs(output)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to parse output %q: %w", output, err)
+	}
+	exporter, ok := attrs["type"]
+	if !ok || exporter == "" {
+		return "", nil, fmt.Errorf("output %q does not specify the exporter type", output)
+	}
+	delete(attrs, "type")
+	return exporter, attrs, nil
+}
+
+// validateBuildOutput checks the attributes of an --output exporter.
+// hasTag reports whether the image was named with -t.
+func validateBuildOutput(exporter string, attrs map[string]string, hasTag bool) error {
+	switch exporter {
+	case "local", "tar":
+		if attrs["dest"] == "" {
+			return fmt.Errorf("output type %q requires the dest option", exporter)
+		}
+		for key := range attrs {
+			if key != "dest" && key != "platform-split" {
+				return fmt.Errorf("output type %q does not support the %q option", exporter, key)
+			}
+		}
+	case "oci", "docker":
+		for _, key := range []string{"push", "unpack"} {
+			if _, ok := attrs[key]; ok {
+				return fmt.Errorf("output type %q does not support the %q option", exporter, key)
+			}
+		}
+	case "image", "registry":
+		if _, ok := attrs["dest"]; ok {
+			return fmt.Errorf("output type %q does not support the %q option", exporter, "dest")
+		}
+		for _, key := range []string{"push", "unpack"} {
+			if value, ok := attrs[key]; ok {
+				if _, err := strconv.ParseBool(value); err != nil {
+					return fmt.Errorf("invalid value %q for the %q option of output type %q", value, key, exporter)
+				}
+			}
+		}
+		push := exporter == "registry"
+		if value, ok := attrs["push"]; ok {
+			push, _ = strconv.ParseBool(value)
+			if exporter == "registry" && !push {
+				return errors.New("output type \"registry\" always pushes, use type=image instead of push=false")
+			}
+		}
+		if push && !hasTag && attrs["name"] == "" {
+			return fmt.Errorf("output type %q with push requires an image name, specify -t or name=", exporter)
+		}
+	default:
+		return fmt.Errorf("unsupported output type %q, expected one of local, tar, oci, docker, image or registry", exporter)
+	}
+	return nil
+}
+
+// normalizeBuildCache validates a --cache-from (export=false) or --cache-to (export=true) value,
+// and expands the <REF> shorthand to type=registry,ref=<REF>.
+// The options of the cache backends that are not known to nerdctl are passed to BuildKit as-is.
+func normalizeBuildCache(cache string, export bool) (string, error) {
+	flagName := "--cache-from"
+	if export {
+		flagName = "--cache-to"
+	}
+	if !strings.Contains(cache, "=") {
+		cache = "type=registry,ref=" + cache
+	}
+	attrs, err := parseAttrs(cache)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s %q: %w", flagName, cache, err)
+	}
+	cacheType, ok := attrs["type"]
+	if !ok {
+		// same default as buildctl
+		cacheType = "registry"
+	}
+	var required string
+	switch cacheType {
+	case "registry":
+		required = "ref"
+	case "local":
+		required = "src"
+		if export {
+			required = "dest"
+		}
+	case "inline":
+		if !export {
+			return "", fmt.Errorf("%s does not support type=inline, import the inline cache with type=registry,ref=<IMAGE>", flagName)
+		}
+		if len(attrs) > 1 {
+			return "", fmt.Errorf("%s type=inline does not take any options", flagName)
+		}
+	case "gha", "s3", "azblob":
+	default:
+		return "", fmt.Errorf("%s: unsupported cache type %q, expected one of registry, local, inline, gha, s3 or azblob", flagName, cacheType)
+	}
+	if required != "" && attrs[required] == "" {
+		return "", fmt.Errorf("%s type=%s requires the %s option", flagName, cacheType, required)
+	}
+	if mode, ok := attrs["mode"]; ok {
+		if !export {
+			return "", fmt.Errorf("%s does not support the mode option", flagName)
+		}
+		if mode != "min" && mode != "max" {
+			return "", fmt.Errorf("%s: invalid mode %q, expected min or max", flagName, mode)
+		}
+	}
+	return cache, nil
+}
+
 var (
 	ErrOCILayoutPrefixNotFound = errors.New("OCI layout prefix not found")
 	ErrOCILayoutEmptyDigest    = errors.New("OCI layout cannot have empty digest")
@@ -238,3 +238,207 @@ func TestParseBuildctlArgsForOCILayout(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeBuildOutput(t *testing.T) {
+	tests := []struct {
+		output   string
+		expected string
+	}{
+		{output: "-", expected: "type=tar,dest=-"},
+		{output: "/tmp/out", expected: "type=local,dest=/tmp/out"},
+		{output: "type=oci,dest=/tmp/out.tar", expected: "type=oci,dest=/tmp/out.tar"},
+		{output: "./tar", expected: "type=local,dest=./tar"},
+		{output: "./out=1", expected: "type=local,dest=./out=1"},
+		{output: "/tmp/a=b,c", expected: `type=local,"dest=/tmp/a=b,c"`},
+		{output: "type=local,dest=./out=1", expected: "type=local,dest=./out=1"},
+		{output: "dest=./out", expected: "dest=./out"},
+	}
+	for _, test := range tests {
+		t.Run(test.output, func(t *testing.T) {
+			output, err := normalizeBuildOutput(test.output)
+			assert.NilError(t, err)
+			assert.Equal(t, output, test.expected)
+		})
+	}
+
+	// A bare exporter type is not taken as a directory
+	for _, output := range []string{"tar", "oci", "registry"} {
+		_, err := normalizeBuildOutput(output)
+		assert.ErrorContains(t, err, "ambiguous output", output)
+	}
+
+	// A directory containing "=" or "," is kept as-is
+	for _, dir := range []string{"./out=1", "/tmp/a=b,c"} {
+		output, err := normalizeBuildOutput(dir)
+		assert.NilError(t, err)
+		exporter, attrs, err := parseBuildOutput(output)
+		assert.NilError(t, err)
+		assert.Equal(t, exporter, "local")
+		assert.DeepEqual(t, attrs, map[string]string{"dest": dir})
+	}
+}
+
+func TestValidateBuildOutput(t *testing.T) {
+	tests := []struct {
+		name        string
+		output      string
+		hasTag      bool
+		expectedErr string
+	}{
+		{
+			name:   "LocalWithDest",
+			output: "type=local,dest=/tmp/out",
+		},
+		{
+			name:        "LocalWithoutDest",
+			output:      "type=local",
+			expectedErr: `output type "local" requires the dest option`,
+		},
+		{
+			name:        "TarWithName",
+			output:      "type=tar,dest=/tmp/out.tar,name=foo",
+			expectedErr: `output type "tar" does not support the "name" option`,
+		},
+		{
+			name:   "OCIWithDest",
+			output: "type=oci,dest=/tmp/out.tar,name=foo",
+		},
+		{
+			name:        "OCIWithPush",
+			output:      "type=oci,push=true",
+			expectedErr: `output type "oci" does not support the "push" option`,
+		},
+		{
+			name:   "ImageWithName",
+			output: `type=image,"name=foo,bar",push=true`,
+		},
+		{
+			name:        "ImageWithInvalidUnpack",
+			output:      "type=image,unpack=yes",
+			expectedErr: `invalid value "yes" for the "unpack" option of output type "image"`,
+		},
+		{
+			name:        "ImagePushWithoutName",
+			output:      "type=image,push=true",
+			expectedErr: `output type "image" with push requires an image name, specify -t or name=`,
+		},
+		{
+			name:   "RegistryWithTag",
+			output: "type=registry",
+			hasTag: true,
+		},
+		{
+			name:        "RegistryWithoutPush",
+			output:      "type=registry,push=false",
+			hasTag:      true,
+			expectedErr: `output type "registry" always pushes, use type=image instead of push=false`,
+		},
+		{
+			name:        "MissingType",
+			output:      "dest=/tmp/out",
+			expectedErr: `output "dest=/tmp/out" does not specify the exporter type`,
+		},
+		{
+			name:        "UnknownType",
+			output:      "type=foo",
+			expectedErr: `unsupported output type "foo", expected one of local, tar, oci, docker, image or registry`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			exporter, attrs, err := parseBuildOutput(test.output)
+			if err == nil {
+				err = validateBuildOutput(exporter, attrs, test.hasTag)
+			}
+			if test.expectedErr == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Error(t, err, test.expectedErr)
+			}
+		})
+	}
+}
+
+func TestNormalizeBuildCache(t *testing.T) {
+	tests := []struct {
+		name        string
+		cache       string
+		export      bool
+		expected    string
+		expectedErr string
+	}{
+		{
+			name:     "RegistryShorthand",
+			cache:    "example.com/app:cache",
+			expected: "type=registry,ref=example.com/app:cache",
+		},
+		{
+			name:     "RegistryExportMax",
+			cache:    "type=registry,ref=example.com/app:cache,mode=max",
+			export:   true,
+			expected: "type=registry,ref=example.com/app:cache,mode=max",
+		},
+		{
+			name:        "RegistryWithoutRef",
+			cache:       "type=registry",
+			expectedErr: "--cache-from type=registry requires the ref option",
+		},
+		{
+			name:     "LocalImport",
+			cache:    "type=local,src=/tmp/cache",
+			expected: "type=local,src=/tmp/cache",
+		},
+		{
+			name:        "LocalExportWithoutDest",
+			cache:       "type=local,src=/tmp/cache",
+			export:      true,
+			expectedErr: "--cache-to type=local requires the dest option",
+		},
+		{
+			name:     "InlineExport",
+			cache:    "type=inline",
+			export:   true,
+			expected: "type=inline",
+		},
+		{
+			name:        "InlineImport",
+			cache:       "type=inline",
+			expectedErr: "--cache-from does not support type=inline, import the inline cache with type=registry,ref=<IMAGE>",
+		},
+		{
+			name:        "InlineWithOptions",
+			cache:       "type=inline,mode=max",
+			export:      true,
+			expectedErr: "--cache-to type=inline does not take any options",
+		},
+		{
+			name:        "InvalidMode",
+			cache:       "type=local,dest=/tmp/cache,mode=all",
+			export:      true,
+			expectedErr: `--cache-to: invalid mode "all", expected min or max`,
+		},
+		{
+			name:        "ModeOnImport",
+			cache:       "type=local,src=/tmp/cache,mode=max",
+			expectedErr: "--cache-from does not support the mode option",
+		},
+		{
+			name:        "UnknownType",
+			cache:       "type=foo",
+			expectedErr: `--cache-from: unsupported cache type "foo", expected one of registry, local, inline, gha, s3 or azblob`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cache, err := normalizeBuildCache(test.cache, test.export)
+			if test.expectedErr == "" {
+				assert.NilError(t, err)
+				assert.Equal(t, cache, test.expected)
+			} else {
+				assert.Error(t, err, test.expectedErr)
+			}
+		})
+	}
+}
@@ -17,26 +17,35 @@
 package container
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"math"
 	"net/url"
 	"os"
 	"os/exec"
+	"os/user"
 	"path/filepath"
 	"reflect"
 	"runtime"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	dockercliopts "github.com/docker/cli/opts"
+	"github.com/docker/go-connections/nat"
+	"github.com/docker/go-units"
 	"github.com/opencontainers/runtime-spec/specs-go"
 
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/containerd/v2/core/containers"
 	"github.com/containerd/containerd/v2/pkg/cio"
 	"github.com/containerd/containerd/v2/pkg/oci"
+	"github.com/containerd/go-cni"
 	"github.com/containerd/log"
 
 	"github.com/containerd/nerdctl/v2/pkg/annotations"
@@ -48,6 +57,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/dnsutil/hostsstore"
 	"github.com/containerd/nerdctl/v2/pkg/flagutil"
 	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
+	"github.com/containerd/nerdctl/v2/pkg/identifiers"
 	"github.com/containerd/nerdctl/v2/pkg/idgen"
 	"github.com/containerd/nerdctl/v2/pkg/imgutil"
 	"github.com/containerd/nerdctl/v2/pkg/imgutil/load"
@@ -59,17 +69,25 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/maputil"
 	"github.com/containerd/nerdctl/v2/pkg/mountutil"
 	"github.com/containerd/nerdctl/v2/pkg/namestore"
+	"github.com/containerd/nerdctl/v2/pkg/netutil/nettype"
 	"github.com/containerd/nerdctl/v2/pkg/netutil/networkstore"
 	"github.com/containerd/nerdctl/v2/pkg/platformutil"
 	"github.com/containerd/nerdctl/v2/pkg/portutil"
 	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
 	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
+	"github.com/containerd/nerdctl/v2/pkg/signalutil"
 	"github.com/containerd/nerdctl/v2/pkg/store"
 	"github.com/containerd/nerdctl/v2/pkg/strutil"
+	"github.com/containerd/nerdctl/v2/pkg/version"
+	"github.com/containerd/nerdctl/v2/pkg/volumedriver"
 )
 
 // Create will create a container.
 func Create(ctx context.Context, client *containerd.Client, args []string, netManager containerutil.NetworkOptionsManager, options types.ContainerCreateOptions) (containerd.Container, func(), error) {
+	if options.Rm && options.Restart != "" && options.Restart != "no" {
+		return nil, nil, errors.New("flags --restart and --rm cannot be specified together")
+	}
+
 	// Acquire an exclusive lock on the volume store until we are done to avoid being raced by any other
 	// volume operations (or any other operation involving volume manipulation)
 	volStore, err := volume.Store(options.GOptions.Namespace, options.GOptions.DataRoot, options.GOptions.Address)
@@ -89,6 +107,15 @@ func Create(ctx context.Context, client *containerd.Client, args []string, netMa
 		newArg = append(newArg, args[2:]...)
 		args = newArg
 	}
+	// When the same key is specified multiple times, the last value wins
+	annotationMap := strutil.ConvertKVStringsToMap(options.Annotations)
+	if err := validateAnnotations(annotationMap); err != nil {
+		return nil, nil, err
+	}
+	if options.GOptions.ProvenanceAnnotations {
+		maps.Copy(annotationMap, provenanceAnnotations())
+	}
+
 	var internalLabels internalLabels
 	internalLabels.platform = options.Platform
 	internalLabels.namespace = options.GOptions.Namespace
@@ -122,6 +149,17 @@ func Create(ctx context.Context, client *containerd.Client, args []string, netMa
 		oci.WithDefaultSpec(),
 	)
 
+	if len(options.Sysctl) > 0 {
+		netType, err := nettype.Detect(netManager.NetworkOptions().NetworkSlice)
+		if err != nil {
+			return nil, generateRemoveStateDirFunc(ctx, id, internalLabels), err
+		}
+		if err := validateSysctls(strutil.ConvertKVStringsToMap(options.Sysctl), netType == nettype.Host,
+			strings.ToLower(options.IPC) == "host", netManager.NetworkOptions().UTSNamespace == "host"); err != nil {
+			return nil, generateRemoveStateDirFunc(ctx, id, internalLabels), err
+		}
+	}
+
 	platformOpts, err := setPlatformOptions(ctx, client, id, netManager.NetworkOptions().UTSNamespace, &internalLabels, options)
 	if err != nil {
 		return nil, generateRemoveStateDirFunc(ctx, id, internalLabels), err
@@ -194,6 +232,10 @@ func Create(ctx context.Context, client *containerd.Client, args []string, netMa
 		internalLabels.user = options.User
 	}
 
+	if options.CPUs > 0 {
+		internalLabels.nanoCPUs = int64(math.Round(options.CPUs * 1e9))
+	}
+
 	rootfsOpts, rootfsCOpts, err := generateRootfsOpts(args, id, ensuredImage, options)
 	if err != nil {
 		return nil, generateRemoveStateDirFunc(ctx, id, internalLabels), err
@@ -208,10 +250,11 @@ func Create(ctx context.Context, client *containerd.Client, args []string, netMa
 			} else if rootlessutil.IsRootless() {
 				return nil, generateRemoveStateDirFunc(ctx, id, internalLabels), errors.New("UserNS is only supported in Rootful Linux")
 			}
-			userNameSpaceOpts, userNameSpaceCOpts, err := getUserNamespaceOpts(ctx, client, &options, *ensuredImage, id)
+			userNameSpaceOpts, userNameSpaceCOpts, releaseUserns, err := getUserNamespaceOpts(ctx, client, &options, *ensuredImage, id, dataStore)
 			if err != nil {
 				return nil, generateRemoveStateDirFunc(ctx, id, internalLabels), err
 			}
+			defer releaseUserns()
 			opts = append(opts, userNameSpaceOpts...)
 			cOpts = append(cOpts, userNameSpaceCOpts...)
 
@@ -238,6 +281,12 @@ func Create(ctx context.Context, client *containerd.Client, args []string, netMa
 	if err != nil {
 		return nil, generateRemoveStateDirFunc(ctx, id, internalLabels), err
 	}
+	for _, k := range options.RedactEnv {
+		if k == "" || strings.Contains(k, "=") {
+			return nil, generateRemoveStateDirFunc(ctx, id, internalLabels), fmt.Errorf("invalid environment variable name %q for --redact-env", k)
+		}
+	}
+	internalLabels.redactedEnv = strutil.DedupeStrSlice(options.RedactEnv)
 
 	if options.Interactive {
 		if options.Detach {
@@ -250,7 +299,7 @@ func Create(ctx context.Context, client *containerd.Client, args []string, netMa
 	}
 
 	var mountOpts []oci.SpecOpts
-	mountOpts, internalLabels.anonVolumes, internalLabels.mountPoints, err = generateMountOpts(ctx, client, ensuredImage, volStore, options)
+	mountOpts, internalLabels.anonVolumes, internalLabels.mountPoints, err = generateMountOpts(ctx, client, ensuredImage, &containerVolumeStore{VolumeStore: volStore, containerID: id}, internalLabels.stateDir, options)
 	if err != nil {
 		return nil, generateRemoveStateDirFunc(ctx, id, internalLabels), err
 	}
@@ -272,11 +321,24 @@ func Create(ctx context.Context, client *containerd.Client, args []string, netMa
 		internalLabels.logConfig.Driver = "json-file"
 	}
 
-	restartOpts, err := generateRestartOpts(ctx, client, options.Restart, logConfig.LogURI, options.InRun)
+	restartPolicy, restartExitCodes, err := parseRestartExitCodes(options.Restart)
+	if err != nil {
+		return nil, generateRemoveStateDirFunc(ctx, id, internalLabels), err
+	}
+	if len(restartExitCodes) > 0 && logConfig.Driver == "" {
+		// The exit codes are applied by the logging process of nerdctl, which is not used with a custom log URI
+		return nil, generateRemoveStateDirFunc(ctx, id, internalLabels), fmt.Errorf("restart exit codes are not supported with --log-driver=%s", options.LogDriver)
+	}
+	restartOpts, err := generateRestartOpts(ctx, client, restartPolicy, logConfig.LogURI, options.InRun)
 	if err != nil {
 		return nil, generateRemoveStateDirFunc(ctx, id, internalLabels), err
 	}
 	cOpts = append(cOpts, restartOpts...)
+	internalLabels.restartExitCodes = restartExitCodes
+	if err := validateRestartMinUptime(options.Restart, options.RestartMinUptime); err != nil {
+		return nil, generateRemoveStateDirFunc(ctx, id, internalLabels), err
+	}
+	internalLabels.restartMinUptime = options.RestartMinUptime
 
 	if err = netManager.VerifyNetworkOptions(ctx); err != nil {
 		return nil, generateRemoveStateDirFunc(ctx, id, internalLabels), fmt.Errorf("failed to verify networking settings: %w", err)
@@ -294,9 +356,31 @@ func Create(ctx context.Context, client *containerd.Client, args []string, netMa
 		return nil, generateRemoveOrphanedDirsFunc(ctx, id, dataStore, internalLabels), fmt.Errorf("failed to generate internal networking labels: %w", err)
 	}
 
-	envs = append(envs, "HOSTNAME="+netLabelOpts.Hostname)
+	// HOSTNAME can be overridden with --env
+	if !slices.ContainsFunc(envs, func(e string) bool { return strings.HasPrefix(e, "HOSTNAME=") || e == "HOSTNAME" }) {
+		envs = append(envs, "HOSTNAME="+netLabelOpts.Hostname)
+	}
 	opts = append(opts, oci.WithEnv(envs))
 
+	var imageExposedPorts map[string]struct{}
+	if ensuredImage != nil {
+		imageExposedPorts = ensuredImage.ImageConfig.ExposedPorts
+	}
+	exposedPorts, err := mergeExposedPorts(imageExposedPorts, netLabelOpts.Expose)
+	if err != nil {
+		return nil, generateRemoveOrphanedDirsFunc(ctx, id, dataStore, internalLabels), err
+	}
+	internalLabels.exposedPorts = exposedPorts
+
+	if netLabelOpts.PublishAll {
+		if netType, err := nettype.Detect(netLabelOpts.NetworkSlice); err == nil && netType == nettype.CNI {
+			netLabelOpts.PortMappings, err = publishExposedPorts(netLabelOpts.PortMappings, exposedPorts)
+			if err != nil {
+				return nil, generateRemoveOrphanedDirsFunc(ctx, id, dataStore, internalLabels), err
+			}
+		}
+	}
+
 	internalLabels.loadNetOpts(netLabelOpts)
 
 	// NOTE: OCI hooks are currently not supported on Windows so we skip setting them altogether.
@@ -345,12 +429,16 @@ func Create(ctx context.Context, client *containerd.Client, args []string, netMa
 		internalLabels.healthcheck = healthcheckConfig
 	}
 
-	lCOpts, err := withContainerLabels(options.Label, options.LabelFile, ensuredImage)
+	lCOpts, err := withContainerLabels(options.Label, options.LabelFile, ensuredImage, options.GOptions.MaxLabelSize)
 	if err != nil {
 		return nil, generateRemoveOrphanedDirsFunc(ctx, id, dataStore, internalLabels), err
 	}
 	cOpts = append(cOpts, lCOpts...)
 
+	if options.Name != "" {
+		warnNonDNSLabelName(ctx, options.Name, netLabelOpts.NetworkSlice)
+	}
+
 	var containerNameStore namestore.NameStore
 	if options.Name == "" {
 		var imageRef string
@@ -373,6 +461,13 @@ func Create(ctx context.Context, client *containerd.Client, args []string, netMa
 		return nil, generateRemoveOrphanedDirsFunc(ctx, id, dataStore, internalLabels), err
 	}
 
+	if err := reserveStaticIP(ctx, client, dataStore, options.GOptions.Namespace, id, internalLabels.ipAddress, internalLabels.networks); err != nil {
+		if errE := containerNameStore.Release(options.Name, id); errE != nil {
+			log.G(ctx).WithError(errE).Warnf("failed to release container name %q (%s)", options.Name, id)
+		}
+		return nil, generateRemoveOrphanedDirsFunc(ctx, id, dataStore, internalLabels), err
+	}
+
 	internalLabels.name = options.Name
 	internalLabels.pidFile = options.PidFile
 
@@ -400,7 +495,7 @@ func Create(ctx context.Context, client *containerd.Client, args []string, netMa
 	}
 
 	opts = append(opts, propagateInternalContainerdLabelsToOCIAnnotations(),
-		oci.WithAnnotations(strutil.ConvertKVStringsToMap(options.Annotations)))
+		oci.WithAnnotations(annotationMap))
 
 	var s specs.Spec
 	spec := containerd.WithSpec(&s, opts...)
@@ -469,6 +564,8 @@ func generateRootfsOpts(args []string, id string, ensured *imgutil.EnsuredImage,
 		if !options.Rootfs {
 			opts = append(opts, oci.WithImageConfig(ensured.Image))
 		}
+		// Like Docker, overriding the entrypoint also discards the CMD of the image,
+		// so only the user-provided command (if any) is passed to the new entrypoint.
 		var processArgs []string
 		if len(options.Entrypoint) != 0 {
 			processArgs = append(processArgs, options.Entrypoint...)
@@ -491,6 +588,11 @@ func generateRootfsOpts(args []string, id string, ensured *imgutil.EnsuredImage,
 		entrypointPath == "/usr/local/sbin/init")
 
 	stopSignal := options.StopSignal
+	if stopSignal != "" {
+		if _, err := signalutil.ParseSignal(stopSignal); err != nil {
+			return nil, nil, fmt.Errorf("invalid --stop-signal: %w", err)
+		}
+	}
 
 	if options.Systemd == "always" || (options.Systemd == "true" && isEntryPointSystemd) {
 		if options.Privileged {
@@ -519,11 +621,14 @@ func generateRootfsOpts(args []string, id string, ensured *imgutil.EnsuredImage,
 		stopSignal = "SIGRTMIN+3"
 	}
 
-	cOpts = append(cOpts, withStop(stopSignal, options.StopTimeout, ensured))
+	cOpts = append(cOpts, withStop(stopSignal, options.StopTimeout, options.StopTimeoutChanged, ensured))
 
 	if options.InitBinary != nil {
 		options.InitProcessFlag = true
 	}
+	if err := validateInitArgs(options.InitArgs, options.InitProcessFlag); err != nil {
+		return nil, nil, err
+	}
 	if options.InitProcessFlag {
 		binaryPath, err := exec.LookPath(*options.InitBinary)
 		if err != nil {
@@ -534,7 +639,7 @@ func generateRootfsOpts(args []string, id string, ensured *imgutil.EnsuredImage,
 		}
 		inContainerPath := filepath.Join("/sbin", filepath.Base(*options.InitBinary))
 		opts = append(opts, func(_ context.Context, _ oci.Client, _ *containers.Container, spec *oci.Spec) error {
-			spec.Process.Args = append([]string{inContainerPath, "--"}, spec.Process.Args...)
+			spec.Process.Args = initProcessArgs(inContainerPath, options.InitArgs, spec)
 			spec.Mounts = append([]specs.Mount{{
 				Destination: inContainerPath,
 				Type:        "bind",
@@ -550,6 +655,126 @@ func generateRootfsOpts(args []string, id string, ensured *imgutil.EnsuredImage,
 	return opts, cOpts, nil
 }
 
+// mergeExposedPorts returns the ports exposed by the image, merged with the ports (or ranges) of --expose,
+// as a list of "<port>/<proto>" or "<start>-<end>/<proto>", sorted by protocol and port.
+// The overlapping and adjacent ranges are merged, so that a large range is stored as-is in the labels.
+func mergeExposedPorts(imageExposedPorts map[string]struct{}, expose []string) ([]string, error) {
+	type portRange struct {
+		start, end uint64
+	}
+	ranges := make(map[string][]portRange)
+	for _, e := range slices.Concat(slices.Collect(maps.Keys(imageExposedPorts)), expose) {
+		ports, proto, _ := strings.Cut(e, "/")
+		proto = strings.ToLower(proto)
+		switch proto {
+		case "":
+			proto = "tcp"
+		case "tcp", "udp", "sctp":
+		default:
+			return nil, fmt.Errorf("invalid protocol %q in exposed port %q", proto, e)
+		}
+		start, end, err := nat.ParsePortRange(ports)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exposed port %q: %w", e, err)
+		}
+		ranges[proto] = append(ranges[proto], portRange{start, end})
+	}
+
+	var res []string
+	for _, proto := range slices.Sorted(maps.Keys(ranges)) {
+		protoRanges := ranges[proto]
+		slices.SortFunc(protoRanges, func(a, b portRange) int { return cmp.Compare(a.start, b.start) })
+		var merged []portRange
+		for _, r := range protoRanges {
+			if last := len(merged) - 1; last >= 0 && r.start <= merged[last].end+1 {
+				merged[last].end = max(merged[last].end, r.end)
+				continue
+			}
+			merged = append(merged, r)
+		}
+		for _, r := range merged {
+			if r.start == r.end {
+				res = append(res, fmt.Sprintf("%d/%s", r.start, proto))
+			} else {
+				res = append(res, fmt.Sprintf("%d-%d/%s", r.start, r.end, proto))
+			}
+		}
+	}
+	return res, nil
+}
+
+// publishExposedPorts returns portMappings, completed with mappings of the exposed ports (-P) to
+// ephemeral host ports. The exposed ports that are already published with -p are left as-is.
+func publishExposedPorts(portMappings []cni.PortMapping, exposedPorts []string) ([]cni.PortMapping, error) {
+	published := func(pm cni.PortMapping) bool {
+		return slices.ContainsFunc(portMappings, func(p cni.PortMapping) bool {
+			return p.ContainerPort == pm.ContainerPort && p.Protocol == pm.Protocol
+		})
+	}
+
+	res := slices.Clone(portMappings)
+	for _, exposed := range exposedPorts {
+		pms, err := portutil.ParseFlagP(exposed)
+		if err != nil {
+			return nil, fmt.Errorf("failed to publish exposed port %q: %w", exposed, err)
+		}
+		for _, pm := range pms {
+			if !published(pm) {
+				res = append(res, pm)
+			}
+		}
+	}
+	return res, nil
+}
+
+// initProcessArgs returns the args of the container process wrapped by the init binary.
+// tini exits with the exit code of the child process as-is (128+signal if it was killed), as long as it is
+// not asked to remap it with -e, and as long as it is able to wait for the child.
+// When the container does not have a PID namespace of its own, tini is not PID 1, so it is registered as a
+// child subreaper (-s) to keep reaping the child and reporting its exit code.
+func initProcessArgs(initPath string, initArgs []string, spec *oci.Spec) []string {
+	args := []string{initPath}
+	if strings.HasPrefix(filepath.Base(initPath), "tini") && !hasOwnPIDNamespace(spec) {
+		args = append(args, "-s")
+	}
+	args = append(args, initArgs...)
+	return append(append(args, "--"), spec.Process.Args...)
+}
+
+// validateInitArgs checks that the arguments of --init-args are non-empty tokens, passed with --init (or --init-binary).
+// "--" is rejected, as it separates the arguments of the init process from the command of the container.
+func validateInitArgs(initArgs []string, initProcess bool) error {
+	if len(initArgs) == 0 {
+		return nil
+	}
+	if !initProcess {
+		return errors.New("--init-args requires --init")
+	}
+	for _, arg := range initArgs {
+		switch {
+		case strings.TrimSpace(arg) == "":
+			return errors.New("--init-args must not be empty")
+		case strings.ContainsAny(arg, " \t\n"):
+			return fmt.Errorf("--init-args %q must be a single argument, specify --init-args for each argument", arg)
+		case arg == "--":
+			return errors.New(`--init-args must not be "--"`)
+		}
+	}
+	return nil
+}
+
+func hasOwnPIDNamespace(spec *oci.Spec) bool {
+	if spec.Linux == nil {
+		return false
+	}
+	for _, ns := range spec.Linux.Namespaces {
+		if ns.Type == specs.PIDNamespace {
+			return ns.Path == ""
+		}
+	}
+	return false
+}
+
 // GenerateLogURI generates a log URI for the current container store
 func GenerateLogURI(dataStore string) (*url.URL, error) {
 	selfExe, err := os.Executable()
@@ -611,11 +836,14 @@ func withNerdctlOCIHook(cmd string, args []string) (oci.SpecOpts, error) {
 	}, nil
 }
 
-func withContainerLabels(label, labelFile []string, ensuredImage *imgutil.EnsuredImage) ([]containerd.NewContainerOpts, error) {
+func withContainerLabels(label, labelFile []string, ensuredImage *imgutil.EnsuredImage, maxLabelSize int) ([]containerd.NewContainerOpts, error) {
 	var opts []containerd.NewContainerOpts
 
 	// add labels defined by image
 	if ensuredImage != nil {
+		if err := validateLabelSizes("image label", ensuredImage.ImageConfig.Labels, maxLabelSize); err != nil {
+			return nil, err
+		}
 		imageLabelOpts := containerd.WithAdditionalContainerLabels(ensuredImage.ImageConfig.Labels)
 		opts = append(opts, imageLabelOpts)
 	}
@@ -631,16 +859,96 @@ func withContainerLabels(label, labelFile []string, ensuredImage *imgutil.Ensure
 			return nil, fmt.Errorf("internal label %q must not be specified manually", k)
 		}
 	}
+	if err := validateLabelSizes("label", labelMap, maxLabelSize); err != nil {
+		return nil, err
+	}
 	o := containerd.WithAdditionalContainerLabels(labelMap)
 	opts = append(opts, o)
 
 	return opts, nil
 }
 
+// validateAnnotations rejects the user-specified annotations that would override the annotations
+// managed by nerdctl, i.e., the annotations with the "nerdctl/" prefix (except for the
+// user-facing annotations defined in the annotations package) and the compose labels.
+func validateAnnotations(annotationMap map[string]string) error {
+	for k := range annotationMap {
+		if strings.HasPrefix(k, annotations.Prefix) {
+			if !strings.HasPrefix(k, annotations.Bypass4netns) {
+				return fmt.Errorf("annotation %q is reserved for internal use and must not be specified manually", k)
+			}
+		} else if strings.HasPrefix(k, labels.ComposePrefix) {
+			return fmt.Errorf("annotation %q is reserved for compose and must not be specified manually", k)
+		}
+	}
+	return nil
+}
+
+// provenanceAnnotations returns the annotations recording which nerdctl version created the container,
+// when, and on behalf of which user.
+func provenanceAnnotations() map[string]string {
+	m := map[string]string{
+		annotations.CreatedByVersion: version.GetVersion(),
+		annotations.CreatedAt:        time.Now().UTC().Format(time.RFC3339),
+	}
+	if name := invokingUser(); name != "" {
+		m[annotations.CreatedByUser] = name
+	}
+	return m
+}
+
+// invokingUser returns the name (or the ID, if the name is unknown) of the user who invoked nerdctl.
+// Under sudo, this is the user who ran sudo, rather than root.
+func invokingUser() string {
+	if rootlessutil.IsRootlessChild() {
+		return lookupUsername(strconv.Itoa(rootlessutil.ParentEUID()))
+	}
+	if os.Geteuid() == 0 {
+		if uid := os.Getenv("SUDO_UID"); uid != "" {
+			return lookupUsername(uid)
+		}
+		if name := os.Getenv("SUDO_USER"); name != "" {
+			return name
+		}
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return ""
+}
+
+// lookupUsername returns the name of the user uid, or uid if the name is unknown.
+func lookupUsername(uid string) string {
+	if u, err := user.LookupId(uid); err == nil {
+		return u.Username
+	}
+	return uid
+}
+
+// validateLabelSizes rejects the labels whose key and value exceed maxSize bytes in total,
+// so that the user gets an actionable error instead of an error from the containerd metadata store.
+// The check is disabled when maxSize is not positive.
+func validateLabelSizes(kind string, m map[string]string, maxSize int) error {
+	if maxSize <= 0 {
+		return nil
+	}
+	keys := slices.Sorted(maps.Keys(m))
+	for _, k := range keys {
+		if size := len(k) + len(m[k]); size > maxSize {
+			if len(k) > 64 {
+				k = k[:64] + "..."
+			}
+			return fmt.Errorf("%s %q is too large: the key and the value are %d bytes in total, exceeding the maximum of %d bytes (max_label_size)", kind, k, size, maxSize)
+		}
+	}
+	return nil
+}
+
 func readKVStringsMapfFromLabel(label, labelFile []string) (map[string]string, error) {
-	labelsMap := strutil.DedupeStrSlice(label)
 	labelsFilePath := strutil.DedupeStrSlice(labelFile)
-	kvStrings, err := dockercliopts.ReadKVStrings(labelsFilePath, labelsMap)
+	// The labels specified with --label are placed after the ones read from --label-file, so that they take precedence.
+	// When the same key is specified multiple times, the last value wins.
+	kvStrings, err := dockercliopts.ReadKVStrings(labelsFilePath, label)
 	if err != nil {
 		return nil, err
 	}
@@ -662,7 +970,7 @@ func parseKVStringsMapFromLogOpt(logOpt []string, logDriver string) (map[string]
 	return logOptMap, nil
 }
 
-func withStop(stopSignal string, stopTimeout int, ensuredImage *imgutil.EnsuredImage) containerd.NewContainerOpts {
+func withStop(stopSignal string, stopTimeout int, stopTimeoutChanged bool, ensuredImage *imgutil.EnsuredImage) containerd.NewContainerOpts {
 	return func(ctx context.Context, _ *containerd.Client, c *containers.Container) error {
 		if c.Labels == nil {
 			c.Labels = make(map[string]string)
@@ -675,19 +983,43 @@ func withStop(stopSignal string, stopTimeout int, ensuredImage *imgutil.EnsuredI
 			}
 		}
 		c.Labels[containerd.StopSignalLabel] = stopSignal
-		if stopTimeout != 0 {
+		// An explicit 0 is stored too, so that the container is killed without a grace period,
+		// while the default timeout applies when the label is missing.
+		if stopTimeout != 0 || stopTimeoutChanged {
 			c.Labels[labels.StopTimeout] = strconv.Itoa(stopTimeout)
 		}
 		return nil
 	}
 }
 
+// warnNonDNSLabelName warns when the container joins a CNI network, on which the other containers
+// resolve it by its name, but the name is not a valid DNS label (e.g., it contains `_`, `.` or uppercase letters).
+// Such names are still accepted for compatibility with Docker, and the hosts entries of the container
+// also resolve its normalized name (see identifiers.NormalizeDNSLabel).
+func warnNonDNSLabelName(ctx context.Context, name string, networks []string) {
+	if netType, err := nettype.Detect(networks); err != nil || netType != nettype.CNI {
+		return
+	}
+	if err := identifiers.ValidateDNSLabel(name); err != nil {
+		normalized := identifiers.NormalizeDNSLabel(name)
+		if normalized == "" {
+			log.G(ctx).Warnf("container name %q is not a valid DNS label, so the other containers on the network may fail to resolve it", name)
+			return
+		}
+		log.G(ctx).Warnf("container name %q is not a valid DNS label, so the other containers on the network may fail to resolve it: "+
+			"it is also resolved as %q (hint: use --name=%s)", name, normalized, normalized)
+	}
+}
+
 type internalLabels struct {
 	// labels from cmd options
 	namespace  string
 	platform   string
 	extraHosts []string
 	pidFile    string
+	// restart
+	restartMinUptime time.Duration
+	restartExitCodes []int
 	// labels from cmd options or automatically set
 	name       string
 	hostname   string
@@ -699,9 +1031,13 @@ type internalLabels struct {
 	ipAddress            string
 	ip6Address           string
 	macAddress           string
+	networkMTU           int
 	dnsServers           []string
 	dnsSearchDomains     []string
 	dnsResolvConfOptions []string
+	noResolv             bool
+	noHosts              bool
+	exposedPorts         []string
 	// volume
 	mountPoints []*mountutil.Processed
 	anonVolumes []string
@@ -724,9 +1060,28 @@ type internalLabels struct {
 	// label for device mapping set by the --device flag
 	deviceMapping []dockercompat.DeviceMapping
 
+	// label for the original --cpus value, in units of 1e-9 CPUs
+	nanoCPUs int64
+
+	// label for the --cpuset-cpus value, validated against the online CPUs
+	cpusetCPUs string
+
+	// label for the --cpu-shares value
+	cpuShares uint64
+
+	// label for ulimits set by the --ulimit flag
+	ulimits []*units.Ulimit
+
+	// label for the blkio devices, to keep the device paths set by the --blkio-weight-device
+	// and --device-{read,write}-{bps,iops} flags
+	blkioSettings dockercompat.LinuxBlkioSettings
+
 	user string
 
 	healthcheck string
+
+	// environment variables whose values are redacted in inspect
+	redactedEnv []string
 }
 
 // WithInternalLabels sets the internal labels for a container.
@@ -769,6 +1124,14 @@ func withInternalLabels(internalLabels internalLabels) (containerd.NewContainerO
 		m[labels.PIDFile] = internalLabels.pidFile
 	}
 
+	if internalLabels.restartMinUptime > 0 {
+		m[labels.RestartMinUptime] = internalLabels.restartMinUptime.String()
+	}
+
+	if len(internalLabels.restartExitCodes) > 0 {
+		m[labels.RestartExitCodes] = formatRestartExitCodes(internalLabels.restartExitCodes)
+	}
+
 	if internalLabels.ipAddress != "" {
 		m[labels.IPAddress] = internalLabels.ipAddress
 	}
@@ -777,6 +1140,14 @@ func withInternalLabels(internalLabels internalLabels) (containerd.NewContainerO
 		m[labels.IP6Address] = internalLabels.ip6Address
 	}
 
+	if len(internalLabels.exposedPorts) > 0 {
+		exposedPortsJSON, err := json.Marshal(internalLabels.exposedPorts)
+		if err != nil {
+			return nil, err
+		}
+		m[labels.ExposedPorts] = string(exposedPortsJSON)
+	}
+
 	m[labels.Platform], err = platformutil.NormalizeString(internalLabels.platform)
 	if err != nil {
 		return nil, err
@@ -789,12 +1160,37 @@ func withInternalLabels(internalLabels internalLabels) (containerd.NewContainerO
 			return nil, err
 		}
 		m[labels.Mounts] = string(mountPointsJSON)
+
+		if driverMounts := volumeDriverMounts(internalLabels.mountPoints); len(driverMounts) > 0 {
+			driverMountsJSON, err := json.Marshal(driverMounts)
+			if err != nil {
+				return nil, err
+			}
+			m[labels.VolumeDriverMounts] = string(driverMountsJSON)
+		}
+
+		if imageMounts := imageMountRecords(internalLabels.mountPoints); len(imageMounts) > 0 {
+			imageMountsJSON, err := json.Marshal(imageMounts)
+			if err != nil {
+				return nil, err
+			}
+			m[labels.ImageMounts] = string(imageMountsJSON)
+			// The container references the views of the rootfs of the images, so that they are kept until it is removed
+			for i, r := range imageMounts {
+				k, v := r.GCLabel(i)
+				m[k] = v
+			}
+		}
 	}
 
 	if internalLabels.macAddress != "" {
 		m[labels.MACAddress] = internalLabels.macAddress
 	}
 
+	if internalLabels.networkMTU != 0 {
+		m[labels.NetworkMTU] = strconv.Itoa(internalLabels.networkMTU)
+	}
+
 	if internalLabels.pidContainer != "" {
 		m[labels.PIDContainer] = internalLabels.pidContainer
 	}
@@ -823,10 +1219,23 @@ func withInternalLabels(internalLabels internalLabels) (containerd.NewContainerO
 		dnsSettings.DNSResolvConfOptions = internalLabels.dnsResolvConfOptions
 	}
 
+	dnsSettings.NoResolv = internalLabels.noResolv
+	dnsSettings.NoHosts = internalLabels.noHosts
+
 	if len(internalLabels.deviceMapping) > 0 {
 		hostConfigLabel.Devices = append(hostConfigLabel.Devices, internalLabels.deviceMapping...)
 	}
 
+	hostConfigLabel.NanoCPUs = internalLabels.nanoCPUs
+	hostConfigLabel.CPUSetCPUs = internalLabels.cpusetCPUs
+	hostConfigLabel.CPUShares = internalLabels.cpuShares
+	hostConfigLabel.Ulimits = internalLabels.ulimits
+	hostConfigLabel.BlkioWeightDevice = internalLabels.blkioSettings.BlkioWeightDevice
+	hostConfigLabel.BlkioDeviceReadBps = internalLabels.blkioSettings.BlkioDeviceReadBps
+	hostConfigLabel.BlkioDeviceWriteBps = internalLabels.blkioSettings.BlkioDeviceWriteBps
+	hostConfigLabel.BlkioDeviceReadIOps = internalLabels.blkioSettings.BlkioDeviceReadIOps
+	hostConfigLabel.BlkioDeviceWriteIOps = internalLabels.blkioSettings.BlkioDeviceWriteIOps
+
 	hostConfigJSON, err := json.Marshal(hostConfigLabel)
 	if err != nil {
 		return nil, err
@@ -847,6 +1256,14 @@ func withInternalLabels(internalLabels internalLabels) (containerd.NewContainerO
 		m[labels.HealthCheck] = internalLabels.healthcheck
 	}
 
+	if len(internalLabels.redactedEnv) > 0 {
+		redactedEnvJSON, err := json.Marshal(internalLabels.redactedEnv)
+		if err != nil {
+			return nil, err
+		}
+		m[labels.RedactedEnv] = string(redactedEnvJSON)
+	}
+
 	return containerd.WithAdditionalContainerLabels(m), nil
 }
 
@@ -894,6 +1311,20 @@ func withHealthcheck(options types.ContainerCreateOptions, ensuredImage *imgutil
 	if options.HealthStartInterval != 0 {
 		hc.StartInterval = options.HealthStartInterval
 	}
+	if len(options.HealthEnv) > 0 {
+		hc.Env = options.HealthEnv
+	}
+
+	// Sub-second intervals would make the probe exec in the container continuously
+	minInterval, err := healthcheck.ParseMinInterval(options.GOptions.MinHealthInterval)
+	if err != nil {
+		return "", fmt.Errorf("invalid minimum health check interval: %w", err)
+	}
+	if interval, clamped := healthcheck.ClampInterval(hc.Interval, minInterval); clamped {
+		log.L.Warnf("health check interval %s is shorter than the minimum of %s, using %s instead (configure min_health_interval in nerdctl.toml to change the minimum)",
+			hc.Interval, minInterval, interval)
+		hc.Interval = interval
+	}
 
 	// If no healthcheck config is set (via CLI or image), return empty string so we skip adding to container config.
 	if reflect.DeepEqual(hc, &healthcheck.Healthcheck{}) {
@@ -914,9 +1345,12 @@ func (il *internalLabels) loadNetOpts(opts types.NetworkOptions) {
 	il.ip6Address = opts.IP6Address
 	il.networks = opts.NetworkSlice
 	il.macAddress = opts.MACAddress
+	il.networkMTU = opts.MTU
 	il.dnsServers = opts.DNSServers
 	il.dnsSearchDomains = opts.DNSSearchDomains
 	il.dnsResolvConfOptions = opts.DNSResolvConfOptions
+	il.noResolv = opts.NoResolv
+	il.noHosts = opts.NoHosts
 }
 
 func dockercompatMounts(mountPoints []*mountutil.Processed) []dockercompat.MountPoint {
@@ -928,8 +1362,9 @@ func dockercompatMounts(mountPoints []*mountutil.Processed) []dockercompat.Mount
 			Name:        mp.Name,
 			Source:      mp.Mount.Source,
 			Destination: mp.Mount.Destination,
-			Driver:      "",
+			Driver:      mp.Driver,
 			Mode:        mp.Mode,
+			Subpath:     mp.Subpath,
 		}
 		result[i].RW, result[i].Propagation = dockercompat.ParseMountProperties(strings.Split(mp.Mode, ","))
 
@@ -938,9 +1373,30 @@ func dockercompatMounts(mountPoints []*mountutil.Processed) []dockercompat.Mount
 			result[i].Name = mp.AnonymousVolume
 		}
 
-		// volume only support local driver
-		if mp.Type == "volume" {
-			result[i].Driver = "local"
+		if mp.Type == "volume" && result[i].Driver == "" {
+			result[i].Driver = volumedriver.LocalDriverName
+		}
+	}
+	return result
+}
+
+// volumeDriverMounts returns the mounts made by the drivers of the non-local volumes.
+func volumeDriverMounts(mountPoints []*mountutil.Processed) []volumedriver.MountRecord {
+	var result []volumedriver.MountRecord
+	for _, mp := range mountPoints {
+		if mp.DriverMountID != "" {
+			result = append(result, volumedriver.MountRecord{Volume: mp.Name, Driver: mp.Driver, ID: mp.DriverMountID, Mountpoint: mp.DriverMountpoint})
+		}
+	}
+	return result
+}
+
+// imageMountRecords returns the rootfs of the images of the image mounts mounted on the host.
+func imageMountRecords(mountPoints []*mountutil.Processed) []mountutil.ImageMountRecord {
+	var result []mountutil.ImageMountRecord
+	for _, mp := range mountPoints {
+		if mp.ImageMount != nil {
+			result = append(result, *mp.ImageMount)
 		}
 	}
 	return result
@@ -959,6 +1415,9 @@ func processeds(mountPoints []dockercompat.MountPoint) []*mountutil.Processed {
 			},
 			Mode: mp.Mode,
 		}
+		if !volumedriver.IsLocal(mp.Driver) {
+			result[i].Driver = mp.Driver
+		}
 	}
 	return result
 }
@@ -971,6 +1430,20 @@ func propagateInternalContainerdLabelsToOCIAnnotations() oci.SpecOpts {
 				allowed[k] = v
 			}
 		}
+		// The values of --health-env are only read from the labels by the health checker,
+		// so they are kept out of the annotations, which are visible to the runtime and the hooks.
+		if hcJSON, ok := allowed[labels.HealthCheck]; ok {
+			hc, err := healthcheck.HealthCheckFromJSON(hcJSON)
+			if err != nil {
+				return err
+			}
+			if len(hc.Env) > 0 {
+				hc.Env = nil
+				if allowed[labels.HealthCheck], err = hc.ToJSONString(); err != nil {
+					return err
+				}
+			}
+		}
 		return oci.WithAnnotations(allowed)(ctx, oc, c, s)
 	}
 }
@@ -1043,6 +1516,8 @@ func generateLogConfig(dataStore string, id string, logDriver string, logOpt []s
 
 func generateRemoveStateDirFunc(ctx context.Context, id string, internalLabels internalLabels) func() {
 	return func() {
+		releaseVolumeDriverMounts(ctx, internalLabels.mountPoints...)
+		releaseImageMounts(internalLabels.mountPoints...)
 		if rmErr := os.RemoveAll(internalLabels.stateDir); rmErr != nil {
 			log.G(ctx).WithError(rmErr).Warnf("failed to remove container %q state dir %q", id, internalLabels.stateDir)
 		}
@@ -1051,6 +1526,8 @@ func generateRemoveStateDirFunc(ctx context.Context, id string, internalLabels i
 
 func generateRemoveOrphanedDirsFunc(ctx context.Context, id, dataStore string, internalLabels internalLabels) func() {
 	return func() {
+		releaseVolumeDriverMounts(ctx, internalLabels.mountPoints...)
+		releaseImageMounts(internalLabels.mountPoints...)
 		if rmErr := os.RemoveAll(internalLabels.stateDir); rmErr != nil {
 			log.G(ctx).WithError(rmErr).Warnf("failed to remove container %q state dir %q", id, internalLabels.stateDir)
 		}
@@ -1072,6 +1549,10 @@ func generateGcFunc(ctx context.Context, container containerd.Container, ns, id,
 				log.G(ctx).WithError(netGcErr).Warnf("failed to revert container %q networking settings", id)
 			}
 		} else {
+			// The volume driver mounts and the image mounts are released by the removal of the container once it is created
+			releaseVolumeDriverMounts(ctx, internalLabels.mountPoints...)
+			releaseImageMounts(internalLabels.mountPoints...)
+
 			hs, err := hostsstore.New(dataStore, internalLabels.namespace)
 			if err != nil {
 				log.G(ctx).WithError(err).Warnf("failed to instantiate hostsstore for %q", internalLabels.namespace)
@@ -1103,5 +1584,7 @@ func generateGcFunc(ctx context.Context, container containerd.Container, ns, id,
 		if errE := containerNameStore.Release(name, id); errE != nil && !errors.Is(errE, store.ErrNotFound) {
 			log.G(ctx).WithError(errE).Warnf("failed to release container name store for container %q (%s)", name, id)
 		}
+
+		releaseStaticIP(ctx, dataStore, ns, id, internalLabels.ipAddress, internalLabels.networks)
 	}
 }
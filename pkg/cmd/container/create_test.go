@@ -0,0 +1,139 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/containerd/v2/pkg/oci"
+)
+
+func TestReadKVStringsMapfFromLabel(t *testing.T) {
+	labelFile := filepath.Join(t.TempDir(), "labels")
+	assert.NilError(t, os.WriteFile(labelFile, []byte("foo=file\nbar=file\n"), 0o644))
+
+	result, err := readKVStringsMapfFromLabel([]string{"foo=flag1", "foo=flag2"}, []string{labelFile})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, result, map[string]string{
+		"foo": "flag2",
+		"bar": "file",
+	})
+
+	// the last value wins, even if it was already specified before
+	result, err = readKVStringsMapfFromLabel([]string{"foo=1", "bar=baz", "foo=2", "empty", "foo=1", "qux=3=three"}, nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, result, map[string]string{
+		"foo":   "1",
+		"bar":   "baz",
+		"empty": "",
+		"qux":   "3=three",
+	})
+}
+
+func TestValidateLabelSizes(t *testing.T) {
+	m := map[string]string{
+		"small": "value",
+		"large": strings.Repeat("x", 100),
+	}
+	assert.NilError(t, validateLabelSizes("label", m, 105))
+	assert.Error(t, validateLabelSizes("label", m, 104),
+		`label "large" is too large: the key and the value are 105 bytes in total, exceeding the maximum of 104 bytes (max_label_size)`)
+	// the check is disabled when the maximum is not positive
+	assert.NilError(t, validateLabelSizes("label", m, 0))
+
+	longKey := strings.Repeat("k", 100)
+	err := validateLabelSizes("label", map[string]string{longKey: ""}, 10)
+	assert.ErrorContains(t, err, `label "`+longKey[:64]+`..." is too large`)
+}
+
+func TestInitProcessArgs(t *testing.T) {
+	withPIDNamespace := func(path string) *oci.Spec {
+		return &oci.Spec{
+			Process: &specs.Process{Args: []string{"sh", "-c", "exit 42"}},
+			Linux: &specs.Linux{
+				Namespaces: []specs.LinuxNamespace{{Type: specs.PIDNamespace, Path: path}},
+			},
+		}
+	}
+	hostPID := withPIDNamespace("")
+	hostPID.Linux.Namespaces = nil
+
+	assert.DeepEqual(t, initProcessArgs("/sbin/tini", nil, withPIDNamespace("")),
+		[]string{"/sbin/tini", "--", "sh", "-c", "exit 42"})
+	assert.DeepEqual(t, initProcessArgs("/sbin/tini", nil, withPIDNamespace("/proc/42/ns/pid")),
+		[]string{"/sbin/tini", "-s", "--", "sh", "-c", "exit 42"})
+	assert.DeepEqual(t, initProcessArgs("/sbin/tini-custom", nil, hostPID),
+		[]string{"/sbin/tini-custom", "-s", "--", "sh", "-c", "exit 42"})
+	// Other init binaries are invoked as-is
+	assert.DeepEqual(t, initProcessArgs("/sbin/dumb-init", nil, hostPID),
+		[]string{"/sbin/dumb-init", "--", "sh", "-c", "exit 42"})
+	// --init-args are placed after the init binary, before the command
+	assert.DeepEqual(t, initProcessArgs("/sbin/tini", []string{"-v", "-g"}, hostPID),
+		[]string{"/sbin/tini", "-s", "-v", "-g", "--", "sh", "-c", "exit 42"})
+}
+
+func TestValidateInitArgs(t *testing.T) {
+	assert.NilError(t, validateInitArgs(nil, false))
+	assert.NilError(t, validateInitArgs([]string{"-v", "--verbose"}, true))
+	assert.ErrorContains(t, validateInitArgs([]string{"-v"}, false), "requires --init")
+	assert.ErrorContains(t, validateInitArgs([]string{"-v", " "}, true), "must not be empty")
+	assert.ErrorContains(t, validateInitArgs([]string{"-v -g"}, true), "must be a single argument")
+	assert.ErrorContains(t, validateInitArgs([]string{"--"}, true), `must not be "--"`)
+}
+
+func TestMergeExposedPorts(t *testing.T) {
+	res, err := mergeExposedPorts(map[string]struct{}{"80/tcp": {}, "53": {}}, []string{"8000-8002/udp", "80", "443/TCP"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, res, []string{"53/tcp", "80/tcp", "443/tcp", "8000-8002/udp"})
+
+	// overlapping and adjacent ranges are merged, and large ranges are kept compact
+	res, err = mergeExposedPorts(map[string]struct{}{"8080/tcp": {}}, []string{"8000-8079", "8081-8090/tcp", "8085", "1-65535/udp", "7000/udp"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, res, []string{"8000-8090/tcp", "1-65535/udp"})
+
+	res, err = mergeExposedPorts(nil, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, len(res), 0)
+
+	_, err = mergeExposedPorts(nil, []string{"80/icmp"})
+	assert.ErrorContains(t, err, "invalid protocol")
+	_, err = mergeExposedPorts(nil, []string{"80-70"})
+	assert.ErrorContains(t, err, "invalid exposed port")
+}
+
+func TestInvokingUserUnderSudo(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("requires running as root, like sudo")
+	}
+	t.Setenv("SUDO_UID", "0")
+	t.Setenv("SUDO_USER", "")
+	assert.Equal(t, invokingUser(), "root")
+
+	// The ID is returned when the user is unknown
+	t.Setenv("SUDO_UID", "424242")
+	assert.Equal(t, invokingUser(), "424242")
+
+	t.Setenv("SUDO_UID", "")
+	t.Setenv("SUDO_USER", "someone")
+	assert.Equal(t, invokingUser(), "someone")
+}
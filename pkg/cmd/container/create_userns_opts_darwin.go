@@ -33,8 +33,9 @@ func getUserNamespaceOpts(
 	options *types.ContainerCreateOptions,
 	ensuredImage imgutil.EnsuredImage,
 	id string,
-) ([]oci.SpecOpts, []containerd.NewContainerOpts, error) {
-	return []oci.SpecOpts{}, []containerd.NewContainerOpts{}, nil
+	dataStore string,
+) ([]oci.SpecOpts, []containerd.NewContainerOpts, func(), error) {
+	return []oci.SpecOpts{}, []containerd.NewContainerOpts{}, func() {}, nil
 }
 
 func getContainerUserNamespaceNetOpts(
@@ -23,6 +23,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -31,13 +32,16 @@ import (
 
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/containerd/v2/core/snapshots"
+	"github.com/containerd/containerd/v2/pkg/namespaces"
 	"github.com/containerd/containerd/v2/pkg/oci"
+	"github.com/containerd/log"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/containerutil"
 	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
 	"github.com/containerd/nerdctl/v2/pkg/imgutil"
 	"github.com/containerd/nerdctl/v2/pkg/netutil/nettype"
+	"github.com/containerd/nerdctl/v2/pkg/store"
 )
 
 // IDMap contains a single entry for user namespace range remapping. An array
@@ -58,6 +62,15 @@ type IdentityMapping struct {
 
 const (
 	capabRemapIDs = "remap-ids"
+
+	// autoUserns is the value of --userns that allocates a free ID range for the container.
+	autoUserns = "auto"
+	// autoUsernsUser is the user whose /etc/subuid and /etc/subgid ranges are allocated by --userns=auto.
+	autoUsernsUser = "containers"
+	// autoUsernsDefaultSize is the number of IDs allocated by --userns=auto, unless size=N is specified.
+	autoUsernsDefaultSize = 65536
+	// autoUsernsLockDir is the directory of the lock of lockAutoUserns, under the data store.
+	autoUsernsLockDir = "userns-auto"
 )
 
 func getUserNamespaceOpts(
@@ -66,31 +79,54 @@ func getUserNamespaceOpts(
 	options *types.ContainerCreateOptions,
 	ensuredImage imgutil.EnsuredImage,
 	id string,
-) ([]oci.SpecOpts, []containerd.NewContainerOpts, error) {
+	dataStore string,
+) (_ []oci.SpecOpts, _ []containerd.NewContainerOpts, release func(), retErr error) {
+	release = func() {}
 	if isDefaultUserns(options) {
-		return nil, createDefaultSnapshotOpts(id, ensuredImage), nil
+		return nil, createDefaultSnapshotOpts(id, ensuredImage), release, nil
 	}
 
 	supportsRemap, err := snapshotterSupportsRemapLabels(ctx, client, ensuredImage.Snapshotter)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	} else if !supportsRemap {
-		return nil, nil, errors.New("snapshotter does not support remap-ids capability")
+		return nil, nil, nil, errors.New("snapshotter does not support remap-ids capability")
 	}
 
-	idMapping, err := loadAndValidateIDMapping(options.UserNS)
-	if err != nil {
-		return nil, nil, err
+	var idMapping IdentityMapping
+	if size, auto, err := parseAutoUserns(options.UserNS); err != nil {
+		return nil, nil, nil, err
+	} else if auto {
+		// The lock is held until the container is created, so that its ID range is not allocated twice
+		lock, err := lockAutoUserns(dataStore)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		release = func() { lock.Release() }
+		defer func() {
+			if retErr != nil {
+				release()
+			}
+		}()
+		idMapping, err = allocateAutoIDMapping(ctx, client, size)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	} else {
+		idMapping, err = loadAndValidateIDMapping(options.UserNS)
+		if err != nil {
+			return nil, nil, nil, err
+		}
 	}
 
 	uidMaps, gidMaps := convertMappings(idMapping)
 	specOpts := getUserNamespaceSpecOpts(uidMaps, gidMaps)
 	snapshotOpts, err := createSnapshotOpts(id, ensuredImage, uidMaps, gidMaps)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
-	return specOpts, snapshotOpts, nil
+	return specOpts, snapshotOpts, release, nil
 }
 
 // getContainerUserNamespaceNetOpts retrieves the user namespace path for the specified container.
@@ -438,6 +474,116 @@ func loadAndValidateIDMapping(userNS string) (IdentityMapping, error) {
 	return idMapping, nil
 }
 
+// parseAutoUserns parses "auto" and "auto:size=N", and returns the number of IDs to allocate.
+// auto is false if userNS is not an auto mode.
+func parseAutoUserns(userNS string) (size int, auto bool, err error) {
+	mode, opts, _ := strings.Cut(userNS, ":")
+	if mode != autoUserns {
+		return 0, false, nil
+	}
+	size = autoUsernsDefaultSize
+	if opts == "" {
+		return size, true, nil
+	}
+	for _, opt := range strings.Split(opts, ",") {
+		k, v, _ := strings.Cut(opt, "=")
+		switch k {
+		case "size":
+			size, err = strconv.Atoi(v)
+			if err != nil || size <= 0 {
+				return 0, true, fmt.Errorf("invalid --userns=%s: size must be a positive integer, got %q", userNS, v)
+			}
+		default:
+			return 0, true, fmt.Errorf("invalid --userns=%s: unknown option %q (supported: size=N)", userNS, k)
+		}
+	}
+	return size, true, nil
+}
+
+// lockAutoUserns locks the allocation of the ID ranges of --userns=auto.
+// The lock is global to the data store, as the ID ranges are shared by all the namespaces.
+func lockAutoUserns(dataStore string) (store.Store, error) {
+	lock, err := store.New(filepath.Join(dataStore, autoUsernsLockDir), 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err := lock.Lock(); err != nil {
+		return nil, err
+	}
+	return lock, nil
+}
+
+// allocateAutoIDMapping allocates size UIDs and GIDs from the /etc/subuid and /etc/subgid ranges
+// of autoUsernsUser, skipping the host IDs already mapped by the containers of all the namespaces.
+// It must be called with the lock of lockAutoUserns held.
+func allocateAutoIDMapping(ctx context.Context, client *containerd.Client, size int) (IdentityMapping, error) {
+	filter := func(sid user.SubID) bool { return sid.Name == autoUsernsUser }
+	subUIDs, err := user.ParseSubIDFileFilter("/etc/subuid", filter)
+	if err != nil {
+		return IdentityMapping{}, err
+	}
+	subGIDs, err := user.ParseSubIDFileFilter("/etc/subgid", filter)
+	if err != nil {
+		return IdentityMapping{}, err
+	}
+	if len(subUIDs) == 0 || len(subGIDs) == 0 {
+		return IdentityMapping{}, fmt.Errorf("--userns=%s requires subordinate ID ranges for user %q in /etc/subuid and /etc/subgid", autoUserns, autoUsernsUser)
+	}
+
+	nsList, err := client.NamespaceService().List(ctx)
+	if err != nil {
+		return IdentityMapping{}, err
+	}
+	var usedUIDs, usedGIDs []specs.LinuxIDMapping
+	for _, ns := range nsList {
+		nsCtx := namespaces.WithNamespace(ctx, ns)
+		containers, err := client.Containers(nsCtx)
+		if err != nil {
+			return IdentityMapping{}, err
+		}
+		for _, c := range containers {
+			spec, err := c.Spec(nsCtx)
+			if err != nil {
+				// The container may have been removed in the meantime
+				log.G(ctx).WithError(err).Debugf("failed to get the spec of container %s in namespace %s", c.ID(), ns)
+				continue
+			}
+			if spec.Linux != nil {
+				usedUIDs = append(usedUIDs, spec.Linux.UIDMappings...)
+				usedGIDs = append(usedGIDs, spec.Linux.GIDMappings...)
+			}
+		}
+	}
+
+	uidMap, err := allocateIDRange(subUIDs, usedUIDs, size)
+	if err != nil {
+		return IdentityMapping{}, fmt.Errorf("failed to allocate UIDs: %w", err)
+	}
+	gidMap, err := allocateIDRange(subGIDs, usedGIDs, size)
+	if err != nil {
+		return IdentityMapping{}, fmt.Errorf("failed to allocate GIDs: %w", err)
+	}
+	return IdentityMapping{UIDMaps: []IDMap{uidMap}, GIDMaps: []IDMap{gidMap}}, nil
+}
+
+// allocateIDRange returns the mapping of container IDs [0, size) to the lowest host IDs
+// of the available ranges that do not overlap with the used ones.
+func allocateIDRange(available []user.SubID, used []specs.LinuxIDMapping, size int) (IDMap, error) {
+	for _, r := range available {
+		start, end := r.SubID, r.SubID+r.Count
+		for start+int64(size) <= end {
+			i := slices.IndexFunc(used, func(m specs.LinuxIDMapping) bool {
+				return int64(m.HostID) < start+int64(size) && start < int64(m.HostID)+int64(m.Size)
+			})
+			if i < 0 {
+				return IDMap{ContainerID: 0, HostID: int(start), Size: size}, nil
+			}
+			start = int64(used[i].HostID) + int64(used[i].Size)
+		}
+	}
+	return IDMap{}, fmt.Errorf("no free range of %d IDs in the subordinate ranges of user %q", size, autoUsernsUser)
+}
+
 // Validates that both UID and GID mappings are available.
 func validIDMapping(mapping IdentityMapping) bool {
 	return len(mapping.UIDMaps) > 0 && len(mapping.GIDMaps) > 0
@@ -19,6 +19,7 @@ package container
 import (
 	"testing"
 
+	"github.com/moby/sys/user"
 	"github.com/opencontainers/runtime-spec/specs-go"
 	"gotest.tools/v3/assert"
 
@@ -142,3 +143,113 @@ func TestGetContainerNameFromNetworkSlice(t *testing.T) {
 		})
 	}
 }
+
+// TestParseAutoUserns tests the parseAutoUserns function.
+func TestParseAutoUserns(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		userNS       string
+		expectedSize int
+		expectedAuto bool
+		expectError  bool
+	}{
+		{userNS: "auto", expectedSize: autoUsernsDefaultSize, expectedAuto: true},
+		{userNS: "auto:size=1024", expectedSize: 1024, expectedAuto: true},
+		{userNS: "auto:size=0", expectedAuto: true, expectError: true},
+		{userNS: "auto:size=foo", expectedAuto: true, expectError: true},
+		{userNS: "auto:uidmapping=0:1:1", expectedAuto: true, expectError: true},
+		{userNS: "nerdctltestuser", expectedAuto: false},
+		{userNS: "automatic", expectedAuto: false},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.userNS, func(t *testing.T) {
+			t.Parallel()
+			size, auto, err := parseAutoUserns(testCase.userNS)
+			assert.Equal(t, testCase.expectedAuto, auto)
+			if testCase.expectError {
+				assert.Assert(t, err != nil)
+			} else {
+				assert.NilError(t, err)
+				assert.Equal(t, testCase.expectedSize, size)
+			}
+		})
+	}
+}
+
+// TestAllocateIDRange tests the allocateIDRange function.
+func TestAllocateIDRange(t *testing.T) {
+	t.Parallel()
+	available := []user.SubID{
+		{Name: autoUsernsUser, SubID: 100000, Count: 200000},
+		{Name: autoUsernsUser, SubID: 1000000, Count: 65536},
+	}
+	tests := []struct {
+		name        string
+		used        []specs.LinuxIDMapping
+		size        int
+		expected    IDMap
+		expectError bool
+	}{
+		{
+			name:     "No container",
+			size:     65536,
+			expected: IDMap{ContainerID: 0, HostID: 100000, Size: 65536},
+		},
+		{
+			name: "Skips the used ranges",
+			used: []specs.LinuxIDMapping{
+				{ContainerID: 0, HostID: 100000, Size: 65536},
+				{ContainerID: 0, HostID: 165536, Size: 1000},
+			},
+			size:     65536,
+			expected: IDMap{ContainerID: 0, HostID: 166536, Size: 65536},
+		},
+		{
+			name: "Skips a used range in the middle",
+			used: []specs.LinuxIDMapping{
+				{ContainerID: 0, HostID: 150000, Size: 65536},
+			},
+			size:     80000,
+			expected: IDMap{ContainerID: 0, HostID: 215536, Size: 80000},
+		},
+		{
+			name: "Uses a gap before a used range",
+			used: []specs.LinuxIDMapping{
+				{ContainerID: 0, HostID: 200000, Size: 100000},
+			},
+			size:     65536,
+			expected: IDMap{ContainerID: 0, HostID: 100000, Size: 65536},
+		},
+		{
+			name: "Second available range",
+			used: []specs.LinuxIDMapping{
+				{ContainerID: 0, HostID: 100000, Size: 200000},
+			},
+			size:     65536,
+			expected: IDMap{ContainerID: 0, HostID: 1000000, Size: 65536},
+		},
+		{
+			name: "No free range",
+			used: []specs.LinuxIDMapping{
+				{ContainerID: 0, HostID: 100000, Size: 200000},
+				{ContainerID: 0, HostID: 1000000, Size: 1},
+			},
+			size:        65536,
+			expectError: true,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			idMap, err := allocateIDRange(available, testCase.used, testCase.size)
+			if testCase.expectError {
+				assert.Assert(t, err != nil)
+			} else {
+				assert.NilError(t, err)
+				assert.Equal(t, testCase.expected, idMap)
+			}
+		})
+	}
+}
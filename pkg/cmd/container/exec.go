@@ -92,9 +92,6 @@ func execActionWithContainer(ctx context.Context, client *containerd.Client, con
 	if err != nil {
 		return err
 	}
-	stdinC.Closer = func() {
-		process.CloseIO(ctx, containerd.WithStdinCloser)
-	}
 	// if detach, we should not call this defer
 	if !options.Detach {
 		defer process.Delete(ctx)
@@ -130,6 +127,11 @@ func execActionWithContainer(ctx context.Context, client *containerd.Client, con
 	if err := process.Start(ctx); err != nil {
 		return err
 	}
+	// The EOF of stdin is forwarded to the process by closing its stdin, which is only possible once it is started.
+	// A short input may have been read to the end already, in which case SetCloser closes the stdin right away.
+	stdinC.SetCloser(func() {
+		process.CloseIO(ctx, containerd.WithStdinCloser)
+	})
 	if options.Detach {
 		return nil
 	}
@@ -0,0 +1,113 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/ipstore"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/netutil/nettype"
+	"github.com/containerd/nerdctl/v2/pkg/store"
+)
+
+// staticIPNetworks returns the CNI networks on which the static IP address of a container has to be reserved.
+func staticIPNetworks(ip string, networks []string) []string {
+	if ip == "" {
+		return nil
+	}
+	if netType, err := nettype.Detect(networks); err != nil || netType != nettype.CNI {
+		return nil
+	}
+	return networks
+}
+
+// reserveStaticIP reserves the static IP address (--ip) of a container on each of its CNI networks, so that it is
+// neither handed out to another container with --ip, nor allocated dynamically while the container is stopped.
+// Reservations whose owner has been removed (or no longer uses that address) are taken over.
+// Containers created before the reservation was introduced are checked through their labels.
+func reserveStaticIP(ctx context.Context, client *containerd.Client, dataStore, namespace, id, ip string, networks []string) (err error) {
+	networks = staticIPNetworks(ip, networks)
+	if len(networks) == 0 {
+		return nil
+	}
+
+	ips, err := ipstore.New(dataStore)
+	if err != nil {
+		return err
+	}
+
+	existing, err := client.Containers(ctx, fmt.Sprintf("labels.%q==%q", labels.IPAddress, ip))
+	if err != nil {
+		return err
+	}
+
+	owner := ipstore.Owner{Namespace: namespace, ID: id}
+	var reserved []string
+	defer func() {
+		if err != nil {
+			releaseStaticIP(ctx, dataStore, namespace, id, ip, reserved)
+		}
+	}()
+	for _, network := range networks {
+		for _, c := range existing {
+			if c.ID() == id {
+				continue
+			}
+			containerLabels, err := c.Labels(ctx)
+			if err != nil {
+				return err
+			}
+			if ipstore.ContainerHasStaticIP(containerLabels, network, ip) {
+				return fmt.Errorf("IP address %s on network %q is already used by container %q", ip, network, c.ID())
+			}
+		}
+		if err = ips.Reserve(network, ip, owner, ipstore.StaleReservation(ctx, client, network, ip)); err != nil {
+			return err
+		}
+		reserved = append(reserved, network)
+	}
+
+	return nil
+}
+
+// releaseStaticIP releases the reservations made by reserveStaticIP - soft failure.
+func releaseStaticIP(ctx context.Context, dataStore, namespace, id, ip string, networks []string) {
+	networks = staticIPNetworks(ip, networks)
+	if len(networks) == 0 {
+		return
+	}
+
+	ips, err := ipstore.New(dataStore)
+	if err != nil {
+		log.G(ctx).WithError(err).Warnf("failed to instantiate ipstore for container %q", id)
+		return
+	}
+
+	owner := ipstore.Owner{Namespace: namespace, ID: id}
+	for _, network := range networks {
+		// The reservation may have been taken over already, or never been made - ignore NotFound errors
+		if err := ips.Release(network, ip, owner); err != nil && !errors.Is(err, store.ErrNotFound) {
+			log.G(ctx).WithError(err).Warnf("failed to release IP address %s on network %q for container %q", ip, network, id)
+		}
+	}
+}
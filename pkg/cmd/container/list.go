@@ -195,9 +195,15 @@ func prepareContainers(ctx context.Context, client *containerd.Client, container
 				snapshottersCache[info.Snapshotter] = containerdutil.SnapshotService(client, info.Snapshotter)
 				snapshotter = snapshottersCache[info.Snapshotter]
 			}
+			// The snapshot is kept when the container is stopped, so the size is available for stopped containers too.
 			containerSize, err := getContainerSize(ctx, snapshotter, info.SnapshotKey)
 			if err != nil {
-				return nil, err
+				if !errdefs.IsNotFound(err) {
+					return nil, err
+				}
+				// e.g., the container is being removed concurrently
+				log.G(ctx).WithError(err).Warnf("failed to get the size of container %q", c.ID())
+				containerSize = "-"
 			}
 			li.Size = containerSize
 		}
@@ -0,0 +1,74 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/containerd/v2/core/snapshots"
+	"github.com/containerd/errdefs"
+)
+
+type fakeSnapshotter struct {
+	snapshots.Snapshotter
+	infos  map[string]snapshots.Info
+	usages map[string]snapshots.Usage
+}
+
+func (s *fakeSnapshotter) Stat(_ context.Context, key string) (snapshots.Info, error) {
+	info, ok := s.infos[key]
+	if !ok {
+		return snapshots.Info{}, errdefs.ErrNotFound
+	}
+	return info, nil
+}
+
+func (s *fakeSnapshotter) Usage(_ context.Context, key string) (snapshots.Usage, error) {
+	usage, ok := s.usages[key]
+	if !ok {
+		return snapshots.Usage{}, errdefs.ErrNotFound
+	}
+	return usage, nil
+}
+
+func TestGetContainerSize(t *testing.T) {
+	sn := &fakeSnapshotter{
+		infos: map[string]snapshots.Info{
+			"rw":    {Name: "rw", Parent: "layer"},
+			"layer": {Name: "layer"},
+		},
+		usages: map[string]snapshots.Usage{
+			"rw":    {Size: 5 * 1024 * 1024},
+			"layer": {Size: 1024 * 1024},
+		},
+	}
+
+	size, err := getContainerSize(context.Background(), sn, "rw")
+	assert.NilError(t, err)
+	assert.Equal(t, size, "5.0 MiB (virtual 6.0 MiB)")
+
+	// containers without a snapshot
+	size, err = getContainerSize(context.Background(), sn, "")
+	assert.NilError(t, err)
+	assert.Equal(t, size, "0.0 B (virtual 0.0 B)")
+
+	_, err = getContainerSize(context.Background(), sn, "nonexistent")
+	assert.Assert(t, errdefs.IsNotFound(err))
+}
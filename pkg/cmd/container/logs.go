@@ -135,6 +135,18 @@ func Logs(ctx context.Context, client *containerd.Client, container string, opti
 							}
 						}
 
+						if tagTemplate, ok := logCfg.Opts[logging.Tag]; ok {
+							tagInfo, err := logging.NewTagInfo(ctx, found.Container)
+							if err != nil {
+								return err
+							}
+							tag, err := logging.RenderTag(tagTemplate, tagInfo)
+							if err != nil {
+								return err
+							}
+							optPairs = append(optPairs, fmt.Sprintf("tag=%s", tag))
+						}
+
 						if len(optPairs) > 0 {
 							sort.Strings(optPairs)
 							detailPrefix = strings.Join(optPairs, ",")
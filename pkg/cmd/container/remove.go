@@ -37,10 +37,12 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
 	"github.com/containerd/nerdctl/v2/pkg/ipcutil"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/mountutil"
 	"github.com/containerd/nerdctl/v2/pkg/mountutil/volumestore"
 	"github.com/containerd/nerdctl/v2/pkg/namestore"
 	"github.com/containerd/nerdctl/v2/pkg/portutil"
 	"github.com/containerd/nerdctl/v2/pkg/store"
+	"github.com/containerd/nerdctl/v2/pkg/volumedriver"
 )
 
 var _ error = ErrContainerStatus{}
@@ -218,6 +220,20 @@ func RemoveContainer(ctx context.Context, c containerd.Container, globalOptions
 			log.G(ctx).WithError(err).WithField("container", id).Infof("unable to retrieve networking information for that container")
 		}
 
+		// Unmount the rootfs of the images of the image mounts before the container is deleted, as the deletion
+		// releases the views of the rootfs to the garbage collection - soft failure
+		if imageMountsJSON, ok := containerLabels[labels.ImageMounts]; ok {
+			var imageMounts []mountutil.ImageMountRecord
+			if err = json.Unmarshal([]byte(imageMountsJSON), &imageMounts); err != nil {
+				log.G(ctx).WithError(err).Warnf("failed to unmarshall image mounts for container %q", id)
+			}
+			for _, im := range imageMounts {
+				if err = im.Unmount(); err != nil {
+					log.G(ctx).WithError(err).Warnf("failed to unmount image %q of container %q", im.Image, id)
+				}
+			}
+		}
+
 		// Delete the container now. If it fails, try again without snapshot cleanup
 		// If it still fails, time to stop.
 		if c.Delete(ctx, delOpts...) != nil {
@@ -242,6 +258,9 @@ func RemoveContainer(ctx context.Context, c containerd.Container, globalOptions
 			}
 		}
 
+		// Release the reservation of the static IP address - soft failure
+		releaseStaticIP(ctx, dataStore, containerNamespace, id, containerLabels[labels.IPAddress], netOpts.NetworkSlice)
+
 		hs, err := hostsstore.New(dataStore, containerNamespace)
 		if err != nil {
 			log.G(ctx).WithError(err).Warnf("failed to instantiate hostsstore for %q", containerNamespace)
@@ -250,6 +269,19 @@ func RemoveContainer(ctx context.Context, c containerd.Container, globalOptions
 			log.G(ctx).WithError(err).Warnf("failed to remove hosts file for container %q", id)
 		}
 
+		// Release the mounts of the volumes of non-local drivers - soft failure
+		if driverMountsJSON, ok := containerLabels[labels.VolumeDriverMounts]; ok {
+			var driverMounts []volumedriver.MountRecord
+			if err = json.Unmarshal([]byte(driverMountsJSON), &driverMounts); err != nil {
+				log.G(ctx).WithError(err).Warnf("failed to unmarshall volume driver mounts for container %q", id)
+			}
+			for _, dm := range driverMounts {
+				if err = dm.Unmount(ctx); err != nil {
+					log.G(ctx).WithError(err).Warnf("failed to unmount volume %q of container %q", dm.Volume, id)
+				}
+			}
+		}
+
 		// Volume removal is not handled by the poststop hook lifecycle because it depends on removeAnonVolumes option
 		// Note that the anonymous volume list has been obtained earlier, without locking the volume store.
 		// Technically, a concurrent operation MAY have deleted these anonymous volumes already at this point, which
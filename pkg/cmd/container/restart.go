@@ -25,10 +25,16 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/containerutil"
 	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
+	"github.com/containerd/nerdctl/v2/pkg/signalutil"
 )
 
 // Restart will restart one or more containers.
 func Restart(ctx context.Context, client *containerd.Client, containers []string, options types.ContainerRestartOptions) error {
+	if options.Signal != "" {
+		if _, err := signalutil.ParseSignal(options.Signal); err != nil {
+			return err
+		}
+	}
 	walker := &containerwalker.ContainerWalker{
 		Client: client,
 		OnFound: func(ctx context.Context, found containerwalker.Found) error {
@@ -20,6 +20,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -33,6 +36,7 @@ import (
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/infoutil"
+	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/dockercompat"
 )
 
 // WeightDevice is a structure that holds device:weight pair
@@ -152,7 +156,18 @@ func withBlkioWriteIOPSDevice(devices []specs.LinuxThrottleDevice) oci.SpecOpts
 	}
 }
 
-func BlkioOCIOpts(options types.ContainerCreateOptions) ([]oci.SpecOpts, error) {
+// toDockerCompatThrottleDevices converts the throttle devices to be kept in the internal labels.
+func toDockerCompatThrottleDevices(devs []*ThrottleDevice) []*dockercompat.ThrottleDevice {
+	res := make([]*dockercompat.ThrottleDevice, len(devs))
+	for i, d := range devs {
+		res[i] = &dockercompat.ThrottleDevice{Path: d.Path, Rate: d.Rate}
+	}
+	return res
+}
+
+// BlkioOCIOpts returns the spec options for the blkio flags, and records the devices
+// given by their paths in internalLabels for inspect.
+func BlkioOCIOpts(options types.ContainerCreateOptions, internalLabels *internalLabels) ([]oci.SpecOpts, error) {
 	var opts []oci.SpecOpts
 
 	// Handle BlkioWeight
@@ -185,6 +200,10 @@ func BlkioOCIOpts(options types.ContainerCreateOptions) ([]oci.SpecOpts, error)
 				return nil, err
 			}
 			opts = append(opts, withBlkioWeightDevice(linuxWeightDevices))
+			for _, d := range weightDevices {
+				internalLabels.blkioSettings.BlkioWeightDevice = append(internalLabels.blkioSettings.BlkioWeightDevice,
+					&dockercompat.WeightDevice{Path: d.Path, Weight: d.Weight})
+			}
 		}
 	}
 
@@ -202,6 +221,7 @@ func BlkioOCIOpts(options types.ContainerCreateOptions) ([]oci.SpecOpts, error)
 				return nil, err
 			}
 			opts = append(opts, withBlkioReadBpsDevice(throttleDevices))
+			internalLabels.blkioSettings.BlkioDeviceReadBps = toDockerCompatThrottleDevices(readBpsDevices)
 		}
 	}
 
@@ -219,6 +239,7 @@ func BlkioOCIOpts(options types.ContainerCreateOptions) ([]oci.SpecOpts, error)
 				return nil, err
 			}
 			opts = append(opts, withBlkioWriteBpsDevice(throttleDevices))
+			internalLabels.blkioSettings.BlkioDeviceWriteBps = toDockerCompatThrottleDevices(writeBpsDevices)
 		}
 	}
 
@@ -236,6 +257,7 @@ func BlkioOCIOpts(options types.ContainerCreateOptions) ([]oci.SpecOpts, error)
 				return nil, err
 			}
 			opts = append(opts, withBlkioReadIOPSDevice(throttleDevices))
+			internalLabels.blkioSettings.BlkioDeviceReadIOps = toDockerCompatThrottleDevices(readIopsDevices)
 		}
 	}
 
@@ -253,6 +275,7 @@ func BlkioOCIOpts(options types.ContainerCreateOptions) ([]oci.SpecOpts, error)
 				return nil, err
 			}
 			opts = append(opts, withBlkioWriteIOPSDevice(throttleDevices))
+			internalLabels.blkioSettings.BlkioDeviceWriteIOps = toDockerCompatThrottleDevices(writeIopsDevices)
 		}
 	}
 
@@ -318,7 +341,9 @@ func validateThrottleBpsDevices(vals []string) ([]*ThrottleDevice, error) {
 	return throttleDevices, nil
 }
 
-// validateThrottleIOpsDevices validates an array of device-rate strings for IO operations per second
+// validateThrottleIOpsDevices validates an array of device-rate strings for IO operations per second.
+// The rate is a positive integer, optionally with a "k" suffix for thousands (e.g., "1k" is 1000).
+// When a device is specified more than once, the last rate wins.
 //
 // from https://github.com/docker/cli/blob/master/opts/throttledevice.go#L40
 func validateThrottleIOpsDevices(vals []string) ([]*ThrottleDevice, error) {
@@ -329,14 +354,19 @@ func validateThrottleIOpsDevices(vals []string) ([]*ThrottleDevice, error) {
 			return nil, fmt.Errorf("bad format: %s", val)
 		}
 
-		if !strings.HasPrefix(k, "/dev/") {
+		if !strings.HasPrefix(k, "/dev/") || k != filepath.Clean(k) {
 			return nil, fmt.Errorf("bad format for device path: %s", val)
 		}
-		rate, err := strconv.ParseUint(v, 10, 64)
+		rate, err := parseIOps(v)
 		if err != nil {
-			return nil, fmt.Errorf("invalid rate for device: %s. The correct format is <device-path>:<number>. Number must be a positive integer", val)
+			return nil, fmt.Errorf("invalid rate for device: %s. The correct format is <device-path>:<number>[k]. Number must be a positive integer, and \"k\" multiplies it by 1000 (byte units such as \"kb\" are only for the bps flags): %w", val, err)
 		}
 
+		if i := slices.IndexFunc(throttleDevices, func(d *ThrottleDevice) bool { return d.Path == k }); i >= 0 {
+			log.L.Debugf("device %s is specified more than once, using the rate %d", k, rate)
+			throttleDevices[i].Rate = rate
+			continue
+		}
 		throttleDevices = append(throttleDevices, &ThrottleDevice{
 			Path: k,
 			Rate: rate,
@@ -344,3 +374,22 @@ func validateThrottleIOpsDevices(vals []string) ([]*ThrottleDevice, error) {
 	}
 	return throttleDevices, nil
 }
+
+// parseIOps parses a positive number of IO operations per second, with an optional "k" (or "K") suffix.
+func parseIOps(s string) (uint64, error) {
+	var multiplier uint64 = 1
+	if trimmed, ok := strings.CutSuffix(strings.ToLower(s), "k"); ok {
+		s, multiplier = trimmed, 1000
+	}
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if n == 0 {
+		return 0, errors.New("the rate must be greater than 0")
+	}
+	if n > math.MaxUint64/multiplier {
+		return 0, fmt.Errorf("the rate %sk is out of range", s)
+	}
+	return n * multiplier, nil
+}
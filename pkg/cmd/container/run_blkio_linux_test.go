@@ -0,0 +1,45 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestValidateThrottleIOpsDevices(t *testing.T) {
+	devices, err := validateThrottleIOpsDevices([]string{"/dev/sda:1000", "/dev/sdb:2k", "/dev/sda:3K"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, devices, []*ThrottleDevice{
+		{Path: "/dev/sda", Rate: 3000},
+		{Path: "/dev/sdb", Rate: 2000},
+	})
+
+	for _, val := range []string{"/dev/sda:0", "/dev/sda:-1", "/dev/sda:1kb", "/dev/sda:1m", "/dev/sda:k", "/dev/sda:18446744073709552k"} {
+		_, err := validateThrottleIOpsDevices([]string{val})
+		assert.ErrorContains(t, err, "invalid rate for device: "+val, val)
+	}
+	for _, val := range []string{"/dev/sda", ":1000"} {
+		_, err := validateThrottleIOpsDevices([]string{val})
+		assert.ErrorContains(t, err, "bad format: "+val, val)
+	}
+	for _, val := range []string{"sda:1000", "/dev/../etc/passwd:1000"} {
+		_, err := validateThrottleIOpsDevices([]string{val})
+		assert.ErrorContains(t, err, "bad format for device path: "+val, val)
+	}
+}
@@ -18,8 +18,11 @@ package container
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"tags.cncf.io/container-device-interface/pkg/cdi"
+	cdiparser "tags.cncf.io/container-device-interface/pkg/parser"
 
 	"github.com/containerd/containerd/v2/core/containers"
 	cdispec "github.com/containerd/containerd/v2/pkg/cdi"
@@ -41,6 +44,58 @@ func withCDIDevices(cdiSpecDirs []string, devices ...string) oci.SpecOpts {
 			cdi.WithAutoRefresh(false),
 		)
 
+		devices, err := expandCDIDeviceClasses(cdi.GetDefaultCache(), devices)
+		if err != nil {
+			return err
+		}
+
 		return cdispec.WithCDIDevices(devices...)(ctx, client, c, s)
 	}
 }
+
+// cdiDeviceClassPrefix is the prefix of `--device=class:<vendor.com/class>`,
+// which selects all the CDI devices of a class.
+const cdiDeviceClassPrefix = "class:"
+
+// IsCDIDeviceClass returns whether the --device value selects all the CDI devices of a class.
+func IsCDIDeviceClass(device string) bool {
+	return strings.HasPrefix(device, cdiDeviceClassPrefix)
+}
+
+// expandCDIDeviceClasses replaces the `class:<vendor.com/class>` entries of devices
+// with the qualified names of all the devices of that class known to the cache.
+// A device named "all" is skipped, as it conventionally aggregates the other devices of its class.
+func expandCDIDeviceClasses(cache *cdi.Cache, devices []string) ([]string, error) {
+	var res []string
+	for _, device := range devices {
+		if !IsCDIDeviceClass(device) {
+			res = append(res, device)
+			continue
+		}
+		kind := strings.TrimPrefix(device, cdiDeviceClassPrefix)
+		vendor, class := cdiparser.ParseQualifier(kind)
+		if err := cdiparser.ValidateVendorName(vendor); err != nil {
+			return nil, fmt.Errorf("invalid CDI device class %q: %w", kind, err)
+		}
+		if err := cdiparser.ValidateClassName(class); err != nil {
+			return nil, fmt.Errorf("invalid CDI device class %q: %w", kind, err)
+		}
+		var matched []string
+		for _, name := range cache.ListDevices() {
+			v, c, n, err := cdiparser.ParseQualifiedName(name)
+			if err != nil || v != vendor || c != class || n == "all" {
+				continue
+			}
+			matched = append(matched, name)
+		}
+		if len(matched) == 0 {
+			if cache.GetDevice(cdiparser.QualifiedName(vendor, class, "all")) != nil {
+				matched = append(matched, cdiparser.QualifiedName(vendor, class, "all"))
+			} else {
+				return nil, fmt.Errorf("unknown CDI device class %q: no devices found in %v", kind, cache.GetSpecDirectories())
+			}
+		}
+		res = append(res, matched...)
+	}
+	return res, nil
+}
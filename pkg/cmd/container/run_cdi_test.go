@@ -0,0 +1,62 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"tags.cncf.io/container-device-interface/pkg/cdi"
+)
+
+const testCDISpec = `
+cdiVersion: "0.3.0"
+kind: "vendor1.com/gpu"
+devices:
+- name: gpu0
+  containerEdits:
+    env:
+    - GPU0=injected
+- name: gpu1
+  containerEdits:
+    env:
+    - GPU1=injected
+- name: all
+  containerEdits:
+    env:
+    - GPU0=injected
+    - GPU1=injected
+`
+
+func TestExpandCDIDeviceClasses(t *testing.T) {
+	specDir := t.TempDir()
+	assert.NilError(t, os.WriteFile(filepath.Join(specDir, "vendor1.yaml"), []byte(testCDISpec), 0o600))
+	cache, err := cdi.NewCache(cdi.WithSpecDirs(specDir), cdi.WithAutoRefresh(false))
+	assert.NilError(t, err)
+
+	devices, err := expandCDIDeviceClasses(cache, []string{"vendor2.com/nic=eth0", "class:vendor1.com/gpu"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, devices, []string{"vendor2.com/nic=eth0", "vendor1.com/gpu=gpu0", "vendor1.com/gpu=gpu1"})
+
+	_, err = expandCDIDeviceClasses(cache, []string{"class:vendor1.com/nic"})
+	assert.ErrorContains(t, err, "unknown CDI device class \"vendor1.com/nic\"")
+
+	_, err = expandCDIDeviceClasses(cache, []string{"class:gpu"})
+	assert.ErrorContains(t, err, "invalid CDI device class")
+}
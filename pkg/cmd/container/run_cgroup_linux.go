@@ -20,13 +20,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/docker/go-units"
+	"github.com/moby/sys/userns"
 	"github.com/opencontainers/runtime-spec/specs-go"
+	"golang.org/x/sys/unix"
 
 	"github.com/containerd/containerd/v2/core/containers"
+	"github.com/containerd/containerd/v2/pkg/cap"
 	"github.com/containerd/containerd/v2/pkg/oci"
 	"github.com/containerd/log"
 
@@ -42,7 +48,7 @@ type customMemoryOptions struct {
 	disableOOMKiller  *bool
 }
 
-func generateCgroupOpts(id string, options types.ContainerCreateOptions, internalLabels *internalLabels) ([]oci.SpecOpts, error) {
+func generateCgroupOpts(ctx context.Context, id string, options types.ContainerCreateOptions, internalLabels *internalLabels) ([]oci.SpecOpts, error) {
 	if options.KernelMemory != "" {
 		log.L.Warnf("The --kernel-memory flag is no longer supported. This flag is a noop.")
 	}
@@ -64,7 +70,11 @@ func generateCgroupOpts(id string, options types.ContainerCreateOptions, interna
 			log.L.Warnf(`cgroup manager is set to "none", ignoring cgroup parent %q`+
 				"(Hint: enable cgroup v2 with systemd: https://rootlesscontaine.rs/getting-started/common/cgroup2/)", options.CgroupParent)
 		}
-		return []oci.SpecOpts{oci.WithCgroup("")}, nil
+		cgroupnsOpt, err := generateCgroupnsOpt(options.Cgroupns)
+		if err != nil {
+			return nil, err
+		}
+		return []oci.SpecOpts{oci.WithCgroup(""), cgroupnsOpt}, nil
 	}
 
 	var opts []oci.SpecOpts // nolint: prealloc
@@ -75,43 +85,57 @@ func generateCgroupOpts(id string, options types.ContainerCreateOptions, interna
 	if path != "" {
 		opts = append(opts, oci.WithCgroup(path))
 	}
-
 	// cpus: from https://github.com/containerd/containerd/blob/v1.4.3/cmd/ctr/commands/run/run_unix.go#L187-L193
+	// effectiveQuota is the quota of --cpus or --cpu-quota, for validating --cpu-burst
+	var effectiveQuota int64
 	if options.CPUs > 0.0 {
 		var (
 			period = uint64(100000)
 			quota  = int64(options.CPUs * 100000.0)
 		)
 		opts = append(opts, oci.WithCPUCFS(quota, period))
+		effectiveQuota = quota
 	}
 
 	if options.CPUShares != 0 {
 		opts = append(opts, oci.WithCPUShares(options.CPUShares))
+		internalLabels.cpuShares = options.CPUShares
 	}
 
 	if options.CPUSetCPUs != "" {
+		if err := validateCPUSetCPUs(options.CPUSetCPUs, onlineCPUsPath); err != nil {
+			return nil, err
+		}
 		opts = append(opts, oci.WithCPUs(options.CPUSetCPUs))
+		internalLabels.cpusetCPUs = options.CPUSetCPUs
+		if options.CPUShares != 0 {
+			log.L.Debugf("--cpu-shares=%d is a weight relative to the other containers competing for the CPUs %q, "+
+				"it does not cap the CPU usage of the container; use --cpus or --cpu-quota for a hard limit",
+				options.CPUShares, options.CPUSetCPUs)
+		}
 	}
 	if options.CPUQuota != -1 || options.CPUPeriod != 0 {
 		if options.CPUs > 0.0 {
 			return nil, errors.New("cpus and quota/period should be used separately")
 		}
 		opts = append(opts, oci.WithCPUCFS(options.CPUQuota, options.CPUPeriod))
+		effectiveQuota = options.CPUQuota
+	}
+	if options.CPUBurst > 0 {
+		if infoutil.CgroupsVersion() != "2" {
+			return nil, errors.New("--cpu-burst requires cgroup v2")
+		}
+		if err := validateCPUBurst(options.CPUBurst, effectiveQuota, cgroupV2Root); err != nil {
+			return nil, err
+		}
+		opts = append(opts, withCPUBurst(options.CPUBurst))
 	}
 	if options.CPUSetMems != "" {
 		opts = append(opts, oci.WithCPUsMems(options.CPUSetMems))
 	}
 
-	if options.CPURealtimePeriod != 0 || options.CPURealtimeRuntime != 0 {
-		if !infoutil.CPURealtime(options.GOptions.CgroupManager) {
-			// CPU realtime scheduling is not supported in cgroup V2
-			return nil, errors.New("kernel does not support CPU real-time scheduler")
-		}
-
-		if options.CPURealtimePeriod != 0 && options.CPURealtimeRuntime != 0 &&
-			options.CPURealtimeRuntime > options.CPURealtimePeriod {
-			return nil, errors.New("cpu real-time runtime cannot be higher than cpu real-time period")
-		}
+	if err := validateCPURealtime(options.CPURealtimeRuntime, options.CPURealtimePeriod, options.GOptions.CgroupManager); err != nil {
+		return nil, err
 	}
 	opts = append(opts, oci.WithCPURT(int64(options.CPURealtimeRuntime), options.CPURealtimePeriod))
 
@@ -131,6 +155,9 @@ func generateCgroupOpts(id string, options types.ContainerCreateOptions, interna
 			return nil, fmt.Errorf("failed to parse memory bytes %q: %w", options.MemoryReservation, err)
 		}
 	}
+	if err := validateMemoryReservation(mem64, memReserve64); err != nil {
+		return nil, err
+	}
 	var memSwap64 int64
 	if options.MemorySwap != "" {
 		if options.MemorySwap == "-1" {
@@ -155,9 +182,6 @@ func generateCgroupOpts(id string, options types.ContainerCreateOptions, interna
 	if memSwap64 != 0 {
 		opts = append(opts, oci.WithMemorySwap(memSwap64))
 	}
-	if mem64 > 0 && memReserve64 > 0 && mem64 < memReserve64 {
-		return nil, fmt.Errorf("minimum memory limit can not be less than memory reservation limit, see usage")
-	}
 	if options.MemorySwappiness64 > 100 || options.MemorySwappiness64 < -1 {
 		return nil, fmt.Errorf("invalid value: %v, valid memory swappiness range is 0-100", options.MemorySwappiness64)
 	}
@@ -189,44 +213,241 @@ func generateCgroupOpts(id string, options types.ContainerCreateOptions, interna
 		if len(splitUnified) < 2 {
 			return nil, errors.New("--cgroup-conf must be formatted KEY=VALUE")
 		}
-		unifieds[splitUnified[0]] = splitUnified[1]
+		key, value := splitUnified[0], splitUnified[1]
+		switch key {
+		case "io.max", "io.latency":
+			resolved, err := resolveIOCgroupConf(key, value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid --cgroup-conf %q: %w", unified, err)
+			}
+			value = resolved
+		}
+		unifieds[key] = value
 	}
 	opts = append(opts, withUnified(unifieds))
 
-	blkioOpts, err := BlkioOCIOpts(options)
+	blkioOpts, err := BlkioOCIOpts(options, internalLabels)
 	if err != nil {
 		return nil, err
 	}
 	opts = append(opts, blkioOpts...)
 
-	switch options.Cgroupns {
-	case "private":
-		ns := specs.LinuxNamespace{
-			Type: specs.CgroupNamespace,
-		}
-		opts = append(opts, oci.WithLinuxNamespace(ns))
-	case "host":
-		opts = append(opts, oci.WithHostNamespace(specs.CgroupNamespace))
-	default:
-		return nil, fmt.Errorf("unknown cgroupns mode %q", options.Cgroupns)
+	cgroupnsOpt, err := generateCgroupnsOpt(options.Cgroupns)
+	if err != nil {
+		return nil, err
 	}
+	opts = append(opts, cgroupnsOpt)
 
 	for _, f := range options.Device {
-		devPath, conPath, mode, err := ParseDevice(f)
+		devPattern, conPattern, mode, err := ParseDevice(f)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse device %q: %w", f, err)
 		}
-		opts = append(opts, oci.WithDevices(devPath, conPath, mode))
-		var deviceMap dockercompat.DeviceMapping
-		deviceMap.PathOnHost = devPath
-		deviceMap.PathInContainer = conPath
-		deviceMap.CgroupPermissions = mode
-		internalLabels.deviceMapping = append(internalLabels.deviceMapping, deviceMap)
+		devPaths, err := expandDeviceGlob(devPattern, conPattern)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add device %q: %w", f, err)
+		}
+		for _, devPath := range devPaths {
+			conPath := conPattern
+			if conPath == devPattern {
+				conPath = devPath
+			}
+			deviceOpt, err := withDevice(devPath, conPath, mode)
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, deviceOpt)
+			if conPath == fuseDevice && !containerHasCapability(options.Privileged, options.CapAdd, options.CapDrop, "CAP_SYS_ADMIN") {
+				log.L.Warnf("device %q is added without CAP_SYS_ADMIN, so FUSE filesystems cannot be mounted in the container (hint: add `--cap-add=SYS_ADMIN`)", conPath)
+			}
+			var deviceMap dockercompat.DeviceMapping
+			deviceMap.PathOnHost = devPath
+			deviceMap.PathInContainer = conPath
+			deviceMap.CgroupPermissions = mode
+			internalLabels.deviceMapping = append(internalLabels.deviceMapping, deviceMap)
+		}
+	}
+
+	// The slice is created last, so that no transient unit is left behind when an option is invalid
+	if options.GOptions.CgroupManager == "systemd" && options.CgroupParent != "" {
+		if err := ensureSystemdSlice(ctx, options.CgroupParent); err != nil {
+			return nil, err
+		}
 	}
 
 	return opts, nil
 }
 
+// generateCgroupnsOpt returns the spec option for --cgroupns.
+// "private" is honored on cgroup v1 (and hybrid) hosts too, as long as the kernel supports cgroup namespaces (Linux 4.6+).
+// When it does not, the container falls back to the host cgroup namespace with a warning.
+func generateCgroupnsOpt(cgroupns string) (oci.SpecOpts, error) {
+	switch cgroupns {
+	case "private":
+		if !cgroupnsSupported() {
+			log.L.Warn("the kernel does not support cgroup namespaces, ignoring --cgroupns=private")
+			return oci.WithHostNamespace(specs.CgroupNamespace), nil
+		}
+		return oci.WithLinuxNamespace(specs.LinuxNamespace{Type: specs.CgroupNamespace}), nil
+	case "host":
+		return oci.WithHostNamespace(specs.CgroupNamespace), nil
+	default:
+		return nil, fmt.Errorf("unknown cgroupns mode %q", cgroupns)
+	}
+}
+
+// cgroupnsSupported returns whether the kernel supports cgroup namespaces.
+func cgroupnsSupported() bool {
+	_, err := os.Stat("/proc/self/ns/cgroup")
+	return err == nil
+}
+
+// validateMemoryReservation returns an error when the memory soft limit (--memory-reservation, memory.low on cgroup v2)
+// exceeds the hard limit (--memory, memory.max on cgroup v2), as the OCI runtime would fail to apply it.
+// Zero means unset, so either limit can be set alone.
+func validateMemoryReservation(memory, reservation int64) error {
+	if memory > 0 && reservation > memory {
+		return fmt.Errorf("--memory-reservation (%s) must be less than or equal to --memory (%s)",
+			units.BytesSize(float64(reservation)), units.BytesSize(float64(memory)))
+	}
+	return nil
+}
+
+// validateCPURealtime validates --cpu-rt-runtime and --cpu-rt-period,
+// and checks that the CPU real-time scheduler is available.
+func validateCPURealtime(runtime, period uint64, cgroupManager string) error {
+	if runtime == 0 && period == 0 {
+		return nil
+	}
+	if runtime != 0 && period != 0 && runtime > period {
+		return fmt.Errorf("cpu real-time runtime (%d) cannot be higher than cpu real-time period (%d)", runtime, period)
+	}
+	if rootlessutil.IsRootless() {
+		return errors.New("cpu real-time scheduler (--cpu-rt-runtime, --cpu-rt-period) is not supported in rootless mode")
+	}
+	if !infoutil.CPURealtime(cgroupManager) {
+		// CPU realtime scheduling is not supported in cgroup v2
+		return errors.New("kernel does not support CPU real-time scheduler: cpu.rt_runtime_us is not available " +
+			"(requires cgroup v1 and a kernel built with CONFIG_RT_GROUP_SCHED)")
+	}
+	return nil
+}
+
+// cgroupV2Root is the mount point of the cgroup v2 hierarchy.
+const cgroupV2Root = "/sys/fs/cgroup"
+
+// validateCPUBurst validates --cpu-burst against the rules of the kernel: cpu.max.burst must be supported
+// (Linux >= 5.14), and the burst must not exceed the quota.
+// Support is detected from the cpu.max.burst files of the cgroups directly under the cgroup v2 cgroupRoot.
+func validateCPUBurst(burst uint64, quota int64, cgroupRoot string) error {
+	if matches, _ := filepath.Glob(filepath.Join(cgroupRoot, "*", "cpu.max.burst")); len(matches) == 0 {
+		return errors.New("kernel does not support --cpu-burst: cpu.max.burst is not available (requires kernel >= 5.14 and the cpu controller)")
+	}
+	if quota <= 0 {
+		return errors.New("--cpu-burst requires a CPU quota (--cpus or --cpu-quota)")
+	}
+	if burst > uint64(quota) {
+		return fmt.Errorf("--cpu-burst (%d) cannot be higher than the CPU quota (%d)", burst, quota)
+	}
+	return nil
+}
+
+// withCPUBurst sets the CPU burst (cpu.max.burst) in microseconds.
+func withCPUBurst(burst uint64) oci.SpecOpts {
+	return func(_ context.Context, _ oci.Client, _ *containers.Container, s *oci.Spec) error {
+		if s.Linux == nil {
+			s.Linux = &specs.Linux{}
+		}
+		if s.Linux.Resources == nil {
+			s.Linux.Resources = &specs.LinuxResources{}
+		}
+		if s.Linux.Resources.CPU == nil {
+			s.Linux.Resources.CPU = &specs.LinuxCPU{}
+		}
+		s.Linux.Resources.CPU.Burst = &burst
+		return nil
+	}
+}
+
+// onlineCPUsPath is the list of the online CPUs, e.g., "0-7".
+const onlineCPUsPath = "/sys/devices/system/cpu/online"
+
+// validateCPUSetCPUs validates the syntax of --cpuset-cpus (e.g., "0-3", "0,2,4", "0-3,6"),
+// and checks that all its CPUs are listed in the file onlinePath.
+// The check against the online CPUs is skipped when onlinePath cannot be read.
+func validateCPUSetCPUs(cpus, onlinePath string) error {
+	set, err := parseCPUSet(cpus)
+	if err != nil {
+		return fmt.Errorf("invalid --cpuset-cpus %q: %w", cpus, err)
+	}
+	b, err := os.ReadFile(onlinePath)
+	if err != nil {
+		log.L.WithError(err).Debugf("failed to read the online CPUs, not validating --cpuset-cpus %q", cpus)
+		return nil
+	}
+	onlineStr := strings.TrimSpace(string(b))
+	online, err := parseCPUSet(onlineStr)
+	if err != nil {
+		return fmt.Errorf("failed to parse the online CPUs %q from %s: %w", onlineStr, onlinePath, err)
+	}
+	unavailable := -1
+	for _, r := range set {
+		if cpu, ok := firstCPUNotIn(r, online); !ok && (unavailable < 0 || cpu < unavailable) {
+			unavailable = cpu
+		}
+	}
+	if unavailable >= 0 {
+		return fmt.Errorf("invalid --cpuset-cpus %q: CPU %d is not available (online CPUs: %s)", cpus, unavailable, onlineStr)
+	}
+	return nil
+}
+
+// cpuRange is a range of CPUs, from low to high inclusive.
+type cpuRange struct {
+	low, high int
+}
+
+// parseCPUSet parses a list of CPUs in the cpuset format (e.g., "0-3,6") into its ranges of CPUs.
+// The ranges are not expanded, so that a large range such as "0-999999999" is cheap to check.
+func parseCPUSet(s string) ([]cpuRange, error) {
+	var ranges []cpuRange
+	for _, part := range strings.Split(s, ",") {
+		lowStr, highStr, isRange := strings.Cut(part, "-")
+		low, err := strconv.Atoi(lowStr)
+		if err != nil || low < 0 {
+			return nil, fmt.Errorf("invalid CPU %q", lowStr)
+		}
+		high := low
+		if isRange {
+			high, err = strconv.Atoi(highStr)
+			if err != nil || high < 0 {
+				return nil, fmt.Errorf("invalid CPU %q", highStr)
+			}
+			if high < low {
+				return nil, fmt.Errorf("invalid CPU range %q: the end is lower than the start", part)
+			}
+		}
+		ranges = append(ranges, cpuRange{low: low, high: high})
+	}
+	return ranges, nil
+}
+
+// firstCPUNotIn returns the lowest CPU of r that is not in any of the ranges of set.
+// It returns false if there is such a CPU, and true if r is covered by set.
+func firstCPUNotIn(r cpuRange, set []cpuRange) (int, bool) {
+	cpu := r.low
+	for {
+		i := slices.IndexFunc(set, func(s cpuRange) bool { return s.low <= cpu && cpu <= s.high })
+		if i < 0 {
+			return cpu, false
+		}
+		if set[i].high >= r.high {
+			return 0, true
+		}
+		cpu = set[i].high + 1
+	}
+}
+
 func generateCgroupPath(id, cgroupManager, cgroupParent string) (string, error) {
 	var (
 		path         string
@@ -254,8 +475,8 @@ func generateCgroupPath(id, cgroupManager, cgroupParent string) (string, error)
 	//
 	// In the non systemd case, it's just /parent/containerID
 	if usingSystemd {
-		if len(cgroupParent) <= 6 || !strings.HasSuffix(cgroupParent, ".slice") {
-			return "", errors.New(`cgroup-parent for systemd cgroup should be a valid slice named as "xxx.slice"`)
+		if err := validateSystemdSliceName(cgroupParent); err != nil {
+			return "", err
 		}
 		path = cgroupParent + scopePrefix + id
 	} else {
@@ -265,6 +486,98 @@ func generateCgroupPath(id, cgroupManager, cgroupParent string) (string, error)
 	return path, nil
 }
 
+// fuseDevice is the device needed by FUSE filesystems, which also need CAP_SYS_ADMIN to be mounted.
+const fuseDevice = "/dev/fuse"
+
+// creatableDevice is a device node that can be created in the container, and the kernel module providing it.
+type creatableDevice struct {
+	specs.LinuxDevice
+	module string
+}
+
+// creatableDevices are the devices that can be created in the container even when they do not exist on the host.
+// e.g., /dev/net/tun is often missing on the host until the tun module is loaded, but is needed to run VPNs.
+var creatableDevices = map[string]creatableDevice{
+	"/dev/net/tun": {LinuxDevice: specs.LinuxDevice{Type: "c", Major: 10, Minor: 200}, module: "tun"},
+	"/dev/fuse":    {LinuxDevice: specs.LinuxDevice{Type: "c", Major: 10, Minor: 229}, module: "fuse"},
+}
+
+// withDevice returns oci.WithDevices for devPath, unless devPath does not exist on the host and is
+// one of creatableDevices, in which case the device node is created in the container by the OCI runtime.
+func withDevice(devPath, conPath, mode string) (oci.SpecOpts, error) {
+	dev, ok := creatableDevices[devPath]
+	if !ok {
+		return oci.WithDevices(devPath, conPath, mode), nil
+	}
+	if _, err := os.Stat(devPath); !errors.Is(err, os.ErrNotExist) {
+		return oci.WithDevices(devPath, conPath, mode), nil
+	}
+	// The OCI runtime cannot mknod in a user namespace, it bind-mounts the device from the host instead
+	if rootlessutil.IsRootless() || userns.RunningInUserNS() {
+		return nil, fmt.Errorf("device %q does not exist on the host, and cannot be created in a user namespace (hint: load the kernel module, e.g., `modprobe %s`)", devPath, dev.module)
+	}
+	caps, err := cap.Current()
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(caps, "CAP_MKNOD") {
+		return nil, fmt.Errorf("device %q does not exist on the host, and cannot be created without CAP_MKNOD", devPath)
+	}
+	log.L.Debugf("device %q does not exist on the host, creating it in the container", devPath)
+	return func(_ context.Context, _ oci.Client, _ *containers.Container, s *oci.Spec) error {
+		fileMode := os.FileMode(0o666)
+		var uid, gid uint32
+		d := dev.LinuxDevice
+		d.Path = conPath
+		d.FileMode = &fileMode
+		d.UID = &uid
+		d.GID = &gid
+		s.Linux.Devices = append(s.Linux.Devices, d)
+		if s.Linux.Resources == nil {
+			s.Linux.Resources = &specs.LinuxResources{}
+		}
+		s.Linux.Resources.Devices = append(s.Linux.Resources.Devices, specs.LinuxDeviceCgroup{
+			Allow:  true,
+			Type:   d.Type,
+			Major:  &d.Major,
+			Minor:  &d.Minor,
+			Access: mode,
+		})
+		return nil
+	}, nil
+}
+
+// expandDeviceGlob returns the device nodes matching devPath when it is a glob pattern, e.g. "/dev/snd/*".
+// Directories, symlinks and other files matching the pattern are skipped, and an error is returned when no
+// device node matches. The device nodes keep their host paths in the container, so conPath cannot differ from
+// the pattern. When devPath is not a glob pattern, it is returned as is.
+func expandDeviceGlob(devPath, conPath string) ([]string, error) {
+	if !strings.ContainsAny(devPath, "*?[") {
+		return []string{devPath}, nil
+	}
+	if conPath != devPath {
+		return nil, fmt.Errorf("the container path cannot be specified with the glob pattern %q", devPath)
+	}
+	matches, err := filepath.Glob(devPath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid glob pattern %q: %w", devPath, err)
+	}
+	var devPaths []string
+	for _, match := range matches {
+		fi, err := os.Lstat(match)
+		if err != nil {
+			return nil, err
+		}
+		if fi.Mode()&os.ModeDevice != 0 {
+			devPaths = append(devPaths, match)
+		}
+	}
+	if len(devPaths) == 0 {
+		return nil, fmt.Errorf("no device node matches %q", devPath)
+	}
+	return devPaths, nil
+}
+
 // ParseDevice parses the give device string into hostDevPath, containerPath and mode(defaults: "rwm").
 func ParseDevice(s string) (hostDevPath string, containerPath string, mode string, err error) {
 	mode = "rwm"
@@ -311,6 +624,60 @@ func validateDeviceMode(mode string) error {
 	return nil
 }
 
+// resolveIOCgroupConf validates the value of the "io.max" and "io.latency" cgroup v2 files,
+// e.g. "/dev/sda rbps=1048576 wiops=max" or "8:0 target=10".
+// When the device is specified as a path, it is resolved to "MAJ:MIN".
+func resolveIOCgroupConf(key, value string) (string, error) {
+	fields := strings.Fields(value)
+	if len(fields) < 2 {
+		return "", fmt.Errorf("%s must be formatted as \"DEVICE KEY=VALUE...\"", key)
+	}
+	device := fields[0]
+	if strings.HasPrefix(device, "/") {
+		var stat unix.Stat_t
+		if err := unix.Stat(device, &stat); err != nil {
+			return "", fmt.Errorf("failed to stat %s: %w", device, err)
+		}
+		if stat.Mode&unix.S_IFMT != unix.S_IFBLK {
+			return "", fmt.Errorf("%s is not a block device", device)
+		}
+		// The type is 32bit on mips.
+		device = fmt.Sprintf("%d:%d", unix.Major(uint64(stat.Rdev)), unix.Minor(uint64(stat.Rdev))) //nolint: unconvert
+	} else if !isMajorMinor(device) {
+		return "", fmt.Errorf("invalid device %q: must be a device path or MAJ:MIN", device)
+	}
+
+	validKeys := []string{"rbps", "wbps", "riops", "wiops"}
+	if key == "io.latency" {
+		validKeys = []string{"target"}
+	}
+	for _, f := range fields[1:] {
+		k, v, ok := strings.Cut(f, "=")
+		if !ok || !slices.Contains(validKeys, k) {
+			return "", fmt.Errorf("invalid %s key %q, supported keys are: %q", key, f, validKeys)
+		}
+		if v == "max" && key == "io.max" {
+			continue
+		}
+		if _, err := strconv.ParseUint(v, 10, 64); err != nil {
+			return "", fmt.Errorf("invalid %s value %q: %w", key, f, err)
+		}
+	}
+	return strings.Join(append([]string{device}, fields[1:]...), " "), nil
+}
+
+func isMajorMinor(s string) bool {
+	major, minor, ok := strings.Cut(s, ":")
+	if !ok {
+		return false
+	}
+	if _, err := strconv.ParseUint(major, 10, 32); err != nil {
+		return false
+	}
+	_, err := strconv.ParseUint(minor, 10, 32)
+	return err == nil
+}
+
 func withUnified(unified map[string]string) oci.SpecOpts {
 	return func(_ context.Context, _ oci.Client, _ *containers.Container, s *oci.Spec) (err error) {
 		if unified == nil {
@@ -0,0 +1,200 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/v2/pkg/infoutil"
+	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
+)
+
+func TestResolveIOCgroupConf(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		key         string
+		value       string
+		expected    string
+		expectError string
+	}{
+		{key: "io.max", value: "8:0 rbps=1048576 wiops=max", expected: "8:0 rbps=1048576 wiops=max"},
+		{key: "io.latency", value: "8:0  target=10", expected: "8:0 target=10"},
+		{key: "io.max", value: "8:0", expectError: "must be formatted"},
+		{key: "io.max", value: "8:0 foo=1", expectError: "invalid io.max key"},
+		{key: "io.max", value: "8:0 rbps=-1", expectError: "invalid io.max value"},
+		{key: "io.latency", value: "8:0 target=max", expectError: "invalid io.latency value"},
+		{key: "io.latency", value: "8:0 rbps=1", expectError: "invalid io.latency key"},
+		{key: "io.max", value: "sda rbps=1", expectError: "must be a device path or MAJ:MIN"},
+		{key: "io.max", value: "/dev/nonexistent rbps=1", expectError: "failed to stat"},
+		{key: "io.max", value: "/dev/null rbps=1", expectError: "is not a block device"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.key+"="+tc.value, func(t *testing.T) {
+			t.Parallel()
+			resolved, err := resolveIOCgroupConf(tc.key, tc.value)
+			if tc.expectError != "" {
+				assert.ErrorContains(t, err, tc.expectError)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expected, resolved)
+		})
+	}
+}
+
+func TestValidateCPURealtime(t *testing.T) {
+	t.Parallel()
+	assert.NilError(t, validateCPURealtime(0, 0, "cgroupfs"))
+
+	err := validateCPURealtime(2000000, 1000000, "cgroupfs")
+	assert.ErrorContains(t, err, "cannot be higher than cpu real-time period")
+
+	if rootlessutil.IsRootless() {
+		assert.ErrorContains(t, validateCPURealtime(950000, 1000000, "cgroupfs"), "not supported in rootless mode")
+	} else if !infoutil.CPURealtime("cgroupfs") {
+		assert.ErrorContains(t, validateCPURealtime(950000, 1000000, "cgroupfs"), "kernel does not support CPU real-time scheduler")
+	}
+}
+
+func TestValidateCPUSetCPUs(t *testing.T) {
+	t.Parallel()
+	online := filepath.Join(t.TempDir(), "online")
+	assert.NilError(t, os.WriteFile(online, []byte("0-7\n"), 0o644))
+
+	tests := []struct {
+		cpus        string
+		expectError string
+	}{
+		{cpus: "0"},
+		{cpus: "0-7"},
+		{cpus: "0,2,4"},
+		{cpus: "0-1,3,5-6"},
+		{cpus: "0-31", expectError: `invalid --cpuset-cpus "0-31": CPU 8 is not available (online CPUs: 0-7)`},
+		{cpus: "1,3,9-10", expectError: `CPU 9 is not available (online CPUs: 0-7)`},
+		{cpus: "0-999999999", expectError: `CPU 8 is not available (online CPUs: 0-7)`},
+		{cpus: "999999999-1999999999,5-9", expectError: `CPU 8 is not available (online CPUs: 0-7)`},
+		{cpus: "3-1", expectError: "the end is lower than the start"},
+		{cpus: "0,,1", expectError: `invalid CPU ""`},
+		{cpus: "a-b", expectError: `invalid CPU "a"`},
+		{cpus: "-1", expectError: `invalid CPU ""`},
+	}
+	for _, tc := range tests {
+		err := validateCPUSetCPUs(tc.cpus, online)
+		if tc.expectError == "" {
+			assert.NilError(t, err, tc.cpus)
+		} else {
+			assert.ErrorContains(t, err, tc.expectError, tc.cpus)
+		}
+	}
+
+	// The online CPUs may be split into several ranges
+	assert.NilError(t, os.WriteFile(online, []byte("0-3,4,6-7\n"), 0o644))
+	assert.NilError(t, validateCPUSetCPUs("1-4,6-7", online))
+	assert.ErrorContains(t, validateCPUSetCPUs("2-7", online), `CPU 5 is not available (online CPUs: 0-3,4,6-7)`)
+
+	// Only the syntax is validated when the online CPUs are unknown
+	assert.NilError(t, validateCPUSetCPUs("0-999999999", filepath.Join(t.TempDir(), "nonexistent")))
+	assert.ErrorContains(t, validateCPUSetCPUs("1-", filepath.Join(t.TempDir(), "nonexistent")), `invalid CPU ""`)
+}
+
+func TestValidateSystemdSliceName(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		expectError string
+	}{
+		{name: "batch.slice"},
+		{name: "batch-jobs.slice"},
+		{name: "-.slice"},
+		{name: `a\x2db.slice`},
+		{name: "batch", expectError: `should be a valid slice named as "xxx.slice"`},
+		{name: ".slice", expectError: `should be a valid slice named as "xxx.slice"`},
+		{name: "batch/jobs.slice", expectError: `contains the invalid character '/'`},
+		{name: "-batch.slice", expectError: "has an empty level in its hierarchy"},
+		{name: "batch-.slice", expectError: "has an empty level in its hierarchy"},
+		{name: "batch--jobs.slice", expectError: "has an empty level in its hierarchy"},
+		{name: strings.Repeat("a", 250) + ".slice", expectError: "is too long"},
+	}
+	for _, tc := range tests {
+		err := validateSystemdSliceName(tc.name)
+		if tc.expectError == "" {
+			assert.NilError(t, err, tc.name)
+		} else {
+			assert.ErrorContains(t, err, tc.expectError, tc.name)
+		}
+	}
+}
+
+func TestValidateMemoryReservation(t *testing.T) {
+	t.Parallel()
+	assert.NilError(t, validateMemoryReservation(0, 0))
+	assert.NilError(t, validateMemoryReservation(0, 6*1024*1024))
+	assert.NilError(t, validateMemoryReservation(42*1024*1024, 0))
+	assert.NilError(t, validateMemoryReservation(42*1024*1024, 6*1024*1024))
+	assert.NilError(t, validateMemoryReservation(42*1024*1024, 42*1024*1024))
+	assert.ErrorContains(t, validateMemoryReservation(6*1024*1024, 42*1024*1024),
+		"--memory-reservation (42MiB) must be less than or equal to --memory (6MiB)")
+}
+
+func TestExpandDeviceGlob(t *testing.T) {
+	t.Parallel()
+
+	devPaths, err := expandDeviceGlob("/dev/sda1", "/dev/foo1")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, devPaths, []string{"/dev/sda1"})
+
+	devPaths, err = expandDeviceGlob("/dev/nul[l]", "/dev/nul[l]")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, devPaths, []string{"/dev/null"})
+
+	// only device nodes are added
+	notDevices := t.TempDir()
+	assert.NilError(t, os.Mkdir(filepath.Join(notDevices, "by-path"), 0o755))
+	assert.NilError(t, os.WriteFile(filepath.Join(notDevices, "file"), nil, 0o644))
+	assert.NilError(t, os.Symlink("/dev/null", filepath.Join(notDevices, "link")))
+	_, err = expandDeviceGlob(notDevices+"/*", notDevices+"/*")
+	assert.ErrorContains(t, err, "no device node matches")
+
+	_, err = expandDeviceGlob("/dev/nul*", "/dev/foo")
+	assert.ErrorContains(t, err, "the container path cannot be specified with the glob pattern")
+
+	_, err = expandDeviceGlob("/dev/[", "/dev/[")
+	assert.ErrorContains(t, err, "invalid glob pattern")
+}
+
+func TestValidateCPUBurst(t *testing.T) {
+	t.Parallel()
+
+	unsupported := t.TempDir()
+	assert.NilError(t, os.Mkdir(filepath.Join(unsupported, "system.slice"), 0o755))
+	assert.NilError(t, os.WriteFile(filepath.Join(unsupported, "system.slice", "cpu.max"), []byte("max 100000\n"), 0o644))
+	assert.ErrorContains(t, validateCPUBurst(10000, 50000, unsupported), "cpu.max.burst is not available")
+
+	supported := t.TempDir()
+	assert.NilError(t, os.Mkdir(filepath.Join(supported, "system.slice"), 0o755))
+	assert.NilError(t, os.WriteFile(filepath.Join(supported, "system.slice", "cpu.max.burst"), []byte("0\n"), 0o644))
+	assert.NilError(t, validateCPUBurst(10000, 50000, supported))
+	assert.NilError(t, validateCPUBurst(50000, 50000, supported))
+	assert.ErrorContains(t, validateCPUBurst(60000, 50000, supported), "--cpu-burst (60000) cannot be higher than the CPU quota (50000)")
+	assert.ErrorContains(t, validateCPUBurst(10000, 0, supported), "requires a CPU quota")
+	assert.ErrorContains(t, validateCPUBurst(10000, -1, supported), "requires a CPU quota")
+}
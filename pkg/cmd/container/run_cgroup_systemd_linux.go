@@ -0,0 +1,125 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	systemddbus "github.com/coreos/go-systemd/v22/dbus"
+	"github.com/godbus/dbus/v5"
+
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
+)
+
+// systemdUnitNameMax is the maximum length of a systemd unit name (UNIT_NAME_MAX - 1).
+const systemdUnitNameMax = 255
+
+// validateSystemdSliceName checks that name is a valid systemd slice unit name.
+// In slice names, "-" separates the levels of the hierarchy, e.g. "a-b.slice" is a child of "a.slice",
+// so empty levels ("-a.slice", "a-.slice", "a--b.slice") are not allowed. "-.slice" is the root slice.
+func validateSystemdSliceName(name string) error {
+	if len(name) > systemdUnitNameMax {
+		return fmt.Errorf("cgroup-parent %q for systemd cgroup is too long (max %d characters)", name, systemdUnitNameMax)
+	}
+	prefix, ok := strings.CutSuffix(name, ".slice")
+	if !ok || prefix == "" {
+		return fmt.Errorf("cgroup-parent %q for systemd cgroup should be a valid slice named as \"xxx.slice\"", name)
+	}
+	if prefix == "-" {
+		return nil
+	}
+	for _, c := range prefix {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.ContainsRune(":_.-\\", c)) {
+			return fmt.Errorf("cgroup-parent %q for systemd cgroup contains the invalid character %q", name, c)
+		}
+	}
+	if strings.HasPrefix(prefix, "-") || strings.HasSuffix(prefix, "-") || strings.Contains(prefix, "--") {
+		return fmt.Errorf("cgroup-parent %q for systemd cgroup has an empty level in its hierarchy (\"-\" separates the levels, e.g. \"parent-child.slice\")", name)
+	}
+	return nil
+}
+
+// ensureSystemdSlice creates the slice as a transient unit when it is not active yet,
+// so that containers can be placed in a grouping slice that has no unit file.
+// When systemd cannot be reached, the slice is left to the OCI runtime.
+func ensureSystemdSlice(ctx context.Context, slice string) error {
+	conn, err := newSystemdConn(ctx)
+	if err != nil {
+		log.G(ctx).WithError(err).Warnf("failed to connect to systemd, not ensuring that the cgroup parent %q exists", slice)
+		return nil
+	}
+	defer conn.Close()
+
+	prop, err := conn.GetUnitPropertyContext(ctx, slice, "ActiveState")
+	if err != nil {
+		return fmt.Errorf("failed to get the state of the cgroup parent %q: %w", slice, err)
+	}
+	if state, _ := prop.Value.Value().(string); state == "active" {
+		return nil
+	}
+
+	log.G(ctx).Debugf("creating the cgroup parent %q as a transient systemd slice", slice)
+	ch := make(chan string, 1)
+	props := []systemddbus.Property{systemddbus.PropDescription("nerdctl cgroup parent " + slice)}
+	if _, err := conn.StartTransientUnitContext(ctx, slice, "fail", props, ch); err != nil {
+		var dbusErr dbus.Error
+		if errors.As(err, &dbusErr) && dbusErr.Name == "org.freedesktop.systemd1.UnitExists" {
+			// created concurrently, e.g. by another container of the same batch
+			return nil
+		}
+		return fmt.Errorf("failed to create the cgroup parent %q: %w", slice, err)
+	}
+	select {
+	case result := <-ch:
+		if result != "done" {
+			return fmt.Errorf("failed to create the cgroup parent %q: systemd job %s", slice, result)
+		}
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
+
+// newSystemdConn connects to the systemd instance managing the cgroups of the containers:
+// the system instance, or the user instance for rootless.
+func newSystemdConn(ctx context.Context) (*systemddbus.Conn, error) {
+	if !rootlessutil.IsRootlessChild() {
+		return systemddbus.NewSystemConnectionContext(ctx)
+	}
+	// In the user namespace of RootlessKit, os.Getuid() is 0, so authenticate with the uid of the host
+	return systemddbus.NewConnection(func() (*dbus.Conn, error) {
+		conn, err := dbus.SessionBusPrivateNoAutoStartup(dbus.WithContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+		if err := conn.Auth([]dbus.Auth{dbus.AuthExternal(strconv.Itoa(rootlessutil.ParentEUID()))}); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		if err := conn.Hello(); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		return conn, nil
+	})
+}
@@ -29,6 +29,8 @@ type GPUReq struct {
 	Count        int
 	DeviceIDs    []string
 	Capabilities []string
+	// Fraction is the fraction (0, 1] of the GPU time requested for time-slicing. 0 means unset.
+	Fraction float64
 }
 
 // ParseGPUOptCSV parses a GPU option from CSV.
@@ -75,6 +77,11 @@ func ParseGPUOptCSV(value string) (*GPUReq, error) {
 			req.DeviceIDs = strings.Split(value, ",")
 		case "capabilities":
 			req.Capabilities = strings.Split(value, ",")
+		case "fraction":
+			req.Fraction, err = parseFraction(value)
+			if err != nil {
+				return nil, err
+			}
 		case "options":
 			// This option is allowed but not used for gpus.
 			// Please see also: https://github.com/moby/moby/pull/38828
@@ -86,6 +93,13 @@ func ParseGPUOptCSV(value string) (*GPUReq, error) {
 	if req.Count != 0 && len(req.DeviceIDs) > 0 {
 		return nil, errors.New("cannot set both Count and DeviceIDs on device request")
 	}
+	if req.Fraction != 0 {
+		for _, id := range req.DeviceIDs {
+			if isMIGDeviceID(id) {
+				return nil, fmt.Errorf("fraction cannot be used with the MIG device %q", id)
+			}
+		}
+	}
 	if _, ok := seen["count"]; !ok && len(req.DeviceIDs) == 0 {
 		req.Count = 1
 	}
@@ -103,3 +117,20 @@ func parseCount(s string) (int, error) {
 	}
 	return i, nil
 }
+
+func parseFraction(s string) (float64, error) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("fraction must be a number: %w", err)
+	}
+	if f <= 0 || f > 1 {
+		return 0, fmt.Errorf("fraction must be greater than 0 and less than or equal to 1, got %s", s)
+	}
+	return f, nil
+}
+
+// isMIGDeviceID returns true if id selects a MIG (Multi-Instance GPU) device,
+// either by its UUID ("MIG-<UUID>") or by its index ("<GPU>:<MIG>").
+func isMIGDeviceID(id string) bool {
+	return strings.HasPrefix(id, "MIG-") || strings.Contains(id, ":")
+}
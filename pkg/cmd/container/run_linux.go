@@ -18,7 +18,9 @@ package container
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/moby/sys/userns"
@@ -30,6 +32,7 @@ import (
 	"github.com/containerd/containerd/v2/pkg/oci"
 	"github.com/containerd/log"
 
+	"github.com/containerd/nerdctl/v2/pkg/annotations"
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/bypass4netnsutil"
 	"github.com/containerd/nerdctl/v2/pkg/containerutil"
@@ -56,7 +59,7 @@ func setPlatformOptions(ctx context.Context, client *containerd.Client, id, uts
 			{Type: "cgroup", Source: "cgroup", Destination: "/sys/fs/cgroup", Options: []string{"ro", "nosuid", "noexec", "nodev"}},
 		}))
 
-	cgOpts, err := generateCgroupOpts(id, options, internalLabels)
+	cgOpts, err := generateCgroupOpts(ctx, id, options, internalLabels)
 	if err != nil {
 		return nil, err
 	}
@@ -84,10 +87,13 @@ func setPlatformOptions(ctx context.Context, client *containerd.Client, id, uts
 	}
 	opts = append(opts, b4nnOpts...)
 
-	ulimitOpts, err := generateUlimitsOpts(options.Ulimit)
+	ulimits, err := parseUlimits(options.Ulimit)
 	if err != nil {
 		return nil, err
 	}
+	internalLabels.ulimits = ulimits
+	warnUlimitCapabilities(ulimits, options.Privileged, options.CapAdd, options.CapDrop)
+	ulimitOpts := generateUlimitsOpts(ulimits)
 
 	// If without any ulimitOpts, we need to reset the default value from spec
 	// which has 1024 as file limit. Make this behavior same as containerd/cri.
@@ -255,6 +261,8 @@ func setOOMScoreAdj(opts []oci.SpecOpts, oomScoreAdjChanged bool, oomScoreAdj in
 	return opts, nil
 }
 
+// withOOMScoreAdj sets the score on the process of the spec, which is the init binary with --init.
+// The score is inherited across fork, so the workload started by the init gets the same score.
 func withOOMScoreAdj(score int) oci.SpecOpts {
 	return func(_ context.Context, _ oci.Client, _ *containers.Container, s *oci.Spec) error {
 		s.Process.OOMScoreAdj = &score
@@ -263,22 +271,29 @@ func withOOMScoreAdj(score int) oci.SpecOpts {
 }
 
 func parseGPUOpts(value []string) (res []oci.SpecOpts, _ error) {
+	var fraction float64
 	for _, gpu := range value {
-		gpuOpt, err := parseGPUOpt(gpu)
+		req, err := ParseGPUOptCSV(gpu)
 		if err != nil {
 			return nil, err
 		}
-		res = append(res, gpuOpt)
+		if req.Fraction != 0 {
+			if fraction != 0 {
+				return nil, errors.New("fraction can be specified only for a single --gpus request")
+			}
+			fraction = req.Fraction
+		}
+		res = append(res, gpuOptFromReq(req))
+	}
+	if fraction != 0 {
+		res = append(res, oci.WithAnnotations(map[string]string{
+			annotations.GPUFraction: strconv.FormatFloat(fraction, 'f', -1, 64),
+		}))
 	}
 	return res, nil
 }
 
-func parseGPUOpt(value string) (oci.SpecOpts, error) {
-	req, err := ParseGPUOptCSV(value)
-	if err != nil {
-		return nil, err
-	}
-
+func gpuOptFromReq(req *GPUReq) oci.SpecOpts {
 	var gpuOpts []nvidia.Opts
 
 	if len(req.DeviceIDs) > 0 {
@@ -317,5 +332,5 @@ func parseGPUOpt(value string) (oci.SpecOpts, error) {
 		gpuOpts = append(gpuOpts, nvidia.WithNoCgroups)
 	}
 
-	return nvidia.WithGPUs(gpuOpts...), nil
+	return nvidia.WithGPUs(gpuOpts...)
 }
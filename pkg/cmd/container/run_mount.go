@@ -24,7 +24,9 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -43,9 +45,11 @@ import (
 	"github.com/containerd/log"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/image"
 	"github.com/containerd/nerdctl/v2/pkg/idgen"
 	"github.com/containerd/nerdctl/v2/pkg/imgutil"
 	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/dockercompat"
+	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/native"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
 	"github.com/containerd/nerdctl/v2/pkg/mountutil"
 	"github.com/containerd/nerdctl/v2/pkg/mountutil/volumestore"
@@ -91,41 +95,267 @@ func withMounts(mounts []specs.Mount) oci.SpecOpts {
 	}
 }
 
+// mountPoint describes the flag that claimed a mount destination.
+type mountPoint struct {
+	spec string
+	// replace is true for a --mount with the "replace" option, which overrides the other mounts of the destination.
+	replace bool
+}
+
+// mountPointSet maps a cleaned mount destination to the flag that claimed it.
+type mountPointSet map[string]mountPoint
+
+// add records dst as claimed by spec, and fails if dst was already claimed by another spec.
+func (set mountPointSet) add(dst, spec string) error {
+	dst = filepath.Clean(dst)
+	if prev, ok := set[dst]; ok {
+		return fmt.Errorf("duplicate mount point %q: %s conflicts with %s", dst, spec, prev.spec)
+	}
+	set[dst] = mountPoint{spec: spec}
+	return nil
+}
+
+// addReplacing records dst as claimed by spec, overriding the other mounts of dst.
+// It fails if dst was already claimed by another replacing spec.
+func (set mountPointSet) addReplacing(dst, spec string) error {
+	dst = filepath.Clean(dst)
+	if prev, ok := set[dst]; ok && prev.replace {
+		return fmt.Errorf("duplicate mount point %q: %s conflicts with %s", dst, spec, prev.spec)
+	}
+	set[dst] = mountPoint{spec: spec, replace: true}
+	return nil
+}
+
+// replaced returns true if dst is claimed by a replacing spec, in which case
+// the mount described by spec must be dropped.
+func (set mountPointSet) replaced(dst, spec string) bool {
+	if prev, ok := set[filepath.Clean(dst)]; ok && prev.replace {
+		log.L.Debugf("mount point %q: %s is replaced by %s", dst, spec, prev.spec)
+		return true
+	}
+	return false
+}
+
+// claimedBy returns true if dst is claimed by spec, i.e., the mount described by spec is not replaced.
+func (set mountPointSet) claimedBy(dst, spec string) bool {
+	return set[filepath.Clean(dst)].spec == spec
+}
+
+// releaseVolumeDriverMounts unmounts the volumes of mountPoints mounted by their drivers.
+// It is called when the mount points are discarded, e.g. when creating the container fails, as the mounts
+// are only released by the removal of the container once they are recorded in its labels.
+// The mount IDs are cleared, so that a mount is never released twice.
+func releaseVolumeDriverMounts(ctx context.Context, mountPoints ...*mountutil.Processed) {
+	for _, p := range mountPoints {
+		if err := mountutil.ReleaseDriverMount(ctx, p); err != nil {
+			log.G(ctx).WithError(err).Warnf("failed to unmount volume %q with driver %q", p.Name, p.Driver)
+		}
+		p.DriverMountID = ""
+	}
+}
+
+// releaseImageMounts unmounts the rootfs of the images of the image mounts of mountPoints mounted on the host.
+// Like releaseVolumeDriverMounts, it is called when the mount points are discarded.
+// The views of the rootfs are garbage collected once their lease expires.
+func releaseImageMounts(mountPoints ...*mountutil.Processed) {
+	for _, p := range mountPoints {
+		if err := mountutil.ReleaseImageMount(p); err != nil {
+			log.L.WithError(err).Warnf("failed to unmount image %q", p.Name)
+		}
+		p.ImageMount = nil
+	}
+}
+
+// checkRecursiveReadOnlyMounts checks that the runtime supports the "rro" option of the recursively
+// read-only bind mounts of mountPoints, according to its features.
+func checkRecursiveReadOnlyMounts(ctx context.Context, client *containerd.Client, runtimeStr string, mountPoints []*mountutil.Processed) error {
+	if !slices.ContainsFunc(mountPoints, func(p *mountutil.Processed) bool { return slices.Contains(p.Mount.Options, "rro") }) {
+		return nil
+	}
+	supported, ok := runtimeMountOptions(ctx, client, runtimeStr)
+	if !ok {
+		// The runtime does not report its features: keep "rro", which is ignored by older runtimes as "ro" is set too
+		return nil
+	}
+	return dropUnsupportedRecursiveReadOnly(mountPoints, supported)
+}
+
+// dropUnsupportedRecursiveReadOnly removes the "rro" option from the mounts of mountPoints when it is not
+// in the mount options supported by the runtime, falling back to a non-recursive read-only mount with a warning.
+// It fails for the mounts that require it (bind-recursive=readonly).
+func dropUnsupportedRecursiveReadOnly(mountPoints []*mountutil.Processed, supported []string) error {
+	if slices.Contains(supported, "rro") {
+		return nil
+	}
+	for _, p := range mountPoints {
+		if !slices.Contains(p.Mount.Options, "rro") {
+			continue
+		}
+		if p.RecursiveReadOnlyRequired {
+			return fmt.Errorf("failed to make bind mount %q recursively read-only: the runtime does not support recursive read-only mounts", p.Mount.Source)
+		}
+		log.L.Warnf("submounts of the read-only bind mount %q remain writable: the runtime does not support recursive read-only mounts", p.Mount.Source)
+		p.Mount.Options = slices.DeleteFunc(p.Mount.Options, func(o string) bool { return o == "rro" })
+	}
+	return nil
+}
+
+// mountImage resolves the image of the image mount x, pinned to its digest, mounts its rootfs read-only
+// on mountpoint, and sets the source of x to its subpath in the rootfs.
+func mountImage(ctx context.Context, client *containerd.Client, x *mountutil.Processed, mountpoint string, options types.ContainerCreateOptions) error {
+	ensured, err := image.EnsureImage(ctx, client, x.Name, options.ImagePullOpt)
+	if err != nil {
+		return fmt.Errorf("failed to get image %q to mount on %q: %w", x.Name, x.Mount.Destination, err)
+	}
+	x.ImageMount, err = mountutil.MountImage(ctx, client, ensured.Image, options.GOptions.Snapshotter, mountpoint)
+	if err != nil {
+		return fmt.Errorf("failed to mount image %q: %w", x.Name, err)
+	}
+	x.Name = x.ImageMount.Image
+	if err := mountutil.WithImageSubpath(x); err != nil {
+		if rmErr := x.ImageMount.Remove(ctx, client); rmErr != nil {
+			log.G(ctx).WithError(rmErr).Warnf("failed to remove the rootfs of image %q", x.Name)
+		}
+		x.ImageMount = nil
+		return err
+	}
+	return nil
+}
+
 // parseMountFlags parses --volume, --mount and --tmpfs.
-func parseMountFlags(volStore volumestore.VolumeStore, options types.ContainerCreateOptions) ([]*mountutil.Processed, error) {
+// The destinations of the flags and of volumesFrom are checked for collisions by checkMountPoints
+// before any flag is processed, so that a rejected command does not leave the volumes it created behind.
+// The mounts replaced by a --mount with the "replace" option are dropped without being processed.
+// The volumes mounted by their drivers are released on error.
+// It returns the parsed mounts, and the flag that claimed each destination.
+func parseMountFlags(ctx context.Context, volStore volumestore.VolumeStore, options types.ContainerCreateOptions, volumesFrom []volumesFromSource) (_ []*mountutil.Processed, _ mountPointSet, retErr error) {
+	mounted, err := checkMountPoints(options, volumesFrom)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	var parsed []*mountutil.Processed //nolint:prealloc
+	defer func() {
+		if retErr != nil {
+			releaseVolumeDriverMounts(ctx, parsed...)
+		}
+	}()
+
 	for _, v := range strutil.DedupeStrSlice(options.Volume) {
+		if dst, _ := mountutil.FlagVDestination(v); dst != "" && !mounted.claimedBy(dst, fmt.Sprintf("-v %q", v)) {
+			continue
+		}
 		// createDir=true for -v option to allow creation of directory on host if not found.
 		x, err := mountutil.ProcessFlagV(v, volStore, true)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		parsed = append(parsed, x)
 	}
 
 	for _, v := range strutil.DedupeStrSlice(options.Tmpfs) {
+		if dst, _, _ := strings.Cut(v, ":"); dst != "" && !mounted.claimedBy(dst, fmt.Sprintf("--tmpfs %q", v)) {
+			continue
+		}
 		x, err := mountutil.ProcessFlagTmpfs(v)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		parsed = append(parsed, x)
 	}
 
 	for _, v := range strutil.DedupeStrSlice(options.Mount) {
+		if dst, _ := mountutil.FlagMountDestination(v); dst != "" && !mounted.claimedBy(dst, fmt.Sprintf("--mount %q", v)) {
+			continue
+		}
 		x, err := mountutil.ProcessFlagMount(v, volStore)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		parsed = append(parsed, x)
 	}
 
-	return parsed, nil
+	return parsed, mounted, nil
+}
+
+// checkMountPoints checks the destinations of --volume, --tmpfs, --mount and volumesFrom for collisions,
+// without processing the flags, and returns the flag that claims each destination.
+// A --mount with the "replace" option claims its destination over the other flags.
+func checkMountPoints(options types.ContainerCreateOptions, volumesFrom []volumesFromSource) (mountPointSet, error) {
+	mounted := make(mountPointSet)
+	mountFlags := strutil.DedupeStrSlice(options.Mount)
+	for _, v := range mountFlags {
+		if dst, replace := mountutil.FlagMountDestination(v); replace && dst != "" {
+			if err := mounted.addReplacing(dst, fmt.Sprintf("--mount %q", v)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// An empty destination is left to the processing of the flag to report
+	claim := func(dst, spec string) error {
+		if dst == "" || mounted.replaced(dst, spec) {
+			return nil
+		}
+		return mounted.add(dst, spec)
+	}
+	for _, v := range strutil.DedupeStrSlice(options.Volume) {
+		dst, err := mountutil.FlagVDestination(v)
+		if err != nil {
+			return nil, err
+		}
+		if err := claim(dst, fmt.Sprintf("-v %q", v)); err != nil {
+			return nil, err
+		}
+	}
+	for _, v := range strutil.DedupeStrSlice(options.Tmpfs) {
+		dst, _, _ := strings.Cut(v, ":")
+		if err := claim(dst, fmt.Sprintf("--tmpfs %q", v)); err != nil {
+			return nil, err
+		}
+	}
+	for _, v := range mountFlags {
+		if dst, replace := mountutil.FlagMountDestination(v); !replace {
+			if err := claim(dst, fmt.Sprintf("--mount %q", v)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, vf := range volumesFrom {
+		for _, m := range vf.mountPoints {
+			if err := claim(m.Destination, fmt.Sprintf("--volumes-from %q", vf.name)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return mounted, nil
+}
+
+// containerVolumeStore labels the named volumes auto-created by mountutil with the ID of the container creating them.
+type containerVolumeStore struct {
+	volumestore.VolumeStore
+	containerID string
+}
+
+func (vs *containerVolumeStore) withCreatedBy(volLabels []string) []string {
+	if slices.Contains(volLabels, labels.AutoCreatedVolume+"=true") {
+		return append(volLabels, labels.VolumeCreatedBy+"="+vs.containerID)
+	}
+	return volLabels
+}
+
+func (vs *containerVolumeStore) CreateWithoutLock(name string, volLabels []string) (*native.Volume, error) {
+	return vs.VolumeStore.CreateWithoutLock(name, vs.withCreatedBy(volLabels))
+}
+
+func (vs *containerVolumeStore) CreateWithDriverWithoutLock(name string, volLabels []string, driver string, opts map[string]string) (*native.Volume, error) {
+	return vs.VolumeStore.CreateWithDriverWithoutLock(name, vs.withCreatedBy(volLabels), driver, opts)
 }
 
 // generateMountOpts generates volume-related mount opts.
 // Other mounts such as procfs mount are not handled here.
 func generateMountOpts(ctx context.Context, client *containerd.Client, ensuredImage *imgutil.EnsuredImage,
-	volStore volumestore.VolumeStore, options types.ContainerCreateOptions) ([]oci.SpecOpts, []string, []*mountutil.Processed, error) {
+	volStore volumestore.VolumeStore, stateDir string, options types.ContainerCreateOptions) (_ []oci.SpecOpts, _ []string, _ []*mountutil.Processed, retErr error) {
 	//nolint:prealloc
 	var (
 		opts        []oci.SpecOpts
@@ -133,7 +363,14 @@ func generateMountOpts(ctx context.Context, client *containerd.Client, ensuredIm
 		userMounts  []specs.Mount
 		mountPoints []*mountutil.Processed
 	)
-	mounted := make(map[string]struct{})
+	vfModes, err := parseVolumesFrom(options.VolumesFrom)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	volumesFrom, err := findVolumesFrom(ctx, client, vfModes)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 	var imageVolumes map[string]struct{}
 	var tempDir string
 	if ensuredImage != nil {
@@ -214,19 +451,42 @@ func generateMountOpts(ctx context.Context, client *containerd.Client, ensuredIm
 		}
 	}
 
-	if parsed, err := parseMountFlags(volStore, options); err != nil {
+	parsed, mounted, err := parseMountFlags(ctx, volStore, options, volumesFrom)
+	if err != nil {
 		return nil, nil, nil, err
-	} else if len(parsed) > 0 {
+	}
+	defer func() {
+		if retErr != nil {
+			releaseVolumeDriverMounts(ctx, parsed...)
+			releaseImageMounts(parsed...)
+		}
+	}()
+	if err := checkRecursiveReadOnlyMounts(ctx, client, options.Runtime, parsed); err != nil {
+		return nil, nil, nil, err
+	}
+	for i, x := range parsed {
+		if x.Type == mountutil.Image {
+			if err := mountImage(ctx, client, x, filepath.Join(stateDir, "image-mounts", strconv.Itoa(i)), options); err != nil {
+				return nil, nil, nil, err
+			}
+		}
+	}
+	if len(parsed) > 0 {
 		ociMounts := make([]specs.Mount, len(parsed))
 		for i, x := range parsed {
 			ociMounts[i] = x.Mount
-			mounted[filepath.Clean(x.Mount.Destination)] = struct{}{}
 
 			target, err := securejoin.SecureJoin(tempDir, x.Mount.Destination)
 			if err != nil {
 				return nil, nil, nil, err
 			}
 
+			if x.Type == mountutil.Bind && tempDir != "" {
+				if err := mountutil.ValidateBindDestination(x.Mount.Source, target, x.Mount.Destination); err != nil {
+					return nil, nil, nil, err
+				}
+			}
+
 			// Copying content in AnonymousVolume and namedVolume
 			if x.Type == "volume" {
 				if err := copyExistingContents(target, x.Mount.Source); err != nil {
@@ -292,15 +552,81 @@ func generateMountOpts(ctx context.Context, client *containerd.Client, ensuredIm
 
 	opts = append(opts, withMounts(userMounts))
 
+	for _, vf := range volumesFrom {
+		spec := fmt.Sprintf("--volumes-from %q", vf.name)
+		var ps []*mountutil.Processed
+		vfDests := make(map[string]struct{})
+		replacedDests := make(map[string]struct{})
+		for _, p := range processeds(vf.mountPoints) {
+			if !mounted.claimedBy(p.Mount.Destination, spec) {
+				replacedDests[filepath.Clean(p.Mount.Destination)] = struct{}{}
+				continue
+			}
+			vfDests[filepath.Clean(p.Mount.Destination)] = struct{}{}
+			if vf.mode != "" {
+				p.Mode = strings.Join(overrideMountMode(strings.Split(p.Mode, ","), vf.mode), ",")
+			}
+			ps = append(ps, p)
+		}
+		vfMounts := make([]specs.Mount, 0, len(vf.mounts))
+		for _, m := range vf.mounts {
+			if _, ok := replacedDests[filepath.Clean(m.Destination)]; ok {
+				continue
+			}
+			if _, ok := vfDests[filepath.Clean(m.Destination)]; ok && vf.mode != "" {
+				m.Options = overrideMountMode(m.Options, vf.mode)
+			}
+			vfMounts = append(vfMounts, m)
+		}
+		opts = append(opts, withMounts(vfMounts))
+		anonVolumes = append(anonVolumes, vf.anonVolumes...)
+		mountPoints = append(mountPoints, ps...)
+	}
+
+	return opts, anonVolumes, mountPoints, nil
+}
+
+// parseVolumesFrom parses --volumes-from values of the form "CONTAINER[:MODE]",
+// and returns a map from the container name or ID to the mode ("ro", "rw", or "" for no override).
+func parseVolumesFrom(volumesFrom []string) (map[string]string, error) {
+	res := make(map[string]string, len(volumesFrom))
+	for _, vf := range volumesFrom {
+		ref, mode, _ := strings.Cut(vf, ":")
+		if ref == "" {
+			return nil, fmt.Errorf("invalid --volumes-from %q: container name or ID must not be empty", vf)
+		}
+		switch mode {
+		case "", "ro", "rw":
+		default:
+			return nil, fmt.Errorf("invalid --volumes-from %q: mode must be \"ro\" or \"rw\", got %q", vf, mode)
+		}
+		res[ref] = mode
+	}
+	return res, nil
+}
+
+// volumesFromSource is a container whose mounts are mounted with --volumes-from.
+type volumesFromSource struct {
+	// name is the ID or the name of the container, as given to --volumes-from
+	name string
+	// mode is the mode overriding the mode of the mounts ("ro", "rw", or "" for no override)
+	mode        string
+	mountPoints []dockercompat.MountPoint
+	anonVolumes []string
+	mounts      []specs.Mount
+}
+
+// findVolumesFrom returns the containers of --volumes-from, with their mounts.
+func findVolumesFrom(ctx context.Context, client *containerd.Client, vfModes map[string]string) ([]volumesFromSource, error) {
+	if len(vfModes) == 0 {
+		return nil, nil
+	}
 	containers, err := client.Containers(ctx)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, err
 	}
 
-	vfSet := strutil.SliceToSet(options.VolumesFrom)
-	var vfMountPoints []dockercompat.MountPoint
-	var vfAnonVolumes []string
-
+	var result []volumesFromSource
 	for _, c := range containers {
 		ls, err := c.Labels(ctx)
 		if err != nil {
@@ -310,40 +636,56 @@ func generateMountOpts(ctx context.Context, client *containerd.Client, ensuredIm
 				log.G(ctx).Debugf("container %q is gone - ignoring", c.ID())
 				continue
 			}
-			return nil, nil, nil, err
+			return nil, err
 		}
-		_, idMatch := vfSet[c.ID()]
+		vfMode, idMatch := vfModes[c.ID()]
 		nameMatch := false
-		if name, found := ls[labels.Name]; found {
-			_, nameMatch = vfSet[name]
+		if name, found := ls[labels.Name]; found && !idMatch {
+			vfMode, nameMatch = vfModes[name]
+		}
+		if !idMatch && !nameMatch {
+			continue
 		}
 
-		if idMatch || nameMatch {
-			if av, found := ls[labels.AnonymousVolumes]; found {
-				err = json.Unmarshal([]byte(av), &vfAnonVolumes)
-				if err != nil {
-					return nil, nil, nil, err
-				}
-			}
-			if m, found := ls[labels.Mounts]; found {
-				err = json.Unmarshal([]byte(m), &vfMountPoints)
-				if err != nil {
-					return nil, nil, nil, err
-				}
+		vf := volumesFromSource{name: c.ID(), mode: vfMode}
+		if nameMatch {
+			vf.name = ls[labels.Name]
+		}
+		if av, found := ls[labels.AnonymousVolumes]; found {
+			if err := json.Unmarshal([]byte(av), &vf.anonVolumes); err != nil {
+				return nil, err
 			}
-
-			ps := processeds(vfMountPoints)
-			s, err := c.Spec(ctx)
-			if err != nil {
-				return nil, nil, nil, err
+		}
+		if m, found := ls[labels.Mounts]; found {
+			if err := json.Unmarshal([]byte(m), &vf.mountPoints); err != nil {
+				return nil, err
 			}
-			opts = append(opts, withMounts(s.Mounts))
-			anonVolumes = append(anonVolumes, vfAnonVolumes...)
-			mountPoints = append(mountPoints, ps...)
 		}
+		s, err := c.Spec(ctx)
+		if err != nil {
+			return nil, err
+		}
+		vf.mounts = s.Mounts
+		result = append(result, vf)
 	}
+	return result, nil
+}
 
-	return opts, anonVolumes, mountPoints, nil
+// overrideMountMode replaces the read-only/read-write options in opts with mode ("ro" or "rw").
+// A recursively read-only mount is kept as is when mode is "ro".
+func overrideMountMode(opts []string, mode string) []string {
+	if mode == "ro" && (slices.Contains(opts, "ro") || slices.Contains(opts, "rro")) {
+		return opts
+	}
+	res := make([]string, 0, len(opts)+1)
+	for _, o := range opts {
+		switch o {
+		case "", "ro", "rro", "rw":
+			continue
+		}
+		res = append(res, o)
+	}
+	return append(res, mode)
 }
 
 // copyExistingContents copies from the source to the destination and
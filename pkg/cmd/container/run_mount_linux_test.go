@@ -0,0 +1,283 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/dockercompat"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/mountutil"
+	"github.com/containerd/nerdctl/v2/pkg/mountutil/volumestore"
+	"github.com/containerd/nerdctl/v2/pkg/volumedriver"
+)
+
+func TestParseMountFlagsDuplicateMountPoint(t *testing.T) {
+	volStore, err := volumestore.New(t.TempDir(), "test")
+	assert.NilError(t, err)
+	assert.NilError(t, volStore.Lock())
+	defer volStore.Release()
+	src := t.TempDir()
+
+	testCases := []struct {
+		name        string
+		options     types.ContainerCreateOptions
+		volumesFrom []volumesFromSource
+		errMsg      string
+	}{
+		{
+			name: "volume and bind",
+			options: types.ContainerCreateOptions{
+				Volume: []string{"vol:/mnt"},
+				Mount:  []string{"type=bind,src=" + src + ",dst=/mnt"},
+			},
+			errMsg: `duplicate mount point "/mnt": --mount "type=bind,src=` + src + `,dst=/mnt" conflicts with -v "vol:/mnt"`,
+		},
+		{
+			name: "bind and tmpfs",
+			options: types.ContainerCreateOptions{
+				Tmpfs: []string{"/mnt/"},
+				Mount: []string{"type=bind,src=" + src + ",dst=/mnt"},
+			},
+			errMsg: `duplicate mount point "/mnt": --mount "type=bind,src=` + src + `,dst=/mnt" conflicts with --tmpfs "/mnt/"`,
+		},
+		{
+			name: "two volumes",
+			options: types.ContainerCreateOptions{
+				Volume: []string{src + ":/mnt", "vol:/mnt:ro"},
+			},
+			errMsg: `duplicate mount point "/mnt": -v "vol:/mnt:ro" conflicts with -v "` + src + `:/mnt"`,
+		},
+		{
+			name: "volumes-from and volume",
+			options: types.ContainerCreateOptions{
+				Volume: []string{"vol:/mnt"},
+			},
+			volumesFrom: []volumesFromSource{{name: "foo", mountPoints: []dockercompat.MountPoint{{Destination: "/mnt"}}}},
+			errMsg:      `duplicate mount point "/mnt": --volumes-from "foo" conflicts with -v "vol:/mnt"`,
+		},
+		{
+			name: "distinct destinations",
+			options: types.ContainerCreateOptions{
+				Volume: []string{"vol:/mnt/vol"},
+				Tmpfs:  []string{"/mnt/tmpfs"},
+				Mount:  []string{"type=bind,src=" + src + ",dst=/mnt"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			parsed, _, err := parseMountFlags(context.Background(), volStore, tc.options, tc.volumesFrom)
+			if tc.errMsg != "" {
+				assert.Error(t, err, tc.errMsg)
+				// The collision is detected before the volume is created
+				exists, err := volStore.Exists("vol")
+				assert.NilError(t, err)
+				assert.Assert(t, !exists)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, len(parsed), 3)
+		})
+	}
+}
+
+func TestParseMountFlagsReplace(t *testing.T) {
+	volStore, err := volumestore.New(t.TempDir(), "test")
+	assert.NilError(t, err)
+	assert.NilError(t, volStore.Lock())
+	defer volStore.Release()
+	src := t.TempDir()
+
+	parsed, mounted, err := parseMountFlags(context.Background(), volStore, types.ContainerCreateOptions{
+		Volume: []string{"vol:/mnt"},
+		Tmpfs:  []string{"/mnt"},
+		Mount:  []string{"type=bind,src=" + src + ",dst=/mnt", "type=bind,src=" + src + ",dst=/mnt/,replace"},
+	}, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, len(parsed), 1)
+	assert.Equal(t, parsed[0].Mount.Source, src)
+	assert.Assert(t, parsed[0].Replace)
+	assert.Assert(t, mounted.replaced("/mnt", "--volumes-from foo"))
+
+	_, _, err = parseMountFlags(context.Background(), volStore, types.ContainerCreateOptions{
+		Mount: []string{"type=tmpfs,dst=/mnt,replace", "type=bind,src=" + src + ",dst=/mnt,replace=true"},
+	}, nil)
+	assert.Error(t, err, `duplicate mount point "/mnt": --mount "type=bind,src=`+src+`,dst=/mnt,replace=true" conflicts with --mount "type=tmpfs,dst=/mnt,replace"`)
+}
+
+func TestParseMountFlagsAutoCreatedVolumeLabels(t *testing.T) {
+	store, err := volumestore.New(t.TempDir(), "test")
+	assert.NilError(t, err)
+	_, err = store.Create("explicit", nil)
+	assert.NilError(t, err)
+	assert.NilError(t, store.Lock())
+	volStore := &containerVolumeStore{VolumeStore: store, containerID: "cid"}
+
+	_, _, err = parseMountFlags(context.Background(), volStore, types.ContainerCreateOptions{
+		Volume: []string{"auto:/auto", "explicit:/explicit", "/anon"},
+		Mount:  []string{"type=volume,src=labeled,dst=/labeled,volume-label=project=x"},
+	}, nil)
+	assert.NilError(t, err)
+	assert.NilError(t, store.Release())
+
+	vols, err := store.List(false)
+	assert.NilError(t, err)
+	assert.Equal(t, len(vols), 4)
+	for name, vol := range vols {
+		switch name {
+		case "auto":
+			assert.DeepEqual(t, *vol.Labels, map[string]string{labels.AutoCreatedVolume: "true", labels.VolumeCreatedBy: "cid"})
+		case "labeled":
+			// the volumes created with --mount volume-label= are labeled too
+			assert.DeepEqual(t, *vol.Labels, map[string]string{labels.AutoCreatedVolume: "true", labels.VolumeCreatedBy: "cid", "project": "x"})
+		case "explicit":
+			assert.Assert(t, vol.Labels == nil)
+		default:
+			// anonymous volumes are not labeled as auto-created
+			if vol.Labels != nil {
+				_, ok := (*vol.Labels)[labels.AutoCreatedVolume]
+				assert.Assert(t, !ok)
+			}
+		}
+	}
+}
+
+func TestParseVolumesFrom(t *testing.T) {
+	modes, err := parseVolumesFrom([]string{"foo", "bar:ro", "baz:rw"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, modes, map[string]string{"foo": "", "bar": "ro", "baz": "rw"})
+
+	_, err = parseVolumesFrom([]string{"foo:rx"})
+	assert.ErrorContains(t, err, `mode must be "ro" or "rw"`)
+
+	_, err = parseVolumesFrom([]string{":ro"})
+	assert.ErrorContains(t, err, "must not be empty")
+}
+
+func TestOverrideMountMode(t *testing.T) {
+	testCases := []struct {
+		opts     []string
+		mode     string
+		expected []string
+	}{
+		{opts: []string{"rbind"}, mode: "ro", expected: []string{"rbind", "ro"}},
+		{opts: []string{"rbind", "rw"}, mode: "ro", expected: []string{"rbind", "ro"}},
+		{opts: []string{"rbind", "ro", "rro"}, mode: "ro", expected: []string{"rbind", "ro", "rro"}},
+		{opts: []string{"rbind", "ro", "rro"}, mode: "rw", expected: []string{"rbind", "rw"}},
+		{opts: []string{""}, mode: "ro", expected: []string{"ro"}},
+	}
+	for _, tc := range testCases {
+		assert.DeepEqual(t, overrideMountMode(tc.opts, tc.mode), tc.expected)
+	}
+}
+
+// countingVolumeDriver is an in-process volume driver, keeping track of the mounts that are not released.
+type countingVolumeDriver struct {
+	root   string
+	mounts map[string]string
+}
+
+func (d *countingVolumeDriver) Create(_ context.Context, name string, opts map[string]string) error {
+	return nil
+}
+
+func (d *countingVolumeDriver) Remove(_ context.Context, name string) error { return nil }
+
+func (d *countingVolumeDriver) Mount(_ context.Context, name, id string) (string, error) {
+	d.mounts[id] = name
+	return filepath.Join(d.root, name), nil
+}
+
+func (d *countingVolumeDriver) Unmount(_ context.Context, name, id string) error {
+	delete(d.mounts, id)
+	return nil
+}
+
+func TestParseMountFlagsReleasesVolumeDriverMounts(t *testing.T) {
+	d := &countingVolumeDriver{root: t.TempDir(), mounts: map[string]string{}}
+	assert.NilError(t, volumedriver.Register("counting", d))
+	t.Cleanup(func() { volumedriver.Unregister("counting") })
+
+	volStore, err := volumestore.New(t.TempDir(), "test")
+	assert.NilError(t, err)
+	_, err = volStore.CreateWithDriver("vol", nil, "counting", nil)
+	assert.NilError(t, err)
+	assert.NilError(t, volStore.Lock())
+	defer volStore.Release()
+	src := t.TempDir()
+
+	// The mount points collide, so the volume is not mounted by its driver
+	_, _, err = parseMountFlags(context.Background(), volStore, types.ContainerCreateOptions{
+		Volume: []string{"vol:/mnt"},
+		Tmpfs:  []string{"/mnt"},
+	}, nil)
+	assert.ErrorContains(t, err, "duplicate mount point")
+	assert.Equal(t, len(d.mounts), 0)
+
+	_, _, err = parseMountFlags(context.Background(), volStore, types.ContainerCreateOptions{
+		Mount: []string{"type=volume,src=vol,dst=/mnt,subpath=../escape"},
+	}, nil)
+	assert.ErrorContains(t, err, "invalid subpath")
+	assert.Equal(t, len(d.mounts), 0)
+
+	// A replaced volume is released, while the other one is kept for the container
+	parsed, _, err := parseMountFlags(context.Background(), volStore, types.ContainerCreateOptions{
+		Volume: []string{"vol:/mnt", "vol:/data"},
+		Mount:  []string{"type=bind,src=" + src + ",dst=/mnt,replace"},
+	}, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, len(parsed), 2)
+	assert.Equal(t, len(d.mounts), 1)
+
+	// The creation of the container fails after the mount points are parsed
+	generateRemoveStateDirFunc(context.Background(), "cid", internalLabels{mountPoints: parsed, stateDir: t.TempDir()})()
+	assert.Equal(t, len(d.mounts), 0)
+}
+
+func TestDropUnsupportedRecursiveReadOnly(t *testing.T) {
+	newMountPoints := func() []*mountutil.Processed {
+		return []*mountutil.Processed{
+			{Type: mountutil.Bind, Mount: specs.Mount{Source: "/a", Options: []string{"ro", "rro", "rprivate", "rbind"}}},
+			{Type: mountutil.Bind, Mount: specs.Mount{Source: "/b", Options: []string{"ro", "rprivate", "rbind"}}},
+		}
+	}
+
+	// The runtime supports "rro"
+	mountPoints := newMountPoints()
+	assert.NilError(t, dropUnsupportedRecursiveReadOnly(mountPoints, []string{"ro", "rro", "rbind"}))
+	assert.DeepEqual(t, mountPoints[0].Mount.Options, []string{"ro", "rro", "rprivate", "rbind"})
+
+	// The runtime does not support "rro": fall back to a non-recursive read-only mount
+	mountPoints = newMountPoints()
+	assert.NilError(t, dropUnsupportedRecursiveReadOnly(mountPoints, []string{"ro", "rbind"}))
+	assert.DeepEqual(t, mountPoints[0].Mount.Options, []string{"ro", "rprivate", "rbind"})
+	assert.DeepEqual(t, mountPoints[1].Mount.Options, []string{"ro", "rprivate", "rbind"})
+
+	// bind-recursive=readonly requires "rro"
+	mountPoints = newMountPoints()
+	mountPoints[0].RecursiveReadOnlyRequired = true
+	err := dropUnsupportedRecursiveReadOnly(mountPoints, []string{"ro", "rbind"})
+	assert.ErrorContains(t, err, "the runtime does not support recursive read-only mounts")
+}
@@ -18,12 +18,18 @@ package container
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/containers"
 	"github.com/containerd/containerd/v2/core/runtime/restart"
 
+	"github.com/containerd/nerdctl/v2/pkg/labels"
 	"github.com/containerd/nerdctl/v2/pkg/strutil"
 )
 
@@ -74,17 +80,83 @@ func generateRestartOpts(ctx context.Context, client *containerd.Client, restart
 	return opts, nil
 }
 
+// parseRestartExitCodes splits the exit codes off a `--restart=on-failure[:n]:codes=<CODES>` flag.
+// It returns the restart policy to pass to containerd, and the sorted exit codes that the container
+// is restarted on, or nil if the flag does not restrict them.
+func parseRestartExitCodes(restartFlag string) (string, []int, error) {
+	policySlice := strings.Split(restartFlag, ":")
+	codesArg, ok := strings.CutPrefix(policySlice[len(policySlice)-1], "codes=")
+	if !ok {
+		return restartFlag, nil, nil
+	}
+	if policySlice[0] != "on-failure" {
+		return "", nil, fmt.Errorf("invalid restart policy %q: exit codes are only supported by the \"on-failure\" policy", restartFlag)
+	}
+	var codes []int
+	for _, s := range strings.Split(codesArg, ",") {
+		code, err := strconv.Atoi(s)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid restart policy %q: invalid exit code %q", restartFlag, s)
+		}
+		// The container is never restarted on 0, as it did not fail
+		if code < 1 || code > 255 {
+			return "", nil, fmt.Errorf("invalid restart policy %q: exit code %d must be between 1 and 255", restartFlag, code)
+		}
+		codes = append(codes, code)
+	}
+	slices.Sort(codes)
+	return strings.Join(policySlice[:len(policySlice)-1], ":"), slices.Compact(codes), nil
+}
+
+// formatRestartExitCodes formats the exit codes for the labels.RestartExitCodes label.
+func formatRestartExitCodes(codes []int) string {
+	s := make([]string, len(codes))
+	for i, code := range codes {
+		s[i] = strconv.Itoa(code)
+	}
+	return strings.Join(s, ",")
+}
+
+func validateRestartMinUptime(restartFlag string, minUptime time.Duration) error {
+	if minUptime < 0 {
+		return fmt.Errorf("invalid --restart-min-uptime %s: must not be negative", minUptime)
+	}
+	if minUptime > 0 && (restartFlag == "" || restartFlag == "no") {
+		return errors.New("--restart-min-uptime requires a restart policy other than \"no\"")
+	}
+	return nil
+}
+
+// withRestartExitCodes sets the labels.RestartExitCodes label of the container, or removes it when codes is empty.
+func withRestartExitCodes(codes []int) containerd.UpdateContainerOpts {
+	return func(_ context.Context, _ *containerd.Client, c *containers.Container) error {
+		if len(codes) == 0 {
+			delete(c.Labels, labels.RestartExitCodes)
+			return nil
+		}
+		if c.Labels == nil {
+			c.Labels = make(map[string]string)
+		}
+		c.Labels[labels.RestartExitCodes] = formatRestartExitCodes(codes)
+		return nil
+	}
+}
+
 // UpdateContainerRestartPolicyLabel updates the restart policy label of the container.
 func UpdateContainerRestartPolicyLabel(ctx context.Context, client *containerd.Client, container containerd.Container, restartFlag string) error {
-	if _, err := checkRestartCapabilities(ctx, client, restartFlag); err != nil {
+	restartPolicy, exitCodes, err := parseRestartExitCodes(restartFlag)
+	if err != nil {
 		return err
 	}
-	policy, err := restart.NewPolicy(restartFlag)
+	if _, err := checkRestartCapabilities(ctx, client, restartPolicy); err != nil {
+		return err
+	}
+	policy, err := restart.NewPolicy(restartPolicy)
 	if err != nil {
 		return err
 	}
 
-	updateOpts := []containerd.UpdateContainerOpts{restart.WithPolicy(policy)}
+	updateOpts := []containerd.UpdateContainerOpts{restart.WithPolicy(policy), withRestartExitCodes(exitCodes)}
 
 	lables, err := container.Labels(ctx)
 	if err != nil {
@@ -0,0 +1,84 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseRestartExitCodes(t *testing.T) {
+	testCases := []struct {
+		flag     string
+		policy   string
+		expected []int
+		err      string
+	}{
+		{
+			flag:   "always",
+			policy: "always",
+		},
+		{
+			flag:   "on-failure:3",
+			policy: "on-failure:3",
+		},
+		{
+			flag:     "on-failure:3:codes=143,137",
+			policy:   "on-failure:3",
+			expected: []int{137, 143},
+		},
+		{
+			flag:     "on-failure:codes=1,2,1",
+			policy:   "on-failure",
+			expected: []int{1, 2},
+		},
+		{
+			flag: "always:codes=1",
+			err:  `invalid restart policy "always:codes=1": exit codes are only supported by the "on-failure" policy`,
+		},
+		{
+			flag: "on-failure:codes=",
+			err:  `invalid restart policy "on-failure:codes=": invalid exit code ""`,
+		},
+		{
+			flag: "on-failure:codes=1,foo",
+			err:  `invalid restart policy "on-failure:codes=1,foo": invalid exit code "foo"`,
+		},
+		{
+			flag: "on-failure:codes=0",
+			err:  `invalid restart policy "on-failure:codes=0": exit code 0 must be between 1 and 255`,
+		},
+		{
+			flag: "on-failure:2:codes=256",
+			err:  `invalid restart policy "on-failure:2:codes=256": exit code 256 must be between 1 and 255`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.flag, func(t *testing.T) {
+			policy, codes, err := parseRestartExitCodes(tc.flag)
+			if tc.err != "" {
+				assert.Error(t, err, tc.err)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, policy, tc.policy)
+			assert.DeepEqual(t, codes, tc.expected)
+		})
+	}
+}
@@ -18,10 +18,12 @@ package container
 
 import (
 	"context"
+	"fmt"
 	"os/exec"
 	"strings"
 
 	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/opencontainers/runtime-spec/specs-go/features"
 
 	runcoptions "github.com/containerd/containerd/api/types/runc/options"
 	containerd "github.com/containerd/containerd/v2/client"
@@ -32,6 +34,13 @@ import (
 )
 
 func generateRuntimeCOpts(cgroupManager, runtimeStr string) ([]containerd.NewContainerOpts, error) {
+	runtime, runtimeOpts := runtimeWithOptions(cgroupManager, runtimeStr)
+	o := containerd.WithRuntime(runtime, runtimeOpts)
+	return []containerd.NewContainerOpts{o}, nil
+}
+
+// runtimeWithOptions returns the containerd runtime of --runtime and its options.
+func runtimeWithOptions(cgroupManager, runtimeStr string) (string, interface{}) {
 	runtime := plugins.RuntimeRuncV2
 	var (
 		runcOpts    runcoptions.Options
@@ -60,8 +69,23 @@ func generateRuntimeCOpts(cgroupManager, runtimeStr string) ([]containerd.NewCon
 			}
 		}
 	}
-	o := containerd.WithRuntime(runtime, runtimeOpts)
-	return []containerd.NewContainerOpts{o}, nil
+	return runtime, runtimeOpts
+}
+
+// runtimeMountOptions returns the mount options supported by the runtime, as reported by its features.
+// ok is false when the runtime does not report its features, e.g., with an older containerd or runtime.
+func runtimeMountOptions(ctx context.Context, client *containerd.Client, runtimeStr string) (_ []string, ok bool) {
+	runtime, runtimeOpts := runtimeWithOptions("", runtimeStr)
+	info, err := client.RuntimeInfo(ctx, runtime, runtimeOpts)
+	if err != nil {
+		log.G(ctx).WithError(err).Debugf("failed to get the features of runtime %q", runtime)
+		return nil, false
+	}
+	f, ok := info.Features.(*features.Features)
+	if !ok || f == nil {
+		return nil, false
+	}
+	return f.MountOptions, true
 }
 
 // WithSysctls sets the provided sysctls onto the spec
@@ -79,3 +103,50 @@ func WithSysctls(sysctls map[string]string) oci.SpecOpts {
 		return nil
 	}
 }
+
+// ipcSysctls are the sysctls that are namespaced by the IPC namespace.
+var ipcSysctls = map[string]struct{}{
+	"kernel.msgmax":          {},
+	"kernel.msgmnb":          {},
+	"kernel.msgmni":          {},
+	"kernel.sem":             {},
+	"kernel.shmall":          {},
+	"kernel.shmmax":          {},
+	"kernel.shmmni":          {},
+	"kernel.shm_rmid_forced": {},
+}
+
+// utsSysctls are the sysctls that are namespaced by the UTS namespace.
+var utsSysctls = map[string]struct{}{
+	"kernel.domainname": {},
+	"kernel.hostname":   {},
+}
+
+// validateSysctls verifies that the provided sysctls are namespaced, and that the container does not share the
+// corresponding namespace with the host: in both cases, setting them would modify the kernel parameters of the host.
+func validateSysctls(sysctls map[string]string, hostNetwork, hostIPC, hostUTS bool) error {
+	for k := range sysctls {
+		// Sysctls may also be specified with slashes, e.g., "net/ipv4/ip_forward"
+		key := strings.ReplaceAll(k, "/", ".")
+		_, isIPC := ipcSysctls[key]
+		_, isUTS := utsSysctls[key]
+		switch {
+		case strings.HasPrefix(key, "net."):
+			if hostNetwork {
+				return fmt.Errorf("sysctl %q cannot be set with --network=host: the container uses the network namespace of the host, "+
+					"so this would modify the network parameters of the host kernel", k)
+			}
+		case isIPC || strings.HasPrefix(key, "fs.mqueue."):
+			if hostIPC {
+				return fmt.Errorf("sysctl %q cannot be set with --ipc=host", k)
+			}
+		case isUTS:
+			if hostUTS {
+				return fmt.Errorf("sysctl %q cannot be set with --uts=host", k)
+			}
+		default:
+			return fmt.Errorf("sysctl %q is not namespaced and cannot be set on a container", k)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestValidateSysctls(t *testing.T) {
+	testCases := []struct {
+		sysctl                        string
+		hostNetwork, hostIPC, hostUTS bool
+		expectedErr                   string
+	}{
+		{sysctl: "net.ipv4.ip_forward"},
+		{sysctl: "net/ipv4/ip_forward"},
+		{sysctl: "kernel.shmmax", hostNetwork: true},
+		{sysctl: "fs.mqueue.msg_max"},
+		{sysctl: "kernel.domainname", hostNetwork: true},
+		{
+			sysctl:      "net.ipv4.ip_forward",
+			hostNetwork: true,
+			expectedErr: "cannot be set with --network=host: the container uses the network namespace of the host",
+		},
+		{
+			sysctl:      "net/core/somaxconn",
+			hostNetwork: true,
+			expectedErr: "cannot be set with --network=host",
+		},
+		{sysctl: "kernel.sem", hostIPC: true, expectedErr: "cannot be set with --ipc=host"},
+		{sysctl: "fs.mqueue.msg_max", hostIPC: true, expectedErr: "cannot be set with --ipc=host"},
+		{sysctl: "kernel.hostname", hostUTS: true, expectedErr: "cannot be set with --uts=host"},
+		{sysctl: "vm.swappiness", expectedErr: "is not namespaced"},
+		{sysctl: "kernel.pid_max", hostNetwork: true, expectedErr: "is not namespaced"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.sysctl, func(t *testing.T) {
+			err := validateSysctls(map[string]string{tc.sysctl: "1"}, tc.hostNetwork, tc.hostIPC, tc.hostUTS)
+			if tc.expectedErr == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
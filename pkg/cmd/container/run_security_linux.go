@@ -19,6 +19,7 @@ package container
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -202,3 +203,22 @@ func generateCapOpts(capAdd, capDrop []string) ([]oci.SpecOpts, error) {
 	}
 	return opts, nil
 }
+
+// containerHasCapability returns whether the container will have the capability c (e.g., "CAP_SYS_ADMIN"),
+// which is not in the default set, given --privileged, --cap-add, and --cap-drop.
+// As in generateCapOpts, --cap-drop takes precedence over --cap-add, except for --cap-drop=ALL.
+func containerHasCapability(privileged bool, capAdd, capDrop []string, c string) bool {
+	if privileged {
+		return true
+	}
+	matches := func(s string) bool {
+		s = strings.ToUpper(s)
+		return s == c || "CAP_"+s == c
+	}
+	if slices.ContainsFunc(capDrop, matches) {
+		return false
+	}
+	return slices.ContainsFunc(capAdd, func(s string) bool {
+		return strings.ToUpper(s) == "ALL" || matches(s)
+	})
+}
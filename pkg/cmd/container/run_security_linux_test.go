@@ -0,0 +1,45 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestContainerHasCapability(t *testing.T) {
+	testCases := []struct {
+		privileged bool
+		capAdd     []string
+		capDrop    []string
+		expected   bool
+	}{
+		{expected: false},
+		{privileged: true, expected: true},
+		{capAdd: []string{"SYS_ADMIN"}, expected: true},
+		{capAdd: []string{"cap_sys_admin"}, expected: true},
+		{capAdd: []string{"ALL"}, expected: true},
+		{capAdd: []string{"all"}, expected: true},
+		{capAdd: []string{"NET_ADMIN"}, expected: false},
+		{capAdd: []string{"SYS_ADMIN"}, capDrop: []string{"ALL"}, expected: true},
+		{capAdd: []string{"ALL"}, capDrop: []string{"SYS_ADMIN"}, expected: false},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, containerHasCapability(tc.privileged, tc.capAdd, tc.capDrop, "CAP_SYS_ADMIN"), tc.expected, "%+v", tc)
+	}
+}
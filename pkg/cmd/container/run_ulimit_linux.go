@@ -25,29 +25,76 @@ import (
 
 	"github.com/containerd/containerd/v2/core/containers"
 	"github.com/containerd/containerd/v2/pkg/oci"
+	"github.com/containerd/log"
 
 	"github.com/containerd/nerdctl/v2/pkg/strutil"
 )
 
-func generateUlimitsOpts(ulimits []string) ([]oci.SpecOpts, error) {
+// parseUlimits parses --ulimit values such as "nofile=1024:2048" into their structured form.
+func parseUlimits(ulimits []string) ([]*units.Ulimit, error) {
+	var res []*units.Ulimit
+	for _, ulimit := range strutil.DedupeStrSlice(ulimits) {
+		l, err := units.ParseUlimit(ulimit)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, l)
+	}
+	return res, nil
+}
+
+// ulimitCapabilities maps the ulimits that are usually of no use without a capability that is not in the
+// default set: locking memory beyond the limit (e.g., mlockall(2) of databases) needs CAP_IPC_LOCK, and
+// real-time scheduling (e.g., RT audio) needs CAP_SYS_NICE.
+var ulimitCapabilities = map[string]string{
+	"memlock": "CAP_IPC_LOCK",
+	"rtprio":  "CAP_SYS_NICE",
+}
+
+// ulimitCapabilityUsages describes what the capabilities of ulimitCapabilities allow beyond the ulimit.
+var ulimitCapabilityUsages = map[string]string{
+	"CAP_IPC_LOCK": "locking memory",
+	"CAP_SYS_NICE": "real-time scheduling",
+}
+
+// ulimitMissingCapability returns the capability that the ulimit l needs and that the container will not have
+// given --privileged, --cap-add, and --cap-drop, or "" if there is none.
+func ulimitMissingCapability(l *units.Ulimit, privileged bool, capAdd, capDrop []string) string {
+	c, ok := ulimitCapabilities[l.Name]
+	if !ok || (l.Soft == 0 && l.Hard == 0) {
+		return ""
+	}
+	if containerHasCapability(privileged, capAdd, capDrop, c) {
+		return ""
+	}
+	return c
+}
+
+// warnUlimitCapabilities warns about the ulimits that are set without the capability they are usually used with,
+// as the container cannot go beyond them.
+func warnUlimitCapabilities(ulimits []*units.Ulimit, privileged bool, capAdd, capDrop []string) {
+	for _, l := range ulimits {
+		if c := ulimitMissingCapability(l, privileged, capAdd, capDrop); c != "" {
+			log.L.Warnf("ulimit %q is set without %s, so %s has no effect beyond the ulimit in the container (hint: add `--cap-add=%s`)",
+				l.String(), c, ulimitCapabilityUsages[c], strings.TrimPrefix(c, "CAP_"))
+		}
+	}
+}
+
+func generateUlimitsOpts(ulimits []*units.Ulimit) []oci.SpecOpts {
 	var opts []oci.SpecOpts
-	ulimits = strutil.DedupeStrSlice(ulimits)
 	if len(ulimits) > 0 {
-		var rlimits []specs.POSIXRlimit
-		for _, ulimit := range ulimits {
-			l, err := units.ParseUlimit(ulimit)
-			if err != nil {
-				return nil, err
-			}
-			rlimits = append(rlimits, specs.POSIXRlimit{
+		rlimits := make([]specs.POSIXRlimit, len(ulimits))
+		for i, l := range ulimits {
+			rlimits[i] = specs.POSIXRlimit{
 				Type: "RLIMIT_" + strings.ToUpper(l.Name),
 				Hard: uint64(l.Hard),
 				Soft: uint64(l.Soft),
-			})
+			}
 		}
 		opts = append(opts, withRlimits(rlimits))
 	}
-	return opts, nil
+	return opts
 }
 
 func withRlimits(rlimits []specs.POSIXRlimit) oci.SpecOpts {
@@ -0,0 +1,76 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/go-units"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/containerd/v2/pkg/oci"
+)
+
+func TestParseUlimits(t *testing.T) {
+	ulimits, err := parseUlimits([]string{"nofile=1024:2048", "nproc=512", "nofile=1024:2048"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, ulimits, []*units.Ulimit{
+		{Name: "nofile", Soft: 1024, Hard: 2048},
+		{Name: "nproc", Soft: 512, Hard: 512},
+	})
+
+	_, err = parseUlimits([]string{"nofile=2048:1024"})
+	assert.ErrorContains(t, err, "ulimit soft limit must be less than or equal to hard limit")
+
+	_, err = parseUlimits([]string{"foo=1"})
+	assert.ErrorContains(t, err, "invalid ulimit type")
+}
+
+func TestGenerateUlimitsOpts(t *testing.T) {
+	ulimits, err := parseUlimits([]string{"nofile=1024:2048"})
+	assert.NilError(t, err)
+	opts := generateUlimitsOpts(ulimits)
+	assert.Equal(t, len(opts), 1)
+
+	s := &oci.Spec{Process: &specs.Process{}}
+	assert.NilError(t, opts[0](context.Background(), nil, nil, s))
+	assert.DeepEqual(t, s.Process.Rlimits, []specs.POSIXRlimit{
+		{Type: "RLIMIT_NOFILE", Soft: 1024, Hard: 2048},
+	})
+
+	assert.Equal(t, len(generateUlimitsOpts(nil)), 0)
+}
+
+func TestUlimitMissingCapability(t *testing.T) {
+	ulimits, err := parseUlimits([]string{"memlock=-1", "rtprio=99", "nofile=1024", "rtprio=0"})
+	assert.NilError(t, err)
+	memlock, rtprio, nofile, rtprioZero := ulimits[0], ulimits[1], ulimits[2], ulimits[3]
+
+	assert.Equal(t, ulimitMissingCapability(memlock, false, nil, nil), "CAP_IPC_LOCK")
+	assert.Equal(t, ulimitMissingCapability(rtprio, false, nil, nil), "CAP_SYS_NICE")
+	assert.Equal(t, ulimitMissingCapability(nofile, false, nil, nil), "")
+	assert.Equal(t, ulimitMissingCapability(rtprioZero, false, nil, nil), "")
+
+	assert.Equal(t, ulimitMissingCapability(memlock, false, []string{"IPC_LOCK"}, nil), "")
+	assert.Equal(t, ulimitMissingCapability(rtprio, false, []string{"CAP_SYS_NICE"}, nil), "")
+	assert.Equal(t, ulimitMissingCapability(rtprio, false, []string{"ALL"}, nil), "")
+	assert.Equal(t, ulimitMissingCapability(memlock, true, nil, nil), "")
+	// --cap-drop takes precedence over --cap-add
+	assert.Equal(t, ulimitMissingCapability(memlock, false, []string{"IPC_LOCK"}, []string{"IPC_LOCK"}), "CAP_IPC_LOCK")
+}
@@ -46,10 +46,15 @@ func generateUmaskOpts(umask string) ([]oci.SpecOpts, error) {
 	return opts, nil
 }
 
+// generateGroupsOpts returns the opts for `--group-add`.
+// Numeric GIDs are passed through as-is, as they do not need to exist in /etc/group of the image.
+// Group names are resolved against /etc/group of the image.
+// The GIDs are added in the order of the groups.
 func generateGroupsOpts(groups []string) ([]oci.SpecOpts, error) {
 	var opts []oci.SpecOpts
 
 	if len(groups) != 0 {
+		// oci.WithAppendAdditionalGroups only looks up the group names, numeric GIDs are used as-is
 		opts = append(opts, oci.WithAppendAdditionalGroups(groups...))
 	}
 	return opts, nil
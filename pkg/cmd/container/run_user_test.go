@@ -0,0 +1,103 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/containerd/v2/core/containers"
+	"github.com/containerd/containerd/v2/pkg/oci"
+)
+
+func TestGenerateGroupsOpts(t *testing.T) {
+	rootfs := t.TempDir()
+	assert.NilError(t, os.MkdirAll(filepath.Join(rootfs, "etc"), 0o755))
+	assert.NilError(t, os.WriteFile(filepath.Join(rootfs, "etc", "group"), []byte("root:x:0:\naudio:x:29:\nvideo:x:44:\n"), 0o644))
+
+	// A rootfs without /etc/group, e.g., a distroless image
+	emptyRootfs := t.TempDir()
+
+	testCases := []struct {
+		name     string
+		rootfs   string
+		groups   []string
+		expected []uint32
+		err      string
+	}{
+		{
+			name:     "numeric only, not in /etc/group",
+			groups:   []string{"1234", "44"},
+			expected: []uint32{1000, 1234, 44},
+		},
+		{
+			name:     "numeric only, no /etc/group",
+			rootfs:   emptyRootfs,
+			groups:   []string{"1234", "44"},
+			expected: []uint32{1000, 1234, 44},
+		},
+		{
+			name:     "names only",
+			groups:   []string{"audio", "video"},
+			expected: []uint32{1000, 29, 44},
+		},
+		{
+			name:     "mixed",
+			groups:   []string{"audio", "5678"},
+			expected: []uint32{1000, 29, 5678},
+		},
+		{
+			name:   "unknown name",
+			groups: []string{"nonexistent"},
+			err:    "unable to find group nonexistent",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts, err := generateGroupsOpts(tc.groups)
+			assert.NilError(t, err)
+			root := rootfs
+			if tc.rootfs != "" {
+				root = tc.rootfs
+			}
+			s := &oci.Spec{
+				Root: &specs.Root{Path: root},
+				Process: &specs.Process{
+					User: specs.User{UID: 1000, GID: 1000},
+				},
+			}
+			c := &containers.Container{}
+			for _, opt := range opts {
+				if err = opt(context.Background(), nil, c, s); err != nil {
+					break
+				}
+			}
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, s.Process.User.AdditionalGids, tc.expected)
+		})
+	}
+}
@@ -20,16 +20,20 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
 
 	"github.com/docker/go-units"
 	"github.com/opencontainers/runtime-spec/specs-go"
+	"golang.org/x/sys/windows/svc/mgr"
 
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/containerd/v2/core/containers"
 	"github.com/containerd/containerd/v2/pkg/oci"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/defaults"
 )
 
 const (
@@ -64,24 +68,11 @@ func setPlatformOptions(
 
 	switch options.Isolation {
 	case "hyperv":
-		if options.Memory != "" {
-			mem64, err := units.RAMInBytes(options.Memory)
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse memory bytes %q: %w", options.Memory, err)
-			}
-			uvmMemmory := map[string]string{
-				uvmMemorySizeInMB: fmt.Sprintf("%v", mem64),
-			}
-			opts = append(opts, oci.WithAnnotations(uvmMemmory))
-		}
-
-		if options.CPUs > 0.0 {
-			uvmCPU := map[string]string{
-				uvmCPUCount: fmt.Sprintf("%v", options.CPUs),
-			}
-			opts = append(opts, oci.WithAnnotations(uvmCPU))
+		hypervOpts, err := generateHyperVOpts(options)
+		if err != nil {
+			return nil, err
 		}
-		opts = append(opts, oci.WithWindowsHyperV)
+		opts = append(opts, hypervOpts...)
 	case "host":
 		hpAnnotations := map[string]string{
 			hostProcessContainer: "true",
@@ -102,7 +93,7 @@ func setPlatformOptions(
 		// no op
 		// use containerd's default runtime option `default_runtime` set in the config.toml
 	default:
-		return nil, fmt.Errorf("unknown isolation value %q. valid values are 'host', 'process' or 'default'", options.Isolation)
+		return nil, fmt.Errorf("unknown isolation value %q. valid values are 'hyperv', 'host', 'process' or 'default'", options.Isolation)
 	}
 
 	opts = append(opts,
@@ -123,6 +114,51 @@ func setPlatformOptions(
 	return opts, nil
 }
 
+// generateHyperVOpts generates the options to run the container in a Hyper-V utility VM.
+// --memory and --cpus are applied to the utility VM too, as the container cannot use more than the VM has.
+func generateHyperVOpts(options types.ContainerCreateOptions) ([]oci.SpecOpts, error) {
+	if options.Runtime != "" && !strings.HasPrefix(options.Runtime, "io.containerd.runhcs.") {
+		return nil, fmt.Errorf("--isolation=hyperv requires the runhcs runtime (%q), got %q", defaults.Runtime, options.Runtime)
+	}
+	if err := checkHyperVAvailable(); err != nil {
+		return nil, err
+	}
+
+	uvmAnnotations := map[string]string{}
+	if options.Memory != "" {
+		mem64, err := units.RAMInBytes(options.Memory)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse memory bytes %q: %w", options.Memory, err)
+		}
+		uvmAnnotations[uvmMemorySizeInMB] = strconv.FormatInt((mem64+units.MiB-1)/units.MiB, 10)
+	}
+	if options.CPUs > 0.0 {
+		uvmAnnotations[uvmCPUCount] = strconv.Itoa(int(math.Ceil(options.CPUs)))
+	}
+
+	opts := []oci.SpecOpts{oci.WithWindowsHyperV}
+	if len(uvmAnnotations) > 0 {
+		opts = append(opts, oci.WithAnnotations(uvmAnnotations))
+	}
+	return opts, nil
+}
+
+// checkHyperVAvailable returns an error if the Hyper-V Virtual Machine Management service is not present on the host.
+func checkHyperVAvailable() error {
+	const hypervServiceName = "vmms"
+	m, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the service manager to check Hyper-V availability: %w", err)
+	}
+	defer m.Disconnect()
+	s, err := m.OpenService(hypervServiceName)
+	if err != nil {
+		return fmt.Errorf("--isolation=hyperv requires Hyper-V to be enabled on the host (service %q not found): %w", hypervServiceName, err)
+	}
+	s.Close()
+	return nil
+}
+
 func WithWindowsProcessIsolated() oci.SpecOpts {
 	return func(_ context.Context, _ oci.Client, _ *containers.Container, s *specs.Spec) error {
 		if s.Windows == nil {
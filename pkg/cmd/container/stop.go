@@ -26,10 +26,16 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/containerutil"
 	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
+	"github.com/containerd/nerdctl/v2/pkg/signalutil"
 )
 
 // Stop stops a list of containers specified by `reqs`.
 func Stop(ctx context.Context, client *containerd.Client, reqs []string, opt types.ContainerStopOptions) error {
+	if opt.Signal != "" {
+		if _, err := signalutil.ParseSignal(opt.Signal); err != nil {
+			return err
+		}
+	}
 	walker := &containerwalker.ContainerWalker{
 		Client: client,
 		OnFound: func(ctx context.Context, found containerwalker.Found) error {
@@ -124,6 +124,9 @@ type imagePrintable struct {
 	Name         string // image name
 	Size         string // the size of the unpacked snapshots.
 	BlobSize     string // the size of the blobs in the content store (nerdctl extension)
+	// SizeBytes and BlobSizeBytes are Size and BlobSize in bytes, for scripts (nerdctl extension)
+	SizeBytes     int64
+	BlobSizeBytes int64
 	// TODO: "SharedSize", "UniqueSize"
 	Platform string // nerdctl extension
 }
@@ -367,25 +370,29 @@ func (x *imagePrinter) printImageSinglePlatform(desc ocispec.Descriptor, img ima
 	}
 
 	p := imagePrintable{
-		CreatedAt:    img.CreatedAt.Round(time.Second).Local().String(), // format like "2021-08-07 02:19:45 +0900 JST"
-		CreatedSince: formatter.TimeSinceInHuman(img.CreatedAt),
-		Digest:       img.Target.Digest.String(),
-		ID:           img.Target.Digest.String(),
-		Repository:   repository,
-		Tag:          tag,
-		Name:         img.Name,
-		Size:         units.HumanSize(float64(size)),
-		BlobSize:     units.HumanSize(float64(blobSize)),
-		Platform:     platforms.FormatAll(plt),
+		CreatedAt:     img.CreatedAt.Round(time.Second).Local().String(), // format like "2021-08-07 02:19:45 +0900 JST"
+		CreatedSince:  formatter.TimeSinceInHuman(img.CreatedAt),
+		Digest:        img.Target.Digest.String(),
+		ID:            img.Target.Digest.String(),
+		Repository:    repository,
+		Tag:           tag,
+		Name:          img.Name,
+		Size:          humanSize(size, x.noTrunc),
+		BlobSize:      humanSize(blobSize, x.noTrunc),
+		SizeBytes:     size,
+		BlobSizeBytes: blobSize,
+		Platform:      platforms.FormatAll(plt),
 	}
+	// Dangling images (e.g., the ones created by `nerdctl build` without `-t`) have neither repository nor tag
 	if p.Repository == "" {
 		p.Repository = "<none>"
+		p.Tag = ""
 	}
 	if p.Tag == "" {
 		p.Tag = "<none>" // for Docker compatibility
 	}
 	if !x.noTrunc {
-		// p.Digest does not need to be truncated
+		// p.Digest is truncated only in the table output
 		p.ID = strings.Split(p.ID, ":")[1][:12]
 	}
 	if x.tmpl != nil {
@@ -412,7 +419,11 @@ func (x *imagePrinter) printImageSinglePlatform(desc ocispec.Descriptor, img ima
 		}
 		if x.digestsFlag {
 			format += "%s\t"
-			args = append(args, p.Digest)
+			if x.noTrunc {
+				args = append(args, p.Digest)
+			} else {
+				args = append(args, shortDigest(p.Digest))
+			}
 		}
 
 		format += "%s\t%s\t%s\t%s\t%s\n"
@@ -424,6 +435,24 @@ func (x *imagePrinter) printImageSinglePlatform(desc ocispec.Descriptor, img ima
 	return nil
 }
 
+// humanSize returns the human-readable size, with 4 significant digits unless noTrunc is set.
+func humanSize(size int64, noTrunc bool) string {
+	if noTrunc {
+		return units.HumanSizeWithPrecision(float64(size), 15)
+	}
+	return units.HumanSize(float64(size))
+}
+
+// shortDigest returns the digest with the encoded part truncated to 12 characters,
+// e.g., "sha256:0123456789ab".
+func shortDigest(dgst string) string {
+	algo, encoded, ok := strings.Cut(dgst, ":")
+	if !ok || len(encoded) <= 12 {
+		return dgst
+	}
+	return algo + ":" + encoded[:12]
+}
+
 func isAttestationManifestDescriptor(desc ocispec.Descriptor) bool {
 	const manifestReferenceType = "vnd.docker.reference.type"
 	const attestationManifest = "attestation-manifest"
@@ -0,0 +1,59 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package image
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/platforms"
+
+	"github.com/containerd/nerdctl/v2/pkg/formatter"
+)
+
+func TestPrintImageSizes(t *testing.T) {
+	img := images.Image{
+		Name:   "example.com/foo:latest",
+		Target: ocispec.Descriptor{Digest: digest.FromString("foo")},
+	}
+	tmpl, err := formatter.ParseTemplate("{{.Size}} {{.SizeBytes}} {{.BlobSize}} {{.BlobSizeBytes}}")
+	assert.NilError(t, err)
+
+	testCases := []struct {
+		noTrunc  bool
+		expected string
+	}{
+		{
+			expected: "1.235MB 1234567 7.654MB 7654321\n",
+		},
+		{
+			noTrunc:  true,
+			expected: "1.234567MB 1234567 7.654321MB 7654321\n",
+		},
+	}
+	for _, tc := range testCases {
+		var b bytes.Buffer
+		x := &imagePrinter{w: &b, noTrunc: tc.noTrunc, tmpl: tmpl}
+		assert.NilError(t, x.printImageSinglePlatform(img.Target, img, 7654321, 1234567, platforms.DefaultSpec()))
+		assert.Equal(t, b.String(), tc.expected)
+	}
+}
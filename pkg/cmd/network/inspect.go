@@ -35,16 +35,39 @@ import (
 )
 
 func Inspect(ctx context.Context, client *containerd.Client, options types.NetworkInspectOptions) error {
+	result, errs, err := InspectEntries(ctx, client, options)
+	if err != nil {
+		return err
+	}
+
+	if len(result) > 0 {
+		if formatErr := formatter.FormatSlice(options.Format, options.Stdout, result); formatErr != nil {
+			log.G(ctx).Error(formatErr)
+		}
+		err = nil
+	} else {
+		err = errors.New("unable to find any network matching the provided request")
+	}
+
+	for _, unErr := range errs {
+		log.G(ctx).Error(unErr)
+	}
+
+	return err
+}
+
+// InspectEntries returns the inspected networks in the format of options.Mode, without printing them.
+// The errors of the networks that could not be found are returned in errs.
+func InspectEntries(ctx context.Context, client *containerd.Client, options types.NetworkInspectOptions) (result []any, errs []error, err error) {
 	if options.Mode != "native" && options.Mode != "dockercompat" {
-		return fmt.Errorf("unknown mode %q", options.Mode)
+		return nil, nil, fmt.Errorf("unknown mode %q", options.Mode)
 	}
 
 	cniEnv, err := netutil.NewCNIEnv(options.GOptions.CNIPath, options.GOptions.CNINetConfPath, netutil.WithNamespace(options.GOptions.Namespace))
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
-	var result []interface{}
 	netLists, errs := cniEnv.ListNetworksMatch(options.Networks, true)
 
 	for req, netList := range netLists {
@@ -62,7 +85,7 @@ func Inspect(ctx context.Context, client *containerd.Client, options types.Netwo
 		var filters = []string{fmt.Sprintf(`labels.%q~="\\\"%s\\\""`, labels.Networks, network.Name)}
 		filteredContainers, err := client.Containers(ctx, filters...)
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
 
 		var containers []*native.Container
@@ -91,24 +114,11 @@ func Inspect(ctx context.Context, client *containerd.Client, options types.Netwo
 		case "dockercompat":
 			compat, err := dockercompat.NetworkFromNative(r)
 			if err != nil {
-				return err
+				return nil, nil, err
 			}
 			result = append(result, compat)
 		}
 	}
 
-	if len(result) > 0 {
-		if formatErr := formatter.FormatSlice(options.Format, options.Stdout, result); formatErr != nil {
-			log.G(ctx).Error(formatErr)
-		}
-		err = nil
-	} else {
-		err = errors.New("unable to find any network matching the provided request")
-	}
-
-	for _, unErr := range errs {
-		log.G(ctx).Error(unErr)
-	}
-
-	return err
+	return result, errs, nil
 }
@@ -22,6 +22,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"text/template"
 	"time"
@@ -51,8 +52,14 @@ type Status string
 const (
 	START   Status = "start"
 	UNKNOWN Status = "unknown"
+	// HEARTBEAT is the status of the synthetic events emitted with `--heartbeat`.
+	// These events are not subject to filters.
+	HEARTBEAT Status = "heartbeat"
 )
 
+// HeartbeatTopic is the topic of the synthetic events emitted with `--heartbeat`.
+const HeartbeatTopic = "/nerdctl/heartbeat"
+
 var statuses = [...]Status{START, UNKNOWN}
 
 func isStatus(status string) bool {
@@ -165,12 +172,29 @@ func Events(ctx context.Context, client *containerd.Client, options types.System
 	if err != nil {
 		return err
 	}
+	// heartbeatC stays nil when heartbeats are disabled, so that it never fires.
+	var (
+		heartbeat  *time.Timer
+		heartbeatC <-chan time.Time
+	)
+	if options.Heartbeat > 0 {
+		heartbeat = time.NewTimer(options.Heartbeat)
+		defer heartbeat.Stop()
+		heartbeatC = heartbeat.C
+	}
 	for {
 		var e *events.Envelope
 		select {
 		case e = <-eventsCh:
 		case err := <-errCh:
 			return err
+		case t := <-heartbeatC:
+			eOut := EventOut{Timestamp: t, Namespace: options.GOptions.Namespace, Topic: HeartbeatTopic, Status: HEARTBEAT}
+			if err := printEvent(options.Stdout, tmpl, eOut); err != nil {
+				return err
+			}
+			heartbeat.Reset(options.Heartbeat)
+			continue
 		}
 		if e != nil {
 			var out []byte
@@ -201,27 +225,35 @@ func Events(ctx context.Context, client *containerd.Client, options types.System
 			eOut := EventOut{e.Timestamp, id, e.Namespace, e.Topic, TopicToStatus(e.Topic), string(out)}
 			match := applyFilters(&eOut, filterMap)
 			if match {
-				if tmpl != nil {
-					var b bytes.Buffer
-					if err := tmpl.Execute(&b, eOut); err != nil {
-						return err
-					}
-					if _, err := fmt.Fprintln(options.Stdout, b.String()+"\n"); err != nil {
-						return err
-					}
-				} else {
-					if _, err := fmt.Fprintln(
-						options.Stdout,
-						e.Timestamp,
-						e.Namespace,
-						e.Topic,
-						string(out),
-					); err != nil {
-						return err
-					}
+				if err := printEvent(options.Stdout, tmpl, eOut); err != nil {
+					return err
+				}
+				// heartbeats are only emitted when idle
+				if heartbeat != nil {
+					heartbeat.Reset(options.Heartbeat)
 				}
 			}
 
 		}
 	}
 }
+
+// printEvent prints an event using tmpl, or in the default format when tmpl is nil.
+func printEvent(w io.Writer, tmpl *template.Template, eOut EventOut) error {
+	if tmpl != nil {
+		var b bytes.Buffer
+		if err := tmpl.Execute(&b, eOut); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintln(w, b.String())
+		return err
+	}
+	_, err := fmt.Fprintln(
+		w,
+		eOut.Timestamp,
+		eOut.Namespace,
+		eOut.Topic,
+		eOut.Event,
+	)
+	return err
+}
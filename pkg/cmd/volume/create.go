@@ -37,7 +37,7 @@ func Create(name string, options types.VolumeCreateOptions) (*native.Volume, err
 		return nil, err
 	}
 	labels := strutil.DedupeStrSlice(options.Labels)
-	vol, err := volStore.Create(name, labels)
+	vol, err := volStore.CreateWithDriver(name, labels, options.Driver, options.Options)
 	if err != nil {
 		return nil, err
 	}
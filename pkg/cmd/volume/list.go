@@ -32,6 +32,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/formatter"
 	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/native"
+	"github.com/containerd/nerdctl/v2/pkg/volumedriver"
 )
 
 type volumePrintable struct {
@@ -125,6 +126,9 @@ func lsPrintOutput(vols map[string]native.Volume, options types.VolumeListOption
 			Name:       v.Name,
 			Scope:      "local",
 		}
+		if !volumedriver.IsLocal(v.Driver) {
+			p.Driver = v.Driver
+		}
 		if v.Labels != nil {
 			p.Labels = formatter.FormatLabels(*v.Labels)
 		}
@@ -231,17 +235,7 @@ func getVolumeFilterFuncs(filters []string) ([]func(*map[string]string) bool, []
 					return re.MatchString(name)
 				})
 			case "label":
-				k, v, hasValue := strings.Cut(value, "=")
-				labelFilterFuncs = append(labelFilterFuncs, func(labels *map[string]string) bool {
-					if labels == nil {
-						return false
-					}
-					val, ok := (*labels)[k]
-					if !ok || (hasValue && val != v) {
-						return false
-					}
-					return true
-				})
+				labelFilterFuncs = append(labelFilterFuncs, labelFilterFunc(value))
 			}
 			continue
 		}
@@ -264,6 +258,21 @@ func getVolumeFilterFuncs(filters []string) ([]func(*map[string]string) bool, []
 	return labelFilterFuncs, nameFilterFuncs, sizeFilterFuncs, isFilter, nil
 }
 
+// labelFilterFunc returns a filter matching the volumes with the label <key>[=<value>] of value.
+func labelFilterFunc(value string) func(*map[string]string) bool {
+	k, v, hasValue := strings.Cut(value, "=")
+	return func(labels *map[string]string) bool {
+		if labels == nil {
+			return false
+		}
+		val, ok := (*labels)[k]
+		if !ok || (hasValue && val != v) {
+			return false
+		}
+		return true
+	}
+}
+
 func volumeMatchesFilter(vol native.Volume, labelFilterFuncs []func(*map[string]string) bool, nameFilterFuncs []func(string) bool, sizeFilterFuncs []func(int64) bool) bool {
 	for _, labelFilterFunc := range labelFilterFuncs {
 		if !labelFilterFunc(vol.Labels) {
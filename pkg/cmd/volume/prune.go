@@ -22,6 +22,7 @@ import (
 	"strings"
 
 	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/errdefs"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/native"
@@ -36,6 +37,11 @@ func Prune(ctx context.Context, client *containerd.Client, options types.VolumeP
 		return err
 	}
 
+	labelFilterFuncs, err := getPruneFilterFuncs(options.Filters)
+	if err != nil {
+		return err
+	}
+
 	var toRemove []string // nolint: prealloc
 
 	err = volStore.Prune(func(volumes []*native.Volume) ([]string, error) {
@@ -64,6 +70,9 @@ func Prune(ctx context.Context, client *containerd.Client, options types.VolumeP
 					continue
 				}
 			}
+			if !allMatch(volume.Labels, labelFilterFuncs) {
+				continue
+			}
 			toRemove = append(toRemove, volume.Name)
 		}
 
@@ -82,3 +91,39 @@ func Prune(ctx context.Context, client *containerd.Client, options types.VolumeP
 
 	return nil
 }
+
+// getPruneFilterFuncs returns the label filters of the volumes to prune.
+//
+// Supported filters:
+//   - label=<key>[=<value>]: Prune the volumes with the label.
+//   - label!=<key>[=<value>]: Prune the volumes without the label.
+func getPruneFilterFuncs(filters []string) ([]func(*map[string]string) bool, error) {
+	var labelFilterFuncs []func(*map[string]string) bool
+	for _, filter := range filters {
+		key, value, ok := strings.Cut(filter, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid filter %q: must be key=value (%w)", filter, errdefs.ErrInvalidArgument)
+		}
+		switch key {
+		case "label":
+			labelFilterFuncs = append(labelFilterFuncs, labelFilterFunc(value))
+		case "label!":
+			match := labelFilterFunc(value)
+			labelFilterFuncs = append(labelFilterFuncs, func(labels *map[string]string) bool {
+				return !match(labels)
+			})
+		default:
+			return nil, fmt.Errorf("invalid filter %q: only label filters are supported (%w)", filter, errdefs.ErrInvalidArgument)
+		}
+	}
+	return labelFilterFuncs, nil
+}
+
+func allMatch[T any](v T, filters []func(T) bool) bool {
+	for _, f := range filters {
+		if !f(v) {
+			return false
+		}
+	}
+	return true
+}
@@ -111,12 +111,7 @@ func (c *Composer) logs(ctx context.Context, containers []containerd.Container,
 			args = append(args, "-t")
 		}
 		if lo.Tail != "" {
-			args = append(args, "-n")
-			if lo.Tail == "all" {
-				args = append(args, "+0")
-			} else {
-				args = append(args, lo.Tail)
-			}
+			args = append(args, "-n", lo.Tail)
 		}
 		if lo.LatestRun {
 			args = append(args, fmt.Sprintf("--since=%s", state.startedAt))
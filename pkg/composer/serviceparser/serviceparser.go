@@ -21,9 +21,11 @@ import (
 	"encoding/csv"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -533,7 +535,10 @@ func newContainer(project *types.Project, parsed *Service, i int) (*Container, e
 		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--entrypoint=%s", v))
 	}
 
-	for k, v := range svc.Environment {
+	// svc.Environment is a map, iterate in the order of the keys so that
+	// the environment of the container is the same on every run.
+	for _, k := range slices.Sorted(maps.Keys(svc.Environment)) {
+		v := svc.Environment[k]
 		if v == nil {
 			c.RunArgs = append(c.RunArgs, fmt.Sprintf("-e=%s", k))
 		} else {
@@ -21,6 +21,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/compose-spec/compose-go/v2/types"
@@ -167,6 +168,19 @@ volumes:
 	assert.Assert(t, in(wp1.RunArgs, fmt.Sprintf("--net=%s_default", project.Name)))
 	assert.Assert(t, in(wp1.RunArgs, "--restart=always"))
 	assert.Assert(t, in(wp1.RunArgs, "-e=WORDPRESS_DB_HOST=db"))
+	// the environment is passed in the order of the keys, not in the random order of the map
+	var envArgs []string
+	for _, a := range wp1.RunArgs {
+		if strings.HasPrefix(a, "-e=") {
+			envArgs = append(envArgs, a)
+		}
+	}
+	assert.DeepEqual(t, envArgs, []string{
+		"-e=WORDPRESS_DB_HOST=db",
+		"-e=WORDPRESS_DB_NAME=exampledb",
+		"-e=WORDPRESS_DB_PASSWORD=examplepass",
+		"-e=WORDPRESS_DB_USER=exampleuser",
+	})
 	assert.Assert(t, in(wp1.RunArgs, "-e=WORDPRESS_DB_USER=exampleuser"))
 	assert.Assert(t, in(wp1.RunArgs, "-p=8080:80/tcp"))
 	assert.Assert(t, in(wp1.RunArgs, fmt.Sprintf("-v=%s_wordpress:/var/www/html", project.Name)))
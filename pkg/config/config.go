@@ -20,6 +20,7 @@ import (
 	"github.com/containerd/containerd/v2/defaults"
 	"github.com/containerd/containerd/v2/pkg/namespaces"
 
+	"github.com/containerd/nerdctl/v2/pkg/consoleutil"
 	ncdefaults "github.com/containerd/nerdctl/v2/pkg/defaults"
 )
 
@@ -46,6 +47,25 @@ type Config struct {
 	DNS              []string `toml:"dns,omitempty"`
 	DNSOpts          []string `toml:"dns_opts,omitempty"`
 	DNSSearch        []string `toml:"dns_search,omitempty"`
+	// MaxLabelSize is the maximum size of the key and the value of a container label, in bytes.
+	// 0 disables the check.
+	MaxLabelSize int `toml:"max_label_size,omitempty"`
+	// Verify and the Cosign* properties are the defaults of the `--verify` and `--cosign-*` flags
+	// of `nerdctl pull`, `nerdctl run`, and `nerdctl create`.
+	Verify                            string `toml:"verify,omitempty"`
+	CosignKey                         string `toml:"cosign_key,omitempty"`
+	CosignCertificateIdentity         string `toml:"cosign_certificate_identity,omitempty"`
+	CosignCertificateIdentityRegexp   string `toml:"cosign_certificate_identity_regexp,omitempty"`
+	CosignCertificateOidcIssuer       string `toml:"cosign_certificate_oidc_issuer,omitempty"`
+	CosignCertificateOidcIssuerRegexp string `toml:"cosign_certificate_oidc_issuer_regexp,omitempty"`
+	// DetachKeys is the default of the `--detach-keys` flag of `nerdctl run`, `nerdctl start`, and `nerdctl attach`.
+	DetachKeys string `toml:"detach_keys,omitempty"`
+	// MinHealthInterval is the minimum interval between health check probes, as a Go duration string.
+	// Shorter intervals are raised to this value. "0s" disables the check.
+	MinHealthInterval string `toml:"min_health_interval,omitempty"`
+	// ProvenanceAnnotations enables the annotations recording the nerdctl version, the creation time,
+	// and the invoking user on the containers (see the annotations package).
+	ProvenanceAnnotations bool `toml:"provenance_annotations,omitempty"`
 }
 
 // New creates a default Config object statically,
@@ -71,5 +91,10 @@ func New() *Config {
 		DNS:              []string{},
 		DNSOpts:          []string{},
 		DNSSearch:        []string{},
+		// Same as the limit of containerd for labels
+		MaxLabelSize: 4096,
+		DetachKeys:   consoleutil.DefaultDetachKeys,
+		// Keeps accidental sub-second intervals from flooding the container with probe execs
+		MinHealthInterval: "1s",
 	}
 }
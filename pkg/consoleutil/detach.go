@@ -28,6 +28,21 @@ import (
 
 const DefaultDetachKeys = "ctrl-p,ctrl-q"
 
+// DetachKeysNone disables the detach key sequence, so that all the keys are passed to the container.
+const DetachKeysNone = "none"
+
+// ValidateDetachKeys returns an error if keys is not a valid detach key sequence.
+// An empty string (the default sequence) and DetachKeysNone are valid.
+func ValidateDetachKeys(keys string) error {
+	if keys == "" || keys == DetachKeysNone {
+		return nil
+	}
+	if _, err := term.ToBytes(keys); err != nil {
+		return fmt.Errorf("invalid detach keys %q: %w", keys, err)
+	}
+	return nil
+}
+
 type detachableStdin struct {
 	stdin  io.Reader
 	closer func()
@@ -36,7 +51,11 @@ type detachableStdin struct {
 // NewDetachableStdin returns an io.Reader that
 // uses a TTY proxy reader to read from stdin and detect when the specified detach keys are read,
 // in which case closer will be called.
+// When keys is DetachKeysNone, stdin is returned as-is and detaching is disabled.
 func NewDetachableStdin(stdin io.Reader, keys string, closer func()) (io.Reader, error) {
+	if keys == DetachKeysNone {
+		return stdin, nil
+	}
 	if len(keys) == 0 {
 		keys = DefaultDetachKeys
 	}
@@ -0,0 +1,32 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package consoleutil
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestValidateDetachKeys(t *testing.T) {
+	for _, keys := range []string{"", DetachKeysNone, DefaultDetachKeys, "ctrl-a,x", "a"} {
+		assert.NilError(t, ValidateDetachKeys(keys), keys)
+	}
+	for _, keys := range []string{"ctrl-", "ctrl-p,not-a-key", "foo"} {
+		assert.ErrorContains(t, ValidateDetachKeys(keys), "invalid detach keys", keys)
+	}
+}
@@ -18,15 +18,25 @@ package containerinspector
 
 import (
 	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
 
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/errdefs"
 	"github.com/containerd/log"
 	"github.com/containerd/typeurl/v2"
 
+	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
 	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/native"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
 )
 
+// RedactedValue replaces the values of the environment variables specified with `--redact-env`.
+const RedactedValue = "<redacted>"
+
 func Inspect(ctx context.Context, container containerd.Container) (*native.Container, error) {
 	info, err := container.Info(ctx)
 	if err != nil {
@@ -36,12 +46,29 @@ func Inspect(ctx context.Context, container containerd.Container) (*native.Conta
 		Container: info,
 	}
 	id := container.ID()
+	if err := redactHealthcheckEnv(info.Labels); err != nil {
+		log.G(ctx).WithError(err).WithField("id", id).Warnf("failed to redact the healthcheck Env")
+		delete(info.Labels, labels.HealthCheck)
+	}
 
 	n.Spec, err = typeurl.UnmarshalAny(info.Spec)
 	if err != nil {
 		log.G(ctx).WithError(err).WithField("id", id).Warnf("failed to inspect Spec")
 		return n, nil
 	}
+	if spec, ok := n.Spec.(*specs.Spec); ok {
+		if err := redactEnv(spec, info.Labels); err != nil {
+			log.G(ctx).WithError(err).WithField("id", id).Warnf("failed to redact Env")
+			if spec.Process != nil {
+				spec.Process.Env = nil
+			}
+		}
+		// The annotations of the containers created before the values of --health-env were kept out of them
+		if err := redactHealthcheckEnv(spec.Annotations); err != nil {
+			log.G(ctx).WithError(err).WithField("id", id).Warnf("failed to redact the healthcheck Env")
+			delete(spec.Annotations, labels.HealthCheck)
+		}
+	}
 	task, err := container.Task(ctx, nil)
 	if err != nil {
 		if !errdefs.IsNotFound(err) {
@@ -58,6 +85,12 @@ func Inspect(ctx context.Context, container containerd.Container) (*native.Conta
 		return n, nil
 	}
 	n.Process.Status = st
+	if spec, ok := n.Spec.(*specs.Spec); ok && st.Status == containerd.Stopped {
+		n.Process.OOMKilled, err = InspectOOMKilled(spec)
+		if err != nil {
+			log.G(ctx).WithError(err).WithField("id", id).Warnf("failed to inspect OOMKilled")
+		}
+	}
 	netNS, err := InspectNetNS(ctx, n.Process.Pid)
 	if err != nil {
 		log.G(ctx).WithError(err).WithField("id", id).Warnf("failed to inspect NetNS")
@@ -66,3 +99,41 @@ func Inspect(ctx context.Context, container containerd.Container) (*native.Conta
 	n.Process.NetNS = netNS
 	return n, nil
 }
+
+// redactEnv replaces the values of the environment variables listed in the
+// labels.RedactedEnv label, so that they do not leak in the inspect output.
+func redactEnv(spec *specs.Spec, containerLabels map[string]string) error {
+	redactedJSON, ok := containerLabels[labels.RedactedEnv]
+	if !ok || spec.Process == nil {
+		return nil
+	}
+	var redacted []string
+	if err := json.Unmarshal([]byte(redactedJSON), &redacted); err != nil {
+		return err
+	}
+	for i, env := range spec.Process.Env {
+		k, _, _ := strings.Cut(env, "=")
+		if slices.Contains(redacted, k) {
+			spec.Process.Env[i] = k + "=" + RedactedValue
+		}
+	}
+	return nil
+}
+
+// redactHealthcheckEnv replaces the values of the environment variables of the health probe (--health-env)
+// in the labels.HealthCheck label, so that they do not leak in the inspect output.
+func redactHealthcheckEnv(containerLabels map[string]string) error {
+	hcJSON, ok := containerLabels[labels.HealthCheck]
+	if !ok || hcJSON == "" {
+		return nil
+	}
+	hc, err := healthcheck.HealthCheckFromJSON(hcJSON)
+	if err != nil {
+		return err
+	}
+	if len(hc.Env) == 0 {
+		return nil
+	}
+	containerLabels[labels.HealthCheck], err = hc.Redacted().ToJSONString()
+	return err
+}
@@ -17,14 +17,22 @@
 package containerinspector
 
 import (
+	"bufio"
 	"context"
 	"fmt"
 	"net"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/containerd/cgroups/v3"
 
 	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/native"
+	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
 )
 
 func InspectNetNS(ctx context.Context, pid int) (*native.NetNS, error) {
@@ -73,3 +81,61 @@ func determinePrimaryInterface(interfaces []native.NetInterface) int {
 	}
 	return 0
 }
+
+// InspectOOMKilled returns true if a process of the container was killed by the OOM killer.
+// The count is read from "memory.events" (cgroup v2) or "memory.oom_control" (cgroup v1),
+// so the cgroup of the container has to be still present, i.e., the task must not be deleted yet.
+func InspectOOMKilled(spec *specs.Spec) (bool, error) {
+	if spec.Linux == nil || spec.Linux.CgroupsPath == "" || rootlessutil.IsRootless() {
+		return false, nil
+	}
+	group := cgroupGroupPath(spec.Linux.CgroupsPath)
+	var eventsPath string
+	if cgroups.Mode() == cgroups.Unified {
+		eventsPath = filepath.Join("/sys/fs/cgroup", group, "memory.events")
+	} else {
+		eventsPath = filepath.Join("/sys/fs/cgroup/memory", group, "memory.oom_control")
+	}
+	f, err := os.Open(eventsPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		k, v, ok := strings.Cut(scanner.Text(), " ")
+		if !ok || k != "oom_kill" {
+			continue
+		}
+		count, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return false, fmt.Errorf("failed to parse %q: %w", eventsPath, err)
+		}
+		return count > 0, nil
+	}
+	return false, scanner.Err()
+}
+
+// cgroupGroupPath converts the cgroupsPath of the OCI spec to the path relative to the cgroup mountpoint.
+// For the systemd cgroup driver, cgroupsPath is in the form of "slice:prefix:name",
+// e.g., "system.slice:nerdctl:<ID>" corresponds to "/system.slice/nerdctl-<ID>.scope".
+func cgroupGroupPath(cgroupsPath string) string {
+	parts := strings.Split(cgroupsPath, ":")
+	if len(parts) != 3 {
+		return cgroupsPath
+	}
+	slice, prefix, name := parts[0], parts[1], parts[2]
+	if slice == "" {
+		slice = "system.slice"
+	}
+	// "-" in a slice name denotes the hierarchy, e.g., "foo-bar.slice" is "/foo.slice/foo-bar.slice"
+	var path string
+	sliceParts := strings.Split(strings.TrimSuffix(slice, ".slice"), "-")
+	for i := range sliceParts {
+		path = filepath.Join(path, strings.Join(sliceParts[:i+1], "-")+".slice")
+	}
+	return filepath.Join("/", path, prefix+"-"+name+".scope")
+}
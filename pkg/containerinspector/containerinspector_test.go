@@ -0,0 +1,64 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package containerinspector
+
+import (
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+)
+
+func TestRedactEnv(t *testing.T) {
+	spec := &specs.Spec{
+		Process: &specs.Process{
+			Env: []string{"PATH=/bin", "API_TOKEN=s3cr3t", "PASSWORD=a=b", "TOKEN_FILE=/run/token"},
+		},
+	}
+	err := redactEnv(spec, map[string]string{labels.RedactedEnv: `["API_TOKEN","PASSWORD"]`})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, spec.Process.Env, []string{"PATH=/bin", "API_TOKEN=<redacted>", "PASSWORD=<redacted>", "TOKEN_FILE=/run/token"})
+
+	err = redactEnv(spec, map[string]string{labels.RedactedEnv: "invalid"})
+	assert.ErrorContains(t, err, "invalid")
+
+	// no label
+	spec.Process.Env = []string{"API_TOKEN=s3cr3t"}
+	assert.NilError(t, redactEnv(spec, nil))
+	assert.DeepEqual(t, spec.Process.Env, []string{"API_TOKEN=s3cr3t"})
+}
+
+func TestRedactHealthcheckEnv(t *testing.T) {
+	containerLabels := map[string]string{
+		labels.HealthCheck: `{"Test":["CMD-SHELL","curl -u $USER:$PASSWORD localhost"],"Env":["USER=admin","PASSWORD=s3cr3t"]}`,
+	}
+	assert.NilError(t, redactHealthcheckEnv(containerLabels))
+	hc, err := healthcheck.HealthCheckFromJSON(containerLabels[labels.HealthCheck])
+	assert.NilError(t, err)
+	assert.DeepEqual(t, hc.Env, []string{"USER=<redacted>", "PASSWORD=<redacted>"})
+
+	// no env
+	noEnv := `{"Test":["CMD-SHELL","true"]}`
+	containerLabels[labels.HealthCheck] = noEnv
+	assert.NilError(t, redactHealthcheckEnv(containerLabels))
+	assert.Equal(t, containerLabels[labels.HealthCheck], noEnv)
+
+	assert.NilError(t, redactHealthcheckEnv(nil))
+}
@@ -21,6 +21,8 @@ package containerinspector
 import (
 	"context"
 
+	"github.com/opencontainers/runtime-spec/specs-go"
+
 	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/native"
 )
 
@@ -29,3 +31,7 @@ func InspectNetNS(ctx context.Context, pid int) (*native.NetNS, error) {
 
 	return r, nil
 }
+
+func InspectOOMKilled(spec *specs.Spec) (bool, error) {
+	return false, nil
+}
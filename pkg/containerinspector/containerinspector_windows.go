@@ -19,6 +19,8 @@ package containerinspector
 import (
 	"context"
 
+	"github.com/opencontainers/runtime-spec/specs-go"
+
 	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/native"
 )
 
@@ -27,3 +29,7 @@ func InspectNetNS(ctx context.Context, pid int) (*native.NetNS, error) {
 
 	return r, nil
 }
+
+func InspectOOMKilled(spec *specs.Spec) (bool, error) {
+	return false, nil
+}
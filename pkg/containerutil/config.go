@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"runtime"
 	"strings"
 
@@ -28,10 +29,14 @@ import (
 
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/containerd/v2/pkg/oci"
+	"github.com/containerd/log"
 
+	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/dockercompat"
 	"github.com/containerd/nerdctl/v2/pkg/ipcutil"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/mountutil"
 	"github.com/containerd/nerdctl/v2/pkg/netutil/nettype"
+	"github.com/containerd/nerdctl/v2/pkg/volumedriver"
 )
 
 // ReconfigNetContainer reconfigures the container's network namespace path.
@@ -78,6 +83,96 @@ func ReconfigNetContainer(ctx context.Context, c containerd.Container, client *c
 	return nil
 }
 
+// ReconfigVolumeDriverMounts mounts the volumes of non-local drivers again through their driver,
+// and updates the sources of the mounts of the container when the driver returns another mountpoint.
+// Without it, a container restarted after the driver lost the mount (e.g. an NFS volume after a reboot)
+// would bind mount a stale path.
+func ReconfigVolumeDriverMounts(ctx context.Context, c containerd.Container, lab map[string]string) error {
+	driverMountsJSON, ok := lab[labels.VolumeDriverMounts]
+	if !ok {
+		return nil
+	}
+	var driverMounts []volumedriver.MountRecord
+	if err := json.Unmarshal([]byte(driverMountsJSON), &driverMounts); err != nil {
+		return fmt.Errorf("failed to parse the volume driver mounts of container %q: %w", c.ID(), err)
+	}
+	moved := make(map[string]string)
+	for i, dm := range driverMounts {
+		mountpoint, err := dm.Remount(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to mount volume %q with driver %q: %w", dm.Volume, dm.Driver, err)
+		}
+		if dm.Mountpoint != "" && dm.Mountpoint != mountpoint {
+			log.G(ctx).Debugf("volume %q of container %q moved from %q to %q", dm.Volume, c.ID(), dm.Mountpoint, mountpoint)
+			moved[dm.Mountpoint] = mountpoint
+		}
+		driverMounts[i].Mountpoint = mountpoint
+	}
+	if len(moved) == 0 {
+		return nil
+	}
+
+	spec, err := c.Spec(ctx)
+	if err != nil {
+		return err
+	}
+	for i := range spec.Mounts {
+		spec.Mounts[i].Source = movedSource(spec.Mounts[i].Source, moved)
+	}
+	newLabels := make(map[string]string)
+	if b, err := json.Marshal(driverMounts); err == nil {
+		newLabels[labels.VolumeDriverMounts] = string(b)
+	}
+	if mountsJSON, ok := lab[labels.Mounts]; ok {
+		var mounts []dockercompat.MountPoint
+		if err := json.Unmarshal([]byte(mountsJSON), &mounts); err == nil {
+			for i := range mounts {
+				mounts[i].Source = movedSource(mounts[i].Source, moved)
+			}
+			if b, err := json.Marshal(mounts); err == nil {
+				newLabels[labels.Mounts] = string(b)
+			}
+		}
+	}
+	return c.Update(ctx,
+		containerd.UpdateContainerOpts(containerd.WithSpec(spec)),
+		containerd.UpdateContainerOpts(containerd.WithAdditionalContainerLabels(newLabels)),
+	)
+}
+
+// ReconfigImageMounts mounts the rootfs of the images of the image mounts of the container again
+// if they are not mounted anymore (e.g. after a reboot). Their mountpoints do not change.
+func ReconfigImageMounts(ctx context.Context, client *containerd.Client, c containerd.Container, lab map[string]string) error {
+	imageMountsJSON, ok := lab[labels.ImageMounts]
+	if !ok {
+		return nil
+	}
+	var imageMounts []mountutil.ImageMountRecord
+	if err := json.Unmarshal([]byte(imageMountsJSON), &imageMounts); err != nil {
+		return fmt.Errorf("failed to parse the image mounts of container %q: %w", c.ID(), err)
+	}
+	for _, im := range imageMounts {
+		if err := im.Remount(ctx, client); err != nil {
+			return fmt.Errorf("failed to mount image %q: %w", im.Image, err)
+		}
+	}
+	return nil
+}
+
+// movedSource returns source with the old mountpoint it is in (the volume itself, or a subpath of it)
+// replaced with the new one.
+func movedSource(source string, moved map[string]string) string {
+	for oldPath, newPath := range moved {
+		if source == oldPath {
+			return newPath
+		}
+		if rel, ok := strings.CutPrefix(source, oldPath+string(filepath.Separator)); ok {
+			return filepath.Join(newPath, rel)
+		}
+	}
+	return source
+}
+
 // ReconfigPIDContainer reconfigures the container's spec options for sharing PID namespace.
 func ReconfigPIDContainer(ctx context.Context, c containerd.Container, client *containerd.Client, lab map[string]string) error {
 	targetContainerID, ok := lab[labels.PIDContainer]
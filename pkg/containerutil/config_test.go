@@ -0,0 +1,129 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package containerutil
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"gotest.tools/v3/assert"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/containers"
+	"github.com/containerd/containerd/v2/pkg/oci"
+
+	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/dockercompat"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/volumedriver"
+)
+
+// fakeContainer keeps the record of a container in memory.
+type fakeContainer struct {
+	containerd.Container
+	record  containers.Container
+	updates int
+}
+
+func (c *fakeContainer) ID() string {
+	return c.record.ID
+}
+
+func (c *fakeContainer) Spec(_ context.Context) (*oci.Spec, error) {
+	var s oci.Spec
+	if err := json.Unmarshal(c.record.Spec.GetValue(), &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (c *fakeContainer) Update(ctx context.Context, opts ...containerd.UpdateContainerOpts) error {
+	for _, o := range opts {
+		if err := o(ctx, nil, &c.record); err != nil {
+			return err
+		}
+	}
+	c.updates++
+	return nil
+}
+
+// movingVolumeDriver mounts the volumes under root, and counts the mounts by id.
+type movingVolumeDriver struct {
+	root   string
+	mounts map[string]int
+}
+
+func (d *movingVolumeDriver) Create(_ context.Context, name string, opts map[string]string) error {
+	return nil
+}
+
+func (d *movingVolumeDriver) Remove(_ context.Context, name string) error { return nil }
+
+func (d *movingVolumeDriver) Mount(_ context.Context, name, id string) (string, error) {
+	d.mounts[id]++
+	return d.root + "/" + name, nil
+}
+
+func (d *movingVolumeDriver) Unmount(_ context.Context, name, id string) error {
+	if d.mounts[id]--; d.mounts[id] == 0 {
+		delete(d.mounts, id)
+	}
+	return nil
+}
+
+func TestReconfigVolumeDriverMounts(t *testing.T) {
+	ctx := context.Background()
+	d := &movingVolumeDriver{root: "/new", mounts: map[string]int{"id1": 1}}
+	assert.NilError(t, volumedriver.Register("moving", d))
+	t.Cleanup(func() { volumedriver.Unregister("moving") })
+
+	spec := &specs.Spec{Mounts: []specs.Mount{
+		{Destination: "/data", Source: "/old/vol/sub", Type: "bind"},
+		{Destination: "/other", Source: "/old/volume", Type: "bind"},
+	}}
+	driverMounts, err := json.Marshal([]volumedriver.MountRecord{{Volume: "vol", Driver: "moving", ID: "id1", Mountpoint: "/old/vol"}})
+	assert.NilError(t, err)
+	mounts, err := json.Marshal([]dockercompat.MountPoint{{Source: "/old/vol/sub", Destination: "/data"}})
+	assert.NilError(t, err)
+	lab := map[string]string{labels.VolumeDriverMounts: string(driverMounts), labels.Mounts: string(mounts)}
+	c := &fakeContainer{record: containers.Container{ID: "test", Labels: lab}}
+	assert.NilError(t, containerd.WithSpec(spec)(ctx, nil, &c.record))
+
+	assert.NilError(t, ReconfigVolumeDriverMounts(ctx, c, lab))
+	// the mount is released before it is mounted again
+	assert.DeepEqual(t, d.mounts, map[string]int{"id1": 1})
+	newSpec, err := c.Spec(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, newSpec.Mounts[0].Source, "/new/vol/sub")
+	assert.Equal(t, newSpec.Mounts[1].Source, "/old/volume")
+	var newDriverMounts []volumedriver.MountRecord
+	assert.NilError(t, json.Unmarshal([]byte(c.record.Labels[labels.VolumeDriverMounts]), &newDriverMounts))
+	assert.Equal(t, newDriverMounts[0].Mountpoint, "/new/vol")
+	var newMounts []dockercompat.MountPoint
+	assert.NilError(t, json.Unmarshal([]byte(c.record.Labels[labels.Mounts]), &newMounts))
+	assert.Equal(t, newMounts[0].Source, "/new/vol/sub")
+
+	// the container is not updated when the mountpoint does not change
+	assert.NilError(t, ReconfigVolumeDriverMounts(ctx, c, c.record.Labels))
+	assert.Equal(t, c.updates, 1)
+	assert.DeepEqual(t, d.mounts, map[string]int{"id1": 1})
+
+	// nothing is mounted for a container without driver mounts
+	assert.NilError(t, ReconfigVolumeDriverMounts(ctx, c, map[string]string{}))
+	assert.DeepEqual(t, d.mounts, map[string]int{"id1": 1})
+}
@@ -26,6 +26,8 @@ import (
 	"path/filepath"
 	"reflect"
 	"runtime"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/opencontainers/runtime-spec/specs-go"
@@ -39,6 +41,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/clientutil"
 	"github.com/containerd/nerdctl/v2/pkg/dnsutil/hostsstore"
 	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
+	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/dockercompat"
 	"github.com/containerd/nerdctl/v2/pkg/internal/filesystem"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
 	"github.com/containerd/nerdctl/v2/pkg/mountutil"
@@ -89,7 +92,11 @@ func withCustomHosts(src string) func(context.Context, oci.Client, *containers.C
 	}
 }
 
-func fetchDNSResolverConfig(netOpts types.NetworkOptions) ([]string, []string, []string, error) {
+// fetchDNSResolverConfig returns the nameservers, the search domains and the options for the resolv.conf of the container.
+// The values not specified in netOpts (i.e., with --dns, --dns-search, --dns-option) are taken from the resolv.conf of the host.
+// Localhost nameservers of the host are dropped unless keepLocalhostNS is set, as they are not reachable from
+// a container that does not share the network namespace with the host.
+func fetchDNSResolverConfig(netOpts types.NetworkOptions, keepLocalhostNS bool) ([]string, []string, []string, error) {
 	dns := netOpts.DNSServers
 	dnsSearch := netOpts.DNSSearchDomains
 	dnsOptions := netOpts.DNSResolvConfOptions
@@ -103,15 +110,17 @@ func fetchDNSResolverConfig(netOpts types.NetworkOptions) ([]string, []string, [
 		conf = &resolvconf.File{}
 		log.L.WithError(err).Debugf("resolvConf file doesn't exist on host")
 	}
-	conf, err = resolvconf.FilterResolvDNS(conf.Content, true)
-	if err != nil {
-		return nil, nil, nil, err
+	if !keepLocalhostNS || len(resolvconf.GetNameservers(conf.Content, resolvconf.IP)) == 0 {
+		conf, err = resolvconf.FilterResolvDNS(conf.Content, true)
+		if err != nil {
+			return nil, nil, nil, err
+		}
 	}
 	if len(netOpts.DNSServers) == 0 {
 		dns = resolvconf.GetNameservers(conf.Content, resolvconf.IP)
 	}
 	if len(netOpts.DNSSearchDomains) == 0 {
-		dnsSearch = resolvconf.GetSearchDomains(conf.Content)
+		dnsSearch = searchDomainsWithDomainname(resolvconf.GetSearchDomains(conf.Content), netOpts.Domainname)
 	}
 	if len(netOpts.DNSResolvConfOptions) == 0 {
 		dnsOptions = resolvconf.GetOptions(conf.Content)
@@ -119,6 +128,16 @@ func fetchDNSResolverConfig(netOpts types.NetworkOptions) ([]string, []string, [
 	return dns, dnsSearch, dnsOptions, err
 }
 
+// searchDomainsWithDomainname returns the search domains inherited from the host with the domain of
+// the container (`--domainname`) first, so that the short names of the domain are resolved.
+// It is not used when the search domains are set with `--dns-search`.
+func searchDomainsWithDomainname(searchDomains []string, domainname string) []string {
+	if domainname == "" || slices.Contains(searchDomains, domainname) {
+		return searchDomains
+	}
+	return append([]string{domainname}, searchDomains...)
+}
+
 // NetworkOptionsManager types.NetworkOptionsManager is an interface for reading/setting networking
 // options for containers based on the provided command flags.
 type NetworkOptionsManager interface {
@@ -272,8 +291,9 @@ func (m *noneNetworkManager) CleanupNetworking(ctx context.Context, container co
 // InternalNetworkingOptionLabels Returns the set of NetworkingOptions which should be set as labels on the container.
 func (m *noneNetworkManager) InternalNetworkingOptionLabels(_ context.Context) (types.NetworkOptions, error) {
 	opts := m.netOpts
-	// Cannot have a MAC address in host networking mode.
+	// Cannot have a MAC address or an MTU in host networking mode.
 	opts.MACAddress = ""
+	opts.MTU = 0
 	return opts, nil
 }
 
@@ -290,48 +310,43 @@ func (m *noneNetworkManager) ContainerNetworkingOpts(_ context.Context, containe
 		return nil, nil, err
 	}
 
-	resolvConfPath := filepath.Join(stateDir, "resolv.conf")
-	dns, dnsSearch, dnsOptions, err := fetchDNSResolverConfig(m.netOpts)
-	if err != nil {
-		return nil, nil, err
-	}
-	_, err = resolvconf.Build(resolvConfPath, dns, dnsSearch, dnsOptions)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	hs, err := hostsstore.New(dataStore, m.globalOptions.Namespace)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	etcHostsPath, err := hs.AllocHostsFile(containerID, []byte{})
-	if err != nil {
-		return nil, nil, err
-	}
-
 	// `/etc/host` does not exist in FreeBSD minimal rootfs image
 	// `/etc/resolv.conf` does not exist in FreeBSD minimal rootfs image
 	specs := []oci.SpecOpts{}
-	if runtime.GOOS == "linux" {
-		specs = []oci.SpecOpts{
-			withDedupMounts("/etc/resolv.conf", withCustomResolvConf(resolvConfPath)),
-			withDedupMounts("/etc/hosts", withCustomHosts(etcHostsPath)),
+	if runtime.GOOS == "linux" && !m.netOpts.NoResolv {
+		resolvConfPath := filepath.Join(stateDir, "resolv.conf")
+		dns, dnsSearch, dnsOptions, err := fetchDNSResolverConfig(m.netOpts, false)
+		if err != nil {
+			return nil, nil, err
+		}
+		_, err = resolvconf.Build(resolvConfPath, dns, dnsSearch, dnsOptions)
+		if err != nil {
+			return nil, nil, err
 		}
+		specs = append(specs, withDedupMounts("/etc/resolv.conf", withCustomResolvConf(resolvConfPath)))
 	}
 
 	// `/etc/hostname` does not exist on FreeBSD
 	if runtime.GOOS == "linux" {
-		// If no hostname is set, default to first 12 characters of the container ID.
-		hostname := m.netOpts.Hostname
-		if hostname == "" {
-			hostname = containerID
-			if len(hostname) > 12 {
-				hostname = hostname[0:12]
-			}
+		m.netOpts.Hostname = DefaultHostname(m.netOpts.Hostname, containerID)
+	}
+
+	if runtime.GOOS == "linux" && !m.netOpts.NoHosts {
+		hs, err := hostsstore.New(dataStore, m.globalOptions.Namespace)
+		if err != nil {
+			return nil, nil, err
 		}
-		m.netOpts.Hostname = hostname
 
+		// Without a network, the OCI hook does not write /etc/hosts,
+		// so the hostname is resolved to the loopback address here.
+		etcHostsPath, err := hs.AllocHostsFile(containerID, hostsstore.LoopbackHosts(m.netOpts.Hostname, m.netOpts.Domainname))
+		if err != nil {
+			return nil, nil, err
+		}
+		specs = append(specs, withDedupMounts("/etc/hosts", withCustomHosts(etcHostsPath)))
+	}
+
+	if runtime.GOOS == "linux" {
 		hostnameOpts, err := writeEtcHostnameForContainer(m.globalOptions, m.netOpts.Hostname, containerID)
 		if err != nil {
 			return nil, nil, err
@@ -339,6 +354,9 @@ func (m *noneNetworkManager) ContainerNetworkingOpts(_ context.Context, containe
 		if hostnameOpts != nil {
 			specs = append(specs, hostnameOpts...)
 		}
+		if m.netOpts.Domainname != "" {
+			specs = append(specs, oci.WithDomainname(m.netOpts.Domainname))
+		}
 	}
 	return specs, []containerd.NewContainerOpts{}, nil
 }
@@ -372,9 +390,11 @@ func (m *containerNetworkManager) VerifyNetworkOptions(_ context.Context) error
 		"--hostname":   m.netOpts.Hostname,
 		"--domainname": m.netOpts.Domainname,
 		// NOTE: an empty slice still counts as a non-zero value so we check its length:
-		"-p/--publish": len(m.netOpts.PortMappings) != 0,
-		"--dns":        len(m.netOpts.DNSServers) != 0,
-		"--add-host":   len(m.netOpts.AddHost) != 0,
+		"-p/--publish":     len(m.netOpts.PortMappings) != 0,
+		"-P/--publish-all": m.netOpts.PublishAll,
+		"--expose":         len(m.netOpts.Expose) != 0,
+		"--dns":            len(m.netOpts.DNSServers) != 0,
+		"--add-host":       len(m.netOpts.AddHost) != 0,
 	})
 
 	if len(nonZeroParams) != 0 {
@@ -462,8 +482,9 @@ func (m *containerNetworkManager) InternalNetworkingOptionLabels(ctx context.Con
 	if m.netOpts.NetworkSlice == nil || len(m.netOpts.NetworkSlice) != 1 {
 		return opts, fmt.Errorf("conflicting options: exactly one network specification is allowed when using '--network=container:<container>'")
 	}
-	// MacAddress is not allowed with container networking
+	// MacAddress and MTU are not allowed with container networking
 	opts.MACAddress = ""
+	opts.MTU = 0
 
 	container, err := m.getNetworkingContainerForArgument(ctx, m.netOpts.NetworkSlice[0], m.client)
 	if err != nil {
@@ -511,11 +532,15 @@ func (m *containerNetworkManager) ContainerNetworkingOpts(ctx context.Context, _
 			Type: specs.NetworkNamespace,
 			Path: netNSPath,
 		}),
-		withCustomResolvConf(resolvConfPath),
-		withCustomHosts(etcHostsPath),
 		oci.WithHostname(hostname),
 		withCustomEtcHostname(hostnamePath),
 	)
+	if !m.netOpts.NoResolv {
+		opts = append(opts, withCustomResolvConf(resolvConfPath))
+	}
+	if !m.netOpts.NoHosts {
+		opts = append(opts, withCustomHosts(etcHostsPath))
+	}
 
 	return opts, cOpts, nil
 }
@@ -616,8 +641,9 @@ func (m *hostNetworkManager) CleanupNetworking(ctx context.Context, container co
 // InternalNetworkingOptionLabels Returns the set of NetworkingOptions which should be set as labels on the container.
 func (m *hostNetworkManager) InternalNetworkingOptionLabels(_ context.Context) (types.NetworkOptions, error) {
 	opts := m.netOpts
-	// Cannot have a MAC address in host networking mode.
+	// Cannot have a MAC address or an MTU in host networking mode.
 	opts.MACAddress = ""
+	opts.MTU = 0
 	return opts, nil
 }
 
@@ -670,41 +696,47 @@ func (m *hostNetworkManager) ContainerNetworkingOpts(_ context.Context, containe
 		return nil, nil, err
 	}
 
-	resolvConfPath := filepath.Join(stateDir, "resolv.conf")
-	dns, dnsSearch, dnsOptions, err := fetchDNSResolverConfig(m.netOpts)
+	// The resolv.conf is generated even for the host network, so that --dns, --dns-search and --dns-option take effect.
+	// The localhost nameservers of the host (e.g., systemd-resolved) are reachable when the host network namespace is
+	// shared, except for rootless, where the network namespace of RootlessKit is used.
+	netModeArg := m.netOpts.NetworkSlice[0]
+	netNamespace, err := getHostNetworkingNamespace(netModeArg)
 	if err != nil {
 		return nil, nil, err
 	}
+	specs := []oci.SpecOpts{netNamespace}
 
-	_, err = resolvconf.Build(resolvConfPath, dns, dnsSearch, dnsOptions)
-	if err != nil {
-		return nil, nil, err
-	}
+	if !m.netOpts.NoResolv {
+		sharesHostNetNS := !rootlessutil.IsRootless() && !strings.Contains(netModeArg, ":")
+		resolvConfPath := filepath.Join(stateDir, "resolv.conf")
+		dns, dnsSearch, dnsOptions, err := fetchDNSResolverConfig(m.netOpts, sharesHostNetNS)
+		if err != nil {
+			return nil, nil, err
+		}
 
-	hs, err := hostsstore.New(dataStore, m.globalOptions.Namespace)
-	if err != nil {
-		return nil, nil, err
+		_, err = resolvconf.Build(resolvConfPath, dns, dnsSearch, dnsOptions)
+		if err != nil {
+			return nil, nil, err
+		}
+		specs = append(specs, withDedupMounts("/etc/resolv.conf", withCustomResolvConf(resolvConfPath)))
 	}
 
-	content, err := filesystem.ReadFile("/etc/hosts")
-	if err != nil {
-		return nil, nil, err
-	}
+	if !m.netOpts.NoHosts {
+		hs, err := hostsstore.New(dataStore, m.globalOptions.Namespace)
+		if err != nil {
+			return nil, nil, err
+		}
 
-	etcHostsPath, err := hs.AllocHostsFile(containerID, content)
-	if err != nil {
-		return nil, nil, err
-	}
+		content, err := filesystem.ReadFile("/etc/hosts")
+		if err != nil {
+			return nil, nil, err
+		}
 
-	netModeArg := m.netOpts.NetworkSlice[0]
-	netNamespace, err := getHostNetworkingNamespace(netModeArg)
-	if err != nil {
-		return nil, nil, err
-	}
-	specs := []oci.SpecOpts{
-		netNamespace,
-		withDedupMounts("/etc/resolv.conf", withCustomResolvConf(resolvConfPath)),
-		withDedupMounts("/etc/hosts", withCustomHosts(etcHostsPath)),
+		etcHostsPath, err := hs.AllocHostsFile(containerID, content)
+		if err != nil {
+			return nil, nil, err
+		}
+		specs = append(specs, withDedupMounts("/etc/hosts", withCustomHosts(etcHostsPath)))
 	}
 
 	// `/etc/hostname` does not exist on FreeBSD
@@ -715,11 +747,7 @@ func (m *hostNetworkManager) ContainerNetworkingOpts(_ context.Context, containe
 			hostname, err = os.Hostname()
 			if err != nil {
 				log.L.WithError(err).Warn("could not get hostname")
-				// If no hostname is set, default to first 12 characters of the container ID.
-				hostname = containerID
-				if len(hostname) > 12 {
-					hostname = hostname[0:12]
-				}
+				hostname = DefaultHostname("", containerID)
 			}
 		}
 		m.netOpts.Hostname = hostname
@@ -809,6 +837,17 @@ func validateUtsSettings(netOpts types.NetworkOptions) error {
 	return nil
 }
 
+// DefaultHostname returns hostname, or the first 12 characters of the container ID when hostname is not set.
+func DefaultHostname(hostname, containerID string) string {
+	if hostname != "" {
+		return hostname
+	}
+	if len(containerID) > 12 {
+		return containerID[0:12]
+	}
+	return containerID
+}
+
 // Writes the provided hostname string in a "hostname" file in the Container's
 // Nerdctl-managed datastore and returns the oci.SpecOpts required in the container
 // spec for the file to be mounted under /etc/hostname in the new container.
@@ -882,6 +921,13 @@ func NetworkOptionsFromSpec(spec *specs.Spec) (types.NetworkOptions, error) {
 		opts.MACAddress = macAddress
 	}
 
+	if mtu, ok := spec.Annotations[labels.NetworkMTU]; ok {
+		var err error
+		if opts.MTU, err = strconv.Atoi(mtu); err != nil {
+			return opts, fmt.Errorf("invalid %s annotation %q: %w", labels.NetworkMTU, mtu, err)
+		}
+	}
+
 	if ipAddress, ok := spec.Annotations[labels.IPAddress]; ok {
 		opts.IPAddress = ipAddress
 	}
@@ -893,6 +939,15 @@ func NetworkOptionsFromSpec(spec *specs.Spec) (types.NetworkOptions, error) {
 	}
 	opts.NetworkSlice = networks
 
+	if dnsSettingJSON, ok := spec.Annotations[labels.DNSSetting]; ok {
+		var dnsSettings dockercompat.DNSSettings
+		if err := json.Unmarshal([]byte(dnsSettingJSON), &dnsSettings); err != nil {
+			return opts, err
+		}
+		opts.NoResolv = dnsSettings.NoResolv
+		opts.NoHosts = dnsSettings.NoHosts
+	}
+
 	return opts, nil
 }
 
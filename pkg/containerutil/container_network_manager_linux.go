@@ -93,34 +93,31 @@ func (m *cniNetworkManager) ContainerNetworkingOpts(_ context.Context, container
 		return nil, nil, err
 	}
 
-	resolvConfPath := filepath.Join(stateDir, "resolv.conf")
-	if err := m.buildResolvConf(resolvConfPath); err != nil {
-		return nil, nil, err
+	// With --no-resolv and --no-hosts, the files of the image are left intact.
+	if !m.netOpts.NoResolv {
+		resolvConfPath := filepath.Join(stateDir, "resolv.conf")
+		if err := m.buildResolvConf(resolvConfPath); err != nil {
+			return nil, nil, err
+		}
+		opts = append(opts, withCustomResolvConf(resolvConfPath))
 	}
 
-	// the content of /etc/hosts is created in OCI Hook
-	hs, err := hostsstore.New(dataStore, m.globalOptions.Namespace)
-	if err != nil {
-		return nil, nil, err
-	}
+	if !m.netOpts.NoHosts {
+		// the content of /etc/hosts is created in OCI Hook
+		hs, err := hostsstore.New(dataStore, m.globalOptions.Namespace)
+		if err != nil {
+			return nil, nil, err
+		}
 
-	etcHostsPath, err := hs.AllocHostsFile(containerID, []byte(""))
-	if err != nil {
-		return nil, nil, err
+		etcHostsPath, err := hs.AllocHostsFile(containerID, []byte(""))
+		if err != nil {
+			return nil, nil, err
+		}
+		opts = append(opts, withCustomHosts(etcHostsPath))
 	}
 
-	opts = append(opts, withCustomResolvConf(resolvConfPath), withCustomHosts(etcHostsPath))
-
 	if m.netOpts.UTSNamespace != UtsNamespaceHost {
-		// If no hostname is set, default to first 12 characters of the container ID.
-		hostname := m.netOpts.Hostname
-		if hostname == "" {
-			hostname = containerID
-			if len(hostname) > 12 {
-				hostname = hostname[0:12]
-			}
-		}
-		m.netOpts.Hostname = hostname
+		m.netOpts.Hostname = DefaultHostname(m.netOpts.Hostname, containerID)
 
 		hostnameOpts, err := writeEtcHostnameForContainer(m.globalOptions, m.netOpts.Hostname, containerID)
 		if err != nil {
@@ -172,7 +169,7 @@ func (m *cniNetworkManager) buildResolvConf(resolvConfPath string) error {
 			nameServers = resolvconf.GetNameservers(conf.Content, resolvconf.IPv4)
 		}
 		if len(searchDomains) == 0 {
-			searchDomains = resolvconf.GetSearchDomains(conf.Content)
+			searchDomains = searchDomainsWithDomainname(resolvconf.GetSearchDomains(conf.Content), m.netOpts.Domainname)
 		}
 		if len(dnsOptions) == 0 {
 			dnsOptions = resolvconf.GetOptions(conf.Content)
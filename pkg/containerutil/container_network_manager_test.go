@@ -115,3 +115,10 @@ func TestZeroMapValues(t *testing.T) {
 		})
 	}
 }
+
+func TestSearchDomainsWithDomainname(t *testing.T) {
+	assert.DeepEqual(t, searchDomainsWithDomainname([]string{"example.org"}, ""), []string{"example.org"})
+	assert.DeepEqual(t, searchDomainsWithDomainname(nil, "example.com"), []string{"example.com"})
+	assert.DeepEqual(t, searchDomainsWithDomainname([]string{"example.org"}, "example.com"), []string{"example.com", "example.org"})
+	assert.DeepEqual(t, searchDomainsWithDomainname([]string{"example.org", "example.com"}, "example.com"), []string{"example.org", "example.com"})
+}
@@ -54,6 +54,7 @@ func (m *cniNetworkManager) VerifyNetworkOptions(_ context.Context) error {
 		// NOTE: IP and MAC settings are currently ignored on Windows.
 		"--ip-address":  m.netOpts.IPAddress,
 		"--mac-address": m.netOpts.MACAddress,
+		"--network-mtu": m.netOpts.MTU,
 		// NOTE: zero-length slices count as a non-zero-value so we explicitly check length:
 		"--dns-opt/--dns-option": len(m.netOpts.DNSResolvConfOptions) != 0,
 		"--dns-servers":          len(m.netOpts.DNSServers) != 0,
@@ -31,7 +31,6 @@ import (
 
 	dockercliopts "github.com/docker/cli/opts"
 	dockeropts "github.com/docker/docker/opts"
-	"github.com/moby/sys/signal"
 	"github.com/opencontainers/runtime-spec/specs-go"
 	"golang.org/x/term"
 
@@ -111,6 +110,18 @@ func UpdateStatusLabel(ctx context.Context, container containerd.Container, stat
 	return container.Update(ctx, containerd.UpdateContainerOpts(opt))
 }
 
+// restoreStatusLabel sets the "containerd.io/restart.status" label of the container back to status,
+// or removes the label if status is empty, i.e., if the container had no desired status.
+func restoreStatusLabel(ctx context.Context, container containerd.Container, status string) error {
+	if status != "" {
+		return UpdateStatusLabel(ctx, container, containerd.ProcessStatus(status))
+	}
+	return container.Update(ctx, func(_ context.Context, _ *containerd.Client, c *containers.Container) error {
+		delete(c.Labels, restart.StatusLabel)
+		return nil
+	})
+}
+
 // UpdateExplicitlyStoppedLabel updates the "containerd.io/restart.explicitly-stopped"
 // label of the container according to the value of explicitlyStopped.
 func UpdateExplicitlyStoppedLabel(ctx context.Context, container containerd.Container, explicitlyStopped bool) error {
@@ -228,6 +239,10 @@ func Start(ctx context.Context, container containerd.Container, isAttach bool, i
 		return err
 	}
 
+	if err := ReconfigImageMounts(ctx, client, container, lab); err != nil {
+		return err
+	}
+
 	process, err := container.Spec(ctx)
 	if err != nil {
 		return err
@@ -253,6 +268,11 @@ func Start(ctx context.Context, container containerd.Container, isAttach bool, i
 		return nil
 	}
 
+	// Remounting releases the mount of the running container, so it is only done for a stopped container
+	if err := ReconfigVolumeDriverMounts(ctx, container, lab); err != nil {
+		return err
+	}
+
 	_, restartPolicyExist := lab[restart.PolicyLabel]
 	if restartPolicyExist {
 		if err := UpdateStatusLabel(ctx, container, containerd.Running); err != nil {
@@ -268,6 +288,12 @@ func Start(ctx context.Context, container containerd.Container, isAttach bool, i
 			log.G(ctx).WithError(err).Debug("failed to delete old task")
 		}
 	}
+	// An explicit start is not subject to the backoff of --restart-min-uptime.
+	if _, ok := lab[labels.RestartMinUptime]; ok {
+		if err := resetRestartBackoff(ctx, container, lab[labels.StateDir]); err != nil {
+			log.G(ctx).WithError(err).Warn("failed to reset the restart backoff")
+		}
+	}
 	detachC := make(chan struct{})
 	attachStreamOpt := []string{}
 	if isAttach {
@@ -340,6 +366,29 @@ func Stop(ctx context.Context, container containerd.Container, timeout *time.Dur
 	if err != nil {
 		return err
 	}
+
+	// The restart monitor only honors the explicitly-stopped label for the "unless-stopped" policy,
+	// so for the other policies it could restart the container as soon as the task exits,
+	// or SIGKILL it before the stop signal is handled if the desired status were already "stopped".
+	// Set the desired status to "unknown", which the monitor ignores, while the stop is in progress.
+	if _, ok := l[restart.PolicyLabel]; ok {
+		if err := UpdateStatusLabel(ctx, container, containerd.Unknown); err != nil {
+			return err
+		}
+		defer func() {
+			var uerr error
+			if err == nil {
+				uerr = UpdateStatusLabel(ctx, container, containerd.Stopped)
+			} else {
+				// restore the previous desired status, as the container may still be running
+				uerr = restoreStatusLabel(ctx, container, l[restart.StatusLabel])
+			}
+			if uerr != nil {
+				log.G(ctx).WithError(uerr).Warnf("failed to update the restart status label of container %s", container.ID())
+			}
+		}()
+	}
+
 	ipc, err := ipcutil.DecodeIPCLabel(l[labels.IPC])
 	if err != nil {
 		return err
@@ -430,7 +479,7 @@ func Stop(ctx context.Context, container containerd.Container, timeout *time.Dur
 		}
 	}
 
-	sig, err := signal.ParseSignal("SIGKILL")
+	sig, err := signalutil.ParseSignal("SIGKILL")
 	if err != nil {
 		return err
 	}
@@ -450,14 +499,14 @@ func Stop(ctx context.Context, container containerd.Container, timeout *time.Dur
 
 func getSignal(signalValue string, containerLabels map[string]string) (syscall.Signal, error) {
 	if signalValue != "" {
-		return signal.ParseSignal(signalValue)
+		return signalutil.ParseSignal(signalValue)
 	}
 
 	if stopSignal, ok := containerLabels[containerd.StopSignalLabel]; ok {
-		return signal.ParseSignal(stopSignal)
+		return signalutil.ParseSignal(stopSignal)
 	}
 
-	return signal.ParseSignal("SIGTERM")
+	return signalutil.ParseSignal("SIGTERM")
 }
 
 func waitContainerStop(ctx context.Context, exitCh <-chan containerd.ExitStatus, id string) error {
@@ -81,3 +81,21 @@ func TestParseExtraHosts(t *testing.T) {
 		})
 	}
 }
+
+func TestMovedSource(t *testing.T) {
+	moved := map[string]string{"/mnt/nfs/vol": "/mnt/nfs-2/vol"}
+	tests := []struct {
+		source   string
+		expected string
+	}{
+		{source: "/mnt/nfs/vol", expected: "/mnt/nfs-2/vol"},
+		{source: "/mnt/nfs/vol/sub/dir", expected: "/mnt/nfs-2/vol/sub/dir"},
+		{source: "/mnt/nfs/volume", expected: "/mnt/nfs/volume"},
+		{source: "/tmp", expected: "/tmp"},
+	}
+	for _, tt := range tests {
+		if got := movedSource(tt.source, moved); got != tt.expected {
+			t.Errorf("movedSource(%q) = %q, want %q", tt.source, got, tt.expected)
+		}
+	}
+}
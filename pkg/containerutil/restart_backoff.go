@@ -0,0 +1,137 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package containerutil
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/ocihook/state"
+)
+
+// maxRestartBackoff caps the delay applied to the restart of a container that keeps exiting too early.
+const maxRestartBackoff = time.Minute
+
+// restartMinUptime returns the minimum uptime (--restart-min-uptime) from the labels of a container, or 0 if not set.
+func restartMinUptime(m map[string]string) (time.Duration, error) {
+	v, ok := m[labels.RestartMinUptime]
+	if !ok || v == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s label %q: %w", labels.RestartMinUptime, v, err)
+	}
+	return d, nil
+}
+
+// restartBackoff returns the delay before restarting a container that exited rapidExits times in a row
+// before running for minUptime. The delay starts at minUptime and doubles on each rapid exit.
+func restartBackoff(minUptime time.Duration, rapidExits int) time.Duration {
+	if minUptime <= 0 || rapidExits <= 0 {
+		return 0
+	}
+	backoff := minUptime
+	for i := 1; i < rapidExits && backoff < maxRestartBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxRestartBackoff)
+}
+
+// recordExit updates the count of consecutive exits that happened before the container ran for minUptime.
+func recordExit(lf *state.Store, minUptime time.Duration, now time.Time) {
+	if minUptime <= 0 || lf.StartedAt.IsZero() {
+		return
+	}
+	uptime := now.Sub(lf.StartedAt)
+	if uptime >= minUptime {
+		lf.RapidExits = 0
+		return
+	}
+	lf.RapidExits++
+	log.L.Warnf("container exited after running for %s, less than the minimum uptime of %s (%d time(s) in a row)",
+		uptime.Round(time.Millisecond), minUptime, lf.RapidExits)
+}
+
+// recordRapidExit records the exit of the container in its state store, and returns the time before which
+// the container must not be restarted, or the zero time if it can be restarted right away.
+func recordRapidExit(stateDir string, minUptime time.Duration, now time.Time) (time.Time, error) {
+	lf, err := state.New(stateDir)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var nextStart time.Time
+	err = lf.Transform(func(lf *state.Store) error {
+		// The exit is already recorded, e.g., the last start was skipped by the oci hook
+		if lf.ExitRecorded {
+			return nil
+		}
+		recordExit(lf, minUptime, now)
+		lf.ExitRecorded = true
+		if backoff := restartBackoff(minUptime, lf.RapidExits); backoff > 0 {
+			nextStart = now.Add(backoff)
+		}
+		return nil
+	})
+	return nextStart, err
+}
+
+// RecordRestartBackoff is called when the task of a container with a minimum uptime exits.
+// If the container kept exiting before running for its minimum uptime, it records the time before which
+// the restart monitor must not restart it in the labels.RestartNextStart label, which is checked by the oci hook.
+func RecordRestartBackoff(ctx context.Context, container containerd.Container) error {
+	l, err := container.Labels(ctx)
+	if err != nil {
+		return err
+	}
+	minUptime, err := restartMinUptime(l)
+	if err != nil || minUptime == 0 {
+		return err
+	}
+	nextStart, err := recordRapidExit(l[labels.StateDir], minUptime, time.Now())
+	if err != nil {
+		return err
+	}
+	var v string
+	if !nextStart.IsZero() {
+		v = nextStart.Format(time.RFC3339Nano)
+		log.G(ctx).Warnf("container exited before running for %s, delaying its restart until %s", minUptime, v)
+	}
+	_, err = container.SetLabels(ctx, map[string]string{labels.RestartNextStart: v})
+	return err
+}
+
+// resetRestartBackoff resets the backoff of --restart-min-uptime, as an explicit start is not subject to it.
+func resetRestartBackoff(ctx context.Context, container containerd.Container, stateDir string) error {
+	lf, err := state.New(stateDir)
+	if err != nil {
+		return err
+	}
+	if err := lf.Transform(func(lf *state.Store) error {
+		lf.RapidExits = 0
+		return nil
+	}); err != nil {
+		return err
+	}
+	_, err = container.SetLabels(ctx, map[string]string{labels.RestartNextStart: ""})
+	return err
+}
@@ -0,0 +1,98 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package containerutil
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/v2/pkg/ocihook/state"
+)
+
+func TestRestartBackoff(t *testing.T) {
+	testCases := []struct {
+		minUptime  time.Duration
+		rapidExits int
+		expected   time.Duration
+	}{
+		{minUptime: 0, rapidExits: 3, expected: 0},
+		{minUptime: time.Second, rapidExits: 0, expected: 0},
+		{minUptime: time.Second, rapidExits: 1, expected: time.Second},
+		{minUptime: time.Second, rapidExits: 3, expected: 4 * time.Second},
+		{minUptime: time.Second, rapidExits: 100, expected: maxRestartBackoff},
+		{minUptime: 2 * time.Minute, rapidExits: 1, expected: maxRestartBackoff},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, restartBackoff(tc.minUptime, tc.rapidExits), tc.expected)
+	}
+}
+
+func TestRecordExit(t *testing.T) {
+	now := time.Now()
+	lf := &state.Store{StartedAt: now.Add(-time.Second)}
+
+	recordExit(lf, 10*time.Second, now)
+	recordExit(lf, 10*time.Second, now)
+	assert.Equal(t, lf.RapidExits, 2)
+
+	// a container that ran for long enough resets the count
+	lf.StartedAt = now.Add(-time.Minute)
+	recordExit(lf, 10*time.Second, now)
+	assert.Equal(t, lf.RapidExits, 0)
+
+	// nothing is recorded without a minimum uptime
+	lf.StartedAt = now.Add(-time.Second)
+	recordExit(lf, 0, now)
+	assert.Equal(t, lf.RapidExits, 0)
+}
+
+func TestRecordRapidExit(t *testing.T) {
+	stateDir := t.TempDir()
+	lf, err := state.New(stateDir)
+	assert.NilError(t, err)
+	start := func(at time.Time) {
+		assert.NilError(t, lf.Transform(func(lf *state.Store) error {
+			lf.StartedAt = at
+			lf.ExitRecorded = false
+			return nil
+		}))
+	}
+	now := time.Now()
+
+	// the delay doubles on each exit happening before the minimum uptime
+	for i, expected := range []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second, time.Minute} {
+		start(now.Add(-time.Second))
+		nextStart, err := recordRapidExit(stateDir, 10*time.Second, now)
+		assert.NilError(t, err)
+		assert.Equal(t, nextStart, now.Add(expected), "exit %d", i+1)
+	}
+
+	// an exit is only recorded once per start, e.g., when the restart was skipped
+	nextStart, err := recordRapidExit(stateDir, 10*time.Second, now.Add(5*time.Second))
+	assert.NilError(t, err)
+	assert.Assert(t, nextStart.IsZero())
+	assert.NilError(t, lf.Load())
+	assert.Equal(t, lf.RapidExits, 4)
+
+	// a container that ran for long enough can be restarted right away
+	start(now.Add(-time.Minute))
+	nextStart, err = recordRapidExit(stateDir, 10*time.Second, now)
+	assert.NilError(t, err)
+	assert.Assert(t, nextStart.IsZero())
+}
@@ -64,3 +64,25 @@ func TestParseHostsButSkipMarkedRegion(t *testing.T) {
 
 	}
 }
+
+func TestLoopbackHosts(t *testing.T) {
+	assert.Equal(t, string(LoopbackHosts("35af3f0922a9", "")), `# <nerdctl>
+127.0.0.1	localhost localhost.localdomain
+::1		localhost localhost.localdomain
+127.0.0.1       35af3f0922a9
+::1             35af3f0922a9
+# </nerdctl>
+`)
+	assert.Equal(t, string(LoopbackHosts("foo", "example.com")), `# <nerdctl>
+127.0.0.1	localhost localhost.localdomain
+::1		localhost localhost.localdomain
+127.0.0.1       foo.example.com foo
+::1             foo.example.com foo
+# </nerdctl>
+`)
+
+	// the region is replaced as a whole when the file is parsed
+	var buf bytes.Buffer
+	assert.NilError(t, parseHostsButSkipMarkedRegion(&buf, bytes.NewReader(LoopbackHosts("foo", ""))))
+	assert.Equal(t, buf.String(), "")
+}
@@ -166,6 +166,25 @@ func (x *hostsStore) Release(id string) (err error) {
 	})
 }
 
+// LoopbackHosts returns the content of the /etc/hosts file of a container without a network,
+// which resolves localhost and the hostname of the container to the loopback addresses.
+func LoopbackHosts(hostname, domainname string) []byte {
+	names := hostname
+	if hostname != "" && domainname != "" {
+		names = hostname + "." + domainname + " " + hostname
+	}
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("# %s\n", MarkerBegin))
+	buf.WriteString("127.0.0.1	localhost localhost.localdomain\n")
+	buf.WriteString("::1		localhost localhost.localdomain\n")
+	if names != "" {
+		buf.WriteString(fmt.Sprintf("%-15s %s\n", "127.0.0.1", names))
+		buf.WriteString(fmt.Sprintf("%-15s %s\n", "::1", names))
+	}
+	buf.WriteString(fmt.Sprintf("# %s\n", MarkerEnd))
+	return buf.Bytes()
+}
+
 // AllocHostsFile is used for creating mount-bindable /etc/hosts file.
 func (x *hostsStore) AllocHostsFile(id string, content []byte) (location string, err error) {
 	defer func() {
@@ -17,6 +17,7 @@
 package hostsstore
 
 import (
+	"github.com/containerd/nerdctl/v2/pkg/identifiers"
 	"github.com/containerd/nerdctl/v2/pkg/netutil"
 )
 
@@ -27,6 +28,10 @@ import (
 // line is line "bar.example.com bar bar.nw0 foo foo.nw0\n"
 // for  `nerdctl --name=foo --hostname=bar --domainname=example.com --network=n0`.
 //
+// line is like "bar bar.nw0 My_App My_App.nw0 my-app my-app.nw0\n"
+// for `nerdctl --name=My_App --hostname=bar --network=nw0`, as a name that is not a valid DNS label
+// cannot be resolved by some resolvers (e.g., musl), so its normalized form is added too.
+//
 // May return an empty string slice
 func createLine(thatNetwork string, meta *Meta, myNetworks map[string]struct{}) []string {
 	line := []string{}
@@ -43,6 +48,11 @@ func createLine(thatNetwork string, meta *Meta, myNetworks map[string]struct{})
 
 	if meta.Name != "" {
 		baseHostnames = append(baseHostnames, meta.Name)
+		if identifiers.ValidateDNSLabel(meta.Name) != nil {
+			if normalized := identifiers.NormalizeDNSLabel(meta.Name); normalized != "" && normalized != meta.Hostname {
+				baseHostnames = append(baseHostnames, normalized)
+			}
+		}
 	}
 
 	for _, baseHostname := range baseHostnames {
@@ -114,6 +114,22 @@ func TestCreateLine(t *testing.T) {
 			myNetwork:      netutil.DefaultNetworkName,
 			expected:       "bar.example.com.example.com bar.example.com",
 		},
+		{
+			thatIP:       "10.4.2.10",
+			thatNetwork:  "n1",
+			thatHostname: "bar",
+			thatName:     "My_App", // not a valid DNS label
+			myNetwork:    "n1",
+			expected:     "bar bar.n1 My_App My_App.n1 my-app my-app.n1",
+		},
+		{
+			thatIP:       "10.4.2.11",
+			thatNetwork:  "n1",
+			thatHostname: "foo-bar",
+			thatName:     "foo_bar", // the normalized name is the hostname
+			myNetwork:    "n1",
+			expected:     "foo-bar foo-bar.n1 foo_bar foo_bar.n1",
+		},
 	}
 	for _, tc := range testCases {
 		thatMeta := &Meta{
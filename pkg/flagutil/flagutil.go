@@ -22,8 +22,6 @@ import (
 	"fmt"
 	"os"
 	"strings"
-
-	"github.com/containerd/nerdctl/v2/pkg/strutil"
 )
 
 // ReplaceOrAppendEnvValues returns the defaults with the overrides either
@@ -31,6 +29,7 @@ import (
 // FYI: https://github.com/containerd/containerd/blob/698622b89a053294593b9b5a363efff7715e9394/oci/spec_opts.go#L186-L222
 // defaults should have valid `k=v` strings.
 // overrides may have the following formats: `k=v` (override k), `k=` (emptify k), `k` (remove k).
+// When an override key is repeated, the last one wins.
 func ReplaceOrAppendEnvValues(defaults, overrides []string) []string {
 	cache := make(map[string]int, len(defaults))
 	results := make([]string, 0, len(defaults))
@@ -54,6 +53,7 @@ func ReplaceOrAppendEnvValues(defaults, overrides []string) []string {
 		if i, exists := cache[k]; exists {
 			results[i] = value
 		} else {
+			cache[k] = len(results)
 			results = append(results, value)
 		}
 	}
@@ -122,26 +122,65 @@ func withOSEnv(envs []string) ([]string, error) {
 	return newEnvs, nil
 }
 
+// LookupOSEnv returns envs with the values of the `k` entries (without "=") taken from the OS environment.
+// Unlike withOSEnv, the `k` entries that are not set in the OS environment are dropped, instead of
+// being kept to unset `k`.
+func LookupOSEnv(envs []string) []string {
+	results := make([]string, 0, len(envs))
+	for _, e := range envs {
+		if strings.Contains(e, "=") {
+			results = append(results, e)
+			continue
+		}
+		if v, ok := os.LookupEnv(e); ok {
+			results = append(results, e+"="+v)
+		}
+	}
+	return results
+}
+
 // MergeEnvFileAndOSEnv combines environment variables from `--env-file` and `--env`.
 // Pass an empty slice if any arg is not used.
+//
+// The result is deduplicated by key: `--env` takes precedence over `--env-file`,
+// regardless of the position of the flags.
+// Env files are applied in the order given, so a later file overrides an earlier one,
+// and the last occurrence wins within each file and within `--env`.
+// A key keeps the position of its first occurrence.
 func MergeEnvFileAndOSEnv(envFile []string, env []string) ([]string, error) {
 	var envs []string
 	var err error
 
-	if envFiles := strutil.DedupeStrSlice(envFile); len(envFiles) > 0 {
-		envs, err = parseEnvVars(envFiles)
+	// envFile is not deduplicated, so that a file given again later
+	// still overrides the files given in between.
+	if len(envFile) > 0 {
+		envs, err = parseEnvVars(envFile)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	if env := strutil.DedupeStrSlice(env); len(env) > 0 {
-		envs = append(envs, env...)
-	}
+	envs = append(envs, env...)
 
 	if envs, err = withOSEnv(envs); err != nil {
 		return nil, err
 	}
 
-	return envs, nil
+	return dedupeEnvByKey(envs), nil
+}
+
+// dedupeEnvByKey deduplicates `k=v` (or `k`) strings by key, the last value wins.
+func dedupeEnvByKey(envs []string) []string {
+	index := make(map[string]int, len(envs))
+	results := make([]string, 0, len(envs))
+	for _, e := range envs {
+		k, _, _ := strings.Cut(e, "=")
+		if i, exists := index[k]; exists {
+			results[i] = e
+			continue
+		}
+		index[k] = len(results)
+		results = append(results, e)
+	}
+	return results
 }
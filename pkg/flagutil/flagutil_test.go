@@ -73,6 +73,12 @@ func TestReplaceOrAppendEnvValues(t *testing.T) {
 			overrides: []string{"A=override", "B"},
 			expected:  []string{"A=override"},
 		},
+		// repeated overrides, the last one wins
+		{
+			defaults:  []string{"A=default"},
+			overrides: []string{"C=override1", "A=override", "C=override2"},
+			expected:  []string{"A=override", "C=override2"},
+		},
 	}
 
 	comparator := func(s1, s2 []string) bool {
@@ -317,3 +323,43 @@ func TestMergeEnvFileAndOSEnv(t *testing.T) {
 		t.Fatal("the PATH variable is not properly imported as the second variable")
 	}
 }
+
+func TestMergeEnvFileAndOSEnvPrecedence(t *testing.T) {
+	tmpFile := tmpFileWithContent(t, "A=env-file\nB=env-file-1\nC=env-file\nB=env-file-2")
+
+	variables, err := MergeEnvFileAndOSEnv([]string{tmpFile}, []string{"C=env-1", "D=env", "C=env-2"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, variables, []string{"A=env-file", "B=env-file-2", "C=env-2", "D=env"})
+}
+
+func TestMergeEnvFileAndOSEnvMultipleFiles(t *testing.T) {
+	base := tmpFileWithContent(t, "A=base\nB=base\nC=base")
+	override := tmpFileWithContent(t, "B=override\nC=override")
+
+	variables, err := MergeEnvFileAndOSEnv([]string{base, override}, []string{"C=env"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, variables, []string{"A=base", "B=override", "C=env"})
+
+	// the order of the files matters, and a file given again is applied again
+	variables, err = MergeEnvFileAndOSEnv([]string{base, override, base}, nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, variables, []string{"A=base", "B=base", "C=base"})
+}
+
+func TestLookupOSEnv(t *testing.T) {
+	t.Setenv("NERDCTL_TEST_LOOKUP_SET", "from-os")
+	t.Setenv("NERDCTL_TEST_LOOKUP_EMPTY", "")
+	envs := LookupOSEnv([]string{
+		"KEY=value",
+		"NERDCTL_TEST_LOOKUP_SET",
+		"NERDCTL_TEST_LOOKUP_EMPTY",
+		"NERDCTL_TEST_LOOKUP_UNSET",
+		"NERDCTL_TEST_LOOKUP_SET=explicit",
+	})
+	assert.DeepEqual(t, envs, []string{
+		"KEY=value",
+		"NERDCTL_TEST_LOOKUP_SET=from-os",
+		"NERDCTL_TEST_LOOKUP_EMPTY=",
+		"NERDCTL_TEST_LOOKUP_SET=explicit",
+	})
+}
@@ -29,11 +29,27 @@ import (
 	"github.com/containerd/containerd/v2/pkg/cio"
 	"github.com/containerd/log"
 
+	"github.com/containerd/nerdctl/v2/pkg/flagutil"
 	"github.com/containerd/nerdctl/v2/pkg/idgen"
 )
 
 // ExecuteHealthCheck executes the health check command for a container
 func ExecuteHealthCheck(ctx context.Context, task containerd.Task, container containerd.Container, hc *Healthcheck) error {
+	// Built-in probes (e.g., "tcp://localhost:8080") are executed without spawning a process in the container
+	u, err := probeURL(hc)
+	if err != nil {
+		return err
+	}
+	if u != nil {
+		startTime := time.Now()
+		result := probeBuiltin(ctx, task.Pid(), u, hc.Timeout)
+		result.Start = startTime
+		if err := updateHealthStatus(ctx, container, hc, result); err != nil {
+			return fmt.Errorf("failed to update health status: %w", err)
+		}
+		return nil
+	}
+
 	// Prepare process spec for health check command
 	processSpec, err := prepareProcessSpec(ctx, container, hc)
 	if err != nil {
@@ -194,7 +210,8 @@ func prepareProcessSpec(ctx context.Context, container containerd.Container, hcC
 	}
 	processSpec := &specs.Process{
 		Args: args,
-		Env:  spec.Process.Env,
+		// --health-env is set on top of the environment of the container, for the probe only
+		Env:  flagutil.ReplaceOrAppendEnvValues(spec.Process.Env, hcConfig.Env),
 		User: spec.Process.User,
 		Cwd:  spec.Process.Cwd,
 	}
@@ -18,6 +18,8 @@ package healthcheck
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -76,6 +78,50 @@ type Healthcheck struct {
 	Retries       int           `json:"Retries,omitempty"`       // Retries is the number of consecutive failures needed to consider a container as unhealthy
 	StartPeriod   time.Duration `json:"StartPeriod,omitempty"`   // StartPeriod is the period for the container to initialize before the health check starts
 	StartInterval time.Duration `json:"StartInterval,omitempty"` // StartInterval is the time between health checks during the start period
+	Env           []string      `json:"Env,omitempty"`           // Env is added to the environment of the probe only (nerdctl extension)
+}
+
+// redactedEnvValue replaces the values of Healthcheck.Env for display.
+const redactedEnvValue = "<redacted>"
+
+// Redacted returns a copy of the Healthcheck with the values of Env hidden, so that
+// credentials passed to the probe are not displayed (e.g., by inspect).
+func (hc *Healthcheck) Redacted() *Healthcheck {
+	if len(hc.Env) == 0 {
+		return hc
+	}
+	redacted := *hc
+	redacted.Env = make([]string, len(hc.Env))
+	for i, e := range hc.Env {
+		k, _, _ := strings.Cut(e, "=")
+		redacted.Env[i] = k + "=" + redactedEnvValue
+	}
+	return &redacted
+}
+
+// ParseMinInterval parses the minimum interval between probe runs (`min_health_interval` in nerdctl.toml).
+// An empty string is treated as "0s", which disables the check.
+func ParseMinInterval(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("must not be negative, got %s", d)
+	}
+	return d, nil
+}
+
+// ClampInterval raises a non-zero interval shorter than minInterval to minInterval,
+// and reports whether it did so. Zero keeps meaning "use the default".
+func ClampInterval(interval, minInterval time.Duration) (time.Duration, bool) {
+	if interval == 0 || interval >= minInterval {
+		return interval, false
+	}
+	return minInterval, true
 }
 
 // HealthState stores the current health state of a container
@@ -0,0 +1,76 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package healthcheck
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestHealthcheckRedacted(t *testing.T) {
+	hc := &Healthcheck{
+		Test: []string{CmdShell, "curl -u $USER:$PASSWORD localhost"},
+		Env:  []string{"USER=admin", "PASSWORD=secret=value"},
+	}
+
+	redacted := hc.Redacted()
+	assert.DeepEqual(t, redacted.Test, hc.Test)
+	assert.DeepEqual(t, redacted.Env, []string{"USER=<redacted>", "PASSWORD=<redacted>"})
+	// the original is left unchanged
+	assert.DeepEqual(t, hc.Env, []string{"USER=admin", "PASSWORD=secret=value"})
+
+	noEnv := &Healthcheck{Test: []string{CmdShell, "true"}}
+	assert.Equal(t, noEnv.Redacted(), noEnv)
+}
+
+func TestClampInterval(t *testing.T) {
+	interval, clamped := ClampInterval(100*time.Millisecond, time.Second)
+	assert.Assert(t, clamped)
+	assert.Equal(t, interval, time.Second)
+
+	// zero means "use the default"
+	interval, clamped = ClampInterval(0, time.Second)
+	assert.Assert(t, !clamped)
+	assert.Equal(t, interval, time.Duration(0))
+
+	interval, clamped = ClampInterval(5*time.Second, time.Second)
+	assert.Assert(t, !clamped)
+	assert.Equal(t, interval, 5*time.Second)
+
+	// a minimum of zero disables the check
+	interval, clamped = ClampInterval(time.Millisecond, 0)
+	assert.Assert(t, !clamped)
+	assert.Equal(t, interval, time.Millisecond)
+}
+
+func TestParseMinInterval(t *testing.T) {
+	d, err := ParseMinInterval("")
+	assert.NilError(t, err)
+	assert.Equal(t, d, time.Duration(0))
+
+	d, err = ParseMinInterval("500ms")
+	assert.NilError(t, err)
+	assert.Equal(t, d, 500*time.Millisecond)
+
+	_, err = ParseMinInterval("-1s")
+	assert.ErrorContains(t, err, "must not be negative")
+
+	_, err = ParseMinInterval("fast")
+	assert.ErrorContains(t, err, "invalid duration")
+}
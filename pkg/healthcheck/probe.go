@@ -0,0 +1,161 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseProbeURL parses a built-in probe shorthand such as "tcp://localhost:8080" or "http://localhost/healthz".
+// It returns nil if cmd is not a built-in probe, i.e., cmd should be executed as a shell command.
+func ParseProbeURL(cmd string) (*url.URL, error) {
+	cmd = strings.TrimSpace(cmd)
+	if strings.ContainsAny(cmd, " \t\n") || !strings.Contains(cmd, "://") {
+		return nil, nil
+	}
+	u, err := url.Parse(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("invalid health check probe %q: %w", cmd, err)
+	}
+	switch u.Scheme {
+	case "tcp":
+		if u.Port() == "" {
+			return nil, fmt.Errorf("invalid health check probe %q: port must be specified", cmd)
+		}
+	case "http", "https":
+	default:
+		return nil, fmt.Errorf("invalid health check probe %q: unsupported scheme %q (must be tcp, http or https)", cmd, u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid health check probe %q: no host", cmd)
+	}
+	return u, nil
+}
+
+// probeURL returns the built-in probe URL of the health check, or nil if the health check is not a built-in probe.
+func probeURL(hc *Healthcheck) (*url.URL, error) {
+	if len(hc.Test) != 2 || hc.Test[0] != CmdShell {
+		return nil, nil
+	}
+	return ParseProbeURL(hc.Test[1])
+}
+
+// probeBuiltin connects to u (tcp) or sends a GET request to u (http, https) from the network namespace of pid,
+// without executing a process in the container.
+// The probe is considered healthy if the connection succeeds, or the HTTP status code is 2xx.
+func probeBuiltin(ctx context.Context, pid uint32, u *url.URL, timeout time.Duration) *HealthcheckResult {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	port := u.Port()
+	if port == "" {
+		port = map[string]string{"http": "80", "https": "443"}[u.Scheme]
+	}
+	address := net.JoinHostPort(u.Hostname(), port)
+	// The connection is established by dialInNetNS in the network namespace of the container,
+	// with the host resolved beforehand, rather than by the HTTP transport.
+	var conn net.Conn
+	ips, err := resolveProbeHost(ctx, u.Hostname())
+	if err == nil {
+		var portNum int
+		if portNum, err = strconv.Atoi(port); err == nil {
+			conn, err = dialInNetNS(ctx, pid, ips, portNum)
+		}
+	}
+	output := fmt.Sprintf("connected to %s", address)
+	if err == nil {
+		if u.Scheme == "tcp" {
+			conn.Close()
+		} else {
+			output, err = probeHTTP(ctx, u, conn)
+		}
+	}
+
+	result := &HealthcheckResult{
+		Output: output,
+		End:    time.Now(),
+	}
+	if err != nil {
+		result.ExitCode = 1
+		result.Output = err.Error()
+		if ctx.Err() == context.DeadlineExceeded {
+			result.ExitCode = -1
+			result.Output = fmt.Sprintf("Health check exceeded timeout (%v)", timeout)
+		}
+	}
+	return result
+}
+
+// resolveProbeHost resolves the host of a probe URL to IP addresses.
+// "localhost" is the loopback interface of the network namespace the probe is dialed from,
+// other names are resolved on the host.
+func resolveProbeHost(ctx context.Context, host string) ([]net.IP, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IP{ip}, nil
+	}
+	if strings.EqualFold(host, "localhost") {
+		return []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback}, nil
+	}
+	return net.DefaultResolver.LookupIP(ctx, "ip", host)
+}
+
+// probeHTTP sends a GET request to u over conn.
+func probeHTTP(ctx context.Context, u *url.URL, conn net.Conn) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		conn.Close()
+		return "", err
+	}
+	// The transport is given conn, dialed in the network namespace of the container.
+	// It must never dial by itself, as it would dial from the network namespace of nerdctl.
+	conns := make(chan net.Conn, 1)
+	conns <- conn
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(context.Context, string, string) (net.Conn, error) {
+				select {
+				case c := <-conns:
+					return c, nil
+				default:
+					return nil, errors.New("the connection to the container has already been used")
+				}
+			},
+			DisableKeepAlives: true,
+		},
+		// Redirects would require another connection
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("GET %s: unexpected status %q", u, resp.Status)
+	}
+	return fmt.Sprintf("GET %s: %s", u, resp.Status), nil
+}
@@ -0,0 +1,91 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	"golang.org/x/sys/unix"
+)
+
+// dialInNetNS connects to the first reachable address of ips on port, from the network namespace of pid.
+//
+// The net package resolves names and dials on goroutines that may run on threads outside of the network
+// namespace, so the socket is created and connected with raw syscalls on the thread locked in the network
+// namespace by ns.WithNetNSPath. A socket stays in the network namespace it was created in.
+func dialInNetNS(ctx context.Context, pid uint32, ips []net.IP, port int) (net.Conn, error) {
+	var conn net.Conn
+	err := ns.WithNetNSPath(fmt.Sprintf("/proc/%d/ns/net", pid), func(_ ns.NetNS) error {
+		var errs []error
+		for _, ip := range ips {
+			c, err := connectTCP(ctx, ip, port)
+			if err == nil {
+				conn = c
+				return nil
+			}
+			errs = append(errs, err)
+		}
+		return errors.Join(errs...)
+	})
+	return conn, err
+}
+
+// connectTCP connects a new TCP socket to ip:port on the current thread.
+func connectTCP(ctx context.Context, ip net.IP, port int) (net.Conn, error) {
+	addr := &net.TCPAddr{IP: ip, Port: port}
+	family := unix.AF_INET6
+	var sa unix.Sockaddr = &unix.SockaddrInet6{Port: port, Addr: [16]byte(ip.To16())}
+	if ip4 := ip.To4(); ip4 != nil {
+		family = unix.AF_INET
+		sa = &unix.SockaddrInet4{Port: port, Addr: [4]byte(ip4)}
+	}
+	fd, err := unix.Socket(family, unix.SOCK_STREAM|unix.SOCK_CLOEXEC, unix.IPPROTO_TCP)
+	if err != nil {
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Addr: addr, Err: os.NewSyscallError("socket", err)}
+	}
+	f := os.NewFile(uintptr(fd), "tcp:"+addr.String())
+	defer f.Close()
+
+	// connect(2) blocks on the socket, so it is bounded by the deadline of ctx with SO_SNDTIMEO
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout := time.Until(deadline)
+		if timeout <= 0 {
+			return nil, &net.OpError{Op: "dial", Net: "tcp", Addr: addr, Err: context.DeadlineExceeded}
+		}
+		tv := unix.NsecToTimeval(timeout.Nanoseconds())
+		if err := unix.SetsockoptTimeval(fd, unix.SOL_SOCKET, unix.SO_SNDTIMEO, &tv); err != nil {
+			return nil, &net.OpError{Op: "dial", Net: "tcp", Addr: addr, Err: os.NewSyscallError("setsockopt", err)}
+		}
+	}
+	if err := unix.Connect(fd, sa); err != nil {
+		if errors.Is(err, unix.EINPROGRESS) {
+			// SO_SNDTIMEO expired
+			err = context.DeadlineExceeded
+		} else {
+			err = os.NewSyscallError("connect", err)
+		}
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Addr: addr, Err: err}
+	}
+	// FileConn duplicates the file descriptor, f is closed on return
+	return net.FileConn(f)
+}
@@ -0,0 +1,73 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package healthcheck
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestProbeBuiltinNetNS(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("requires root to create a network namespace")
+	}
+
+	// The server is only listening in the network namespace of the host
+	srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer srv.Close()
+	_, port, err := net.SplitHostPort(srv.Listener.Addr().String())
+	assert.NilError(t, err)
+
+	// A process in a new network namespace, standing for the container
+	cmd := exec.Command("sleep", "infinity")
+	cmd.SysProcAttr = &syscall.SysProcAttr{Cloneflags: syscall.CLONE_NEWNET}
+	if err := cmd.Start(); err != nil {
+		t.Skipf("failed to create a network namespace: %v", err)
+	}
+	defer func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}()
+
+	for _, probe := range []string{
+		"tcp://127.0.0.1:" + port,
+		"tcp://localhost:" + port,
+		"http://127.0.0.1:" + port + "/",
+		"http://localhost:" + port + "/",
+	} {
+		u, err := url.Parse(probe)
+		assert.NilError(t, err)
+
+		// reachable from the network namespace of the host
+		result := probeBuiltin(context.Background(), uint32(os.Getpid()), u, 5*time.Second)
+		assert.Equal(t, result.ExitCode, 0, "%s: %s", probe, result.Output)
+
+		// but not from the network namespace of the container
+		result = probeBuiltin(context.Background(), uint32(cmd.Process.Pid), u, 5*time.Second)
+		assert.Equal(t, result.ExitCode, 1, "%s: %s", probe, result.Output)
+	}
+}
@@ -0,0 +1,45 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package healthcheck
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseProbeURL(t *testing.T) {
+	u, err := ParseProbeURL("tcp://localhost:8080")
+	assert.NilError(t, err)
+	assert.Equal(t, u.Scheme, "tcp")
+	assert.Equal(t, u.Host, "localhost:8080")
+
+	u, err = ParseProbeURL("http://localhost/healthz")
+	assert.NilError(t, err)
+	assert.Equal(t, u.Path, "/healthz")
+
+	// Shell commands are not probes
+	u, err = ParseProbeURL("curl -f http://localhost/healthz")
+	assert.NilError(t, err)
+	assert.Assert(t, u == nil)
+
+	_, err = ParseProbeURL("tcp://localhost")
+	assert.ErrorContains(t, err, "port must be specified")
+
+	_, err = ParseProbeURL("ftp://localhost:21")
+	assert.ErrorContains(t, err, "unsupported scheme")
+}
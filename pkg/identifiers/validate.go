@@ -20,6 +20,7 @@ package identifiers
 import (
 	"fmt"
 	"regexp"
+	"strings"
 
 	"github.com/containerd/errdefs"
 )
@@ -44,3 +45,37 @@ func ValidateDockerCompat(s string) error {
 
 	return nil
 }
+
+// dnsLabelPattern is the pattern of a DNS label (RFC 1123), lowercased.
+var dnsLabelPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// ValidateDNSLabel checks that s can be used as a hostname on a network, i.e., that it is
+// a DNS label of at most 63 lowercase letters, digits and hyphens, not starting or ending with a hyphen.
+// Identifiers allowed by ValidateDockerCompat may still contain underscores, dots and uppercase letters,
+// which are not valid in a DNS label.
+func ValidateDNSLabel(s string) error {
+	if !dnsLabelPattern.MatchString(s) {
+		return fmt.Errorf("%q is not a valid DNS label, it must consist of at most 63 lowercase letters, digits and hyphens: %w", s, errdefs.ErrInvalidArgument)
+	}
+	return nil
+}
+
+// NormalizeDNSLabel returns a DNS label derived from s, to be suggested when ValidateDNSLabel fails.
+// Uppercase letters are lowercased, underscores and dots are replaced with hyphens, and other
+// characters are dropped. It returns an empty string when nothing is left.
+func NormalizeDNSLabel(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+			b.WriteRune(r)
+		case r == '_', r == '.':
+			b.WriteRune('-')
+		}
+	}
+	label := strings.Trim(b.String(), "-")
+	if len(label) > 63 {
+		label = strings.TrimRight(label[:63], "-")
+	}
+	return label
+}
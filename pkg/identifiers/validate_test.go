@@ -0,0 +1,47 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package identifiers
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestValidateDNSLabel(t *testing.T) {
+	t.Parallel()
+
+	for _, valid := range []string{"a", "web", "web-1", "1web", strings.Repeat("a", 63)} {
+		assert.NilError(t, ValidateDNSLabel(valid), valid)
+	}
+	for _, invalid := range []string{"", "my_app", "MyApp", "web.1", "-web", "web-", strings.Repeat("a", 64)} {
+		assert.ErrorContains(t, ValidateDNSLabel(invalid), "is not a valid DNS label", invalid)
+	}
+}
+
+func TestNormalizeDNSLabel(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, NormalizeDNSLabel("My_App.1"), "my-app-1")
+	assert.Equal(t, NormalizeDNSLabel("_web_"), "web")
+	assert.Equal(t, NormalizeDNSLabel("__"), "")
+	assert.Equal(t, NormalizeDNSLabel(strings.Repeat("a", 62)+"_b"), strings.Repeat("a", 62))
+	for _, s := range []string{"My_App.1", "_web_", strings.Repeat("A", 70)} {
+		assert.NilError(t, ValidateDNSLabel(NormalizeDNSLabel(s)), s)
+	}
+}
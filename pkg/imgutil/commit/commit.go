@@ -143,13 +143,15 @@ func Commit(ctx context.Context, client *containerd.Client, container containerd
 	}
 
 	if opts.Pause {
+		var status containerd.Status
 		task, err := container.Task(ctx, cio.Load)
 		if err != nil {
-			return emptyDigest, err
-		}
-
-		status, err := task.Status(ctx)
-		if err != nil {
+			// A container that has never been started has no task, so there is nothing to pause
+			if !errdefs.IsNotFound(err) {
+				return emptyDigest, err
+			}
+			status.Status = containerd.Created
+		} else if status, err = task.Status(ctx); err != nil {
 			return emptyDigest, err
 		}
 
@@ -35,6 +35,7 @@ type opts struct {
 	skipVerifyCerts bool
 	hostsDirs       []string
 	authCreds       AuthCreds
+	mirrors         []string
 }
 
 // Opt for New
@@ -68,6 +69,14 @@ func WithAuthCreds(ac AuthCreds) Opt {
 	}
 }
 
+// WithMirrors specifies registry mirrors to try, in order, before the registry hosts
+// configured in hosts.toml (and the registry itself). Mirrors are only used for pulling.
+func WithMirrors(mirrors []string) Opt {
+	return func(o *opts) {
+		o.mirrors = mirrors
+	}
+}
+
 // NewHostOptions instantiates a HostOptions struct using $DOCKER_CONFIG/config.json .
 //
 // $DOCKER_CONFIG defaults to "~/.docker".
@@ -146,9 +155,18 @@ func New(ctx context.Context, refHostname string, optFuncs ...Opt) (remotes.Reso
 		return nil, err
 	}
 
+	var o opts
+	for _, of := range optFuncs {
+		of(&o)
+	}
+	hosts := dockerconfig.ConfigureHosts(ctx, *ho)
+	if len(o.mirrors) > 0 {
+		hosts = withMirrors(hosts, o.mirrors, o.plainHTTP)
+	}
+
 	resolverOpts := docker.ResolverOptions{
 		Tracker: PushTracker,
-		Hosts:   dockerconfig.ConfigureHosts(ctx, *ho),
+		Hosts:   hosts,
 	}
 
 	resolver := docker.NewResolver(resolverOpts)
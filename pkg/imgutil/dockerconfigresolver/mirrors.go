@@ -0,0 +1,118 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package dockerconfigresolver
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/containerd/containerd/v2/core/remotes/docker"
+	"github.com/containerd/log"
+)
+
+// parseMirror parses a mirror address like "mirror.example.com", "mirror.example.com:5000"
+// or "http://mirror.example.com/prefix".
+// When no scheme is given, defaultScheme is used.
+func parseMirror(mirror, defaultScheme string) (*url.URL, error) {
+	if !strings.Contains(mirror, "://") {
+		mirror = defaultScheme + "://" + mirror
+	}
+	u, err := url.Parse(mirror)
+	if err != nil {
+		return nil, fmt.Errorf("invalid registry mirror %q: %w", mirror, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid registry mirror %q: unsupported scheme %q", mirror, u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid registry mirror %q: no host", mirror)
+	}
+	return u, nil
+}
+
+// withMirrors wraps hosts so that the given mirrors are tried, in order, before the hosts
+// already configured (e.g., from hosts.toml) for Docker Hub. Like Docker, the mirrors are only
+// used for Docker Hub, other registries are left untouched.
+// Mirrors are only used for pulling and resolving, never for pushing.
+func withMirrors(hosts docker.RegistryHosts, mirrors []string, plainHTTP bool) docker.RegistryHosts {
+	defaultScheme := "https"
+	if plainHTTP {
+		defaultScheme = "http"
+	}
+	return func(host string) ([]docker.RegistryHost, error) {
+		registryHosts, err := hosts(host)
+		if err != nil || len(registryHosts) == 0 || !isDockerHub(host) {
+			return registryHosts, err
+		}
+		// The last host is the upstream registry, mirrors inherit its client and authorizer
+		upstream := registryHosts[len(registryHosts)-1]
+		mirrorHosts := make([]docker.RegistryHost, 0, len(mirrors)+len(registryHosts))
+		for _, mirror := range mirrors {
+			u, err := parseMirror(mirror, defaultScheme)
+			if err != nil {
+				return nil, err
+			}
+			mirrorHost := upstream
+			mirrorHost.Scheme = u.Scheme
+			mirrorHost.Host = u.Host
+			mirrorHost.Path = path.Join("/", u.Path, "v2")
+			mirrorHost.Capabilities = docker.HostCapabilityPull | docker.HostCapabilityResolve
+			mirrorHost.Client = newMirrorClient(upstream.Client, u.Host)
+			mirrorHosts = append(mirrorHosts, mirrorHost)
+		}
+		return append(mirrorHosts, registryHosts...), nil
+	}
+}
+
+// isDockerHub returns true if host is the host of Docker Hub.
+func isDockerHub(host string) bool {
+	return host == "docker.io" || host == "registry-1.docker.io"
+}
+
+// newMirrorClient returns a copy of client that logs the first successful response served by the mirror.
+func newMirrorClient(client *http.Client, mirror string) *http.Client {
+	var c http.Client
+	if client != nil {
+		c = *client
+	}
+	base := c.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	c.Transport = &mirrorTransport{base: base, mirror: mirror}
+	return &c
+}
+
+type mirrorTransport struct {
+	base   http.RoundTripper
+	mirror string
+	once   sync.Once
+}
+
+func (t *mirrorTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err == nil && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		t.once.Do(func() {
+			log.G(req.Context()).Infof("pulling from registry mirror %q", t.mirror)
+		})
+	}
+	return resp, err
+}
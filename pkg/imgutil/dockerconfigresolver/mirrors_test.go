@@ -0,0 +1,68 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package dockerconfigresolver
+
+import (
+	"net/http"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/containerd/v2/core/remotes/docker"
+)
+
+func TestWithMirrors(t *testing.T) {
+	upstream := docker.RegistryHost{
+		Client:       &http.Client{},
+		Host:         "registry-1.docker.io",
+		Scheme:       "https",
+		Path:         "/v2",
+		Capabilities: docker.HostCapabilityPull | docker.HostCapabilityResolve | docker.HostCapabilityPush,
+	}
+	hosts := func(string) ([]docker.RegistryHost, error) {
+		return []docker.RegistryHost{upstream}, nil
+	}
+
+	registryHosts, err := withMirrors(hosts, []string{"mirror.example.com", "http://localhost:5000/prefix"}, false)("docker.io")
+	assert.NilError(t, err)
+	assert.Equal(t, len(registryHosts), 3)
+
+	assert.Equal(t, registryHosts[0].Scheme, "https")
+	assert.Equal(t, registryHosts[0].Host, "mirror.example.com")
+	assert.Equal(t, registryHosts[0].Path, "/v2")
+	assert.Equal(t, registryHosts[0].Capabilities, docker.HostCapabilityPull|docker.HostCapabilityResolve)
+
+	assert.Equal(t, registryHosts[1].Scheme, "http")
+	assert.Equal(t, registryHosts[1].Host, "localhost:5000")
+	assert.Equal(t, registryHosts[1].Path, "/prefix/v2")
+
+	assert.Equal(t, registryHosts[2].Host, upstream.Host)
+	assert.Equal(t, registryHosts[2].Capabilities, upstream.Capabilities)
+
+	_, err = withMirrors(hosts, []string{"ftp://mirror.example.com"}, false)("docker.io")
+	assert.ErrorContains(t, err, "unsupported scheme")
+
+	registryHosts, err = withMirrors(hosts, []string{"mirror.example.com"}, false)("registry-1.docker.io")
+	assert.NilError(t, err)
+	assert.Equal(t, len(registryHosts), 2)
+
+	// Other registries are not mirrored
+	registryHosts, err = withMirrors(hosts, []string{"mirror.example.com"}, false)("ghcr.io")
+	assert.NilError(t, err)
+	assert.Equal(t, len(registryHosts), 1)
+	assert.Equal(t, registryHosts[0].Host, upstream.Host)
+}
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"net/http"
 	"reflect"
+	"time"
 
 	"github.com/opencontainers/image-spec/identity"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
@@ -31,6 +32,7 @@ import (
 	"github.com/containerd/containerd/v2/core/content"
 	"github.com/containerd/containerd/v2/core/images"
 	"github.com/containerd/containerd/v2/core/remotes"
+	remoteerrors "github.com/containerd/containerd/v2/core/remotes/errors"
 	"github.com/containerd/containerd/v2/core/snapshots"
 	"github.com/containerd/errdefs"
 	"github.com/containerd/imgcrypt/v2"
@@ -60,6 +62,25 @@ type EnsuredImage struct {
 // PullMode is either one of "always", "missing", "never"
 type PullMode = string
 
+// notFoundLocallyError is returned by GetExistingImage when the image is not available locally.
+// It wraps errdefs.ErrNotFound.
+type notFoundLocallyError struct {
+	ref string
+	// platform is set when the image is available locally, but not for this platform
+	platform string
+}
+
+func (e *notFoundLocallyError) Error() string {
+	if e.platform != "" {
+		return fmt.Sprintf("image %q was found locally, but not for platform %q", e.ref, e.platform)
+	}
+	return fmt.Sprintf("image %q was not found locally", e.ref)
+}
+
+func (e *notFoundLocallyError) Unwrap() error {
+	return errdefs.ErrNotFound
+}
+
 // GetExistingImage returns the specified image if exists in containerd. Return errdefs.NotFound() if not exists.
 func GetExistingImage(ctx context.Context, client *containerd.Client, snapshotter, rawRef string, platform ocispec.Platform) (*EnsuredImage, error) {
 	var res *EnsuredImage
@@ -69,26 +90,9 @@ func GetExistingImage(ctx context.Context, client *containerd.Client, snapshotte
 			if res != nil {
 				return nil
 			}
-			image := containerd.NewImageWithPlatform(client, found.Image, platforms.OnlyStrict(platform))
-			imgConfig, err := getImageConfig(ctx, image)
-			if err != nil {
-				// Image found but blob not found for foreign arch
-				// Ignore err and return nil, so that the walker can visit the next candidate.
-				return nil
-			}
-			res = &EnsuredImage{
-				Ref:         found.Image.Name,
-				Image:       image,
-				ImageConfig: *imgConfig,
-				Snapshotter: snapshotter,
-				Remote:      getSnapshotterOpts(snapshotter).isRemote(),
-			}
-			if unpacked, err := image.IsUnpacked(ctx, snapshotter); err == nil && !unpacked {
-				if err := image.Unpack(ctx, snapshotter); err != nil {
-					return err
-				}
-			}
-			return nil
+			var err error
+			res, err = ensuredImageOf(ctx, client, snapshotter, found.Image, platform)
+			return err
 		},
 	}
 	count, err := imgwalker.Walk(ctx, rawRef)
@@ -96,14 +100,91 @@ func GetExistingImage(ctx context.Context, client *containerd.Client, snapshotte
 		return nil, err
 	}
 	if count == 0 {
-		return nil, errors.Join(errdefs.ErrNotFound, errors.New("got count 0 after walking"))
+		return nil, &notFoundLocallyError{ref: rawRef}
 	}
 	if res == nil {
-		return nil, errors.Join(errdefs.ErrNotFound, errors.New("got nil res after walking"))
+		// The image is there, but its config could not be read for the platform (e.g., an image pulled for another arch)
+		return nil, &notFoundLocallyError{ref: rawRef, platform: platforms.Format(platform)}
+	}
+	return res, nil
+}
+
+// ensuredImageOf returns img for platform, unpacked for snapshotter.
+// It returns nil without an error when the config of img cannot be read for platform.
+func ensuredImageOf(ctx context.Context, client *containerd.Client, snapshotter string, img images.Image, platform ocispec.Platform) (*EnsuredImage, error) {
+	image := containerd.NewImageWithPlatform(client, img, platforms.OnlyStrict(platform))
+	imgConfig, err := getImageConfig(ctx, image)
+	if err != nil {
+		// Image found but blob not found for foreign arch
+		// Ignore err and return nil, so that the walker can visit the next candidate.
+		return nil, nil
+	}
+	res := &EnsuredImage{
+		Ref:         img.Name,
+		Image:       image,
+		ImageConfig: *imgConfig,
+		Snapshotter: snapshotter,
+		Remote:      getSnapshotterOpts(snapshotter).isRemote(),
+	}
+	if unpacked, err := image.IsUnpacked(ctx, snapshotter); err == nil && !unpacked {
+		if err := image.Unpack(ctx, snapshotter); err != nil {
+			return nil, err
+		}
 	}
 	return res, nil
 }
 
+// isDigestPinned returns whether rawRef is a "name@sha256:..." reference.
+func isDigestPinned(rawRef string) bool {
+	parsedReference, err := referenceutil.Parse(rawRef)
+	if err != nil {
+		return false
+	}
+	return parsedReference.Protocol == "" && parsedReference.Path != "" && parsedReference.Digest != ""
+}
+
+// getExistingImageByDigest looks up the digest-pinned rawRef among the local images of the same repository
+// (e.g., an image pulled by its tag). When create is set, the image is named as rawRef, as pulling rawRef
+// would do; otherwise (--pull=never) the image store is left untouched.
+// Returns errdefs.NotFound() if no local image of the repository has the digest.
+func getExistingImageByDigest(ctx context.Context, client *containerd.Client, snapshotter, rawRef string, platform ocispec.Platform, create bool) (*EnsuredImage, error) {
+	parsedReference, err := referenceutil.Parse(rawRef)
+	if err != nil {
+		return nil, err
+	}
+	imageService := client.ImageService()
+	candidates, err := imageService.List(ctx, fmt.Sprintf("target.digest==%s", parsedReference.Digest))
+	if err != nil {
+		return nil, err
+	}
+	for _, candidate := range candidates {
+		candidateReference, err := referenceutil.Parse(candidate.Name)
+		if err != nil || candidateReference.Name() != parsedReference.Name() {
+			continue
+		}
+		img := images.Image{
+			Name:   parsedReference.String(),
+			Target: candidate.Target,
+			Labels: candidate.Labels,
+		}
+		if create {
+			if _, err := imageService.Create(ctx, img); err != nil && !errdefs.IsAlreadyExists(err) {
+				return nil, err
+			}
+		}
+		log.G(ctx).Debugf("found the digest-pinned image %q locally as %q", rawRef, candidate.Name)
+		res, err := ensuredImageOf(ctx, client, snapshotter, img, platform)
+		if err != nil {
+			return nil, err
+		}
+		if res == nil {
+			return nil, &notFoundLocallyError{ref: rawRef, platform: platforms.Format(platform)}
+		}
+		return res, nil
+	}
+	return nil, &notFoundLocallyError{ref: rawRef}
+}
+
 // EnsureImage ensures the image.
 //
 // # When insecure is set, skips verifying certs, and also falls back to HTTP when the registry does not speak HTTPS
@@ -115,17 +196,34 @@ func EnsureImage(ctx context.Context, client *containerd.Client, rawRef string,
 		return nil, fmt.Errorf("unexpected pull mode: %q", options.Mode)
 	}
 
-	// if not `always` pull and given one platform and image found locally, return existing image directly.
-	if options.Mode != "always" && len(options.OCISpecPlatform) == 1 {
-		if res, err := GetExistingImage(ctx, client, options.GOptions.Snapshotter, rawRef, options.OCISpecPlatform[0]); err == nil {
+	// A digest-pinned reference always resolves to the same content, so pulling it again when it is
+	// present locally would be a no-op, even with `always` pull.
+	pinned := isDigestPinned(rawRef)
+
+	// if not `always` pull (or digest-pinned) and given one platform and image found locally, return existing image directly.
+	var notFoundErr error
+	if (options.Mode != "always" || pinned) && len(options.OCISpecPlatform) == 1 {
+		res, err := GetExistingImage(ctx, client, options.GOptions.Snapshotter, rawRef, options.OCISpecPlatform[0])
+		if err != nil && errdefs.IsNotFound(err) && pinned {
+			res, err = getExistingImageByDigest(ctx, client, options.GOptions.Snapshotter, rawRef, options.OCISpecPlatform[0], options.Mode != "never")
+		}
+		if err == nil {
+			if options.Mode == "always" {
+				log.G(ctx).Debugf("skipping pulling the digest-pinned image %q, as it is present locally", rawRef)
+			}
 			return res, nil
 		} else if !errdefs.IsNotFound(err) {
 			return nil, err
 		}
+		notFoundErr = err
 	}
 
 	if options.Mode == "never" {
-		return nil, fmt.Errorf("image not available: %q", rawRef)
+		if notFoundErr == nil {
+			// the local images are only looked up for a single platform
+			notFoundErr = &notFoundLocallyError{ref: rawRef}
+		}
+		return nil, fmt.Errorf("%w, and --pull=never prevents pulling it", notFoundErr)
 	}
 
 	parsedReference, err := referenceutil.Parse(rawRef)
@@ -133,12 +231,60 @@ func EnsureImage(ctx context.Context, client *containerd.Client, rawRef string,
 		return nil, err
 	}
 
+	var img *EnsuredImage
+	err = retryPull(ctx, options.Retry, options.RetryDelay, func() error {
+		img, err = pullImageWithFallback(ctx, client, parsedReference, options)
+		return err
+	})
+	return img, err
+}
+
+// retryPull calls pullFn, and calls it again up to retry times while it fails with a retryable error,
+// waiting for delay between the attempts.
+func retryPull(ctx context.Context, retry int, delay time.Duration, pullFn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := pullFn()
+		if err == nil || attempt > retry || !isRetryablePullError(err) {
+			return err
+		}
+		log.G(ctx).WithError(err).Warnf("failed to pull, retrying in %s (retry %d/%d)", delay, attempt, retry)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// isRetryablePullError returns false for the errors that are not going to be fixed by pulling again,
+// such as a missing image or missing credentials.
+func isRetryablePullError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) ||
+		errdefs.IsNotFound(err) || errdefs.IsUnauthorized(err) || errdefs.IsPermissionDenied(err) {
+		return false
+	}
+	var statusErr remoteerrors.ErrUnexpectedStatus
+	if errors.As(err, &statusErr) {
+		switch statusErr.StatusCode {
+		case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound:
+			return false
+		}
+	}
+	return true
+}
+
+// pullImageWithFallback pulls the image, falling back to plain HTTP when the registry does not speak HTTPS
+// and insecure registries are allowed.
+func pullImageWithFallback(ctx context.Context, client *containerd.Client, parsedReference *referenceutil.ImageReference, options types.ImagePullOptions) (*EnsuredImage, error) {
 	var dOpts []dockerconfigresolver.Opt
 	if options.GOptions.InsecureRegistry {
 		log.G(ctx).Warnf("skipping verifying HTTPS certs for %q", parsedReference.Domain)
 		dOpts = append(dOpts, dockerconfigresolver.WithSkipVerifyCerts(true))
 	}
 	dOpts = append(dOpts, dockerconfigresolver.WithHostsDirs(options.GOptions.HostsDir))
+	if len(options.RegistryMirrors) > 0 {
+		dOpts = append(dOpts, dockerconfigresolver.WithMirrors(options.RegistryMirrors))
+	}
 	resolver, err := dockerconfigresolver.New(ctx, parsedReference.Domain, dOpts...)
 	if err != nil {
 		return nil, err
@@ -17,9 +17,17 @@
 package imgutil
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
 	"testing"
+	"time"
 
 	"gotest.tools/v3/assert"
+
+	remoteerrors "github.com/containerd/containerd/v2/core/remotes/errors"
+	"github.com/containerd/errdefs"
 )
 
 func TestParseRepoTag(t *testing.T) {
@@ -57,3 +65,71 @@ func TestParseRepoTag(t *testing.T) {
 		assert.Equal(t, tc.tag, tag)
 	}
 }
+
+func TestRetryPull(t *testing.T) {
+	ctx := context.Background()
+	transient := errors.New("connection reset by peer")
+	testCases := []struct {
+		name     string
+		retry    int
+		err      error
+		attempts int
+	}{
+		{name: "no retry", retry: 0, err: transient, attempts: 1},
+		{name: "retries exhausted", retry: 3, err: transient, attempts: 4},
+		{name: "server error", retry: 2, err: remoteerrors.ErrUnexpectedStatus{StatusCode: http.StatusInternalServerError}, attempts: 3},
+		{name: "not found", retry: 3, err: fmt.Errorf("failed to resolve: %w", errdefs.ErrNotFound), attempts: 1},
+		{name: "unauthorized", retry: 3, err: remoteerrors.ErrUnexpectedStatus{StatusCode: http.StatusUnauthorized}, attempts: 1},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			attempts := 0
+			err := retryPull(ctx, tc.retry, time.Millisecond, func() error {
+				attempts++
+				return tc.err
+			})
+			assert.Error(t, err, tc.err.Error())
+			assert.Equal(t, attempts, tc.attempts)
+		})
+	}
+
+	attempts := 0
+	err := retryPull(ctx, 3, time.Millisecond, func() error {
+		attempts++
+		if attempts < 2 {
+			return transient
+		}
+		return nil
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, attempts, 2)
+}
+
+func TestNotFoundLocallyError(t *testing.T) {
+	var err error = &notFoundLocallyError{ref: "alpine"}
+	assert.Assert(t, errdefs.IsNotFound(err))
+	assert.Error(t, err, `image "alpine" was not found locally`)
+
+	err = &notFoundLocallyError{ref: "alpine", platform: "linux/arm64"}
+	assert.Assert(t, errdefs.IsNotFound(err))
+	assert.Error(t, err, `image "alpine" was found locally, but not for platform "linux/arm64"`)
+
+	// the error of --pull=never
+	err = fmt.Errorf("%w, and --pull=never prevents pulling it", err)
+	assert.Assert(t, errdefs.IsNotFound(err))
+	assert.Error(t, err, `image "alpine" was found locally, but not for platform "linux/arm64", and --pull=never prevents pulling it`)
+}
+
+func TestIsDigestPinned(t *testing.T) {
+	const dgst = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+	for ref, expected := range map[string]bool{
+		"alpine@" + dgst:                       true,
+		"alpine:3.21@" + dgst:                  true,
+		"registry.example.com/foo/bar@" + dgst: true,
+		"alpine":                               false,
+		"alpine:3.21":                          false,
+		dgst:                                   false,
+	} {
+		assert.Equal(t, isDigestPinned(ref), expected, ref)
+	}
+}
@@ -49,6 +49,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/native"
 	"github.com/containerd/nerdctl/v2/pkg/ipcutil"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/logging/jsonfile"
 	"github.com/containerd/nerdctl/v2/pkg/ocihook/state"
 )
 
@@ -176,12 +177,15 @@ type HostConfig struct {
 	CPUQuota           int64             `json:"CpuQuota"`           // CPU CFS (Completely Fair Scheduler) quota
 	CPUShares          uint64            `json:"CpuShares"`          // CPU shares (relative weight vs. other containers)
 	CPUPeriod          uint64            `json:"CpuPeriod"`          // Limits the CPU CFS (Completely Fair Scheduler) period
+	CPUBurst           uint64            `json:"CpuBurst,omitempty"` // CPU time in microseconds usable beyond the quota (nerdctl extension, cgroup v2 cpu.max.burst)
 	CPURealtimePeriod  uint64            `json:"CpuRealtimePeriod"`  // Limits the CPU real-time period in microseconds
 	CPURealtimeRuntime int64             `json:"CpuRealtimeRuntime"` // Limits the CPU real-time runtime in microseconds
+	NanoCPUs           int64             `json:"NanoCpus"`           // CPU quota in units of 10<sup>-9</sup> CPUs
 	Memory             int64             // Memory limit (in bytes)
 	MemorySwap         int64             // Total memory usage (memory + swap); set `-1` to enable unlimited swap
 	OomKillDisable     bool              // specifies whether to disable OOM Killer
 	Devices            []DeviceMapping   // List of devices to map inside the container
+	Ulimits            []*units.Ulimit   // List of ulimits to be set in the container
 	LinuxBlkioSettings
 }
 
@@ -197,6 +201,8 @@ type MountPoint struct {
 	Mode        string
 	RW          bool
 	Propagation string
+	// Subpath is the subpath of the volume or the image mounted, not present in Docker
+	Subpath string `json:",omitempty"`
 }
 
 // config is from https://github.com/moby/moby/blob/8dbd90ec00daa26dc45d7da2431c965dec99e8b4/api/types/container/config.go#L37-L69
@@ -224,7 +230,7 @@ type Config struct {
 	// TODO: OnBuild         []string            // ONBUILD metadata that were defined on the image Dockerfile
 	Labels map[string]string `json:",omitempty"` // List of labels set to this container
 	// TODO: StopSignal      string              `json:",omitempty"` // Signal to stop a container
-	// TODO: StopTimeout     *int                `json:",omitempty"` // Timeout (in seconds) to stop a container
+	StopTimeout *int `json:",omitempty"` // Timeout (in seconds) to stop a container
 	// TODO: Shell           []string            `json:",omitempty"` // Shell for shell-form of RUN, CMD, ENTRYPOINT
 }
 
@@ -234,7 +240,7 @@ type ContainerState struct {
 	Running    bool
 	Paused     bool
 	Restarting bool
-	// TODO: OOMKilled  bool
+	OOMKilled  bool
 	// TODO:	Dead       bool
 	Pid        int
 	ExitCode   int
@@ -254,12 +260,30 @@ type DNSSettings struct {
 	DNSServers           []string
 	DNSResolvConfOptions []string
 	DNSSearchDomains     []string
+	// NoResolv and NoHosts are set when nerdctl does not manage /etc/resolv.conf and /etc/hosts
+	NoResolv bool `json:",omitempty"`
+	NoHosts  bool `json:",omitempty"`
 }
 
 type HostConfigLabel struct {
 	BlkioWeight uint16
 	CidFile     string
 	Devices     []DeviceMapping
+	// NanoCPUs is the original `--cpus` value in units of 1e-9 CPUs
+	NanoCPUs int64 `json:",omitempty"`
+	// CPUSetCPUs is the `--cpuset-cpus` value, validated against the online CPUs on creation
+	CPUSetCPUs string `json:",omitempty"`
+	// CPUShares is the `--cpu-shares` value, a weight relative to the other containers running on the same CPUs
+	CPUShares uint64 `json:",omitempty"`
+	// Ulimits are the ulimits set by `--ulimit`
+	Ulimits []*units.Ulimit `json:",omitempty"`
+	// The blkio devices keep the device paths given on creation, as the runtime spec
+	// only records the major and minor numbers of the devices
+	BlkioWeightDevice    []*WeightDevice   `json:",omitempty"`
+	BlkioDeviceReadBps   []*ThrottleDevice `json:",omitempty"`
+	BlkioDeviceWriteBps  []*ThrottleDevice `json:",omitempty"`
+	BlkioDeviceReadIOps  []*ThrottleDevice `json:",omitempty"`
+	BlkioDeviceWriteIOps []*ThrottleDevice `json:",omitempty"`
 }
 
 type DeviceMapping struct {
@@ -274,6 +298,7 @@ type CPUSettings struct {
 	CPUShares          uint64
 	CPUQuota           int64
 	CPUPeriod          uint64
+	CPUBurst           uint64
 	CPURealtimePeriod  uint64
 	CPURealtimeRuntime int64
 }
@@ -311,11 +336,23 @@ type NetworkEndpointSettings struct {
 
 type LinuxBlkioSettings struct {
 	BlkioWeight          uint16 // Block IO weight (relative weight vs. other containers)
-	BlkioWeightDevice    []*specs.LinuxWeightDevice
-	BlkioDeviceReadBps   []*specs.LinuxThrottleDevice
-	BlkioDeviceWriteBps  []*specs.LinuxThrottleDevice
-	BlkioDeviceReadIOps  []*specs.LinuxThrottleDevice
-	BlkioDeviceWriteIOps []*specs.LinuxThrottleDevice
+	BlkioWeightDevice    []*WeightDevice
+	BlkioDeviceReadBps   []*ThrottleDevice
+	BlkioDeviceWriteBps  []*ThrottleDevice
+	BlkioDeviceReadIOps  []*ThrottleDevice
+	BlkioDeviceWriteIOps []*ThrottleDevice
+}
+
+// WeightDevice is from https://github.com/moby/moby/blob/v28.3.3/api/types/blkiodev/blkio.go#L6-L10
+type WeightDevice struct {
+	Path   string
+	Weight uint16
+}
+
+// ThrottleDevice is from https://github.com/moby/moby/blob/v28.3.3/api/types/blkiodev/blkio.go#L16-L20
+type ThrottleDevice struct {
+	Path string
+	Rate uint64
 }
 
 // ContainerFromNative instantiates a Docker-compatible Container from containerd-native Container.
@@ -367,10 +404,6 @@ func ContainerFromNative(n *native.Container) (*Container, error) {
 		if _, err := os.Stat(hostnamePath); err == nil {
 			c.HostnamePath = hostnamePath
 		}
-		c.LogPath = filepath.Join(nerdctlStateDir, n.ID+"-json.log")
-		if _, err := os.Stat(c.LogPath); err != nil {
-			c.LogPath = ""
-		}
 		hostsPath := filepath.Join(nerdctlStateDir, "hosts")
 		if _, err := os.Stat(hostsPath); err == nil {
 			c.HostsPath = hostsPath
@@ -415,6 +448,18 @@ func ContainerFromNative(n *native.Container) (*Container, error) {
 		}
 	}
 
+	// LogPath is only meaningful for the json-file driver, as other drivers
+	// do not persist the logs on the host.
+	if c.HostConfig.LogConfig.Driver == "json-file" {
+		logPath := c.HostConfig.LogConfig.Opts["log-path"]
+		if nerdctlStateDir := n.Labels[labels.StateDir]; logPath == "" && nerdctlStateDir != "" {
+			logPath = filepath.Join(nerdctlStateDir, jsonfile.Filename(n.ID))
+		}
+		if _, err := os.Stat(logPath); logPath != "" && err == nil {
+			c.LogPath = logPath
+		}
+	}
+
 	hostConfigLabel, err := getHostConfigLabelFromNative(n.Labels)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch HostConfigLabel: %v", err)
@@ -422,6 +467,8 @@ func ContainerFromNative(n *native.Container) (*Container, error) {
 
 	c.HostConfig.BlkioWeight = hostConfigLabel.BlkioWeight
 	c.HostConfig.ContainerIDFile = hostConfigLabel.CidFile
+	c.HostConfig.NanoCPUs = hostConfigLabel.NanoCPUs
+	c.HostConfig.Ulimits = hostConfigLabel.Ulimits
 
 	groupAdd, err := groupAddFromNative(n.Spec.(*specs.Spec))
 	if err != nil {
@@ -455,6 +502,7 @@ func ContainerFromNative(n *native.Container) (*Container, error) {
 		cs.Paused = n.Process.Status.Status == containerd.Paused
 		cs.Pid = n.Process.Pid
 		cs.ExitCode = int(n.Process.Status.ExitStatus)
+		cs.OOMKilled = n.Labels[labels.OOMKilled] == "true" || n.Process.OOMKilled
 		if containerAnnotations[labels.StateDir] != "" {
 			if lf, err := state.New(containerAnnotations[labels.StateDir]); err != nil {
 				log.L.WithError(err).Errorf("failed retrieving state")
@@ -492,8 +540,16 @@ func ContainerFromNative(n *native.Container) (*Container, error) {
 	c.HostConfig.CPUQuota = cpuSetting.CPUQuota
 	c.HostConfig.CPUShares = cpuSetting.CPUShares
 	c.HostConfig.CPUPeriod = cpuSetting.CPUPeriod
+	c.HostConfig.CPUBurst = cpuSetting.CPUBurst
 	c.HostConfig.CPURealtimePeriod = cpuSetting.CPURealtimePeriod
 	c.HostConfig.CPURealtimeRuntime = cpuSetting.CPURealtimeRuntime
+	// The spec is authoritative, as it is updated by `nerdctl update`; the label covers the specs without CPU resources.
+	if c.HostConfig.CPUSetCPUs == "" {
+		c.HostConfig.CPUSetCPUs = hostConfigLabel.CPUSetCPUs
+	}
+	if c.HostConfig.CPUShares == 0 {
+		c.HostConfig.CPUShares = hostConfigLabel.CPUShares
+	}
 
 	cgroupNamespace, err := getCgroupnsFromNative(n.Spec.(*specs.Spec))
 	if err != nil {
@@ -559,6 +615,27 @@ func ContainerFromNative(n *native.Container) (*Container, error) {
 		c.Config.Domainname = n.Labels[labels.Domainname]
 	}
 
+	if exposedPortsJSON := n.Labels[labels.ExposedPorts]; exposedPortsJSON != "" {
+		var exposedPorts []string
+		if err := json.Unmarshal([]byte(exposedPortsJSON), &exposedPorts); err != nil {
+			log.L.WithError(err).Warnf("failed to parse label %q", labels.ExposedPorts)
+		} else {
+			c.Config.ExposedPorts = make(nat.PortSet, len(exposedPorts))
+			for _, p := range exposedPorts {
+				// The ranges are stored as "<start>-<end>/<proto>", and listed port by port like Docker
+				proto, ports := nat.SplitProtoPort(p)
+				start, end, err := nat.ParsePortRange(ports)
+				if err != nil {
+					log.L.WithError(err).Warnf("ignoring the invalid exposed port %q", p)
+					continue
+				}
+				for port := start; port <= end; port++ {
+					c.Config.ExposedPorts[nat.Port(fmt.Sprintf("%d/%s", port, proto))] = struct{}{}
+				}
+			}
+		}
+	}
+
 	c.HostConfig.Devices = hostConfigLabel.Devices
 
 	var pidMode string
@@ -567,7 +644,7 @@ func ContainerFromNative(n *native.Container) (*Container, error) {
 	}
 	c.HostConfig.PidMode = pidMode
 
-	if err := getBlkioSettingsFromSpec(n.Spec.(*specs.Spec), c.HostConfig); err != nil {
+	if err := getBlkioSettingsFromSpec(n.Spec.(*specs.Spec), hostConfigLabel, c.HostConfig); err != nil {
 		return nil, fmt.Errorf("failed to get blkio settings: %w", err)
 	}
 
@@ -581,13 +658,21 @@ func ContainerFromNative(n *native.Container) (*Container, error) {
 		c.Config.User = n.Labels[labels.User]
 	}
 
+	if t, ok := n.Labels[labels.StopTimeout]; ok {
+		stopTimeout, err := strconv.Atoi(t)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse stop timeout label %q: %w", t, err)
+		}
+		c.Config.StopTimeout = &stopTimeout
+	}
+
 	// Add health check config if present in labels
 	if hConfig, ok := n.Labels[labels.HealthCheck]; ok && hConfig != "" {
 		healthCheckConfig, err := healthcheck.HealthCheckFromJSON(hConfig)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse healthcheck label: %w", err)
 		}
-		c.Config.Healthcheck = healthCheckConfig
+		c.Config.Healthcheck = healthCheckConfig.Redacted()
 	}
 
 	// Add health status to container state.
@@ -704,6 +789,16 @@ func networkSettingsFromNative(n *native.NetNS, _ *specs.Spec) (*NetworkSettings
 		return res, nil
 	}
 
+	// The port mappings come from the network store of the container, where ephemeral host ports
+	// are recorded with the value that was allocated to them.
+	nports, err := convertToNatPort(n.PortMappings)
+	if err != nil {
+		return nil, err
+	}
+	for portLabel, portBindings := range *nports {
+		resPortMap[portLabel] = portBindings
+	}
+
 	var primary *NetworkEndpointSettings
 	for _, x := range n.Interfaces {
 		if x.Interface.Flags&net.FlagLoopback != 0 {
@@ -737,14 +832,6 @@ func networkSettingsFromNative(n *native.NetNS, _ *specs.Spec) (*NetworkSettings
 		fakeDockerNetworkName := fmt.Sprintf("unknown-%s", x.Name)
 		res.Networks[fakeDockerNetworkName] = nes
 
-		nports, err := convertToNatPort(n.PortMappings)
-		if err != nil {
-			return nil, err
-		}
-		for portLabel, portBindings := range *nports {
-			resPortMap[portLabel] = portBindings
-		}
-
 		if x.Index == n.PrimaryInterface {
 			primary = nes
 		}
@@ -781,6 +868,9 @@ func cpuSettingsFromNative(sp *specs.Spec) (*CPUSettings, error) {
 		if sp.Linux.Resources.CPU.Period != nil && *sp.Linux.Resources.CPU.Period > 0 {
 			res.CPUPeriod = *sp.Linux.Resources.CPU.Period
 		}
+		if sp.Linux.Resources.CPU.Burst != nil && *sp.Linux.Resources.CPU.Burst > 0 {
+			res.CPUBurst = *sp.Linux.Resources.CPU.Burst
+		}
 		if sp.Linux.Resources.CPU.RealtimePeriod != nil && *sp.Linux.Resources.CPU.RealtimePeriod > 0 {
 			res.CPURealtimePeriod = *sp.Linux.Resources.CPU.RealtimePeriod
 		}
@@ -819,7 +909,6 @@ func groupAddFromNative(sp *specs.Spec) ([]string, error) {
 func convertToNatPort(portMappings []cni.PortMapping) (*nat.PortMap, error) {
 	portMap := make(nat.PortMap)
 	for _, portMapping := range portMappings {
-		ports := []nat.PortBinding{}
 		p := nat.PortBinding{
 			HostIP:   portMapping.HostIP,
 			HostPort: strconv.FormatInt(int64(portMapping.HostPort), 10),
@@ -828,8 +917,8 @@ func convertToNatPort(portMappings []cni.PortMapping) (*nat.PortMap, error) {
 		if err != nil {
 			return nil, err
 		}
-		ports = append(ports, p)
-		portMap[newP] = ports
+		// A container port may be published several times (e.g., on different host IPs)
+		portMap[newP] = append(portMap[newP], p)
 	}
 	return &portMap, nil
 }
@@ -1021,21 +1110,27 @@ func ParseMountProperties(option []string) (rw bool, propagation string) {
 func getDefaultLinuxBlkioSettings() LinuxBlkioSettings {
 	return LinuxBlkioSettings{
 		BlkioWeight:          0,
-		BlkioWeightDevice:    make([]*specs.LinuxWeightDevice, 0),
-		BlkioDeviceReadBps:   make([]*specs.LinuxThrottleDevice, 0),
-		BlkioDeviceWriteBps:  make([]*specs.LinuxThrottleDevice, 0),
-		BlkioDeviceReadIOps:  make([]*specs.LinuxThrottleDevice, 0),
-		BlkioDeviceWriteIOps: make([]*specs.LinuxThrottleDevice, 0),
+		BlkioWeightDevice:    make([]*WeightDevice, 0),
+		BlkioDeviceReadBps:   make([]*ThrottleDevice, 0),
+		BlkioDeviceWriteBps:  make([]*ThrottleDevice, 0),
+		BlkioDeviceReadIOps:  make([]*ThrottleDevice, 0),
+		BlkioDeviceWriteIOps: make([]*ThrottleDevice, 0),
 	}
 }
 
-func getBlkioSettingsFromSpec(spec *specs.Spec, hostConfig *HostConfig) error {
+// getBlkioSettingsFromSpec fills the blkio settings of hostConfig from the spec.
+// The device paths are taken from hostConfigLabel; containers created without them
+// in the label report the /dev/block/<major>:<minor> path of the device instead.
+func getBlkioSettingsFromSpec(spec *specs.Spec, hostConfigLabel *HostConfigLabel, hostConfig *HostConfig) error {
 	if spec == nil {
 		return fmt.Errorf("spec cannot be nil")
 	}
 	if hostConfig == nil {
 		return fmt.Errorf("hostConfig cannot be nil")
 	}
+	if hostConfigLabel == nil {
+		hostConfigLabel = &HostConfigLabel{}
+	}
 
 	// Initialize empty arrays by default
 	hostConfig.LinuxBlkioSettings = getDefaultLinuxBlkioSettings()
@@ -1053,42 +1148,48 @@ func getBlkioSettingsFromSpec(spec *specs.Spec, hostConfig *HostConfig) error {
 
 	// Set weight devices
 	if len(blockIO.WeightDevice) > 0 {
-		hostConfig.BlkioWeightDevice = make([]*specs.LinuxWeightDevice, len(blockIO.WeightDevice))
-		for i, dev := range blockIO.WeightDevice {
-			hostConfig.BlkioWeightDevice[i] = &dev
+		if len(hostConfigLabel.BlkioWeightDevice) == len(blockIO.WeightDevice) {
+			hostConfig.BlkioWeightDevice = hostConfigLabel.BlkioWeightDevice
+		} else {
+			for _, dev := range blockIO.WeightDevice {
+				d := &WeightDevice{Path: blockDevicePath(dev.Major, dev.Minor)}
+				if dev.Weight != nil {
+					d.Weight = *dev.Weight
+				}
+				hostConfig.BlkioWeightDevice = append(hostConfig.BlkioWeightDevice, d)
+			}
 		}
 	}
 
-	// Set throttle devices for read BPS
+	// Set throttle devices
 	if len(blockIO.ThrottleReadBpsDevice) > 0 {
-		hostConfig.BlkioDeviceReadBps = make([]*specs.LinuxThrottleDevice, len(blockIO.ThrottleReadBpsDevice))
-		for i, dev := range blockIO.ThrottleReadBpsDevice {
-			hostConfig.BlkioDeviceReadBps[i] = &dev
-		}
+		hostConfig.BlkioDeviceReadBps = throttleDevicesFromSpec(blockIO.ThrottleReadBpsDevice, hostConfigLabel.BlkioDeviceReadBps)
 	}
-
-	// Set throttle devices for write BPS
 	if len(blockIO.ThrottleWriteBpsDevice) > 0 {
-		hostConfig.BlkioDeviceWriteBps = make([]*specs.LinuxThrottleDevice, len(blockIO.ThrottleWriteBpsDevice))
-		for i, dev := range blockIO.ThrottleWriteBpsDevice {
-			hostConfig.BlkioDeviceWriteBps[i] = &dev
-		}
+		hostConfig.BlkioDeviceWriteBps = throttleDevicesFromSpec(blockIO.ThrottleWriteBpsDevice, hostConfigLabel.BlkioDeviceWriteBps)
 	}
-
-	// Set throttle devices for read IOPs
 	if len(blockIO.ThrottleReadIOPSDevice) > 0 {
-		hostConfig.BlkioDeviceReadIOps = make([]*specs.LinuxThrottleDevice, len(blockIO.ThrottleReadIOPSDevice))
-		for i, dev := range blockIO.ThrottleReadIOPSDevice {
-			hostConfig.BlkioDeviceReadIOps[i] = &dev
-		}
+		hostConfig.BlkioDeviceReadIOps = throttleDevicesFromSpec(blockIO.ThrottleReadIOPSDevice, hostConfigLabel.BlkioDeviceReadIOps)
 	}
-
-	// Set throttle devices for write IOPs
 	if len(blockIO.ThrottleWriteIOPSDevice) > 0 {
-		hostConfig.BlkioDeviceWriteIOps = make([]*specs.LinuxThrottleDevice, len(blockIO.ThrottleWriteIOPSDevice))
-		for i, dev := range blockIO.ThrottleWriteIOPSDevice {
-			hostConfig.BlkioDeviceWriteIOps[i] = &dev
-		}
+		hostConfig.BlkioDeviceWriteIOps = throttleDevicesFromSpec(blockIO.ThrottleWriteIOPSDevice, hostConfigLabel.BlkioDeviceWriteIOps)
 	}
 	return nil
 }
+
+// throttleDevicesFromSpec returns the devices recorded in the label, which are in the same order
+// as the devices of the spec, or the devices of the spec when the label does not have them.
+func throttleDevicesFromSpec(devs []specs.LinuxThrottleDevice, fromLabel []*ThrottleDevice) []*ThrottleDevice {
+	if len(fromLabel) == len(devs) {
+		return fromLabel
+	}
+	res := make([]*ThrottleDevice, len(devs))
+	for i, dev := range devs {
+		res[i] = &ThrottleDevice{Path: blockDevicePath(dev.Major, dev.Minor), Rate: dev.Rate}
+	}
+	return res
+}
+
+func blockDevicePath(major, minor int64) string {
+	return fmt.Sprintf("/dev/block/%d:%d", major, minor)
+}
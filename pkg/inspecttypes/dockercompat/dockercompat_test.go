@@ -17,6 +17,7 @@
 package dockercompat
 
 import (
+	"encoding/json"
 	"net"
 	"os"
 	"path/filepath"
@@ -371,6 +372,141 @@ func TestContainerFromNative(t *testing.T) {
 	}
 }
 
+func TestContainerFromNativeCPUSharesAndCpuset(t *testing.T) {
+	shares := uint64(512)
+	hostConfigLabel := `{"CPUSetCPUs":"0-1","CPUShares":1024}`
+
+	testcase := []struct {
+		name           string
+		labels         map[string]string
+		cpu            *specs.LinuxCPU
+		expectedCpus   string
+		expectedShares uint64
+	}{
+		{
+			name:           "from the spec",
+			cpu:            &specs.LinuxCPU{Cpus: "0", Shares: &shares},
+			expectedCpus:   "0",
+			expectedShares: 512,
+		},
+		{
+			name:           "the spec wins over the label, e.g. after update",
+			labels:         map[string]string{labels.HostConfigLabel: hostConfigLabel},
+			cpu:            &specs.LinuxCPU{Cpus: "0", Shares: &shares},
+			expectedCpus:   "0",
+			expectedShares: 512,
+		},
+		{
+			name:           "from the label",
+			labels:         map[string]string{labels.HostConfigLabel: hostConfigLabel},
+			expectedCpus:   "0-1",
+			expectedShares: 1024,
+		},
+	}
+
+	for _, tc := range testcase {
+		t.Run(tc.name, func(tt *testing.T) {
+			n := &native.Container{
+				Container: containers.Container{Labels: tc.labels},
+				Spec:      &specs.Spec{Linux: &specs.Linux{Resources: &specs.LinuxResources{CPU: tc.cpu}}},
+			}
+			d, err := ContainerFromNative(n)
+			assert.NilError(tt, err)
+			assert.Equal(tt, d.HostConfig.CPUSetCPUs, tc.expectedCpus)
+			assert.Equal(tt, d.HostConfig.CPUShares, tc.expectedShares)
+		})
+	}
+}
+
+func TestContainerFromNativeStopTimeout(t *testing.T) {
+	d, err := ContainerFromNative(&native.Container{Spec: &specs.Spec{}})
+	assert.NilError(t, err)
+	assert.Assert(t, d.Config.StopTimeout == nil)
+
+	// an explicit 0 is distinct from the default
+	d, err = ContainerFromNative(&native.Container{
+		Container: containers.Container{Labels: map[string]string{labels.StopTimeout: "0"}},
+		Spec:      &specs.Spec{},
+	})
+	assert.NilError(t, err)
+	assert.Assert(t, d.Config.StopTimeout != nil)
+	assert.Equal(t, *d.Config.StopTimeout, 0)
+}
+
+func TestContainerFromNativeOOMKilled(t *testing.T) {
+	stopped := &native.Process{Status: containerd.Status{Status: containerd.Stopped}}
+	d, err := ContainerFromNative(&native.Container{Spec: &specs.Spec{}, Process: stopped})
+	assert.NilError(t, err)
+	assert.Equal(t, d.State.OOMKilled, false)
+
+	// recorded on exit, while the cgroup of the task may not exist anymore
+	d, err = ContainerFromNative(&native.Container{
+		Container: containers.Container{Labels: map[string]string{labels.OOMKilled: "true"}},
+		Spec:      &specs.Spec{},
+		Process:   stopped,
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, d.State.OOMKilled, true)
+
+	// read from the cgroup of the task
+	d, err = ContainerFromNative(&native.Container{
+		Container: containers.Container{Labels: map[string]string{labels.OOMKilled: "false"}},
+		Spec:      &specs.Spec{},
+		Process:   &native.Process{Status: containerd.Status{Status: containerd.Running}, OOMKilled: true},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, d.State.OOMKilled, true)
+}
+
+func TestContainerFromNativeExposedPorts(t *testing.T) {
+	d, err := ContainerFromNative(&native.Container{Spec: &specs.Spec{}})
+	assert.NilError(t, err)
+	assert.Assert(t, d.Config.ExposedPorts == nil)
+
+	d, err = ContainerFromNative(&native.Container{
+		Container: containers.Container{Labels: map[string]string{labels.ExposedPorts: `["53/udp","80/tcp"]`}},
+		Spec:      &specs.Spec{},
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, d.Config.ExposedPorts, nat.PortSet{"53/udp": {}, "80/tcp": {}})
+
+	d, err = ContainerFromNative(&native.Container{
+		Container: containers.Container{Labels: map[string]string{labels.ExposedPorts: `["80/tcp","8000-8002/udp"]`}},
+		Spec:      &specs.Spec{},
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, d.Config.ExposedPorts, nat.PortSet{"80/tcp": {}, "8000/udp": {}, "8001/udp": {}, "8002/udp": {}})
+}
+
+func TestContainerFromNativeBlkioDevices(t *testing.T) {
+	blockIO := &specs.LinuxBlockIO{
+		ThrottleReadBpsDevice: []specs.LinuxThrottleDevice{
+			{LinuxBlockIODevice: specs.LinuxBlockIODevice{Major: 1, Minor: 5}, Rate: 1048576},
+		},
+	}
+	spec := &specs.Spec{Linux: &specs.Linux{Resources: &specs.LinuxResources{BlockIO: blockIO}}}
+
+	// the path of the device is kept in the label
+	d, err := ContainerFromNative(&native.Container{
+		Container: containers.Container{Labels: map[string]string{
+			labels.HostConfigLabel: `{"BlkioDeviceReadBps":[{"Path":"/dev/dummy-zero","Rate":1048576}]}`,
+		}},
+		Spec: spec,
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, d.HostConfig.BlkioDeviceReadBps, []*ThrottleDevice{{Path: "/dev/dummy-zero", Rate: 1048576}})
+	assert.DeepEqual(t, d.HostConfig.BlkioDeviceWriteBps, []*ThrottleDevice{})
+
+	// containers created without the path in the label report the major:minor path
+	d, err = ContainerFromNative(&native.Container{Spec: spec})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, d.HostConfig.BlkioDeviceReadBps, []*ThrottleDevice{{Path: "/dev/block/1:5", Rate: 1048576}})
+
+	b, err := json.Marshal(d.HostConfig.BlkioDeviceReadBps)
+	assert.NilError(t, err)
+	assert.Equal(t, string(b), `[{"Path":"/dev/block/1:5","Rate":1048576}]`)
+}
+
 func TestNetworkSettingsFromNative(t *testing.T) {
 	tempStateDir, err := os.MkdirTemp(t.TempDir(), "rw")
 	if err != nil {
@@ -477,6 +613,57 @@ func TestNetworkSettingsFromNative(t *testing.T) {
 				},
 			},
 		},
+		// Given native.NetNS with a container port published several times, Return all the bindings
+		//   UseCase: Inspect a Running Container with `-p 127.0.0.1::80 -p 8080:80`, where the first host port is ephemeral
+		{
+			name: "Given NetNS with a port published several times, Return all the bindings",
+			n: &native.NetNS{
+				PortMappings: []cni.PortMapping{
+					{
+						HostPort:      49153,
+						ContainerPort: 80,
+						Protocol:      "tcp",
+						HostIP:        "127.0.0.1",
+					},
+					{
+						HostPort:      8080,
+						ContainerPort: 80,
+						Protocol:      "tcp",
+						HostIP:        "0.0.0.0",
+					},
+					{
+						HostPort:      5353,
+						ContainerPort: 53,
+						Protocol:      "udp",
+						HostIP:        "0.0.0.0",
+					},
+				},
+			},
+			s: &specs.Spec{
+				Annotations: map[string]string{},
+			},
+			expected: &NetworkSettings{
+				Ports: &nat.PortMap{
+					nat.Port("80/tcp"): []nat.PortBinding{
+						{
+							HostIP:   "127.0.0.1",
+							HostPort: "49153",
+						},
+						{
+							HostIP:   "0.0.0.0",
+							HostPort: "8080",
+						},
+					},
+					nat.Port("53/udp"): []nat.PortBinding{
+						{
+							HostIP:   "0.0.0.0",
+							HostPort: "5353",
+						},
+					},
+				},
+				Networks: map[string]*NetworkEndpointSettings{},
+			},
+		},
 	}
 
 	for _, tc := range testcase {
@@ -36,6 +36,8 @@ type Process struct {
 	Pid    int               `json:"Pid,omitempty"`
 	Status containerd.Status `json:"Status,omitempty"`
 	NetNS  *NetNS            `json:"NetNS,omitempty"`
+	// OOMKilled is true if a process of the container was killed by the OOM killer
+	OOMKilled bool `json:"OOMKilled,omitempty"`
 }
 
 // NetNS is designed not to depend on CNI
@@ -22,4 +22,8 @@ type Volume struct {
 	Mountpoint string             `json:"Mountpoint"`
 	Labels     *map[string]string `json:"Labels,omitempty"`
 	Size       int64              `json:"Size,omitempty"`
+	// Driver is the volume driver, empty for the local driver.
+	Driver string `json:"Driver,omitempty"`
+	// Options are the options passed to the volume driver.
+	Options map[string]string `json:"Options,omitempty"`
 }
@@ -32,6 +32,7 @@ import (
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/containerd/v2/core/containers"
 	"github.com/containerd/containerd/v2/pkg/oci"
+	"github.com/containerd/log"
 
 	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
@@ -102,6 +103,12 @@ func DetectFlags(ctx context.Context, client *containerd.Client, stateDir string
 		}
 	}
 
+	// The /dev/shm of the host or the other container is used as is, so its size cannot be changed
+	if shmSize != "" && (res.Mode == Host || res.Mode == Container) {
+		log.G(ctx).Warnf("--shm-size is ignored when --ipc=%s is specified, as /dev/shm is shared with the %s", res.Mode, res.Mode)
+		res.ShmSize = ""
+	}
+
 	return res, nil
 }
 
@@ -0,0 +1,44 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ipcutil
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestDetectFlagsShmSize(t *testing.T) {
+	ctx := context.Background()
+
+	ipc, err := DetectFlags(ctx, nil, t.TempDir(), "private", "64m")
+	assert.NilError(t, err)
+	assert.Equal(t, ipc.Mode, Private)
+	assert.Equal(t, ipc.ShmSize, "64m")
+
+	ipc, err = DetectFlags(ctx, nil, t.TempDir(), "shareable", "64m")
+	assert.NilError(t, err)
+	assert.Equal(t, ipc.Mode, Shareable)
+	assert.Equal(t, ipc.ShmSize, "64m")
+
+	// The shm size cannot apply to the /dev/shm of the host
+	ipc, err = DetectFlags(ctx, nil, t.TempDir(), "host", "64m")
+	assert.NilError(t, err)
+	assert.Equal(t, ipc.Mode, Host)
+	assert.Equal(t, ipc.ShmSize, "")
+}
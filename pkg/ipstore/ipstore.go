@@ -0,0 +1,178 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package ipstore provides the interface for /var/lib/nerdctl/<ADDRHASH>/ips
+// It records the static IP addresses (--ip) requested by containers, per network.
+// A reservation is held from container create to container remove, so that the address of a stopped container
+// is not handed out to another container in the meantime.
+// All methods are safe to use concurrently.
+// Note that locking is global (not per namespace), as networks are shared across namespaces.
+// ipstore is currently used by container create, remove, and as part of the ocihook events cycle.
+package ipstore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"path/filepath"
+
+	"github.com/containerd/nerdctl/v2/pkg/store"
+)
+
+// ipsDirBasename is the base name of /var/lib/nerdctl/<ADDRHASH>/ips
+// Reservations are stored as ipsDirBasename/<NETWORK>/<IP>
+const ipsDirBasename = "ips"
+
+// ErrIPStore will wrap all errors here
+var ErrIPStore = errors.New("ip-store error")
+
+// Owner identifies the container holding a reservation.
+type Owner struct {
+	Namespace string `json:"namespace"`
+	ID        string `json:"id"`
+}
+
+// New will return an IPStore.
+func New(dataStore string) (IPStore, error) {
+	if dataStore == "" {
+		return nil, errors.Join(ErrIPStore, store.ErrInvalidArgument)
+	}
+
+	st, err := store.New(filepath.Join(dataStore, ipsDirBasename), 0, 0o600)
+	if err != nil {
+		return nil, errors.Join(ErrIPStore, err)
+	}
+
+	return &ipStore{
+		safeStore: st,
+	}, nil
+}
+
+// IPStore allows reserving, releasing and looking up static IP addresses.
+// A container cannot release an address it does not own.
+// A container cannot reserve an address that is already owned by another container, unless that reservation is stale.
+// Re-reserving an address does not error and is a no-op.
+type IPStore interface {
+	// Reserve exclusively grants `ip` on `network` to `owner`.
+	// If the address is already reserved by another container, isStale is called with the current owner,
+	// and the reservation is taken over if it returns true.
+	Reserve(network, ip string, owner Owner, isStale func(Owner) bool) error
+	// Release allows the owner of a reservation to release it.
+	Release(network, ip string, owner Owner) error
+	// Lookup returns the owner of the reservation of `ip` on `network`.
+	// It returns an error wrapping store.ErrNotFound if the address is not reserved.
+	Lookup(network, ip string) (Owner, error)
+}
+
+type ipStore struct {
+	safeStore store.Store
+}
+
+func (x *ipStore) Reserve(network, ip string, owner Owner, isStale func(Owner) bool) (err error) {
+	defer func() {
+		if err != nil {
+			err = errors.Join(ErrIPStore, err)
+		}
+	}()
+
+	if err = validateIP(ip); err != nil {
+		return err
+	}
+
+	return x.safeStore.WithLock(func() error {
+		current, err := x.get(network, ip)
+		if err != nil {
+			if !errors.Is(err, store.ErrNotFound) {
+				return err
+			}
+		} else if current != owner && (isStale == nil || !isStale(current)) {
+			return fmt.Errorf("IP address %s on network %q is already reserved by container %q (namespace %q)",
+				ip, network, current.ID, current.Namespace)
+		}
+
+		content, err := json.Marshal(owner)
+		if err != nil {
+			return err
+		}
+
+		return x.safeStore.Set(content, network, ip)
+	})
+}
+
+func (x *ipStore) Release(network, ip string, owner Owner) (err error) {
+	defer func() {
+		if err != nil {
+			err = errors.Join(ErrIPStore, err)
+		}
+	}()
+
+	if err = validateIP(ip); err != nil {
+		return err
+	}
+
+	return x.safeStore.WithLock(func() error {
+		current, err := x.get(network, ip)
+		if err != nil {
+			return err
+		}
+
+		if current != owner {
+			return fmt.Errorf("cannot release IP address %s on network %q (reserved by ID %q, not by %q)",
+				ip, network, current.ID, owner.ID)
+		}
+
+		return x.safeStore.Delete(network, ip)
+	})
+}
+
+func (x *ipStore) Lookup(network, ip string) (owner Owner, err error) {
+	defer func() {
+		if err != nil {
+			err = errors.Join(ErrIPStore, err)
+		}
+	}()
+
+	if err = validateIP(ip); err != nil {
+		return owner, err
+	}
+
+	err = x.safeStore.WithLock(func() error {
+		owner, err = x.get(network, ip)
+		return err
+	})
+
+	return owner, err
+}
+
+func (x *ipStore) get(network, ip string) (Owner, error) {
+	var owner Owner
+	content, err := x.safeStore.Get(network, ip)
+	if err != nil {
+		return owner, err
+	}
+
+	err = json.Unmarshal(content, &owner)
+
+	return owner, err
+}
+
+func validateIP(ip string) error {
+	if parsed := net.ParseIP(ip); parsed == nil || parsed.To4() == nil {
+		return fmt.Errorf("invalid IPv4 address %q: %w", ip, store.ErrInvalidArgument)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ipstore
+
+import (
+	"errors"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/store"
+)
+
+func TestIPStore(t *testing.T) {
+	ips, err := New(t.TempDir())
+	assert.NilError(t, err)
+
+	first := Owner{Namespace: "default", ID: "first"}
+	second := Owner{Namespace: "other", ID: "second"}
+	never := func(Owner) bool { return false }
+	always := func(Owner) bool { return true }
+
+	_, err = ips.Lookup("bridge", "10.4.0.100")
+	assert.Assert(t, errors.Is(err, store.ErrNotFound))
+
+	assert.NilError(t, ips.Reserve("bridge", "10.4.0.100", first, never))
+	// Re-reserving is a no-op
+	assert.NilError(t, ips.Reserve("bridge", "10.4.0.100", first, never))
+
+	owner, err := ips.Lookup("bridge", "10.4.0.100")
+	assert.NilError(t, err)
+	assert.Equal(t, owner, first)
+
+	// Reservations are per network
+	assert.NilError(t, ips.Reserve("other-net", "10.4.0.100", second, never))
+
+	err = ips.Reserve("bridge", "10.4.0.100", second, never)
+	assert.Assert(t, errors.Is(err, ErrIPStore))
+	assert.ErrorContains(t, err, "already reserved by container \"first\"")
+
+	err = ips.Release("bridge", "10.4.0.100", second)
+	assert.ErrorContains(t, err, "cannot release")
+
+	// A stale reservation is taken over
+	assert.NilError(t, ips.Reserve("bridge", "10.4.0.100", second, always))
+	owner, err = ips.Lookup("bridge", "10.4.0.100")
+	assert.NilError(t, err)
+	assert.Equal(t, owner, second)
+
+	assert.NilError(t, ips.Release("bridge", "10.4.0.100", second))
+	err = ips.Release("bridge", "10.4.0.100", second)
+	assert.Assert(t, errors.Is(err, store.ErrNotFound))
+
+	assert.ErrorContains(t, ips.Reserve("bridge", "fd00::1", first, never), "invalid IPv4 address")
+	assert.ErrorContains(t, ips.Reserve("bridge", "not-an-ip", first, never), "invalid IPv4 address")
+}
+
+func TestContainerHasStaticIP(t *testing.T) {
+	containerLabels := map[string]string{
+		labels.IPAddress: "10.4.0.100",
+		labels.Networks:  `["bridge","other"]`,
+	}
+	assert.Assert(t, ContainerHasStaticIP(containerLabels, "bridge", "10.4.0.100"))
+	assert.Assert(t, ContainerHasStaticIP(containerLabels, "other", "10.4.0.100"))
+	assert.Assert(t, !ContainerHasStaticIP(containerLabels, "bridge", "10.4.0.101"))
+	assert.Assert(t, !ContainerHasStaticIP(containerLabels, "none", "10.4.0.100"))
+	assert.Assert(t, !ContainerHasStaticIP(map[string]string{labels.IPAddress: "10.4.0.100"}, "bridge", "10.4.0.100"))
+}
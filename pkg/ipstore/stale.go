@@ -0,0 +1,58 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ipstore
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/pkg/namespaces"
+	"github.com/containerd/errdefs"
+
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+)
+
+// StaleReservation returns a function checking whether the owner of a reservation no longer exists, or no longer
+// uses that address on that network, according to its labels.
+func StaleReservation(ctx context.Context, client *containerd.Client, network, ip string) func(Owner) bool {
+	return func(owner Owner) bool {
+		ctx := namespaces.WithNamespace(ctx, owner.Namespace)
+		c, err := client.LoadContainer(ctx, owner.ID)
+		if err != nil {
+			return errdefs.IsNotFound(err)
+		}
+		containerLabels, err := c.Labels(ctx)
+		if err != nil {
+			return errdefs.IsNotFound(err)
+		}
+		return !ContainerHasStaticIP(containerLabels, network, ip)
+	}
+}
+
+// ContainerHasStaticIP returns whether the labels of a container record the static IP address ip on network.
+func ContainerHasStaticIP(containerLabels map[string]string, network, ip string) bool {
+	if containerLabels[labels.IPAddress] != ip {
+		return false
+	}
+	var networks []string
+	if err := json.Unmarshal([]byte(containerLabels[labels.Networks]), &networks); err != nil {
+		return false
+	}
+	return slices.Contains(networks, network)
+}
@@ -29,6 +29,9 @@ const (
 	// WARNING: multiple containers may have same the name label
 	Name = Prefix + "name"
 
+	// ComposePrefix is the common prefix of compose labels
+	ComposePrefix = "com.docker.compose."
+
 	//Compose Project Name
 	ComposeProject = "com.docker.compose.project"
 
@@ -67,6 +70,10 @@ const (
 	// IP6Address is the static IP6 address of the container assigned by the user
 	IP6Address = Prefix + "ip6"
 
+	// ExposedPorts is a JSON-marshalled string of []string, the ports (or ranges) exposed by the image
+	// and by `nerdctl run --expose`, like "80/tcp" or "8000-8010/udp".
+	ExposedPorts = Prefix + "exposed-ports"
+
 	// LogURI is the log URI
 	LogURI = Prefix + "log-uri"
 
@@ -77,17 +84,51 @@ const (
 	// AnonymousVolumes is a JSON-marshalled string of []string
 	AnonymousVolumes = Prefix + "anonymous-volumes"
 
+	// AutoCreatedVolume is set to "true" on the named volumes created by a container mounting them (`-v VOLUME:DST`),
+	// as opposed to the volumes created with `nerdctl volume create`.
+	AutoCreatedVolume = Prefix + "auto-created"
+
+	// VolumeCreatedBy is the ID of the container that auto-created the volume.
+	VolumeCreatedBy = Prefix + "created-by"
+
 	// Platform is the normalized platform string like "linux/ppc64le".
 	Platform = Prefix + "platform"
 
 	// Mounts is the mount points for the container.
 	Mounts = Prefix + "mounts"
 
+	// VolumeDriverMounts is a JSON-marshalled string of []volumedriver.MountRecord,
+	// the mounts of the volumes of non-local drivers to unmount on container removal.
+	VolumeDriverMounts = Prefix + "volume-driver-mounts"
+
+	// ImageMounts is a JSON-marshalled string of []mountutil.ImageMountRecord, the rootfs of the images
+	// of the image mounts (--mount type=image), to mount again on container start and unmount on container removal.
+	ImageMounts = Prefix + "image-mounts"
+
 	// StopTimeout is seconds to wait for stop a container.
 	StopTimeout = Prefix + "stop-timeout"
 
 	MACAddress = Prefix + "mac-address"
 
+	// NetworkMTU is the MTU of the container network interfaces assigned by the user (--network-mtu)
+	NetworkMTU = Prefix + "network-mtu"
+
+	// RestartMinUptime is the minimum uptime of a container with a restart policy (--restart-min-uptime).
+	// A container exiting earlier is restarted with a backoff.
+	RestartMinUptime = Prefix + "restart-min-uptime"
+
+	// RestartNextStart is the time (RFC 3339) before which a container that kept exiting before running for
+	// its minimum uptime must not be restarted by the restart monitor.
+	RestartNextStart = Prefix + "restart-next-start"
+
+	// RestartExitCodes is the comma-separated list of the exit codes that an `on-failure` restart policy
+	// restarts the container on (--restart=on-failure:n:codes=...). The container is not restarted on other codes.
+	RestartExitCodes = Prefix + "restart-exit-codes"
+
+	// OOMKilled is "true" if a process of the last run of the container was killed by the OOM killer.
+	// It is recorded by the logging process when the task exits, as the cgroup may not exist anymore on inspecting.
+	OOMKilled = Prefix + "oom-killed"
+
 	// PIDContainer is the `nerdctl run --pid` for restarting
 	PIDContainer = Prefix + "pid-container"
 
@@ -117,6 +158,9 @@ const (
 	// DNSSettings sets the dockercompat DNS config values
 	DNSSetting = Prefix + "dns"
 
+	// RedactedEnv is a JSON-marshalled list of the environment variable names whose values are redacted in inspect
+	RedactedEnv = Prefix + "redacted-env"
+
 	// User is the username of the container
 	User = Prefix + "user"
 
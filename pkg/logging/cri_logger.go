@@ -37,8 +37,6 @@ import (
 	"github.com/fsnotify/fsnotify"
 
 	"github.com/containerd/log"
-
-	"github.com/containerd/nerdctl/v2/pkg/logging/tail"
 )
 
 // LogStreamType is the type of the stream in CRI container log.
@@ -101,9 +99,9 @@ func ReadLogs(opts *LogViewOptions, stdout, stderr io.Writer, stopChannel chan o
 	}()
 
 	// Search start point based on tail line.
-	start, err := tail.FindTailLineStartIndex(f, opts.Tail)
+	start, err := findTailStart(f, opts.Tail)
 	if err != nil {
-		return fmt.Errorf("failed to tail %d lines of log file %q: %v", opts.Tail, logPath, err)
+		return fmt.Errorf("failed to tail the lines of log file %q: %v", logPath, err)
 	}
 
 	if _, err := f.Seek(start, io.SeekStart); err != nil {
@@ -112,8 +110,11 @@ func ReadLogs(opts *LogViewOptions, stdout, stderr io.Writer, stopChannel chan o
 
 	var watcher *fsnotify.Watcher
 
-	limitedMode := (opts.Tail > 0) && (!opts.Follow)
-	limitedNum := opts.Tail
+	limitedMode := (opts.Tail != nil) && (!opts.Follow)
+	var limitedNum uint
+	if limitedMode {
+		limitedNum = *opts.Tail
+	}
 	// Start parsing the logs.
 	r := bufio.NewReader(f)
 
@@ -55,7 +55,6 @@ func TestReadLogs(t *testing.T) {
 			name: "default log options should output all lines",
 			logViewOptions: LogViewOptions{
 				LogPath: file.Name(),
-				Tail:    0,
 			},
 			expected: "line1\nline2\nline3\n",
 		},
@@ -63,7 +62,7 @@ func TestReadLogs(t *testing.T) {
 			name: "using Tail 2 should output last 2 lines",
 			logViewOptions: LogViewOptions{
 				LogPath: file.Name(),
-				Tail:    2,
+				Tail:    tailLines(2),
 			},
 			expected: "line2\nline3\n",
 		},
@@ -71,17 +70,17 @@ func TestReadLogs(t *testing.T) {
 			name: "using Tail 4 should output all lines when the log has less than 4 lines",
 			logViewOptions: LogViewOptions{
 				LogPath: file.Name(),
-				Tail:    4,
+				Tail:    tailLines(4),
 			},
 			expected: "line1\nline2\nline3\n",
 		},
 		{
-			name: "using Tail 0 should output all",
+			name: "using Tail 0 should output nothing",
 			logViewOptions: LogViewOptions{
 				LogPath: file.Name(),
-				Tail:    0,
+				Tail:    tailLines(0),
 			},
-			expected: "line1\nline2\nline3\n",
+			expected: "",
 		},
 	}
 	for _, tc := range testCases {
@@ -202,7 +201,7 @@ func TestReadLogsLimitsWithTimestamps(t *testing.T) {
 	var buf bytes.Buffer
 	w := io.MultiWriter(&buf)
 
-	err = ReadLogs(&LogViewOptions{LogPath: tmpfile.Name(), Tail: 0, Timestamps: true}, w, w, stopChan)
+	err = ReadLogs(&LogViewOptions{LogPath: tmpfile.Name(), Timestamps: true}, w, w, stopChan)
 	if err != nil {
 		t.Errorf("ReadLogs file %s failed %s", tmpfile.Name(), err.Error())
 	}
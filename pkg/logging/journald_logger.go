@@ -17,7 +17,6 @@
 package logging
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -25,12 +24,12 @@ import (
 	"os"
 	"os/exec"
 	"strconv"
+	"strings"
 	"sync"
-	"text/template"
 	"time"
+	"unicode"
 
 	"github.com/coreos/go-systemd/v22/journal"
-	"github.com/docker/cli/templates"
 	timetypes "github.com/docker/docker/api/types/time"
 
 	"github.com/containerd/containerd/v2/core/runtime/v2/logging"
@@ -47,12 +46,72 @@ var JournalDriverLogOpts = []string{
 	Labels,
 }
 
+// journaldBuiltinFields are the fields set by the journald driver on every entry, which cannot be
+// overridden by the "labels" and "env" log-opts.
+var journaldBuiltinFields = []string{
+	"SYSLOG_IDENTIFIER",
+	"CONTAINER_TAG",
+	"CONTAINER_ID",
+	"CONTAINER_ID_FULL",
+	"CONTAINER_NAME",
+	"IMAGE_NAME",
+}
+
 func JournalLogOptsValidate(logOptMap map[string]string) error {
 	for key := range logOptMap {
 		if !strutil.InStringSlice(JournalDriverLogOpts, key) {
 			log.L.Warnf("log-opt %s is ignored for journald log driver", key)
 		}
 	}
+	for _, key := range []string{Labels, Env} {
+		value, ok := logOptMap[key]
+		if !ok {
+			continue
+		}
+		for _, name := range strings.Split(value, ",") {
+			if err := validateJournalFieldName(name); err != nil {
+				return fmt.Errorf("invalid log-opt %s=%s: %w", key, value, err)
+			}
+		}
+	}
+	return nil
+}
+
+// journalFieldName returns the journal field name for the label or the environment variable name,
+// as Docker does: the letters are upper-cased, the other characters than letters and digits are
+// replaced with underscores, and the leading underscores (reserved for trusted fields) are removed.
+func journalFieldName(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case 'a' <= r && r <= 'z':
+			r = unicode.ToUpper(r)
+		case 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+		default:
+			r = '_'
+		}
+		if b.Len() == 0 && r == '_' {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// validateJournalFieldName checks that the label or the environment variable name can be used as a journal field.
+// See https://www.freedesktop.org/software/systemd/man/latest/systemd.journal-fields.html
+func validateJournalFieldName(name string) error {
+	field := journalFieldName(name)
+	switch {
+	case field == "":
+		return fmt.Errorf("%q is not a valid journal field name", name)
+	case field[0] >= '0' && field[0] <= '9':
+		return fmt.Errorf("journal field name %q of %q must not start with a digit", field, name)
+	case len(field) > 64:
+		return fmt.Errorf("journal field name %q of %q must not be longer than 64 characters", field, name)
+	case strutil.InStringSlice(journaldBuiltinFields, field):
+		return fmt.Errorf("journal field name %q of %q is reserved by the journald log driver", field, name)
+	}
 	return nil
 }
 
@@ -62,12 +121,6 @@ type JournaldLogger struct {
 	Address string
 }
 
-type identifier struct {
-	ID        string
-	FullID    string
-	Namespace string
-}
-
 func (journaldLogger *JournaldLogger) Init(dataStore, ns, id string) error {
 	return nil
 }
@@ -76,30 +129,10 @@ func (journaldLogger *JournaldLogger) PreProcess(ctx context.Context, dataStore
 	if !journal.Enabled() {
 		return errors.New("the local systemd journal is not available for logging")
 	}
-	shortID := config.ID[:12]
-	var syslogIdentifier string
-	if _, ok := journaldLogger.Opts[Tag]; !ok {
-		syslogIdentifier = shortID
-	} else {
-		var tmpl *template.Template
-		var err error
-		tmpl, err = templates.Parse(journaldLogger.Opts[Tag])
-		if err != nil {
-			return err
-		}
-
-		if tmpl != nil {
-			idn := identifier{
-				ID:        shortID,
-				FullID:    config.ID,
-				Namespace: config.Namespace,
-			}
-			var b bytes.Buffer
-			if err := tmpl.Execute(&b, idn); err != nil {
-				return err
-			}
-			syslogIdentifier = b.String()
-		}
+	// The "tag" log-opt is already rendered by renderTagOpt
+	syslogIdentifier := shortID(config.ID)
+	if tag, ok := journaldLogger.Opts[Tag]; ok {
+		syslogIdentifier = tag
 	}
 
 	client, ctx, cancel, err := clientutil.NewClient(ctx, config.Namespace, journaldLogger.Address)
@@ -128,11 +161,39 @@ func (journaldLogger *JournaldLogger) PreProcess(ctx context.Context, dataStore
 	vars := map[string]string{
 		"SYSLOG_IDENTIFIER": syslogIdentifier,
 		"CONTAINER_TAG":     syslogIdentifier,
-		"CONTAINER_ID":      shortID,
+		"CONTAINER_ID":      shortID(containerID),
 		"CONTAINER_ID_FULL": containerID,
 		"CONTAINER_NAME":    containerutil.GetContainerName(containerLabels),
 		"IMAGE_NAME":        containerInfo.Image,
 	}
+
+	// add the extra fields of the "labels" and "env" log-opts, skipping the labels and the variables that are not set
+	if labelNames, ok := journaldLogger.Opts[Labels]; ok {
+		for _, name := range strings.Split(labelNames, ",") {
+			if value, ok := containerLabels[name]; ok {
+				vars[journalFieldName(name)] = value
+			}
+		}
+	}
+	if envNames, ok := journaldLogger.Opts[Env]; ok {
+		spec, err := container.Spec(ctx)
+		if err != nil {
+			return err
+		}
+		env := make(map[string]string)
+		if spec.Process != nil {
+			for _, kv := range spec.Process.Env {
+				if k, v, ok := strings.Cut(kv, "="); ok {
+					env[k] = v
+				}
+			}
+		}
+		for _, name := range strings.Split(envNames, ",") {
+			if value, ok := env[name]; ok {
+				vars[journalFieldName(name)] = value
+			}
+		}
+	}
 	journaldLogger.vars = vars
 	return nil
 }
@@ -199,11 +260,14 @@ func viewLogsJournald(lvopts LogViewOptions, stdout, stderr io.Writer, stopChann
 	if !checkExecutableAvailableInPath("journalctl") {
 		return fmt.Errorf("`journalctl` executable could not be found in PATH, cannot use Journald to view logs")
 	}
-	shortID := lvopts.ContainerID[:12]
-	var journalctlArgs = []string{fmt.Sprintf("SYSLOG_IDENTIFIER=%s", shortID), "--output=cat"}
+	// The entries are matched with CONTAINER_ID, as SYSLOG_IDENTIFIER is changed by the "tag" log-opt
+	var journalctlArgs = []string{fmt.Sprintf("CONTAINER_ID=%s", shortID(lvopts.ContainerID)), "--output=cat"}
 	if lvopts.Follow {
 		journalctlArgs = append(journalctlArgs, "-f")
 	}
+	if lvopts.Tail != nil {
+		journalctlArgs = append(journalctlArgs, fmt.Sprintf("--lines=%d", *lvopts.Tail))
+	}
 	if lvopts.Since != "" {
 		// using GetTimestamp from moby to keep time format consistency
 		ts, err := timetypes.GetTimestamp(lvopts.Since, time.Now())
@@ -0,0 +1,71 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestJournalFieldName(t *testing.T) {
+	for name, expected := range map[string]string{
+		"APP_VERSION":      "APP_VERSION",
+		"com.example.team": "COM_EXAMPLE_TEAM",
+		"_private":         "PRIVATE",
+		"--":               "",
+		"x-1":              "X_1",
+	} {
+		assert.Equal(t, journalFieldName(name), expected, name)
+	}
+}
+
+func TestJournalLogOptsValidate(t *testing.T) {
+	testCases := []struct {
+		opts map[string]string
+		err  string
+	}{
+		{
+			opts: map[string]string{Labels: "com.example.team,com.example.app", Env: "APP_VERSION"},
+		},
+		{
+			opts: map[string]string{Labels: "com.example.team,"},
+			err:  `invalid log-opt labels=com.example.team,: "" is not a valid journal field name`,
+		},
+		{
+			opts: map[string]string{Env: "1VAR"},
+			err:  `invalid log-opt env=1VAR: journal field name "1VAR" of "1VAR" must not start with a digit`,
+		},
+		{
+			opts: map[string]string{Env: strings.Repeat("A", 65)},
+			err:  "must not be longer than 64 characters",
+		},
+		{
+			opts: map[string]string{Labels: "container.name"},
+			err:  `invalid log-opt labels=container.name: journal field name "CONTAINER_NAME" of "container.name" is reserved by the journald log driver`,
+		},
+	}
+	for _, tc := range testCases {
+		err := JournalLogOptsValidate(tc.opts)
+		if tc.err == "" {
+			assert.NilError(t, err)
+		} else {
+			assert.ErrorContains(t, err, tc.err)
+		}
+	}
+}
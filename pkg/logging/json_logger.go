@@ -35,7 +35,6 @@ import (
 
 	"github.com/containerd/nerdctl/v2/pkg/internal/filesystem"
 	"github.com/containerd/nerdctl/v2/pkg/logging/jsonfile"
-	"github.com/containerd/nerdctl/v2/pkg/logging/tail"
 	"github.com/containerd/nerdctl/v2/pkg/strutil"
 )
 
@@ -43,6 +42,7 @@ var JSONDriverLogOpts = []string{
 	LogPath,
 	MaxSize,
 	MaxFile,
+	Tag,
 	Env,
 	Labels,
 }
@@ -161,17 +161,20 @@ func viewLogsJSONFileDirect(lvopts LogViewOptions, jsonLogFilePath string, stdou
 	defer func() { fin.Close() }()
 
 	// Search start point based on tail line.
-	start, err := tail.FindTailLineStartIndex(fin, lvopts.Tail)
+	start, err := findTailStart(fin, lvopts.Tail)
 	if err != nil {
-		return fmt.Errorf("failed to tail %d lines of JSON logfile %q: %w", lvopts.Tail, jsonLogFilePath, err)
+		return fmt.Errorf("failed to tail the lines of JSON logfile %q: %w", jsonLogFilePath, err)
 	}
 
 	if _, err := fin.Seek(start, io.SeekStart); err != nil {
 		return fmt.Errorf("failed to seek in log file %q from %d position: %w", jsonLogFilePath, start, err)
 	}
 
-	limitedMode := (lvopts.Tail > 0) && (!lvopts.Follow)
-	limitedNum := lvopts.Tail
+	limitedMode := (lvopts.Tail != nil) && (!lvopts.Follow)
+	var limitedNum uint
+	if limitedMode {
+		limitedNum = *lvopts.Tail
+	}
 	var stop bool
 	var watcher *fsnotify.Watcher
 	baseName := filepath.Base(jsonLogFilePath)
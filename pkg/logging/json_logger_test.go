@@ -109,6 +109,10 @@ func TestReadRotatedJSONLog(t *testing.T) {
 	}
 }
 
+func tailLines(n uint) *uint {
+	return &n
+}
+
 func TestReadJSONLogs(t *testing.T) {
 	file, err := os.CreateTemp("", "TestFollowLogs")
 	if err != nil {
@@ -129,7 +133,6 @@ func TestReadJSONLogs(t *testing.T) {
 			name: "default log options should output all lines",
 			logViewOptions: LogViewOptions{
 				LogPath: file.Name(),
-				Tail:    0,
 			},
 			expected: "line1\nline2\nline3\n",
 		},
@@ -137,7 +140,7 @@ func TestReadJSONLogs(t *testing.T) {
 			name: "using Tail 2 should output last 2 lines",
 			logViewOptions: LogViewOptions{
 				LogPath: file.Name(),
-				Tail:    2,
+				Tail:    tailLines(2),
 			},
 			expected: "line2\nline3\n",
 		},
@@ -145,17 +148,17 @@ func TestReadJSONLogs(t *testing.T) {
 			name: "using Tail 4 should output all lines when the log has less than 4 lines",
 			logViewOptions: LogViewOptions{
 				LogPath: file.Name(),
-				Tail:    4,
+				Tail:    tailLines(4),
 			},
 			expected: "line1\nline2\nline3\n",
 		},
 		{
-			name: "using Tail 0 should output all",
+			name: "using Tail 0 should output nothing",
 			logViewOptions: LogViewOptions{
 				LogPath: file.Name(),
-				Tail:    0,
+				Tail:    tailLines(0),
 			},
-			expected: "line1\nline2\nline3\n",
+			expected: "",
 		},
 	}
 	for _, tc := range testCases {
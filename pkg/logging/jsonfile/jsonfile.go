@@ -39,8 +39,14 @@ type Entry struct {
 }
 
 func Path(dataStore, ns, id string) string {
+	return filepath.Join(dataStore, "containers", ns, id, Filename(id))
+}
+
+// Filename returns the base name of the json-file log of the container,
+// relative to the container state directory.
+func Filename(id string) string {
 	// the file name corresponds to Docker
-	return filepath.Join(dataStore, "containers", ns, id, id+"-json.log")
+	return id + "-json.log"
 }
 
 func Encode(stdout <-chan string, stderr <-chan string, writer io.Writer) error {
@@ -76,8 +76,8 @@ type LogViewOptions struct {
 	// Whether or not to print timestampts for each line.
 	Timestamps bool
 
-	// Uint representing the number of most recent log entries to display. 0 = "all".
-	Tail uint
+	// Number of most recent log entries to display. nil = "all", 0 = none.
+	Tail *uint
 
 	// Start/end timestampts to filter logs by.
 	Since string
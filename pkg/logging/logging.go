@@ -26,19 +26,28 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/muesli/cancelreader"
 
+	eventstypes "github.com/containerd/containerd/api/events"
 	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/runtime/restart"
 	"github.com/containerd/containerd/v2/core/runtime/v2/logging"
 	"github.com/containerd/errdefs"
 	"github.com/containerd/log"
+	"github.com/containerd/typeurl/v2"
 
+	"github.com/containerd/nerdctl/v2/pkg/containerinspector"
+	"github.com/containerd/nerdctl/v2/pkg/containerutil"
 	"github.com/containerd/nerdctl/v2/pkg/internal/filesystem"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/logging/tail"
 )
 
 const (
@@ -66,6 +75,11 @@ var drivers = make(map[string]DriverFactory)
 var driversLogOptsValidateFunctions = make(map[string]LogOptsValidateFunc)
 
 func ValidateLogOpts(logDriver string, logOpts map[string]string) error {
+	if tagTemplate, ok := logOpts[Tag]; ok {
+		if err := ValidateTag(tagTemplate); err != nil {
+			return err
+		}
+	}
 	if value, ok := driversLogOptsValidateFunctions[logDriver]; ok && value != nil {
 		return value(logOpts)
 	}
@@ -177,7 +191,7 @@ func getContainerWait(ctx context.Context, address string, config *logging.Confi
 
 	task, err := con.Task(ctx, nil)
 	if err == nil {
-		return task.Wait(ctx)
+		return waitTask(ctx, client, con, task)
 	}
 	if !errdefs.IsNotFound(err) {
 		return nil, err
@@ -200,11 +214,150 @@ func getContainerWait(ctx context.Context, address string, config *logging.Confi
 				}
 				return nil, err
 			}
-			return task.Wait(ctx)
+			return waitTask(ctx, client, con, task)
 		}
 	}
 }
 
+// waitTask waits for the task like task.Wait. When the task exits, it records whether the OOM killer killed
+// a process of the container (labels.OOMKilled), applies the exit codes of the `on-failure` restart policy
+// of the container (labels.RestartExitCodes), and records the backoff of its restart (labels.RestartNextStart).
+func waitTask(ctx context.Context, client *containerd.Client, con containerd.Container, task containerd.Task) (<-chan containerd.ExitStatus, error) {
+	oomKilled, stopWatchingOOM := watchOOM(ctx, client, con.ID())
+	exitCh, err := task.Wait(ctx)
+	if err != nil {
+		stopWatchingOOM()
+		return nil, err
+	}
+	// The label describes the last run of the container, so reset it for this run.
+	if err := updateOOMKilledLabel(ctx, con, false); err != nil {
+		log.G(ctx).WithError(err).Warn("failed to reset the OOMKilled label")
+	}
+	ch := make(chan containerd.ExitStatus, 1)
+	go func() {
+		defer close(ch)
+		exitStatus, ok := <-exitCh
+		if !ok {
+			stopWatchingOOM()
+			return
+		}
+		killed := taskOOMKilled(ctx, con, oomKilled)
+		stopWatchingOOM()
+		if err := updateOOMKilledLabel(ctx, con, killed); err != nil {
+			log.G(ctx).WithError(err).Warn("failed to record whether the container was OOM killed")
+		}
+		if err := applyRestartExitCodes(ctx, con, exitStatus.ExitCode()); err != nil {
+			log.G(ctx).WithError(err).Warn("failed to apply the exit codes of the restart policy")
+		}
+		if err := containerutil.RecordRestartBackoff(ctx, con); err != nil {
+			log.G(ctx).WithError(err).Warn("failed to record the restart backoff")
+		}
+		ch <- exitStatus
+	}()
+	return ch, nil
+}
+
+// watchOOM subscribes to the OOM events of the container, and returns a function that reports
+// whether one was received, and a function that cancels the subscription.
+func watchOOM(ctx context.Context, client *containerd.Client, id string) (func() bool, context.CancelFunc) {
+	var oomKilled atomic.Bool
+	ctx, cancel := context.WithCancel(ctx)
+	eventsCh, errCh := client.EventService().Subscribe(ctx, `topic=="/tasks/oom"`)
+	go func() {
+		for {
+			select {
+			case e := <-eventsCh:
+				v, err := typeurl.UnmarshalAny(e.Event)
+				if err != nil {
+					log.G(ctx).WithError(err).Warn("failed to unmarshal the OOM event")
+					continue
+				}
+				if oom, ok := v.(*eventstypes.TaskOOM); ok && oom.ContainerID == id {
+					oomKilled.Store(true)
+				}
+			case err := <-errCh:
+				if err != nil && ctx.Err() == nil {
+					log.G(ctx).WithError(err).Warn("failed to watch the OOM events")
+				}
+				return
+			}
+		}
+	}()
+	return oomKilled.Load, cancel
+}
+
+// taskOOMKilled reports whether the OOM killer killed a process of the exited task.
+// The cgroup of the task is read too, as the OOM event may not have been delivered yet.
+// The cgroup may not exist anymore (e.g., the systemd cgroup driver removes the scope of the task
+// as soon as it exits), so the event is the primary source.
+func taskOOMKilled(ctx context.Context, con containerd.Container, oomEventReceived func() bool) bool {
+	if oomEventReceived() {
+		return true
+	}
+	spec, err := con.Spec(ctx)
+	if err != nil {
+		log.G(ctx).WithError(err).Debug("failed to get the spec of the container")
+		return oomEventReceived()
+	}
+	oomKilled, err := containerinspector.InspectOOMKilled(spec)
+	if err != nil {
+		log.G(ctx).WithError(err).Debug("failed to read the OOM kill count of the container")
+	}
+	return oomKilled || oomEventReceived()
+}
+
+// updateOOMKilledLabel records whether a process of the last run of the container was OOM killed.
+func updateOOMKilledLabel(ctx context.Context, con containerd.Container, oomKilled bool) error {
+	_, err := con.SetLabels(ctx, map[string]string{labels.OOMKilled: strconv.FormatBool(oomKilled)})
+	return err
+}
+
+// applyRestartExitCodes prevents the restart monitor of containerd from restarting a container that exited
+// with a code that its `on-failure` restart policy does not restart on, by setting its desired status to stopped.
+// The monitor only reconciles the containers periodically, so this is done as soon as the task exits.
+func applyRestartExitCodes(ctx context.Context, con containerd.Container, exitCode uint32) error {
+	containerLabels, err := con.Labels(ctx)
+	if err != nil {
+		return err
+	}
+	codes, ok := containerLabels[labels.RestartExitCodes]
+	if !ok || exitCode == 0 || containerLabels[restart.StatusLabel] != string(containerd.Running) {
+		return nil
+	}
+	for _, code := range strings.Split(codes, ",") {
+		if code == strconv.FormatUint(uint64(exitCode), 10) {
+			return nil
+		}
+	}
+	log.G(ctx).Infof("container exited with code %d, which is not one of the exit codes to restart on (%s)", exitCode, codes)
+	_, err = con.SetLabels(ctx, map[string]string{restart.StatusLabel: string(containerd.Stopped)})
+	return err
+}
+
+// renderTagOpt expands the template of the "tag" log-opt in place, so that all the drivers can use the rendered tag.
+// The template is expanded here rather than on creating the container, as the container ID is not known until then.
+func renderTagOpt(ctx context.Context, address string, config *logging.Config, opts map[string]string) error {
+	tagTemplate, ok := opts[Tag]
+	if !ok || !strings.Contains(tagTemplate, "{{") {
+		return nil
+	}
+	client, err := containerd.New(strings.TrimPrefix(address, "unix://"), containerd.WithDefaultNamespace(config.Namespace))
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	con, err := client.LoadContainer(ctx, config.ID)
+	if err != nil {
+		return err
+	}
+	tagInfo, err := NewTagInfo(ctx, con)
+	if err != nil {
+		return err
+	}
+	opts[Tag], err = RenderTag(tagTemplate, tagInfo)
+	return err
+}
+
 type ContainerWaitFunc func(ctx context.Context, address string, config *logging.Config) (<-chan containerd.ExitStatus, error)
 
 func loggingProcessAdapter(ctx context.Context, driver Driver, dataStore, address string, getContainerWait ContainerWaitFunc, config *logging.Config) error {
@@ -299,6 +452,9 @@ func loggerFunc(dataStore string) (logging.LoggerFunc, error) {
 			if err != nil {
 				return err
 			}
+			if err := renderTagOpt(ctx, logConfig.Address, config, logConfig.Opts); err != nil {
+				return err
+			}
 			driver, err := GetDriver(logConfig.Driver, logConfig.Opts, logConfig.Address)
 			if err != nil {
 				return err
@@ -336,6 +492,18 @@ func NewLogFileWatcher(dir string) (*fsnotify.Watcher, error) {
 	return watcher, nil
 }
 
+// findTailStart returns the offset to start reading the log file f from, to show its last n lines.
+// A nil n shows all the lines, and 0 shows none of them (only the new ones, when following the logs).
+func findTailStart(f io.ReadSeeker, n *uint) (int64, error) {
+	if n == nil {
+		return 0, nil
+	}
+	if *n == 0 {
+		return f.Seek(0, io.SeekEnd)
+	}
+	return tail.FindTailLineStartIndex(f, *n)
+}
+
 // startTail wait for the next log write.
 // the boolean value indicates if the log file was recreated;
 // the error is error happens during waiting new logs.
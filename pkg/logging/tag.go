@@ -0,0 +1,101 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/docker/cli/templates"
+
+	containerd "github.com/containerd/containerd/v2/client"
+
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+)
+
+// DefaultTagTemplate is the template of the "tag" log-opt used when not specified.
+const DefaultTagTemplate = "{{.ID}}"
+
+// TagInfo is the information available in the template of the "tag" log-opt.
+// The fields are compatible with Docker.
+// https://docs.docker.com/engine/logging/log_tags/
+type TagInfo struct {
+	ID          string // The first 12 characters of the container ID
+	FullID      string // The full container ID
+	Name        string // The container name
+	ImageID     string // The first 12 characters of the container's image ID
+	ImageFullID string // The container's full image ID
+	ImageName   string // The name of the image used by the container
+	DaemonName  string // The name of the daemon, always "nerdctl"
+	Namespace   string // The containerd namespace of the container
+}
+
+// NewTagInfo returns the TagInfo of the container.
+func NewTagInfo(ctx context.Context, container containerd.Container) (*TagInfo, error) {
+	info, err := container.Info(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id := container.ID()
+	tagInfo := &TagInfo{
+		ID:         shortID(id),
+		FullID:     id,
+		Name:       info.Labels[labels.Name],
+		ImageName:  info.Image,
+		DaemonName: "nerdctl",
+		Namespace:  info.Labels[labels.Namespace],
+	}
+	// The image may have been already removed
+	if image, err := container.Image(ctx); err == nil {
+		tagInfo.ImageFullID = image.Target().Digest.String()
+		tagInfo.ImageID = shortID(image.Target().Digest.Encoded())
+	}
+	return tagInfo, nil
+}
+
+// ValidateTag returns an error if tagTemplate is not a valid template.
+func ValidateTag(tagTemplate string) error {
+	if _, err := templates.Parse(tagTemplate); err != nil {
+		return fmt.Errorf("invalid log-opt %s %q: %w", Tag, tagTemplate, err)
+	}
+	return nil
+}
+
+// RenderTag expands the template of the "tag" log-opt with info.
+// DefaultTagTemplate is used when tagTemplate is empty.
+func RenderTag(tagTemplate string, info *TagInfo) (string, error) {
+	if tagTemplate == "" {
+		tagTemplate = DefaultTagTemplate
+	}
+	tmpl, err := templates.Parse(tagTemplate)
+	if err != nil {
+		return "", fmt.Errorf("invalid log-opt %s %q: %w", Tag, tagTemplate, err)
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, info); err != nil {
+		return "", fmt.Errorf("failed to render log-opt %s %q: %w", Tag, tagTemplate, err)
+	}
+	return b.String(), nil
+}
+
+func shortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
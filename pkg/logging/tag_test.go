@@ -0,0 +1,61 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestRenderTag(t *testing.T) {
+	info := &TagInfo{
+		ID:          "0123456789ab",
+		FullID:      "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+		Name:        "web",
+		ImageID:     "fedcba987654",
+		ImageFullID: "sha256:fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210",
+		ImageName:   "docker.io/library/nginx:alpine",
+		DaemonName:  "nerdctl",
+		Namespace:   "default",
+	}
+
+	tag, err := RenderTag("", info)
+	assert.NilError(t, err)
+	assert.Equal(t, tag, "0123456789ab")
+
+	tag, err = RenderTag("{{.DaemonName}}/{{.Name}}/{{.ImageName}}", info)
+	assert.NilError(t, err)
+	assert.Equal(t, tag, "nerdctl/web/docker.io/library/nginx:alpine")
+
+	tag, err = RenderTag("{{.Namespace}}.{{.FullID}}.{{.ImageID}}", info)
+	assert.NilError(t, err)
+	assert.Equal(t, tag, "default.0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef.fedcba987654")
+
+	tag, err = RenderTag("static-tag", info)
+	assert.NilError(t, err)
+	assert.Equal(t, tag, "static-tag")
+
+	_, err = RenderTag("{{.Name", info)
+	assert.ErrorContains(t, err, "invalid log-opt tag")
+
+	_, err = RenderTag("{{.NoSuchField}}", info)
+	assert.ErrorContains(t, err, "failed to render")
+
+	assert.ErrorContains(t, ValidateTag("{{.Name"), "invalid log-opt tag")
+	assert.NilError(t, ValidateTag("{{.Name}}"))
+}
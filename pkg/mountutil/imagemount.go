@@ -0,0 +1,149 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package mountutil
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/moby/sys/userns"
+	"github.com/opencontainers/image-spec/identity"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/leases"
+	"github.com/containerd/containerd/v2/core/mount"
+	"github.com/containerd/errdefs"
+
+	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
+	"github.com/containerd/nerdctl/v2/pkg/strutil"
+)
+
+// ImageMountRecord records the rootfs of an image mounted on the host for an image mount of a container
+// (--mount type=image), so that it can be mounted again when the container is started, and unmounted
+// when the container is removed.
+//
+// The rootfs is a read-only snapshot view, referenced by the container (see GCLabel) so that it is
+// garbage collected with the container.
+type ImageMountRecord struct {
+	// Image is the reference of the image, pinned to its digest.
+	Image string
+	// Snapshotter is the snapshotter of the view.
+	Snapshotter string
+	// Mountpoint is the host path the view is mounted on. It is also the key of the view.
+	Mountpoint string
+}
+
+// GCLabel returns the label of the container referencing the view of its i-th image mount.
+func (r ImageMountRecord) GCLabel(i int) (string, string) {
+	return "containerd.io/gc.ref.snapshot." + r.Snapshotter + "/image-mount-" + strconv.Itoa(i), r.Mountpoint
+}
+
+// MountImage creates a read-only view of the rootfs of img, and mounts it on mountpoint.
+// The view is held by a lease of 1 hour, so that it is not garbage collected before the container
+// referencing it is created. The view is removed when the lease expires if the container is not created.
+func MountImage(ctx context.Context, client *containerd.Client, img containerd.Image, snapshotter, mountpoint string) (*ImageMountRecord, error) {
+	if err := img.Unpack(ctx, snapshotter); err != nil {
+		return nil, fmt.Errorf("error unpacking image: %w", err)
+	}
+	diffIDs, err := img.RootFS(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ctx, _, err = client.WithLease(ctx, leases.WithRandomID(), leases.WithExpiration(1*time.Hour))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create lease: %w", err)
+	}
+	s := client.SnapshotService(snapshotter)
+	mounts, err := s.View(ctx, mountpoint, identity.ChainID(diffIDs).String())
+	if err != nil {
+		return nil, err
+	}
+	if err := mountSnapshot(mounts, mountpoint); err != nil {
+		if rmErr := s.Remove(ctx, mountpoint); rmErr != nil && !errdefs.IsNotFound(rmErr) {
+			return nil, rmErr
+		}
+		return nil, err
+	}
+	return &ImageMountRecord{Image: pinnedImageRef(img), Snapshotter: snapshotter, Mountpoint: mountpoint}, nil
+}
+
+// pinnedImageRef returns the reference of img pinned to its digest, e.g. "docker.io/library/alpine@sha256:...".
+func pinnedImageRef(img containerd.Image) string {
+	parsed, err := referenceutil.Parse(img.Name())
+	if err != nil || parsed.Protocol != "" {
+		return img.Name()
+	}
+	return parsed.Name() + "@" + img.Target().Digest.String()
+}
+
+// Remount mounts the view again if it is not mounted, e.g. after a reboot.
+func (r ImageMountRecord) Remount(ctx context.Context, client *containerd.Client) error {
+	if info, err := mount.Lookup(r.Mountpoint); err == nil && info.Mountpoint == filepath.Clean(r.Mountpoint) {
+		return nil
+	}
+	mounts, err := client.SnapshotService(r.Snapshotter).Mounts(ctx, r.Mountpoint)
+	if err != nil {
+		return err
+	}
+	return mountSnapshot(mounts, r.Mountpoint)
+}
+
+// Unmount unmounts the view. The view itself is removed by the garbage collection once the container
+// referencing it is removed.
+func (r ImageMountRecord) Unmount() error {
+	if err := mount.UnmountAll(r.Mountpoint, 0); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Remove unmounts the view and removes it, without waiting for the expiration of its lease.
+// It must only be called for a view that is not referenced by a container yet.
+func (r ImageMountRecord) Remove(ctx context.Context, client *containerd.Client) error {
+	if err := r.Unmount(); err != nil {
+		return err
+	}
+	if err := client.SnapshotService(r.Snapshotter).Remove(ctx, r.Mountpoint); err != nil && !errdefs.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// mountSnapshot mounts the mounts of a snapshot on target, creating target if needed.
+func mountSnapshot(mounts []mount.Mount, target string) error {
+	if err := os.MkdirAll(target, 0o700); err != nil {
+		return err
+	}
+	for _, m := range mounts {
+		if m.Type == "bind" && userns.RunningInUserNS() {
+			// For https://github.com/containerd/nerdctl/issues/2056
+			unpriv, err := UnprivilegedMountFlags(m.Source)
+			if err != nil {
+				return err
+			}
+			m.Options = strutil.DedupeStrSlice(append(m.Options, unpriv...))
+		}
+		if err := m.Mount(target); err != nil {
+			return fmt.Errorf("failed to mount %+v on %q: %w", m, target, err)
+		}
+	}
+	return nil
+}
@@ -17,12 +17,17 @@
 package mountutil
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
+	"strconv"
 	"strings"
 
+	securejoin "github.com/cyphar/filepath-securejoin"
 	"github.com/moby/sys/userns"
 	"github.com/opencontainers/runtime-spec/specs-go"
 
@@ -31,8 +36,10 @@ import (
 
 	"github.com/containerd/nerdctl/v2/pkg/identifiers"
 	"github.com/containerd/nerdctl/v2/pkg/idgen"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
 	"github.com/containerd/nerdctl/v2/pkg/mountutil/volumestore"
 	"github.com/containerd/nerdctl/v2/pkg/strutil"
+	"github.com/containerd/nerdctl/v2/pkg/volumedriver"
 )
 
 const (
@@ -40,6 +47,7 @@ const (
 	Volume        = "volume"
 	Tmpfs         = "tmpfs"
 	Npipe         = "npipe"
+	Image         = "image"
 	pathSeparator = string(os.PathSeparator)
 )
 
@@ -50,6 +58,22 @@ type Processed struct {
 	AnonymousVolume string // anonymous volume name
 	Mode            string
 	Opts            []oci.SpecOpts
+	// Replace is set by the "replace" option of --mount, to override the other mounts sharing the destination
+	Replace bool
+	// Driver is the volume driver of a named volume, empty for the local driver
+	Driver string
+	// DriverMountID is the ID of the mount of the volume by its driver, to be passed back when unmounting
+	DriverMountID string
+	// DriverMountpoint is the host path returned by the driver, before applying the subpath of the mount
+	DriverMountpoint string
+	// Subpath is the path in the volume of a volume mount, or in the rootfs of the image of an image mount,
+	// relative to the volume or the rootfs
+	Subpath string
+	// ImageMount is the rootfs of the image of an image mount mounted on the host, set once it is mounted
+	ImageMount *ImageMountRecord
+	// RecursiveReadOnlyRequired is set by bind-recursive=readonly, for the mount to fail rather than
+	// falling back to a non-recursive read-only mount when the runtime does not support "rro"
+	RecursiveReadOnlyRequired bool
 }
 
 type volumeSpec struct {
@@ -57,21 +81,60 @@ type volumeSpec struct {
 	Name            string
 	Source          string
 	AnonymousVolume string
+	Driver          string
+	DriverMountID   string
 }
 
 func ProcessFlagV(s string, volStore volumestore.VolumeStore, createDir bool) (*Processed, error) {
+	split, err := splitVolumeSpec(s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to split volume mount specification: %v", err)
+	}
+	return processVolumeSpec(s, split, volStore, createDir, "")
+}
+
+// FlagVDestination returns the destination of the -v specification s, without processing it,
+// e.g. to check the destinations for collisions before any volume is created.
+func FlagVDestination(s string) (string, error) {
+	split, err := splitVolumeSpec(s)
+	if err != nil {
+		return "", fmt.Errorf("failed to split volume mount specification: %v", err)
+	}
+	if len(split) == 1 {
+		return split[0], nil
+	}
+	return strings.TrimLeft(split[1], ":"), nil
+}
+
+// FlagMountDestination returns the destination of the --mount specification s, and whether it has
+// the "replace" option, without processing it. Invalid fields are left to ProcessFlagMount to report.
+func FlagMountDestination(s string) (dst string, replace bool) {
+	for _, field := range strings.Split(s, ",") {
+		key, value, hasValue := strings.Cut(field, "=")
+		switch strings.ToLower(key) {
+		case "target", "dst", "destination":
+			dst = value
+		case "replace":
+			replace = !hasValue
+			if hasValue {
+				replace, _ = strconv.ParseBool(value)
+			}
+		}
+	}
+	return dst, replace
+}
+
+// processVolumeSpec processes the fields of a volume specification s, split into
+// [destination], [source, destination], or [source, destination, mode].
+// bindRecursive is the bind-recursive option of --mount, empty for -v.
+func processVolumeSpec(s string, split []string, volStore volumestore.VolumeStore, createDir bool, bindRecursive string) (res *Processed, retErr error) {
 	var (
-		res      *Processed
 		volSpec  volumeSpec
 		src, dst string
 		options  []string
+		err      error
 	)
 
-	split, err := splitVolumeSpec(s)
-	if err != nil {
-		return nil, fmt.Errorf("failed to split volume mount specification: %v", err)
-	}
-
 	switch len(split) {
 	case 1:
 		// validate destination
@@ -105,21 +168,37 @@ func ProcessFlagV(s string, volStore volumestore.VolumeStore, createDir bool) (*
 		if err != nil {
 			return nil, err
 		}
+		defer func() {
+			if retErr != nil && volSpec.DriverMountID != "" {
+				if err := ReleaseDriverMount(context.TODO(), &Processed{Name: volSpec.Name, Driver: volSpec.Driver, DriverMountID: volSpec.DriverMountID}); err != nil {
+					log.L.WithError(err).Warnf("failed to unmount volume %q with driver %q", volSpec.Name, volSpec.Driver)
+				}
+			}
+		}()
 
 		src = volSpec.Source
 		res = &Processed{
 			Type:            volSpec.Type,
 			Name:            volSpec.Name,
 			AnonymousVolume: volSpec.AnonymousVolume,
+			Driver:          volSpec.Driver,
+			DriverMountID:   volSpec.DriverMountID,
+		}
+		if volSpec.DriverMountID != "" {
+			res.DriverMountpoint = volSpec.Source
 		}
 
-		// Parse volume options
-		if len(split) == 3 {
-			res.Mode = split[2]
+		// Parse volume options.
+		// Bind mounts without options are parsed too, so that they get the default
+		// propagation ("rprivate" on Linux) like Docker.
+		if len(split) == 3 || res.Type == Bind {
+			if len(split) == 3 {
+				res.Mode = split[2]
+			}
 
 			rawOpts := res.Mode
 
-			options, res.Opts, err = getVolumeOptions(src, res.Type, rawOpts)
+			options, res.Opts, err = getVolumeOptions(src, res.Type, rawOpts, bindRecursive)
 			if err != nil {
 				return nil, err
 			}
@@ -162,6 +241,26 @@ func ProcessFlagV(s string, volStore volumestore.VolumeStore, createDir bool) (*
 	return res, nil
 }
 
+// ReleaseDriverMount unmounts the volume of p mounted by its driver, if any.
+// It must be called when p is discarded before being recorded in the labels of a container
+// (labels.VolumeDriverMounts), as the driver keeps the volume mounted until then.
+func ReleaseDriverMount(ctx context.Context, p *Processed) error {
+	if p.DriverMountID == "" {
+		return nil
+	}
+	return volumedriver.MountRecord{Volume: p.Name, Driver: p.Driver, ID: p.DriverMountID}.Unmount(ctx)
+}
+
+// ReleaseImageMount unmounts the rootfs of the image of p mounted on the host, if any.
+// Like ReleaseDriverMount, it must be called when p is discarded before being recorded in the labels
+// of a container (labels.ImageMounts).
+func ReleaseImageMount(p *Processed) error {
+	if p.ImageMount == nil {
+		return nil
+	}
+	return p.ImageMount.Unmount()
+}
+
 func handleBindMounts(source string, createDir bool) (volumeSpec, error) {
 	var res volumeSpec
 	res.Type = Bind
@@ -177,7 +276,7 @@ func handleBindMounts(source string, createDir bool) (volumeSpec, error) {
 	}
 
 	// Create dir if it does not exist
-	if err := createDirOnHost(source, createDir); err != nil {
+	if err := createDirOnHost(res.Source, createDir); err != nil {
 		return res, err
 	}
 
@@ -199,27 +298,100 @@ func handleAnonymousVolumes(s string, volStore volumestore.VolumeStore) (volumeS
 	return res, nil
 }
 
+// AutoCreatedVolumeLabels returns the labels of the named volumes created on the fly for a container.
+func AutoCreatedVolumeLabels() []string {
+	return []string{labels.AutoCreatedVolume + "=true"}
+}
+
 func handleNamedVolumes(source string, volStore volumestore.VolumeStore) (volumeSpec, error) {
 	var res volumeSpec
 	res.Name = source
 
 	// Create returns an existing volume or creates a new one if necessary.
-	vol, err := volStore.CreateWithoutLock(res.Name, nil)
+	// The labels are only set if the volume is created.
+	vol, err := volStore.CreateWithoutLock(res.Name, AutoCreatedVolumeLabels())
 	if err != nil {
 		return res, fmt.Errorf("failed to get volume %q: %w", res.Name, err)
 	}
-	// src is now an absolute path
 	res.Type = Volume
+	if !volumedriver.IsLocal(vol.Driver) {
+		// The volume is mounted by its driver, which returns the host path
+		// The mount flags are parsed without a context
+		ctx := context.TODO()
+		d, err := volumedriver.Get(ctx, vol.Driver)
+		if err != nil {
+			return res, fmt.Errorf("failed to get the driver of volume %q: %w", res.Name, err)
+		}
+		res.Driver = vol.Driver
+		res.DriverMountID = idgen.GenerateID()
+		if res.Source, err = d.Mount(ctx, res.Name, res.DriverMountID); err != nil {
+			return res, fmt.Errorf("failed to mount volume %q with driver %q: %w", res.Name, res.Driver, err)
+		}
+		return res, nil
+	}
+	// src is now an absolute path
 	res.Source = vol.Mountpoint
 
 	return res, nil
 }
 
-func getVolumeOptions(src string, vType string, rawOpts string) ([]string, []oci.SpecOpts, error) {
+// withVolumeSubpath makes the volume mount use the subpath of the volume as its source,
+// creating the subpath directory in the volume if it does not exist yet.
+// For a read-only mount, the subpath is never created: it has to exist already, as it could not
+// be populated through the mount anyway.
+func withVolumeSubpath(res *Processed, subpath string) error {
+	if res.Type != Volume {
+		return fmt.Errorf("subpath is only supported for volume mounts, got mount type '%s'", res.Type)
+	}
+	if !filepath.IsLocal(subpath) {
+		return fmt.Errorf("invalid subpath %q: must be a relative path that does not escape the volume", subpath)
+	}
+	// SecureJoin resolves the symlinks in the subpath within the volume, so that it cannot escape
+	src, err := securejoin.SecureJoin(res.Mount.Source, subpath)
+	if err != nil {
+		return fmt.Errorf("invalid subpath %q: %w", subpath, err)
+	}
+	if slices.Contains(res.Mount.Options, "ro") {
+		if _, err := os.Stat(src); err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("subpath %q does not exist in volume %q, and is not created for a read-only mount", subpath, res.Name)
+			}
+			return fmt.Errorf("invalid subpath %q: %w", subpath, err)
+		}
+	} else if err := os.MkdirAll(src, 0o755); err != nil {
+		return fmt.Errorf("failed to create subpath %q in volume: %w", subpath, err)
+	}
+	res.Mount.Source = src
+	res.Subpath = filepath.Clean(subpath)
+	return nil
+}
+
+// WithImageSubpath sets the source of the image mount res to its subpath in the rootfs of the image,
+// once the rootfs is mounted on the host (res.ImageMount). The subpath must exist in the rootfs.
+func WithImageSubpath(res *Processed) error {
+	if res.ImageMount == nil {
+		return fmt.Errorf("the rootfs of image %q is not mounted", res.Name)
+	}
+	// SecureJoin resolves the symlinks in the subpath within the rootfs, so that it cannot escape
+	src, err := securejoin.SecureJoin(res.ImageMount.Mountpoint, res.Subpath)
+	if err != nil {
+		return fmt.Errorf("invalid subpath %q: %w", res.Subpath, err)
+	}
+	if _, err := os.Stat(src); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("subpath %q does not exist in image %q", res.Subpath, res.Name)
+		}
+		return fmt.Errorf("invalid subpath %q: %w", res.Subpath, err)
+	}
+	res.Mount.Source = src
+	return nil
+}
+
+func getVolumeOptions(src string, vType string, rawOpts string, bindRecursive string) ([]string, []oci.SpecOpts, error) {
 	// always call parseVolumeOptions for bind mount to allow the parser to add some default options
 	var err error
 	var specOpts []oci.SpecOpts
-	options, specOpts, err := parseVolumeOptions(vType, src, rawOpts)
+	options, specOpts, err := parseVolumeOptions(vType, src, rawOpts, bindRecursive)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to parse volume options (%q, %q, %q): %w", vType, src, rawOpts, err)
 	}
@@ -229,9 +401,13 @@ func getVolumeOptions(src string, vType string, rawOpts string) ([]string, []oci
 }
 
 func createDirOnHost(src string, createDir bool) error {
-	_, err := os.Stat(src)
+	if isPseudoFilesystemPath(src) {
+		return validatePseudoFilesystemSource(src)
+	}
+
+	fi, err := os.Stat(src)
 	if err == nil {
-		return nil
+		return validateBindSource(src, fi)
 	}
 
 	if !createDir {
@@ -259,6 +435,101 @@ func createDirOnHost(src string, createDir bool) error {
 	return nil
 }
 
+// validateBindSource checks that the bind mount source is a directory, a regular file,
+// a device node, a socket, or a named pipe. Sources that are not directories are mounted
+// on a file stub created at the destination by the OCI runtime.
+func validateBindSource(src string, fi os.FileInfo) error {
+	if fi.Mode()&os.ModeIrregular != 0 {
+		return fmt.Errorf("unsupported file type of bind mount source %q: %s", src, fi.Mode().Type())
+	}
+	return nil
+}
+
+// fileKind returns a human-readable kind of fi, e.g., "directory" or "device".
+func fileKind(fi os.FileInfo) string {
+	switch mode := fi.Mode(); {
+	case mode.IsDir():
+		return "directory"
+	case mode&os.ModeDevice != 0:
+		return "device"
+	case mode&os.ModeSocket != 0:
+		return "socket"
+	case mode&os.ModeNamedPipe != 0:
+		return "named pipe"
+	default:
+		return "file"
+	}
+}
+
+// ValidateBindDestination checks that the bind mount of source does not shadow target, the path of
+// the mount destination in the image, with a different kind of file. e.g., a single config file or a device node
+// cannot be mounted onto a directory of the image. Missing paths are not an error, as the OCI runtime creates
+// the destination as a directory or a file stub matching the source.
+func ValidateBindDestination(source, target, destination string) error {
+	srcInfo, err := os.Stat(source)
+	if err != nil {
+		return nil
+	}
+	dstInfo, err := os.Stat(target)
+	if err != nil {
+		return nil
+	}
+	if srcInfo.IsDir() != dstInfo.IsDir() {
+		return fmt.Errorf("cannot bind-mount %s %q onto %s %q of the image", fileKind(srcInfo), source, fileKind(dstInfo), destination)
+	}
+	return nil
+}
+
+// isPseudoFilesystemPath returns true if p is on procfs or sysfs, whose entries are generated by the kernel.
+// Such paths cannot be created on the host, so they must not go through createDirOnHost.
+func isPseudoFilesystemPath(p string) bool {
+	if runtime.GOOS != "linux" {
+		return false
+	}
+	p = filepath.Clean(p)
+	for _, root := range []string{"/proc", "/sys"} {
+		if p == root || strings.HasPrefix(p, root+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// validatePseudoFilesystemSource rejects the procfs/sysfs bind mount sources that cannot be valid:
+// the paths that do not exist, and the paths that refer to the nerdctl process itself ("/proc/self", "/proc/thread-self").
+// The other stat errors (e.g., EACCES on another process in rootless mode) are left to the OCI runtime.
+func validatePseudoFilesystemSource(src string) error {
+	src = filepath.Clean(src)
+	for _, self := range []string{"/proc/self", "/proc/thread-self"} {
+		if src == self || strings.HasPrefix(src, self+"/") {
+			return fmt.Errorf("mount source %q refers to the nerdctl process, not to the container", src)
+		}
+	}
+	// Lstat, not Stat: only the existence of the magic links of procfs (e.g., "/proc/<PID>/ns/net") is checked here,
+	// their targets are resolved by the kernel when the OCI runtime mounts them.
+	if _, err := os.Lstat(src); os.IsNotExist(err) {
+		return fmt.Errorf("mount source %q does not exist (procfs and sysfs paths cannot be created on the host)", src)
+	}
+	return nil
+}
+
+// validateWriteModeOpts returns an error when both read-only ("ro", "rro", "readonly")
+// and read-write ("rw") options are specified for a single mount.
+func validateWriteModeOpts(writeModeOpts []string) error {
+	var readOnly, readWrite bool
+	for _, opt := range writeModeOpts {
+		if opt == "rw" {
+			readWrite = true
+		} else {
+			readOnly = true
+		}
+	}
+	if readOnly && readWrite {
+		return fmt.Errorf("conflicting read-only and read-write options: %+v", writeModeOpts)
+	}
+	return nil
+}
+
 func isNamedVolume(s string) bool {
 	err := identifiers.ValidateDockerCompat(s)
 
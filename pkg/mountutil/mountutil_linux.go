@@ -18,10 +18,12 @@ package mountutil
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -36,6 +38,7 @@ import (
 	"github.com/containerd/log"
 
 	"github.com/containerd/nerdctl/v2/pkg/mountutil/volumestore"
+	"github.com/containerd/nerdctl/v2/pkg/volumedriver"
 )
 
 /*
@@ -91,8 +94,9 @@ func UnprivilegedMountFlags(path string) ([]string, error) {
 
 // parseVolumeOptions parses specified optsRaw with using information of
 // the volume type and the src directory when necessary.
-func parseVolumeOptions(vType, src, optsRaw string) ([]string, []oci.SpecOpts, error) {
-	return parseVolumeOptionsWithMountInfo(vType, src, optsRaw, getMountInfo)
+// bindRecursive is the bind-recursive option of --mount, empty for -v.
+func parseVolumeOptions(vType, src, optsRaw, bindRecursive string) ([]string, []oci.SpecOpts, error) {
+	return parseVolumeOptionsWithMountInfo(vType, src, optsRaw, bindRecursive, getMountInfo)
 }
 
 // getMountInfo gets mount.Info of a directory.
@@ -106,7 +110,7 @@ func getMountInfo(dir string) (mount.Info, error) {
 
 // parseVolumeOptionsWithMountInfo is the testable implementation
 // of parseVolumeOptions.
-func parseVolumeOptionsWithMountInfo(vType, src, optsRaw string, getMountInfoFunc func(string) (mount.Info, error)) ([]string, []oci.SpecOpts, error) {
+func parseVolumeOptionsWithMountInfo(vType, src, optsRaw, bindRecursive string, getMountInfoFunc func(string) (mount.Info, error)) ([]string, []oci.SpecOpts, error) {
 	var (
 		writeModeRawOpts   []string
 		propagationRawOpts []string
@@ -139,12 +143,35 @@ func parseVolumeOptionsWithMountInfo(vType, src, optsRaw string, getMountInfoFun
 		opts = append(opts, bindOpts[0])
 	}
 
+	if err := validateWriteModeOpts(writeModeRawOpts); err != nil {
+		return nil, nil, err
+	}
 	if len(writeModeRawOpts) > 1 {
 		return nil, nil, fmt.Errorf("duplicated read/write volume option: %+v", writeModeRawOpts)
+	} else if len(propagationRawOpts) > 1 {
+		return nil, nil, fmt.Errorf("duplicated volume propagation option: %+v", propagationRawOpts)
 	} else if len(writeModeRawOpts) > 0 {
 		switch writeModeRawOpts[0] {
 		case "ro":
-			opts = append(opts, "ro")
+			if vType != Bind || slices.Contains(bindOpts, "bind") {
+				opts = append(opts, "ro")
+				break
+			}
+			// Like Docker, a read-only recursive bind mount is made recursively read-only when possible
+			var propagation string
+			if len(propagationRawOpts) > 0 {
+				propagation = propagationRawOpts[0]
+			}
+			mode, propagation, err := recursiveReadOnlyBindOptions(src, bindRecursive, propagation)
+			if err != nil {
+				return nil, nil, err
+			}
+			if mode == "rro" {
+				opts = append(opts, "ro", "rro")
+				propagationRawOpts = []string{propagation}
+			} else {
+				opts = append(opts, "ro")
+			}
 		case "rro":
 			// Mount option "rro" is supported since crun v1.4 / runc v1.1 (https://github.com/opencontainers/runc/pull/3272), with kernel >= 5.12.
 			// Older version of runc just ignores "rro", so we have to add "ro" too, to our best effort.
@@ -291,6 +318,14 @@ func ProcessFlagTmpfs(s string) (*Processed, error) {
 	return res, nil
 }
 
+// driverName returns the name of a volume driver, where the empty name is the local driver.
+func driverName(name string) string {
+	if volumedriver.IsLocal(name) {
+		return volumedriver.LocalDriverName
+	}
+	return name
+}
+
 func ProcessFlagMount(s string, volStore volumestore.VolumeStore) (*Processed, error) {
 	fields := strings.Split(s, ",")
 	var (
@@ -299,9 +334,16 @@ func ProcessFlagMount(s string, volStore volumestore.VolumeStore) (*Processed, e
 		dst              string
 		bindPropagation  string
 		bindNonRecursive bool
+		bindRecursive    string
 		rwOption         string
+		rwOptions        []string
 		tmpfsSize        int64
 		tmpfsMode        os.FileMode
+		subpath          string
+		replace          bool
+		volumeDriver     string
+		volumeOpts       = map[string]string{}
+		volumeLabels     []string
 		err              error
 	)
 
@@ -311,8 +353,12 @@ func ProcessFlagMount(s string, volStore volumestore.VolumeStore) (*Processed, e
 
 	// three types of mount(and examples):
 	// --mount type=bind,source="$(pwd)"/target,target=/app2,readonly,bind-propagation=shared
+	// --mount type=bind,source="$(pwd)"/target,target=/app3,readonly,bind-recursive=readonly
 	// --mount type=tmpfs,destination=/app,tmpfs-mode=1770,tmpfs-size=1MB
-	// --mount type=volume,src=vol-1,dst=/app,readonly
+	// --mount type=volume,src=vol-1,dst=/app,readonly,subpath=dir
+	// --mount type=volume,src=vol-2,dst=/app,volume-driver=foo,volume-opt=key=value
+	// --mount type=volume,src=vol-3,dst=/app,volume-label=project=x
+	// --mount type=image,src=alpine@sha256:...,dst=/app,subpath=/usr/bin
 	// if type not specified, default will be set to volume
 	// --mount src=`pwd`/tmp,target=/app
 
@@ -324,10 +370,14 @@ func ProcessFlagMount(s string, volStore volumestore.VolumeStore) (*Processed, e
 			switch key {
 			case "readonly", "ro", "rw", "rro":
 				rwOption = key
+				rwOptions = append(rwOptions, key)
 				continue
 			case "bind-nonrecursive":
 				bindNonRecursive = true
 				continue
+			case "replace":
+				replace = true
+				continue
 			}
 		}
 
@@ -344,8 +394,12 @@ func ProcessFlagMount(s string, volStore volumestore.VolumeStore) (*Processed, e
 			case "bind":
 				mountType = Bind
 			case "volume":
+			case Npipe:
+				return nil, fmt.Errorf("mount type '%s' is only supported on Windows", value)
+			case Image:
+				mountType = Image
 			default:
-				return nil, fmt.Errorf("invalid mount type '%s' must be a volume/bind/tmpfs", value)
+				return nil, fmt.Errorf("invalid mount type '%s' must be a volume/bind/tmpfs/image", value)
 			}
 		case "source", "src":
 			src = value
@@ -358,6 +412,7 @@ func ProcessFlagMount(s string, volStore volumestore.VolumeStore) (*Processed, e
 			}
 			if trueValue {
 				rwOption = key
+				rwOptions = append(rwOptions, key)
 			}
 		case "bind-propagation":
 			// here don't validate the propagation value
@@ -368,6 +423,13 @@ func ProcessFlagMount(s string, volStore volumestore.VolumeStore) (*Processed, e
 			if err != nil {
 				return nil, fmt.Errorf("invalid value for %s: %s", key, value)
 			}
+		case "bind-recursive":
+			switch value {
+			case "enabled", "disabled", "writable", "readonly":
+				bindRecursive = value
+			default:
+				return nil, fmt.Errorf("invalid value for %s: %s (must be enabled, disabled, writable or readonly)", key, value)
+			}
 		case "tmpfs-size":
 			tmpfsSize, err = units.RAMInBytes(value)
 			if err != nil {
@@ -379,11 +441,52 @@ func ProcessFlagMount(s string, volStore volumestore.VolumeStore) (*Processed, e
 				return nil, fmt.Errorf("invalid value for %s: %s", key, value)
 			}
 			tmpfsMode = os.FileMode(ui64)
+		case "subpath", "volume-subpath":
+			subpath = value
+		case "volume-driver":
+			volumeDriver = value
+		case "volume-opt":
+			k, v, ok := strings.Cut(value, "=")
+			if !ok || k == "" {
+				return nil, fmt.Errorf("invalid value for %s: %s (must be key=value)", key, value)
+			}
+			volumeOpts[k] = v
+		case "volume-label":
+			if k, _, ok := strings.Cut(value, "="); !ok || k == "" {
+				return nil, fmt.Errorf("invalid value for %s: %s (must be key=value)", key, value)
+			}
+			volumeLabels = append(volumeLabels, value)
+		case "replace":
+			replace, err = strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for %s: %s", key, value)
+			}
 		default:
 			return nil, fmt.Errorf("unexpected key '%s' in '%s'", key, field)
 		}
 	}
 
+	if err := validateWriteModeOpts(rwOptions); err != nil {
+		return nil, err
+	}
+
+	if mountType == Bind {
+		if err := validateBindSourceNotGlob(src); err != nil {
+			return nil, err
+		}
+	}
+
+	if bindRecursive != "" {
+		if mountType != Bind {
+			return nil, fmt.Errorf("bind-recursive is only supported for bind mounts, got mount type '%s'", mountType)
+		}
+		if bindNonRecursive {
+			return nil, errors.New("bind-recursive and bind-nonrecursive cannot be specified together")
+		}
+		if bindRecursive == "disabled" {
+			bindNonRecursive = true
+		}
+	}
 	// compose new fileds and join into a string
 	// to call legacy ProcessFlagTmpfs or ProcessFlagV function
 	fields = []string{}
@@ -426,14 +529,147 @@ func ProcessFlagMount(s string, volStore volumestore.VolumeStore) (*Processed, e
 
 	log.L.Debugf("Call legacy %s process, spec: %s ", mountType, fieldsStr)
 
+	if subpath != "" && mountType != Volume && mountType != Image {
+		return nil, fmt.Errorf("subpath is only supported for volume and image mounts, got mount type '%s'", mountType)
+	}
+
+	if volumeDriver != "" || len(volumeOpts) > 0 || len(volumeLabels) > 0 {
+		if mountType != Volume || !isNamedVolume(src) {
+			return nil, fmt.Errorf("volume-driver, volume-opt and volume-label are only supported for named volume mounts")
+		}
+		if volumedriver.IsLocal(volumeDriver) && len(volumeOpts) > 0 {
+			return nil, fmt.Errorf("volume-opt is not supported by the %q volume driver", volumedriver.LocalDriverName)
+		}
+		// The named volume is created with the driver and the labels if it does not exist yet, and then mounted as usual
+		vol, err := volStore.CreateWithDriverWithoutLock(src, append(AutoCreatedVolumeLabels(), volumeLabels...), volumeDriver, volumeOpts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create volume %q: %w", src, err)
+		}
+		// An existing volume is returned as-is, so an explicit driver must match the driver it was created with
+		if volumeDriver != "" && driverName(volumeDriver) != driverName(vol.Driver) {
+			return nil, fmt.Errorf("volume %q already exists with driver %q, conflicting with volume-driver=%s", src, driverName(vol.Driver), volumeDriver)
+		}
+	}
+
 	switch mountType {
 	case Tmpfs:
-		return ProcessFlagTmpfs(fieldsStr)
+		res, err := ProcessFlagTmpfs(fieldsStr)
+		if err != nil {
+			return nil, err
+		}
+		res.Replace = replace
+		return res, nil
 	case Volume, Bind:
+		split, err := splitVolumeSpec(fieldsStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to split volume mount specification: %v", err)
+		}
 		// createDir=false for --mount option to disallow creating directories on host if not found
-		return ProcessFlagV(fieldsStr, volStore, false)
+		res, err := processVolumeSpec(fieldsStr, split, volStore, false, bindRecursive)
+		if err != nil {
+			return nil, err
+		}
+		res.RecursiveReadOnlyRequired = bindRecursive == "readonly" && slices.Contains(res.Mount.Options, "rro")
+		if subpath != "" {
+			if err := withVolumeSubpath(res, subpath); err != nil {
+				if relErr := ReleaseDriverMount(context.TODO(), res); relErr != nil {
+					log.L.WithError(relErr).Warnf("failed to unmount volume %q with driver %q", res.Name, res.Driver)
+				}
+				return nil, err
+			}
+		}
+		res.Replace = replace
+		return res, nil
+	case Image:
+		res, err := processImageMount(src, dst, rwOption, subpath)
+		if err != nil {
+			return nil, err
+		}
+		res.Replace = replace
+		return res, nil
+	}
+	return nil, fmt.Errorf("invalid mount type '%s' must be a volume/bind/tmpfs/image", mountType)
+}
+
+// processImageMount processes an image mount (--mount type=image) of the image src, which is always read-only.
+// The image is resolved, and its rootfs mounted on the host, when creating the container (see MountImage and
+// WithImageSubpath), so the source of the mount is not set here.
+func processImageMount(src, dst, rwOption, subpath string) (*Processed, error) {
+	if src == "" {
+		return nil, errors.New("image mount requires a source image")
+	}
+	if _, err := isValidPath(dst); err != nil {
+		return nil, err
+	}
+	if rwOption == "rw" {
+		return nil, errors.New("image mounts are always read-only, rw is not supported")
+	}
+	// The subpath is relative to the rootfs of the image, e.g. "/usr/bin" and "usr/bin" are the same
+	subpath = strings.TrimLeft(subpath, "/")
+	if subpath != "" && !filepath.IsLocal(subpath) {
+		return nil, fmt.Errorf("invalid subpath %q: must not escape the rootfs of the image", subpath)
+	}
+	return &Processed{
+		Type: Image,
+		Name: src,
+		Mount: specs.Mount{
+			Type:        "bind",
+			Destination: dst,
+			Options:     []string{"rbind", "ro"},
+		},
+		Mode:    "ro",
+		Subpath: filepath.Clean(subpath),
+	}, nil
+}
+
+// validateBindSourceNotGlob rejects a bind mount source that looks like a glob pattern, e.g. "/var/log/*.log",
+// as a bind mount source must be a concrete path. Unlike --device, the pattern is never expanded, so that
+// a file literally named "*.log" would be mounted instead. "[" is only rejected when the path does not exist,
+// as brackets are common in directory names.
+func validateBindSourceNotGlob(src string) error {
+	isGlob := strings.ContainsAny(src, "*?")
+	if !isGlob && strings.Contains(src, "[") {
+		_, err := os.Lstat(src)
+		isGlob = os.IsNotExist(err)
+	}
+	if isGlob {
+		return fmt.Errorf("bind mount source %q must be a concrete path: glob patterns are not supported (only --device expands glob patterns)", src)
+	}
+	return nil
+}
+
+// recursiveReadOnlySupported returns whether the kernel supports recursive read-only
+// mounts, i.e., mount_setattr(2) with AT_RECURSIVE, available since Linux 5.12.
+var recursiveReadOnlySupported = func() bool {
+	// The syscall fails with EBADF for the invalid dirfd when it is implemented.
+	err := unix.MountSetattr(-1, "", unix.AT_RECURSIVE, &unix.MountAttr{})
+	return !errors.Is(err, unix.ENOSYS)
+}
+
+// recursiveReadOnlyBindOptions returns the write mode and the propagation options of a read-only
+// recursive bind mount for the bind-recursive mode ("" is the same as "enabled").
+//
+// Like Docker, submounts are made read-only too ("rro") when the kernel supports it and the propagation
+// is private. Otherwise "enabled" falls back to a top-level "ro" with a warning, as the submounts remain
+// writable, while "readonly" fails. "writable" keeps the submounts writable on purpose.
+func recursiveReadOnlyBindOptions(src, bindRecursive, bindPropagation string) (string, string, error) {
+	if bindRecursive == "writable" {
+		return "ro", bindPropagation, nil
+	}
+	var reason string
+	switch {
+	case !recursiveReadOnlySupported():
+		reason = "recursive read-only mounts are not supported by the kernel (requires kernel >= 5.12)"
+	case bindPropagation != "" && bindPropagation != "rprivate":
+		reason = fmt.Sprintf("recursive read-only mounts require bind-propagation=rprivate, got %q", bindPropagation)
+	default:
+		return "rro", "rprivate", nil
+	}
+	if bindRecursive == "readonly" {
+		return "", "", fmt.Errorf("failed to make bind mount %q recursively read-only: %s", src, reason)
 	}
-	return nil, fmt.Errorf("invalid mount type '%s' must be a volume/bind/tmpfs", mountType)
+	log.L.Warnf("submounts of the read-only bind mount %q remain writable: %s (use bind-recursive=readonly to make it an error)", src, reason)
+	return "ro", bindPropagation, nil
 }
 
 // copy from https://github.com/moby/moby/blob/085c6a98d54720e70b28354ccec6da9b1b9e7fcf/volume/mounts/linux_parser.go#L375
@@ -18,15 +18,26 @@ package mountutil
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
 	"github.com/opencontainers/runtime-spec/specs-go"
+	"golang.org/x/sys/unix"
 	"gotest.tools/v3/assert"
 	is "gotest.tools/v3/assert/cmp"
 
 	"github.com/containerd/containerd/v2/core/mount"
 	"github.com/containerd/containerd/v2/pkg/oci"
+
+	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/native"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/mountutil/volumestore"
+	"github.com/containerd/nerdctl/v2/pkg/volumedriver"
 )
 
 // TestParseVolumeOptions tests volume options are parsed as expected.
@@ -72,6 +83,13 @@ func TestParseVolumeOptions(t *testing.T) {
 			optsRaw:  "ro,rw",
 			wantFail: true,
 		},
+		{
+			name:     "conflicting flags (rro/rw) are not allowed",
+			vType:    "bind",
+			src:      "dummy",
+			optsRaw:  "rw,rro",
+			wantFail: true,
+		},
 		{
 			name:     "duplicated flags (ro/ro) are not allowed",
 			vType:    "volume",
@@ -172,7 +190,7 @@ func TestParseVolumeOptions(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			opts, specOpts, err := parseVolumeOptionsWithMountInfo(tt.vType, tt.src, tt.optsRaw, func(string) (mount.Info, error) {
+			opts, specOpts, err := parseVolumeOptionsWithMountInfo(tt.vType, tt.src, tt.optsRaw, "", func(string) (mount.Info, error) {
 				return mount.Info{
 					Mountpoint: tt.src,
 					Optional:   strings.Join(tt.srcOptional, " "),
@@ -209,12 +227,15 @@ func TestProcessTmpfs(t *testing.T) {
 }
 
 func TestProcessFlagV(t *testing.T) {
+	defer func(f func() bool) { recursiveReadOnlySupported = f }(recursiveReadOnlySupported)
+	recursiveReadOnlySupported = func() bool { return true }
+
 	tests := []struct {
 		rawSpec string
 		wants   *Processed
 		err     string
 	}{
-		// Bind volumes: absolute path
+		// Bind volumes: absolute path, recursively read-only like Docker
 		{
 			rawSpec: "/mnt/foo:/mnt/foo:ro",
 			wants: &Processed{
@@ -223,7 +244,19 @@ func TestProcessFlagV(t *testing.T) {
 					Type:        "none",
 					Destination: `/mnt/foo`,
 					Source:      `/mnt/foo`,
-					Options:     []string{"ro", "rprivate", "rbind"},
+					Options:     []string{"ro", "rro", "rprivate", "rbind"},
+				}},
+		},
+		// Bind volumes: the default propagation is rprivate, like Docker
+		{
+			rawSpec: "/mnt/foo:/mnt/foo",
+			wants: &Processed{
+				Type: "bind",
+				Mount: specs.Mount{
+					Type:        "none",
+					Destination: `/mnt/foo`,
+					Source:      `/mnt/foo`,
+					Options:     []string{"rprivate", "rbind"},
 				}},
 		},
 		// Bind volumes: relative path
@@ -235,7 +268,7 @@ func TestProcessFlagV(t *testing.T) {
 					Type:        "none",
 					Source:      "", // will not check source of relative paths
 					Destination: `/mnt/foo`,
-					Options:     []string{"rbind"},
+					Options:     []string{"rprivate", "rbind"},
 				}},
 		},
 		// Named volumes
@@ -284,6 +317,33 @@ func TestProcessFlagV(t *testing.T) {
 	}
 }
 
+func TestProcessFlagVRelativeBind(t *testing.T) {
+	wd, err := os.Getwd()
+	assert.NilError(t, err)
+
+	for rawSpec, source := range map[string]string{
+		"./TestVolume/Path:/mnt/foo":    filepath.Join(wd, "TestVolume/Path"),
+		"../TestVolume/Path:/mnt/foo":   filepath.Join(filepath.Dir(wd), "TestVolume/Path"),
+		".:/mnt/foo":                    wd,
+		"./TestVolume/../Path:/mnt/foo": filepath.Join(wd, "Path"),
+	} {
+		x, err := ProcessFlagV(rawSpec, mockVolumeStore, false)
+		assert.NilError(t, err, rawSpec)
+		assert.Equal(t, x.Type, Bind, rawSpec)
+		assert.Equal(t, x.Mount.Source, source, rawSpec)
+	}
+
+	x, err := ProcessFlagMount("type=bind,src=./TestVolume/Path,dst=/mnt/foo", mockVolumeStore)
+	assert.NilError(t, err)
+	assert.Equal(t, x.Mount.Source, filepath.Join(wd, "TestVolume/Path"))
+
+	// A source without a leading "." or "/" is the name of a volume, not a path
+	x, err = ProcessFlagV("TestVolume:/mnt/foo", mockVolumeStore, false)
+	assert.NilError(t, err)
+	assert.Equal(t, x.Type, Volume)
+	assert.Equal(t, x.Name, "TestVolume")
+}
+
 func TestProcessFlagVAnonymousVolumes(t *testing.T) {
 	tests := []struct {
 		rawSpec string
@@ -348,3 +408,523 @@ func TestProcessFlagVAnonymousVolumes(t *testing.T) {
 		})
 	}
 }
+
+type tempDirVolumeStore struct {
+	volumestore.VolumeStore
+	mountpoint string
+}
+
+func (vs *tempDirVolumeStore) CreateWithoutLock(name string, labels []string) (*native.Volume, error) {
+	return &native.Volume{Name: name, Mountpoint: vs.mountpoint}, nil
+}
+
+func TestProcessFlagMountSubpath(t *testing.T) {
+	volStore := &tempDirVolumeStore{mountpoint: t.TempDir()}
+	assert.NilError(t, os.Symlink("/", filepath.Join(volStore.mountpoint, "escape")))
+
+	x, err := ProcessFlagMount("type=volume,src=TestVolume,dst=/mnt/foo,subpath=foo/bar", volStore)
+	assert.NilError(t, err)
+	assert.Equal(t, x.Mount.Source, filepath.Join(volStore.mountpoint, "foo", "bar"))
+	assert.Equal(t, x.Subpath, "foo/bar")
+	st, err := os.Stat(x.Mount.Source)
+	assert.NilError(t, err)
+	assert.Assert(t, st.IsDir())
+
+	// a symlink pointing outside of the volume is resolved within the volume
+	x, err = ProcessFlagMount("type=volume,src=TestVolume,dst=/mnt/foo,subpath=escape/baz", volStore)
+	assert.NilError(t, err)
+	assert.Equal(t, x.Mount.Source, filepath.Join(volStore.mountpoint, "baz"))
+
+	for _, subpath := range []string{"../foo", "foo/../../bar", "/foo"} {
+		_, err = ProcessFlagMount("type=volume,src=TestVolume,dst=/mnt/foo,subpath="+subpath, volStore)
+		assert.ErrorContains(t, err, "does not escape the volume")
+	}
+
+	_, err = ProcessFlagMount("type=bind,src=/tmp,dst=/mnt/foo,subpath=foo", volStore)
+	assert.ErrorContains(t, err, "subpath is only supported for volume and image mounts")
+}
+
+func TestProcessFlagMountSubpathReadonly(t *testing.T) {
+	volStore := &tempDirVolumeStore{mountpoint: t.TempDir()}
+
+	// a missing subpath is not created for a read-only mount
+	_, err := ProcessFlagMount("type=volume,src=TestVolume,dst=/mnt/foo,subpath=conf,readonly", volStore)
+	assert.ErrorContains(t, err, "does not exist in volume")
+	_, err = os.Stat(filepath.Join(volStore.mountpoint, "conf"))
+	assert.Assert(t, errors.Is(err, os.ErrNotExist))
+
+	assert.NilError(t, os.Mkdir(filepath.Join(volStore.mountpoint, "conf"), 0o755))
+	for _, opt := range []string{"readonly", "ro", "rro"} {
+		x, err := ProcessFlagMount("type=volume,src=TestVolume,dst=/mnt/foo,subpath=conf,"+opt, volStore)
+		assert.NilError(t, err)
+		assert.Equal(t, x.Mount.Source, filepath.Join(volStore.mountpoint, "conf"))
+		assert.Assert(t, slices.Contains(x.Mount.Options, "ro"), "options: %v", x.Mount.Options)
+	}
+}
+
+// fakeVolumeDriver is an in-process volume driver, mounting the volumes in a temporary directory.
+type fakeVolumeDriver struct {
+	root   string
+	mounts map[string]string
+}
+
+func (d *fakeVolumeDriver) Create(_ context.Context, name string, opts map[string]string) error {
+	return os.MkdirAll(filepath.Join(d.root, name), 0o755)
+}
+
+func (d *fakeVolumeDriver) Remove(_ context.Context, name string) error {
+	return os.RemoveAll(filepath.Join(d.root, name))
+}
+
+func (d *fakeVolumeDriver) Mount(_ context.Context, name, id string) (string, error) {
+	d.mounts[id] = name
+	return filepath.Join(d.root, name), nil
+}
+
+func (d *fakeVolumeDriver) Unmount(_ context.Context, name, id string) error {
+	if d.mounts[id] != name {
+		return fmt.Errorf("volume %q is not mounted as %q", name, id)
+	}
+	delete(d.mounts, id)
+	return nil
+}
+
+// driverVolumeStore records the drivers of the volumes it creates, as the volume store does.
+type driverVolumeStore struct {
+	volumestore.VolumeStore
+	drivers map[string]string
+}
+
+func (vs *driverVolumeStore) CreateWithoutLock(name string, labels []string) (*native.Volume, error) {
+	return vs.CreateWithDriverWithoutLock(name, labels, "", nil)
+}
+
+func (vs *driverVolumeStore) CreateWithDriverWithoutLock(name string, labels []string, driver string, opts map[string]string) (*native.Volume, error) {
+	if _, ok := vs.drivers[name]; !ok {
+		if !volumedriver.IsLocal(driver) {
+			d, err := volumedriver.Get(context.Background(), driver)
+			if err != nil {
+				return nil, err
+			}
+			if err := d.Create(context.Background(), name, opts); err != nil {
+				return nil, err
+			}
+		}
+		vs.drivers[name] = driver
+	}
+	if driver = vs.drivers[name]; !volumedriver.IsLocal(driver) {
+		return &native.Volume{Name: name, Driver: driver}, nil
+	}
+	return &native.Volume{Name: name, Mountpoint: "/test/volume"}, nil
+}
+
+func TestProcessFlagMountVolumeDriver(t *testing.T) {
+	d := &fakeVolumeDriver{root: t.TempDir(), mounts: map[string]string{}}
+	assert.NilError(t, volumedriver.Register("fake", d))
+	t.Cleanup(func() { volumedriver.Unregister("fake") })
+	volStore := &driverVolumeStore{drivers: map[string]string{}}
+
+	x, err := ProcessFlagMount("type=volume,src=TestVolume,dst=/mnt/foo,volume-driver=fake,volume-opt=size=1G", volStore)
+	assert.NilError(t, err)
+	assert.Equal(t, x.Mount.Source, filepath.Join(d.root, "TestVolume"))
+	assert.Equal(t, x.Driver, "fake")
+	assert.Assert(t, x.DriverMountID != "")
+	assert.Equal(t, d.mounts[x.DriverMountID], "TestVolume")
+
+	// the existing volume is mounted by its driver again, with a distinct mount ID
+	y, err := ProcessFlagV("TestVolume:/mnt/bar", volStore, false)
+	assert.NilError(t, err)
+	assert.Equal(t, y.Mount.Source, x.Mount.Source)
+	assert.Assert(t, y.DriverMountID != x.DriverMountID)
+	assert.Equal(t, len(d.mounts), 2)
+
+	for _, p := range []*Processed{x, y} {
+		assert.NilError(t, volumedriver.MountRecord{Volume: p.Name, Driver: p.Driver, ID: p.DriverMountID}.Unmount(context.Background()))
+	}
+	assert.Equal(t, len(d.mounts), 0)
+
+	// the volume is unmounted when the mount fails after it is mounted by its driver
+	_, err = ProcessFlagV("TestVolume:/mnt/foo:ro,rw", volStore, false)
+	assert.ErrorContains(t, err, "failed to parse volume options")
+	_, err = ProcessFlagMount("type=volume,src=TestVolume,dst=/mnt/foo,subpath=../escape", volStore)
+	assert.ErrorContains(t, err, "invalid subpath")
+	assert.Equal(t, len(d.mounts), 0)
+
+	// local volumes are not mounted by a driver
+	z, err := ProcessFlagMount("type=volume,src=LocalVolume,dst=/mnt/foo", volStore)
+	assert.NilError(t, err)
+	assert.Equal(t, z.Mount.Source, "/test/volume")
+	assert.Equal(t, z.DriverMountID, "")
+
+	// an explicit driver must match the driver of the existing volume
+	_, err = ProcessFlagMount("type=volume,src=LocalVolume,dst=/mnt/foo,volume-driver=fake", volStore)
+	assert.ErrorContains(t, err, `volume "LocalVolume" already exists with driver "local"`)
+	_, err = ProcessFlagMount("type=volume,src=TestVolume,dst=/mnt/foo,volume-driver=local", volStore)
+	assert.ErrorContains(t, err, `volume "TestVolume" already exists with driver "fake"`)
+	assert.Equal(t, len(d.mounts), 0)
+
+	_, err = ProcessFlagMount("type=volume,src=Other,dst=/mnt/foo,volume-driver=nonexistent", volStore)
+	assert.ErrorContains(t, err, "not found")
+
+	_, err = ProcessFlagMount("type=volume,src=Other,dst=/mnt/foo,volume-opt=size=1G", volStore)
+	assert.ErrorContains(t, err, "not supported by the \"local\" volume driver")
+
+	_, err = ProcessFlagMount("type=bind,src=/tmp,dst=/mnt/foo,volume-driver=fake", volStore)
+	assert.ErrorContains(t, err, "only supported for named volume mounts")
+}
+
+// labelsVolumeStore records the labels of the volumes it creates.
+type labelsVolumeStore struct {
+	volumestore.VolumeStore
+	labels map[string][]string
+}
+
+func (vs *labelsVolumeStore) CreateWithoutLock(name string, labels []string) (*native.Volume, error) {
+	return vs.CreateWithDriverWithoutLock(name, labels, "", nil)
+}
+
+func (vs *labelsVolumeStore) CreateWithDriverWithoutLock(name string, labels []string, driver string, opts map[string]string) (*native.Volume, error) {
+	if _, ok := vs.labels[name]; !ok {
+		vs.labels[name] = labels
+	}
+	return &native.Volume{Name: name, Mountpoint: "/test/volume"}, nil
+}
+
+func TestProcessFlagMountVolumeLabel(t *testing.T) {
+	volStore := &labelsVolumeStore{labels: map[string][]string{}}
+
+	x, err := ProcessFlagMount("type=volume,src=TestVolume,dst=/mnt/foo,volume-label=project=x,volume-label=empty=", volStore)
+	assert.NilError(t, err)
+	assert.Equal(t, x.Name, "TestVolume")
+	assert.DeepEqual(t, volStore.labels["TestVolume"], []string{labels.AutoCreatedVolume + "=true", "project=x", "empty="})
+
+	_, err = ProcessFlagMount("type=volume,src=TestVolume,dst=/mnt/foo,volume-label=project", volStore)
+	assert.ErrorContains(t, err, "must be key=value")
+
+	_, err = ProcessFlagMount("type=volume,dst=/mnt/foo,volume-label=project=x", volStore)
+	assert.ErrorContains(t, err, "only supported for named volume mounts")
+
+	_, err = ProcessFlagMount("type=bind,src=/tmp,dst=/mnt/foo,volume-label=project=x", volStore)
+	assert.ErrorContains(t, err, "only supported for named volume mounts")
+}
+
+func TestProcessFlagMountNpipe(t *testing.T) {
+	_, err := ProcessFlagMount(`type=npipe,src=\\.\pipe\containerd-containerd,dst=\\.\pipe\containerd-containerd`, mockVolumeStore)
+	assert.ErrorContains(t, err, "only supported on Windows")
+}
+
+func TestProcessFlagMountImage(t *testing.T) {
+	const digestRef = "alpine@sha256:0000000000000000000000000000000000000000000000000000000000000000"
+	x, err := ProcessFlagMount("type=image,src="+digestRef+",dst=/mnt/foo,subpath=/usr/bin", mockVolumeStore)
+	assert.NilError(t, err)
+	assert.Equal(t, x.Type, Image)
+	assert.Equal(t, x.Name, digestRef)
+	assert.Equal(t, x.Subpath, "usr/bin")
+	assert.Equal(t, x.Mode, "ro")
+	assert.DeepEqual(t, x.Mount, specs.Mount{Type: "bind", Destination: "/mnt/foo", Options: []string{"rbind", "ro"}})
+
+	// read-only by default, and explicitly
+	for _, s := range []string{"type=image,src=alpine,dst=/mnt/foo", "type=image,src=alpine,dst=/mnt/foo,readonly"} {
+		x, err = ProcessFlagMount(s, mockVolumeStore)
+		assert.NilError(t, err)
+		assert.Equal(t, x.Subpath, ".")
+		assert.Assert(t, slices.Contains(x.Mount.Options, "ro"))
+	}
+
+	for s, expected := range map[string]string{
+		"type=image,src=alpine,dst=/mnt/foo,rw":                      "always read-only",
+		"type=image,dst=/mnt/foo":                                    "requires a source image",
+		"type=image,src=alpine,dst=/mnt/foo,subpath=../etc":          "must not escape the rootfs",
+		"type=image,src=alpine,dst=/mnt/foo,subpath=/usr/../../etc":  "must not escape the rootfs",
+		"type=image,src=alpine,dst=/mnt/foo,bind-recursive=readonly": "only supported for bind mounts",
+	} {
+		_, err = ProcessFlagMount(s, mockVolumeStore)
+		assert.ErrorContains(t, err, expected, s)
+	}
+}
+
+func TestWithImageSubpath(t *testing.T) {
+	rootfs := t.TempDir()
+	assert.NilError(t, os.MkdirAll(filepath.Join(rootfs, "usr", "bin"), 0o755))
+	assert.NilError(t, os.Symlink("../../..", filepath.Join(rootfs, "usr", "escape")))
+
+	process := func(subpath string) (*Processed, error) {
+		x, err := ProcessFlagMount("type=image,src=alpine,dst=/mnt/foo,subpath="+subpath, mockVolumeStore)
+		assert.NilError(t, err)
+		x.ImageMount = &ImageMountRecord{Image: "alpine", Mountpoint: rootfs}
+		return x, WithImageSubpath(x)
+	}
+
+	x, err := process("/usr/bin")
+	assert.NilError(t, err)
+	assert.Equal(t, x.Mount.Source, filepath.Join(rootfs, "usr", "bin"))
+
+	x, err = process("")
+	assert.NilError(t, err)
+	assert.Equal(t, x.Mount.Source, rootfs)
+
+	// a symlink pointing outside of the rootfs is resolved within the rootfs
+	x, err = process("usr/escape/usr")
+	assert.NilError(t, err)
+	assert.Equal(t, x.Mount.Source, filepath.Join(rootfs, "usr"))
+
+	_, err = process("/usr/lib")
+	assert.ErrorContains(t, err, `subpath "usr/lib" does not exist in image "alpine"`)
+}
+
+func TestImageMountRecordMount(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("requires root to mount")
+	}
+	rootfs := t.TempDir()
+	assert.NilError(t, os.MkdirAll(filepath.Join(rootfs, "usr", "bin"), 0o755))
+	mountpoint := filepath.Join(t.TempDir(), "image-mounts", "0")
+
+	// a view of a snapshot is mounted like the bind mount of the rootfs, read-only
+	assert.NilError(t, mountSnapshot([]mount.Mount{{Type: "bind", Source: rootfs, Options: []string{"rbind", "ro"}}}, mountpoint))
+	r := ImageMountRecord{Image: "alpine", Mountpoint: mountpoint}
+	defer r.Unmount()
+
+	x, err := ProcessFlagMount("type=image,src=alpine,dst=/mnt/foo,subpath=usr", mockVolumeStore)
+	assert.NilError(t, err)
+	x.ImageMount = &r
+	assert.NilError(t, WithImageSubpath(x))
+	assert.Equal(t, x.Mount.Source, filepath.Join(mountpoint, "usr"))
+	_, err = os.Stat(filepath.Join(x.Mount.Source, "bin"))
+	assert.NilError(t, err)
+	assert.ErrorIs(t, os.WriteFile(filepath.Join(x.Mount.Source, "foo"), nil, 0o644), unix.EROFS)
+
+	assert.NilError(t, ReleaseImageMount(x))
+	_, err = os.Stat(filepath.Join(mountpoint, "usr"))
+	assert.Assert(t, errors.Is(err, os.ErrNotExist))
+	// unmounting again is not an error
+	assert.NilError(t, r.Unmount())
+}
+
+func TestProcessFlagMountConflictingWriteMode(t *testing.T) {
+	for _, s := range []string{
+		"type=bind,src=/tmp,dst=/mnt/foo,ro,rw",
+		"type=bind,src=/tmp,dst=/mnt/foo,readonly=true,rw",
+		"type=bind,src=/tmp,dst=/mnt/foo,rw=true,rro",
+		"type=volume,src=TestVolume,dst=/mnt/foo,rw,readonly",
+		"type=tmpfs,dst=/mnt/foo,ro,rw",
+	} {
+		_, err := ProcessFlagMount(s, mockVolumeStore)
+		assert.ErrorContains(t, err, "conflicting read-only and read-write options", s)
+	}
+
+	x, err := ProcessFlagMount("type=bind,src=/tmp,dst=/mnt/foo,readonly=false,rw", mockVolumeStore)
+	assert.NilError(t, err)
+	assert.Assert(t, !slices.Contains(x.Mount.Options, "ro"))
+}
+
+func TestProcessFlagMountBindGlob(t *testing.T) {
+	dir := t.TempDir()
+	for _, src := range []string{
+		filepath.Join(dir, "*.log"),
+		filepath.Join(dir, "app?.conf"),
+		filepath.Join(dir, "[ab].conf"),
+	} {
+		_, err := ProcessFlagMount("type=bind,src="+src+",dst=/mnt/foo", mockVolumeStore)
+		assert.ErrorContains(t, err, "must be a concrete path", src)
+	}
+
+	// A file literally named "*.log" is rejected too
+	literal := filepath.Join(dir, "*.log")
+	assert.NilError(t, os.WriteFile(literal, []byte{}, 0o644))
+	_, err := ProcessFlagMount("type=bind,src="+literal+",dst=/mnt/foo", mockVolumeStore)
+	assert.ErrorContains(t, err, "must be a concrete path")
+
+	// Brackets are fine in an existing path
+	bracketed := filepath.Join(dir, "[project]")
+	assert.NilError(t, os.Mkdir(bracketed, 0o755))
+	x, err := ProcessFlagMount("type=bind,src="+bracketed+",dst=/mnt/foo", mockVolumeStore)
+	assert.NilError(t, err)
+	assert.Equal(t, x.Mount.Source, bracketed)
+}
+
+func TestProcessFlagBindPseudoFilesystem(t *testing.T) {
+	x, err := ProcessFlagMount("type=bind,src=/sys/kernel,dst=/mnt/foo,readonly", mockVolumeStore)
+	assert.NilError(t, err)
+	assert.Equal(t, x.Mount.Source, "/sys/kernel")
+	assert.Assert(t, slices.Contains(x.Mount.Options, "ro"))
+
+	x, err = ProcessFlagV("/proc/cpuinfo:/mnt/foo:ro", mockVolumeStore, true)
+	assert.NilError(t, err)
+	assert.Equal(t, x.Mount.Source, "/proc/cpuinfo")
+
+	// Paths on procfs/sysfs are never created on the host
+	_, err = ProcessFlagV("/sys/nerdctl-does-not-exist:/mnt/foo", mockVolumeStore, true)
+	assert.ErrorContains(t, err, "does not exist")
+	_, err = ProcessFlagMount("type=bind,src=/sys/nerdctl-does-not-exist,dst=/mnt/foo", mockVolumeStore)
+	assert.ErrorContains(t, err, "does not exist")
+
+	for _, s := range []string{"/proc/self", "/proc/self/ns/net", "/proc/thread-self/status"} {
+		_, err = ProcessFlagV(s+":/mnt/foo", mockVolumeStore, true)
+		assert.ErrorContains(t, err, "refers to the nerdctl process", s)
+	}
+}
+
+func TestProcessFlagMountBindFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.toml")
+	assert.NilError(t, os.WriteFile(file, []byte{}, 0o644))
+
+	for _, src := range []string{file, "/dev/null"} {
+		x, err := ProcessFlagMount(fmt.Sprintf("type=bind,src=%s,dst=/mnt/foo", src), mockVolumeStore)
+		assert.NilError(t, err, src)
+		assert.Equal(t, x.Type, Bind)
+		assert.Equal(t, x.Mount.Source, src)
+		assert.Equal(t, x.Mount.Destination, "/mnt/foo")
+	}
+}
+
+func TestProcessFlagBindDefaultPropagation(t *testing.T) {
+	// Like Docker, bind mounts default to rprivate, and an explicit propagation is kept as is
+	for spec, wants := range map[string][]string{
+		"/tmp:/mnt/foo":                   {"rprivate", "rbind"},
+		"/tmp:/mnt/foo:rw":                {"rprivate", "rbind"},
+		"/tmp:/mnt/foo:private":           {"private", "rbind"},
+		"type=bind,src=/tmp,dst=/mnt/foo": {"rprivate", "rbind"},
+		"type=bind,src=/tmp,dst=/mnt/foo,bind-propagation=private": {"private", "rbind"},
+	} {
+		var (
+			x   *Processed
+			err error
+		)
+		if strings.HasPrefix(spec, "type=") {
+			x, err = ProcessFlagMount(spec, mockVolumeStore)
+		} else {
+			x, err = ProcessFlagV(spec, mockVolumeStore, false)
+		}
+		assert.NilError(t, err, spec)
+		assert.DeepEqual(t, slices.Sorted(slices.Values(x.Mount.Options)), slices.Sorted(slices.Values(wants)))
+	}
+}
+
+func TestValidateBindDestination(t *testing.T) {
+	srcDir := t.TempDir()
+	srcFile := filepath.Join(srcDir, "config.toml")
+	assert.NilError(t, os.WriteFile(srcFile, []byte{}, 0o644))
+
+	rootfs := t.TempDir()
+	assert.NilError(t, os.MkdirAll(filepath.Join(rootfs, "etc", "app"), 0o755))
+	assert.NilError(t, os.WriteFile(filepath.Join(rootfs, "etc", "app.conf"), []byte{}, 0o644))
+
+	testCases := []struct {
+		source      string
+		destination string
+		expectedErr string
+	}{
+		{source: srcFile, destination: "/etc/app.conf"},
+		{source: srcFile, destination: "/etc/missing.conf"},
+		{source: "/dev/null", destination: "/etc/app.conf"},
+		{source: srcDir, destination: "/etc/app"},
+		{source: srcDir, destination: "/missing"},
+		{source: filepath.Join(srcDir, "missing"), destination: "/etc/app"},
+		{source: srcFile, destination: "/etc/app", expectedErr: fmt.Sprintf("cannot bind-mount file %q onto directory \"/etc/app\" of the image", srcFile)},
+		{source: "/dev/null", destination: "/etc/app", expectedErr: "cannot bind-mount device \"/dev/null\" onto directory \"/etc/app\" of the image"},
+		{source: srcDir, destination: "/etc/app.conf", expectedErr: fmt.Sprintf("cannot bind-mount directory %q onto file \"/etc/app.conf\" of the image", srcDir)},
+	}
+	for _, tc := range testCases {
+		err := ValidateBindDestination(tc.source, filepath.Join(rootfs, tc.destination), tc.destination)
+		if tc.expectedErr == "" {
+			assert.NilError(t, err, "%+v", tc)
+		} else {
+			assert.Error(t, err, tc.expectedErr)
+		}
+	}
+}
+
+func TestProcessFlagMountBindRecursiveReadOnly(t *testing.T) {
+	defer func(f func() bool) { recursiveReadOnlySupported = f }(recursiveReadOnlySupported)
+
+	type testCase struct {
+		spec      string
+		supported bool
+		wants     []string
+		unwants   []string
+		err       string
+	}
+	tests := []testCase{
+		{spec: "readonly", supported: true, wants: []string{"ro", "rro", "rprivate", "rbind"}},
+		{spec: "readonly,bind-recursive=enabled", supported: true, wants: []string{"ro", "rro", "rprivate"}},
+		{spec: "readonly,bind-recursive=readonly", supported: true, wants: []string{"ro", "rro", "rprivate"}},
+		{spec: "readonly,bind-propagation=rprivate", supported: true, wants: []string{"ro", "rro", "rprivate"}},
+		{spec: "readonly", supported: false, wants: []string{"ro", "rbind"}, unwants: []string{"rro"}},
+		{spec: "readonly,bind-recursive=readonly", supported: false, err: "not supported by the kernel"},
+		{spec: "readonly,bind-propagation=private", supported: true, wants: []string{"ro", "private"}, unwants: []string{"rro"}},
+		{spec: "readonly,bind-propagation=private,bind-recursive=readonly", supported: true, err: "require bind-propagation=rprivate"},
+		{spec: "readonly,bind-recursive=writable", supported: true, wants: []string{"ro", "rbind"}, unwants: []string{"rro"}},
+		{spec: "readonly,bind-recursive=disabled", supported: true, wants: []string{"ro", "bind"}, unwants: []string{"rro", "rbind"}},
+		{spec: "readonly,bind-nonrecursive", supported: true, wants: []string{"ro", "bind"}, unwants: []string{"rro"}},
+		{spec: "rw,bind-recursive=readonly", supported: false, unwants: []string{"ro", "rro"}},
+		{spec: "readonly,bind-recursive=foo", supported: true, err: "invalid value for bind-recursive"},
+		{spec: "bind-nonrecursive,bind-recursive=enabled", supported: true, err: "cannot be specified together"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.spec, func(t *testing.T) {
+			recursiveReadOnlySupported = func() bool { return tc.supported }
+			x, err := ProcessFlagMount("type=bind,src=/tmp,dst=/mnt/foo,"+tc.spec, mockVolumeStore)
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+			assert.NilError(t, err)
+			for _, opt := range tc.wants {
+				assert.Assert(t, slices.Contains(x.Mount.Options, opt), "options: %v", x.Mount.Options)
+			}
+			for _, opt := range tc.unwants {
+				assert.Assert(t, !slices.Contains(x.Mount.Options, opt), "options: %v", x.Mount.Options)
+			}
+		})
+	}
+
+	_, err := ProcessFlagMount("type=volume,src=TestVolume,dst=/mnt/foo,readonly,bind-recursive=readonly", mockVolumeStore)
+	assert.ErrorContains(t, err, "bind-recursive is only supported for bind mounts")
+
+	recursiveReadOnlySupported = func() bool { return true }
+	x, err := ProcessFlagMount("type=bind,src=/tmp,dst=/mnt/foo,readonly,bind-recursive=readonly", mockVolumeStore)
+	assert.NilError(t, err)
+	assert.Assert(t, x.RecursiveReadOnlyRequired)
+	x, err = ProcessFlagMount("type=bind,src=/tmp,dst=/mnt/foo,readonly", mockVolumeStore)
+	assert.NilError(t, err)
+	assert.Assert(t, !x.RecursiveReadOnlyRequired)
+}
+
+func TestProcessFlagVRecursiveReadOnly(t *testing.T) {
+	defer func(f func() bool) { recursiveReadOnlySupported = f }(recursiveReadOnlySupported)
+
+	type testCase struct {
+		spec      string
+		supported bool
+		wants     []string
+		unwants   []string
+	}
+	tests := []testCase{
+		{spec: "ro", supported: true, wants: []string{"ro", "rro", "rprivate", "rbind"}},
+		{spec: "ro,rprivate", supported: true, wants: []string{"ro", "rro", "rprivate", "rbind"}},
+		{spec: "ro", supported: false, wants: []string{"ro", "rprivate", "rbind"}, unwants: []string{"rro"}},
+		{spec: "ro,private", supported: true, wants: []string{"ro", "private"}, unwants: []string{"rro"}},
+		{spec: "ro,bind", supported: true, wants: []string{"ro", "bind"}, unwants: []string{"rro", "rbind"}},
+		{spec: "rw", supported: true, unwants: []string{"ro", "rro"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.spec, func(t *testing.T) {
+			recursiveReadOnlySupported = func() bool { return tc.supported }
+			x, err := ProcessFlagV("/tmp:/mnt/foo:"+tc.spec, mockVolumeStore, false)
+			assert.NilError(t, err)
+			for _, opt := range tc.wants {
+				assert.Assert(t, slices.Contains(x.Mount.Options, opt), "options: %v", x.Mount.Options)
+			}
+			for _, opt := range tc.unwants {
+				assert.Assert(t, !slices.Contains(x.Mount.Options, opt), "options: %v", x.Mount.Options)
+			}
+			assert.Assert(t, !x.RecursiveReadOnlyRequired)
+		})
+	}
+
+	// A read-only volume is not a recursive bind mount of the host
+	x, err := ProcessFlagV("TestVolume:/mnt/foo:ro", mockVolumeStore, false)
+	assert.NilError(t, err)
+	assert.Assert(t, !slices.Contains(x.Mount.Options, "rro"), "options: %v", x.Mount.Options)
+}
@@ -24,9 +24,11 @@
 package mountutil
 
 import (
+	"errors"
 	"fmt"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/containerd/containerd/v2/pkg/oci"
@@ -54,7 +56,7 @@ func UnprivilegedMountFlags(path string) ([]string, error) {
 
 // parseVolumeOptions parses specified optsRaw with using information of
 // the volume type and the src directory when necessary.
-func parseVolumeOptions(vType, src, optsRaw string) ([]string, []oci.SpecOpts, error) {
+func parseVolumeOptions(vType, src, optsRaw, _ string) ([]string, []oci.SpecOpts, error) {
 	var writeModeRawOpts []string
 	for _, opt := range strings.Split(optsRaw, ",") {
 		switch opt {
@@ -68,6 +70,9 @@ func parseVolumeOptions(vType, src, optsRaw string) ([]string, []oci.SpecOpts, e
 			log.L.Warnf("unsupported volume option %q", opt)
 		}
 	}
+	if err := validateWriteModeOpts(writeModeRawOpts); err != nil {
+		return nil, nil, err
+	}
 	var opts []string
 	if len(writeModeRawOpts) > 1 {
 		return nil, nil, fmt.Errorf("duplicated read/write volume option: %+v", writeModeRawOpts)
@@ -81,8 +86,148 @@ func ProcessFlagTmpfs(s string) (*Processed, error) {
 	return nil, errdefs.ErrNotImplemented
 }
 
+// ProcessFlagMount processes the --mount flag on Windows.
+// Only the bind, volume and npipe types are supported, e.g.,
+// --mount type=npipe,source=\\.\pipe\containerd-containerd,target=\\.\pipe\containerd-containerd
 func ProcessFlagMount(s string, volStore volumestore.VolumeStore) (*Processed, error) {
-	return nil, errdefs.ErrNotImplemented
+	var (
+		mountType = Volume
+		src       string
+		dst       string
+		readonly  bool
+		replace   bool
+		rwOptions []string
+		err       error
+	)
+	for _, field := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(field, "=")
+		key = strings.ToLower(key)
+		if !ok {
+			switch key {
+			case "readonly", "ro":
+				readonly = true
+				rwOptions = append(rwOptions, key)
+				continue
+			case "rw":
+				rwOptions = append(rwOptions, key)
+				continue
+			case "replace":
+				replace = true
+				continue
+			}
+			return nil, fmt.Errorf("invalid field '%s' must be a key=value pair", field)
+		}
+		switch key {
+		case "type":
+			switch value {
+			case Bind, Volume, Npipe:
+				mountType = value
+			case Image:
+				return nil, fmt.Errorf("mount type '%s' is only supported on Linux", value)
+			default:
+				return nil, fmt.Errorf("invalid mount type '%s' must be a volume/bind/npipe", value)
+			}
+		case "source", "src":
+			src = value
+		case "target", "dst", "destination":
+			dst = value
+		case "readonly", "ro":
+			readonly, err = strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for %s: %s", key, value)
+			}
+			if readonly {
+				rwOptions = append(rwOptions, key)
+			}
+		case "replace":
+			replace, err = strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for %s: %s", key, value)
+			}
+		default:
+			return nil, fmt.Errorf("unexpected key '%s' in '%s'", key, field)
+		}
+	}
+
+	if err := validateWriteModeOpts(rwOptions); err != nil {
+		return nil, err
+	}
+
+	if mountType == Npipe {
+		if !isNamedPipe(src) {
+			return nil, fmt.Errorf("invalid mount source %q for type=npipe: must be a named pipe", src)
+		}
+		if !isNamedPipe(dst) {
+			return nil, fmt.Errorf("invalid mount target %q for type=npipe: must be a named pipe", dst)
+		}
+	}
+
+	if mountType == Bind {
+		if err := validateWindowsBindSource(src); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateWindowsMountTarget(dst); err != nil {
+		return nil, err
+	}
+
+	// The fields are passed as they are, instead of being joined with ":" and split again,
+	// as ":" is also the separator of the drive letters, e.g., "C:\data"
+	fields := []string{src, dst}
+	if src == "" {
+		fields = []string{dst}
+	} else if readonly {
+		fields = append(fields, "ro")
+	}
+	log.L.Debugf("Call legacy %s process, fields: %q", mountType, fields)
+
+	// createDir=false for --mount option to disallow creating directories on host if not found
+	res, err := processVolumeSpec(s, fields, volStore, false, "")
+	if err != nil {
+		return nil, err
+	}
+	if src == "" && readonly {
+		res.Mount.Options = append([]string{"ro"}, res.Mount.Options...)
+	}
+	if res.Type != mountType {
+		return nil, fmt.Errorf("invalid mount source %q for type=%s", src, mountType)
+	}
+	res.Replace = replace
+	return res, nil
+}
+
+// windowsDriveAbsPath matches an absolute path with a drive letter, e.g., "C:\data" or "c:/data".
+var windowsDriveAbsPath = regexp.MustCompile(`^[a-zA-Z]:[\\/]`)
+
+// validateWindowsBindSource validates the source of a bind mount, which must be an absolute path
+// with a drive letter (e.g., "C:\data"), or a UNC path (e.g., "\\server\share\data").
+func validateWindowsBindSource(src string) error {
+	if src == "" {
+		return errors.New("invalid mount: source is required for type=bind")
+	}
+	// Named pipes are rejected later, as a mismatch of the mount type
+	if windowsDriveAbsPath.MatchString(src) || strings.HasPrefix(src, `\\`) {
+		return nil
+	}
+	return fmt.Errorf("invalid mount source %q for type=bind: expected an absolute path with a drive letter (e.g., C:\\data) or a UNC path", src)
+}
+
+// validateWindowsMountTarget validates the target of a mount, which must not be the root of a drive.
+// See https://github.com/moby/moby/blob/v28.0.0/volume/mounts/windows_parser.go#L196-L198
+func validateWindowsMountTarget(dst string) error {
+	if dst == "" {
+		return errors.New("invalid mount: target is required")
+	}
+	if isNamedPipe(dst) {
+		return nil
+	}
+	if !windowsDriveAbsPath.MatchString(dst) {
+		return fmt.Errorf("invalid mount target %q: expected an absolute path with a drive letter (e.g., C:\\data)", dst)
+	}
+	if len(strings.TrimRight(dst, `\/`)) == 2 {
+		return fmt.Errorf("invalid mount target %q: the root of a drive cannot be a mount target", dst)
+	}
+	return nil
 }
 
 func handleVolumeToMount(source string, dst string, volStore volumestore.VolumeStore, createDir bool) (volumeSpec, error) {
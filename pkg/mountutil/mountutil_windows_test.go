@@ -67,7 +67,7 @@ func TestParseVolumeOptions(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(strings.Join([]string{tt.vType, tt.src, tt.optsRaw}, "-"), func(t *testing.T) {
-			opts, _, err := parseVolumeOptions(tt.vType, tt.src, tt.optsRaw)
+			opts, _, err := parseVolumeOptions(tt.vType, tt.src, tt.optsRaw, "")
 			if err != nil {
 				if tt.wantFail {
 					return
@@ -343,3 +343,137 @@ func TestProcessFlagVAnonymousVolumes(t *testing.T) {
 		})
 	}
 }
+
+func TestProcessFlagMount(t *testing.T) {
+	tests := []struct {
+		rawSpec string
+		wants   *Processed
+		err     string
+	}{
+		{
+			rawSpec: `type=npipe,source=\\.\pipe\containerd-containerd,target=\\.\pipe\containerd-containerd`,
+			wants: &Processed{
+				Type: "npipe",
+				Mount: specs.Mount{
+					Type:        "",
+					Source:      `\\.\pipe\containerd-containerd`,
+					Destination: `\\.\pipe\containerd-containerd`,
+					Options:     []string{"rbind"},
+				}},
+		},
+		{
+			rawSpec: `type=npipe,src=\\.\pipe\docker_engine,dst=\\.\pipe\docker_engine,readonly`,
+			wants: &Processed{
+				Type: "npipe",
+				Mount: specs.Mount{
+					Type:        "",
+					Source:      `\\.\pipe\docker_engine`,
+					Destination: `\\.\pipe\docker_engine`,
+					Options:     []string{"ro", "rbind"},
+				}},
+		},
+		{
+			rawSpec: `type=volume,src=TestVolume,dst=C:\TestVolume\Path`,
+			wants: &Processed{
+				Type: "volume",
+				Name: "TestVolume",
+				Mount: specs.Mount{
+					Type:        "",
+					Destination: `C:\TestVolume\Path`,
+					Options:     []string{"rbind"},
+				}},
+		},
+		{
+			rawSpec: `type=npipe,src=C:\TestVolume\Path,dst=\\.\pipe\containerd-containerd`,
+			err:     `invalid mount source "C:\\TestVolume\\Path" for type=npipe: must be a named pipe`,
+		},
+		{
+			rawSpec: `type=npipe,src=\\.\pipe\containerd-containerd,dst=C:\TestVolume\Path`,
+			err:     `invalid mount target "C:\\TestVolume\\Path" for type=npipe: must be a named pipe`,
+		},
+		{
+			rawSpec: `type=bind,src=\\.\pipe\containerd-containerd,dst=\\.\pipe\containerd-containerd`,
+			err:     `invalid mount source "\\\\.\\pipe\\containerd-containerd" for type=bind`,
+		},
+		{
+			rawSpec: `type=npipe,src=\\.\pipe\docker_engine,dst=\\.\pipe\docker_engine,readonly,rw`,
+			err:     "conflicting read-only and read-write options: [readonly rw]",
+		},
+		{
+			rawSpec: `type=bind,src=C:\data,dst=C:\data`,
+			wants: &Processed{
+				Type: "bind",
+				Mount: specs.Mount{
+					Type:        "",
+					Source:      `C:\data`,
+					Destination: `C:\data`,
+					Options:     []string{"rbind"},
+				}},
+		},
+		{
+			rawSpec: `type=bind,source=c:/data/app,target=D:\app\,readonly`,
+			wants: &Processed{
+				Type: "bind",
+				Mount: specs.Mount{
+					Type:        "",
+					Source:      `c:\data\app`,
+					Destination: `D:\app`,
+					Options:     []string{"ro", "rbind"},
+				}},
+		},
+		{
+			rawSpec: `type=bind,src=\\server\share\data,dst=C:\data`,
+			wants: &Processed{
+				Type: "bind",
+				Mount: specs.Mount{
+					Type:        "",
+					Source:      `\\server\share\data`,
+					Destination: `C:\data`,
+					Options:     []string{"rbind"},
+				}},
+		},
+		{
+			rawSpec: `type=bind,src=data,dst=C:\data`,
+			err:     `invalid mount source "data" for type=bind: expected an absolute path with a drive letter (e.g., C:\data) or a UNC path`,
+		},
+		{
+			rawSpec: `type=bind,src=C:data,dst=C:\data`,
+			err:     `invalid mount source "C:data" for type=bind: expected an absolute path with a drive letter (e.g., C:\data) or a UNC path`,
+		},
+		{
+			rawSpec: `type=bind,src=C:\data,dst=C:\`,
+			err:     `invalid mount target "C:\\": the root of a drive cannot be a mount target`,
+		},
+		{
+			rawSpec: `type=bind,src=C:\data,dst=data`,
+			err:     `invalid mount target "data": expected an absolute path with a drive letter (e.g., C:\data)`,
+		},
+		{
+			rawSpec: `type=tmpfs,dst=C:\TestVolume\Path`,
+			err:     "invalid mount type 'tmpfs' must be a volume/bind/npipe",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rawSpec, func(t *testing.T) {
+			processedVolSpec, err := ProcessFlagMount(tt.rawSpec, mockVolumeStore)
+			if tt.err != "" {
+				assert.Error(t, err, tt.err)
+				return
+			}
+			assert.NilError(t, err)
+
+			assert.Equal(t, processedVolSpec.Type, tt.wants.Type)
+			assert.Equal(t, processedVolSpec.Mount.Type, tt.wants.Mount.Type)
+			assert.Equal(t, processedVolSpec.Mount.Destination, tt.wants.Mount.Destination)
+			assert.DeepEqual(t, processedVolSpec.Mount.Options, tt.wants.Mount.Options)
+
+			if tt.wants.Name != "" {
+				assert.Equal(t, processedVolSpec.Name, tt.wants.Name)
+			}
+			if tt.wants.Mount.Source != "" {
+				assert.Equal(t, processedVolSpec.Mount.Source, tt.wants.Mount.Source)
+			}
+		})
+	}
+}
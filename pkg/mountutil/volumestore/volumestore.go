@@ -20,6 +20,7 @@
 package volumestore
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -31,6 +32,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/native"
 	"github.com/containerd/nerdctl/v2/pkg/store"
 	"github.com/containerd/nerdctl/v2/pkg/strutil"
+	"github.com/containerd/nerdctl/v2/pkg/volumedriver"
 )
 
 const (
@@ -68,6 +70,11 @@ type VolumeStore interface {
 	// It is meant to be used between `Lock` and `Release`, and is specifically useful when multiple different volume
 	// creation will have to happen in different method calls (eg: container create).
 	CreateWithoutLock(name string, labels []string) (*native.Volume, error)
+	// CreateWithDriver is like Create, but the volume is created by the given driver (see volumedriver) with opts.
+	// The store only keeps the metadata of the volumes of non-local drivers.
+	CreateWithDriver(name string, labels []string, driver string, opts map[string]string) (*native.Volume, error)
+	// CreateWithDriverWithoutLock is to CreateWithDriver what CreateWithoutLock is to Create.
+	CreateWithDriverWithoutLock(name string, labels []string, driver string, opts map[string]string) (*native.Volume, error)
 	// Release: see store implementation
 	Release() error
 }
@@ -147,7 +154,11 @@ func (vs *volumeStore) Get(name string, size bool) (vol *native.Volume, err erro
 // volStore.Lock()
 // defer volStore.Release()
 // volStore.CreateWithoutLock(...)
-func (vs *volumeStore) CreateWithoutLock(name string, labels []string) (vol *native.Volume, err error) {
+func (vs *volumeStore) CreateWithoutLock(name string, labels []string) (*native.Volume, error) {
+	return vs.CreateWithDriverWithoutLock(name, labels, "", nil)
+}
+
+func (vs *volumeStore) CreateWithDriverWithoutLock(name string, labels []string, driver string, opts map[string]string) (vol *native.Volume, err error) {
 	defer func() {
 		if err != nil {
 			err = errors.Join(ErrVolumeStore, err)
@@ -158,10 +169,14 @@ func (vs *volumeStore) CreateWithoutLock(name string, labels []string) (vol *nat
 		return nil, err
 	}
 
-	return vs.rawCreate(name, labels)
+	return vs.rawCreate(name, labels, driver, opts)
 }
 
 func (vs *volumeStore) Create(name string, labels []string) (vol *native.Volume, err error) {
+	return vs.CreateWithDriver(name, labels, "", nil)
+}
+
+func (vs *volumeStore) CreateWithDriver(name string, labels []string, driver string, opts map[string]string) (vol *native.Volume, err error) {
 	defer func() {
 		if err != nil {
 			err = errors.Join(ErrVolumeStore, err)
@@ -173,7 +188,7 @@ func (vs *volumeStore) Create(name string, labels []string) (vol *native.Volume,
 	}
 
 	err = vs.Locker.WithLock(func() error {
-		vol, err = vs.rawCreate(name, labels)
+		vol, err = vs.rawCreate(name, labels, driver, opts)
 		return err
 	})
 
@@ -263,6 +278,10 @@ func (vs *volumeStore) Remove(generator func() ([]string, []error, error)) (remo
 				// TODO: see above
 				warns = append(warns, fmt.Errorf("volume %q: %w", name, store.ErrNotFound))
 				continue
+			} else if err = vs.removeFromDriver(name); err != nil {
+				// Failing to remove the volume from its driver is a soft error, the volume is kept
+				warns = append(warns, err)
+				continue
 			} else if err = vs.manager.Delete(name); err != nil {
 				return err
 			}
@@ -306,6 +325,10 @@ func (vs *volumeStore) Prune(filter func(vol []*native.Volume) ([]string, error)
 		}
 
 		for _, name := range toDelete {
+			if err = vs.removeFromDriver(name); err != nil {
+				log.L.WithError(err).Warnf("failed to remove volume %q from its driver", name)
+				continue
+			}
 			err = vs.manager.Delete(name)
 			if err != nil {
 				return err
@@ -322,9 +345,18 @@ func (vs *volumeStore) rawGet(name string, size bool) (vol *native.Volume, err e
 		return nil, err
 	}
 
+	volOpts := parseVolumeOpts(content)
 	vol = &native.Volume{
-		Name:   name,
-		Labels: labels(content),
+		Name:    name,
+		Labels:  volOpts.Labels,
+		Driver:  volOpts.Driver,
+		Options: volOpts.Options,
+	}
+
+	// The data of the volumes of other drivers is not in the store,
+	// and their mountpoint is only known once mounted by a container.
+	if !volumedriver.IsLocal(vol.Driver) {
+		return vol, nil
 	}
 
 	vol.Mountpoint, err = vs.manager.Location(name, dataDirName)
@@ -342,14 +374,20 @@ func (vs *volumeStore) rawGet(name string, size bool) (vol *native.Volume, err e
 	return vol, nil
 }
 
-func (vs *volumeStore) rawCreate(name string, labels []string) (vol *native.Volume, err error) {
+func (vs *volumeStore) rawCreate(name string, labels []string, driver string, opts map[string]string) (vol *native.Volume, err error) {
 	volOpts := struct {
-		Labels map[string]string `json:"labels"`
+		Labels  map[string]string `json:"labels"`
+		Driver  string            `json:"driver,omitempty"`
+		Options map[string]string `json:"options,omitempty"`
 	}{}
 
 	if len(labels) > 0 {
 		volOpts.Labels = strutil.ConvertKVStringsToMap(labels)
 	}
+	if !volumedriver.IsLocal(driver) {
+		volOpts.Driver = driver
+		volOpts.Options = opts
+	}
 
 	// Failure here must exit, no need to clean-up
 	labelsJSON, err := json.MarshalIndent(volOpts, "", "    ")
@@ -360,6 +398,17 @@ func (vs *volumeStore) rawCreate(name string, labels []string) (vol *native.Volu
 	if doesExist, err := vs.manager.Exists(name, volumeJSONFileName); err != nil {
 		return nil, err
 	} else if !doesExist {
+		if volOpts.Driver != "" {
+			// The volume store does not take a context
+			ctx := context.TODO()
+			d, err := volumedriver.Get(ctx, volOpts.Driver)
+			if err != nil {
+				return nil, err
+			}
+			if err = d.Create(ctx, name, opts); err != nil {
+				return nil, fmt.Errorf("failed to create volume %q with driver %q: %w", name, volOpts.Driver, err)
+			}
+		}
 		if err = vs.manager.Set(labelsJSON, name, volumeJSONFileName); err != nil {
 			return nil, err
 		}
@@ -369,8 +418,16 @@ func (vs *volumeStore) rawCreate(name string, labels []string) (vol *native.Volu
 	}
 
 	// At this point, we either have an existing volume, or created a new one successfully
+	content, err := vs.manager.Get(name, volumeJSONFileName)
+	if err != nil {
+		return nil, err
+	}
 	vol = &native.Volume{
-		Name: name,
+		Name:   name,
+		Driver: parseVolumeOpts(content).Driver,
+	}
+	if !volumedriver.IsLocal(vol.Driver) {
+		return vol, nil
 	}
 
 	if err = vs.manager.GroupEnsure(name, dataDirName); err != nil {
@@ -384,14 +441,39 @@ func (vs *volumeStore) rawCreate(name string, labels []string) (vol *native.Volu
 	return vol, nil
 }
 
-// Private helpers
-func labels(b []byte) *map[string]string {
-	type volumeOpts struct {
-		Labels *map[string]string `json:"labels,omitempty"`
+// removeFromDriver removes the volume from its driver, if it is not the local driver.
+func (vs *volumeStore) removeFromDriver(name string) error {
+	content, err := vs.manager.Get(name, volumeJSONFileName)
+	if err != nil {
+		return err
+	}
+	driver := parseVolumeOpts(content).Driver
+	if volumedriver.IsLocal(driver) {
+		return nil
 	}
+	// The volume store does not take a context
+	ctx := context.TODO()
+	d, err := volumedriver.Get(ctx, driver)
+	if err != nil {
+		return fmt.Errorf("failed to remove volume %q: %w", name, err)
+	}
+	if err = d.Remove(ctx, name); err != nil {
+		return fmt.Errorf("failed to remove volume %q with driver %q: %w", name, driver, err)
+	}
+	return nil
+}
+
+// Private helpers
+type volumeOpts struct {
+	Labels  *map[string]string `json:"labels,omitempty"`
+	Driver  string             `json:"driver,omitempty"`
+	Options map[string]string  `json:"options,omitempty"`
+}
+
+func parseVolumeOpts(b []byte) volumeOpts {
 	var vo volumeOpts
 	if err := json.Unmarshal(b, &vo); err != nil {
-		return nil
+		return volumeOpts{}
 	}
-	return vo.Labels
+	return vo
 }
@@ -19,6 +19,8 @@ package netutil
 import (
 	"testing"
 
+	"gotest.tools/v3/assert"
+
 	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
 )
 
@@ -32,3 +34,62 @@ func TestDefaultNetworkCreation(t *testing.T) {
 	testDefaultNetworkCreation(t)
 	testDefaultNetworkCreationWithBridgeIP(t)
 }
+
+func TestGenerateVLANPlugins(t *testing.T) {
+	e := &CNIEnv{}
+	ipam := map[string]interface{}{"type": "host-local"}
+
+	type testCase struct {
+		driver string
+		opts   map[string]string
+		mode   string
+		err    string
+	}
+	testCases := []testCase{
+		{
+			driver: "macvlan",
+			opts:   map[string]string{"parent": "lo", "macvlan_mode": "vepa"},
+			mode:   "vepa",
+		},
+		{
+			driver: "macvlan",
+			opts:   map[string]string{"mode": "passthru"},
+			mode:   "passthru",
+		},
+		{
+			driver: "ipvlan",
+			opts:   map[string]string{"parent": "lo", "ipvlan_mode": "l3s"},
+			mode:   "l3s",
+		},
+		{
+			driver: "macvlan",
+			opts:   map[string]string{"macvlan_mode": "l2"},
+			err:    "unknown macvlan mode",
+		},
+		{
+			driver: "ipvlan",
+			opts:   map[string]string{"macvlan_mode": "bridge"},
+			err:    "unsupported \"ipvlan\" network option",
+		},
+		{
+			driver: "ipvlan",
+			opts:   map[string]string{"parent": "nonexistent0"},
+			err:    "invalid parent interface \"nonexistent0\"",
+		},
+	}
+
+	for _, tc := range testCases {
+		plugins, err := e.generateCNIPlugins(tc.driver, "test", ipam, tc.opts, false)
+		if tc.err != "" {
+			assert.ErrorContains(t, err, tc.err)
+			continue
+		}
+		assert.NilError(t, err)
+		assert.Equal(t, len(plugins), 1)
+		vlan, ok := plugins[0].(*vlanConfig)
+		assert.Assert(t, ok)
+		assert.Equal(t, vlan.PluginType, tc.driver)
+		assert.Equal(t, vlan.Master, tc.opts["parent"])
+		assert.Equal(t, vlan.Mode, tc.mode)
+	}
+}
@@ -51,6 +51,12 @@ const (
 	StartingCIDR = "10.4.1.0/24"
 )
 
+var (
+	// macvlanModes and ipvlanModes are the modes supported by the macvlan and ipvlan CNI plugins.
+	macvlanModes = []string{"bridge", "private", "vepa", "passthru"}
+	ipvlanModes  = []string{"l2", "l3", "l3s"}
+)
+
 func (n *NetworkConfig) subnets() []*net.IPNet {
 	var subnets []*net.IPNet
 	if len(n.Plugins) > 0 && n.Plugins[0].Network.Type == "bridge" {
@@ -154,11 +160,11 @@ func (e *CNIEnv) generateCNIPlugins(driver string, name string, ipam map[string]
 				}
 			case "mode", "macvlan_mode", "ipvlan_mode":
 				if driver == "macvlan" && opt != "ipvlan_mode" {
-					if !strutil.InStringSlice([]string{"bridge"}, v) {
+					if !strutil.InStringSlice(macvlanModes, v) {
 						return nil, fmt.Errorf("unknown macvlan mode %q", v)
 					}
 				} else if driver == "ipvlan" && opt != "macvlan_mode" {
-					if !strutil.InStringSlice([]string{"l2", "l3"}, v) {
+					if !strutil.InStringSlice(ipvlanModes, v) {
 						return nil, fmt.Errorf("unknown ipvlan mode %q", v)
 					}
 				} else {
@@ -171,6 +177,12 @@ func (e *CNIEnv) generateCNIPlugins(driver string, name string, ipam map[string]
 				return nil, fmt.Errorf("unsupported %q network option %q", driver, opt)
 			}
 		}
+		// When the parent is not specified, the plugin uses the interface of the default route.
+		if master != "" {
+			if _, err := net.InterfaceByName(master); err != nil {
+				return nil, fmt.Errorf("invalid parent interface %q for %q network: %w", master, driver, err)
+			}
+		}
 		vlan := newVLANPlugin(driver)
 		vlan.MTU = mtu
 		vlan.Master = master
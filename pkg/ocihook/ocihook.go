@@ -26,6 +26,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -34,12 +35,14 @@ import (
 	b4nndclient "github.com/rootless-containers/bypass4netns/pkg/api/daemon/client"
 	rlkclient "github.com/rootless-containers/rootlesskit/v2/pkg/api/client"
 
+	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/go-cni"
 	"github.com/containerd/log"
 
 	"github.com/containerd/nerdctl/v2/pkg/bypass4netnsutil"
 	"github.com/containerd/nerdctl/v2/pkg/dnsutil/hostsstore"
 	"github.com/containerd/nerdctl/v2/pkg/internal/filesystem"
+	"github.com/containerd/nerdctl/v2/pkg/ipstore"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
 	"github.com/containerd/nerdctl/v2/pkg/namestore"
 	"github.com/containerd/nerdctl/v2/pkg/netutil"
@@ -50,6 +53,10 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/store"
 )
 
+// maxReservedIPRetries is the number of times the allocation of a dynamic IP address is retried, when IPAM hands
+// out an address reserved by another container.
+const maxReservedIPRetries = 16
+
 const (
 	// NetworkNamespace is the network namespace path to be passed to the CNI plugins.
 	// When this annotation is set from the runtime spec.State payload, it takes
@@ -63,7 +70,7 @@ const (
 	NetworkNamespace = labels.Prefix + "network-namespace"
 )
 
-func Run(stdin io.Reader, stderr io.Writer, event, dataStore, cniPath, cniNetconfPath, bridgeIP string) error {
+func Run(stdin io.Reader, stderr io.Writer, event, dataStore, cniPath, cniNetconfPath, bridgeIP, address string) error {
 	if stdin == nil || event == "" || dataStore == "" || cniPath == "" || cniNetconfPath == "" {
 		return errors.New("got insufficient args")
 	}
@@ -95,6 +102,14 @@ func Run(stdin io.Reader, stderr io.Writer, event, dataStore, cniPath, cniNetcon
 		}
 	}()
 
+	// Skip the restart of a container that keeps exiting too early, until its backoff expires.
+	// This must happen before taking the global lock below, so that other containers are not blocked.
+	if event == "createRuntime" {
+		if err := checkRestartBackoff(&state, address); err != nil {
+			return err
+		}
+	}
+
 	// FIXME: CNI plugins are not safe to use concurrently
 	// See
 	// https://github.com/containerd/nerdctl/issues/3518
@@ -117,7 +132,7 @@ func Run(stdin io.Reader, stderr io.Writer, event, dataStore, cniPath, cniNetcon
 	}
 	defer filesystem.Unlock(lock)
 
-	opts, err := newHandlerOpts(&state, dataStore, cniPath, cniNetconfPath, bridgeIP)
+	opts, err := newHandlerOpts(&state, dataStore, cniPath, cniNetconfPath, bridgeIP, address)
 	if err != nil {
 		return err
 	}
@@ -132,10 +147,11 @@ func Run(stdin io.Reader, stderr io.Writer, event, dataStore, cniPath, cniNetcon
 	}
 }
 
-func newHandlerOpts(state *specs.State, dataStore, cniPath, cniNetconfPath, bridgeIP string) (*handlerOpts, error) {
+func newHandlerOpts(state *specs.State, dataStore, cniPath, cniNetconfPath, bridgeIP, address string) (*handlerOpts, error) {
 	o := &handlerOpts{
 		state:     state,
 		dataStore: dataStore,
+		address:   address,
 	}
 
 	extraHosts, err := getExtraHosts(state)
@@ -173,6 +189,12 @@ func newHandlerOpts(state *specs.State, dataStore, cniPath, cniNetconfPath, brid
 		return nil, err
 	}
 
+	if mtu, ok := o.state.Annotations[labels.NetworkMTU]; ok {
+		if o.containerMTU, err = strconv.Atoi(mtu); err != nil {
+			return nil, fmt.Errorf("invalid %s annotation %q: %w", labels.NetworkMTU, mtu, err)
+		}
+	}
+
 	switch netType {
 	case nettype.Host, nettype.None, nettype.Container, nettype.Namespace:
 		// NOP
@@ -189,7 +211,13 @@ func newHandlerOpts(state *specs.State, dataStore, cniPath, cniNetconfPath, brid
 			if netw, err = e.NetworkByNameOrID(netstr); err != nil {
 				return nil, err
 			}
-			cniOpts = append(cniOpts, cni.WithConfListBytes(netw.Bytes))
+			confList := netw.Bytes
+			if o.containerMTU != 0 {
+				if confList, err = withNetworkMTU(confList, o.containerMTU); err != nil {
+					return nil, fmt.Errorf("failed to set the MTU of network %q: %w", netstr, err)
+				}
+			}
+			cniOpts = append(cniOpts, cni.WithConfListBytes(confList))
 			o.cniNames = append(o.cniNames, netstr)
 		}
 		o.cni, err = cni.New(cniOpts...)
@@ -253,6 +281,7 @@ func newHandlerOpts(state *specs.State, dataStore, cniPath, cniNetconfPath, brid
 type handlerOpts struct {
 	state             *specs.State
 	dataStore         string
+	address           string
 	rootfs            string
 	ports             []cni.PortMapping
 	cni               cni.CNI
@@ -264,6 +293,42 @@ type handlerOpts struct {
 	containerIP       string
 	containerMAC      string
 	containerIP6      string
+	containerMTU      int
+}
+
+// mtuPluginTypes are the CNI plugins that create the interface of the container and accept the "mtu" key.
+var mtuPluginTypes = map[string]struct{}{
+	"bridge":  {},
+	"ipvlan":  {},
+	"macvlan": {},
+	"ptp":     {},
+	"vlan":    {},
+}
+
+// withNetworkMTU returns the CNI network configuration list confList with the "mtu" key of its plugins
+// creating the interface of the container set to mtu, so that the plugins create the interface with this MTU.
+func withNetworkMTU(confList []byte, mtu int) ([]byte, error) {
+	var conf map[string]any
+	if err := json.Unmarshal(confList, &conf); err != nil {
+		return nil, err
+	}
+	plugins, _ := conf["plugins"].([]any)
+	found := false
+	for _, p := range plugins {
+		plugin, ok := p.(map[string]any)
+		if !ok {
+			continue
+		}
+		pluginType, _ := plugin["type"].(string)
+		if _, ok := mtuPluginTypes[pluginType]; ok {
+			plugin["mtu"] = mtu
+			found = true
+		}
+	}
+	if !found {
+		return nil, errors.New("none of the plugins of the network supports setting the MTU")
+	}
+	return json.Marshal(conf)
 }
 
 // hookSpec is from https://github.com/containerd/containerd/blob/v1.4.3/cmd/containerd/command/oci-hook.go#L59-L64
@@ -420,6 +485,78 @@ func getIP6AddressOpts(opts *handlerOpts) ([]cni.NamespaceOpts, error) {
 	return nil, nil
 }
 
+// setupAvoidingReservedIPs calls cni.Setup.
+// For containers without a static IP address, the setup is retried as long as IPAM hands out an address reserved
+// by another container with --ip (see pkg/ipstore). As host-local allocates addresses round-robin, every retry
+// yields the next free address.
+func setupAvoidingReservedIPs(ctx context.Context, opts *handlerOpts, nsPath string, namespaceOpts []cni.NamespaceOpts) (*cni.Result, error) {
+	for attempt := 0; ; attempt++ {
+		cniRes, err := opts.cni.Setup(ctx, opts.fullID, nsPath, namespaceOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to call cni.Setup: %w", err)
+		}
+		if opts.containerIP != "" {
+			return cniRes, nil
+		}
+		reserved, err := findReservedIP(ctx, opts, cniRes)
+		if err != nil || reserved == "" {
+			return cniRes, err
+		}
+		if attempt == maxReservedIPRetries {
+			return cniRes, fmt.Errorf("failed to allocate an IP address: %s (and the following ones) are reserved by other containers", reserved)
+		}
+		log.L.Debugf("IP address %s is reserved by another container, allocating another one", reserved)
+		if err := opts.cni.Remove(ctx, opts.fullID, nsPath, namespaceOpts...); err != nil {
+			return nil, fmt.Errorf("failed to call cni.Remove: %w", err)
+		}
+	}
+}
+
+// findReservedIP returns the first IPv4 address of cniRes that is reserved on its network, if any.
+// The reservations of the containers that have been removed, or no longer use that address, are ignored.
+func findReservedIP(ctx context.Context, opts *handlerOpts, cniRes *cni.Result) (string, error) {
+	ips, err := ipstore.New(opts.dataStore)
+	if err != nil {
+		return "", err
+	}
+	var client *containerd.Client
+	defer func() {
+		if client != nil {
+			client.Close()
+		}
+	}()
+	for i, res := range cniRes.Raw() {
+		if i >= len(opts.cniNames) {
+			break
+		}
+		for _, ipConfig := range res.IPs {
+			ip := ipConfig.Address.IP.To4()
+			if ip == nil {
+				continue
+			}
+			owner, err := ips.Lookup(opts.cniNames[i], ip.String())
+			if errors.Is(err, store.ErrNotFound) {
+				continue
+			} else if err != nil {
+				return "", err
+			}
+			if client == nil {
+				if client, err = containerd.New(strings.TrimPrefix(opts.address, "unix://")); err != nil {
+					// Without containerd, the reservation cannot be checked and is assumed to be valid
+					log.L.WithError(err).Warnf("failed to check the reservation of IP address %s", ip)
+					return ip.String(), nil
+				}
+			}
+			if ipstore.StaleReservation(ctx, client, opts.cniNames[i], ip.String())(owner) {
+				log.L.Debugf("ignoring the stale reservation of IP address %s by container %q", ip, owner.ID)
+				continue
+			}
+			return ip.String(), nil
+		}
+	}
+	return "", nil
+}
+
 func applyNetworkSettings(opts *handlerOpts) (err error) {
 	portMapOpts, err := getPortMapOpts(opts)
 	if err != nil {
@@ -485,9 +622,9 @@ func applyNetworkSettings(opts *handlerOpts) (err error) {
 		}
 	}()
 
-	cniRes, err := opts.cni.Setup(ctx, opts.fullID, nsPath, namespaceOpts...)
+	cniRes, err := setupAvoidingReservedIPs(ctx, opts, nsPath, namespaceOpts)
 	if err != nil {
-		return fmt.Errorf("failed to call cni.Setup: %w", err)
+		return err
 	}
 
 	cniResRaw := cniRes.Raw()
@@ -549,6 +686,7 @@ func onCreateRuntime(opts *handlerOpts) error {
 
 	err = lf.Transform(func(lf *state.Store) error {
 		lf.StartedAt = time.Now()
+		lf.ExitRecorded = false
 		lf.CreateError = netError != nil
 		return nil
 	})
@@ -0,0 +1,52 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ocihook
+
+import (
+	"encoding/json"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestWithNetworkMTU(t *testing.T) {
+	confList := []byte(`{
+  "cniVersion": "1.0.0",
+  "name": "bridge",
+  "plugins": [
+    {"type": "bridge", "bridge": "nerdctl0", "mtu": 1500},
+    {"type": "portmap", "capabilities": {"portMappings": true}}
+  ]
+}`)
+	b, err := withNetworkMTU(confList, 1400)
+	assert.NilError(t, err)
+
+	var conf struct {
+		Name    string           `json:"name"`
+		Plugins []map[string]any `json:"plugins"`
+	}
+	assert.NilError(t, json.Unmarshal(b, &conf))
+	assert.Equal(t, conf.Name, "bridge")
+	assert.Equal(t, len(conf.Plugins), 2)
+	assert.Equal(t, conf.Plugins[0]["mtu"], float64(1400))
+	assert.Equal(t, conf.Plugins[0]["bridge"], "nerdctl0")
+	_, ok := conf.Plugins[1]["mtu"]
+	assert.Assert(t, !ok)
+
+	_, err = withNetworkMTU([]byte(`{"name": "foo", "plugins": [{"type": "portmap"}]}`), 1400)
+	assert.ErrorContains(t, err, "none of the plugins")
+}
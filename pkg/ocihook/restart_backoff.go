@@ -0,0 +1,96 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ocihook
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/ocihook/state"
+)
+
+// checkRestartBackoff fails the creation of a container that kept exiting before running for its minimum uptime,
+// until the time recorded in its labels.RestartNextStart label by containerutil.RecordRestartBackoff.
+// The restart monitor of containerd then tries again to start the container on its next reconciliation.
+func checkRestartBackoff(st *specs.State, address string) error {
+	if _, ok := st.Annotations[labels.RestartMinUptime]; !ok {
+		return nil
+	}
+	containerLabels, err := containerLabels(st, address)
+	if err != nil {
+		log.L.WithError(err).Warn("failed to get the labels of the container, not applying the restart backoff")
+		return nil
+	}
+	backoffErr := restartBackoffError(containerLabels, time.Now())
+	if backoffErr == nil {
+		return nil
+	}
+	// The postStop hook is called after a failed createRuntime hook, and must not treat the container as deleted
+	lf, err := state.New(st.Annotations[labels.StateDir])
+	if err != nil {
+		return err
+	}
+	if err := lf.Transform(func(lf *state.Store) error {
+		lf.CreateError = true
+		return nil
+	}); err != nil {
+		return err
+	}
+	return backoffErr
+}
+
+// restartBackoffError returns an error if the labels of a container record that it must not be restarted
+// before a time later than now.
+func restartBackoffError(containerLabels map[string]string, now time.Time) error {
+	v := containerLabels[labels.RestartNextStart]
+	if v == "" {
+		return nil
+	}
+	nextStart, err := time.Parse(time.RFC3339Nano, v)
+	if err != nil {
+		log.L.WithError(err).Warnf("ignoring the invalid %s label %q", labels.RestartNextStart, v)
+		return nil
+	}
+	if now.Before(nextStart) {
+		return fmt.Errorf("the container kept exiting before running for its minimum uptime, not restarting it until %s", v)
+	}
+	return nil
+}
+
+// containerLabels returns the labels of the container from containerd.
+func containerLabels(st *specs.State, address string) (map[string]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client, err := containerd.New(strings.TrimPrefix(address, "unix://"), containerd.WithDefaultNamespace(st.Annotations[labels.Namespace]))
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	c, err := client.LoadContainer(ctx, st.ID)
+	if err != nil {
+		return nil, err
+	}
+	return c.Labels(ctx)
+}
@@ -0,0 +1,39 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ocihook
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+)
+
+func TestRestartBackoffError(t *testing.T) {
+	now := time.Now()
+	nextStart := now.Add(10 * time.Second).Format(time.RFC3339Nano)
+
+	assert.ErrorContains(t, restartBackoffError(map[string]string{labels.RestartNextStart: nextStart}, now),
+		"not restarting it until "+nextStart)
+	assert.NilError(t, restartBackoffError(map[string]string{labels.RestartNextStart: nextStart}, now.Add(time.Minute)))
+	// the backoff is reset by an explicit start
+	assert.NilError(t, restartBackoffError(map[string]string{labels.RestartNextStart: ""}, now))
+	assert.NilError(t, restartBackoffError(map[string]string{}, now))
+	assert.NilError(t, restartBackoffError(map[string]string{labels.RestartNextStart: "invalid"}, now))
+}
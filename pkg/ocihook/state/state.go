@@ -58,6 +58,11 @@ type Store struct {
 	// StartedAt reflects the time at which we received the oci-hook onCreateRuntime event
 	StartedAt   time.Time `json:"started_at"`
 	CreateError bool      `json:"create_error"`
+	// RapidExits is the number of consecutive exits that happened before the container
+	// ran for its minimum uptime (--restart-min-uptime)
+	RapidExits int `json:"rapid_exits,omitempty"`
+	// ExitRecorded is set once the exit of the container started at StartedAt is counted in RapidExits
+	ExitRecorded bool `json:"exit_recorded,omitempty"`
 }
 
 // Load will populate the struct with existing in-store lifecycle information
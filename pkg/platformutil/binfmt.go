@@ -46,7 +46,7 @@ func canExecProbably(s string) (bool, error) {
 	if s == "" {
 		return true, nil
 	}
-	p, err := platforms.Parse(s)
+	p, err := Parse(s)
 	if err != nil {
 		return false, err
 	}
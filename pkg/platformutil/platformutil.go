@@ -18,6 +18,7 @@ package platformutil
 
 import (
 	"fmt"
+	"strings"
 
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 
@@ -62,7 +63,7 @@ func NewOCISpecPlatformSlice(all bool, ss []string) ([]ocispec.Platform, error)
 	if dss := strutil.DedupeStrSlice(ss); len(dss) > 0 {
 		var op []ocispec.Platform
 		for _, s := range dss {
-			p, err := platforms.Parse(s)
+			p, err := Parse(s)
 			if err != nil {
 				return nil, fmt.Errorf("invalid platform: %q", s)
 			}
@@ -77,10 +78,68 @@ func NormalizeString(s string) (string, error) {
 	if s == "" {
 		return platforms.DefaultString(), nil
 	}
-	parsed, err := platforms.Parse(s)
+	parsed, err := Parse(s)
 	if err != nil {
 		return "", err
 	}
-	normalized := platforms.Normalize(parsed)
-	return platforms.Format(normalized), nil
+	return platforms.Format(parsed), nil
+}
+
+// knownOSes is the list of the OS components accepted by Parse.
+var knownOSes = []string{
+	"aix", "android", "darwin", "dragonfly", "freebsd", "illumos", "ios", "js",
+	"linux", "netbsd", "openbsd", "plan9", "solaris", "wasip1", "windows",
+}
+
+// archVariantAliases maps the architecture spellings that embed the variant
+// (e.g., "armv7" as reported by `uname -m`) to their canonical architecture and variant.
+var archVariantAliases = map[string][2]string{
+	"armhf":  {"arm", "v7"},
+	"armv7":  {"arm", "v7"},
+	"armv7l": {"arm", "v7"},
+	"armel":  {"arm", "v6"},
+	"armv6":  {"arm", "v6"},
+	"armv6l": {"arm", "v6"},
+	"armv5":  {"arm", "v5"},
+	"armv5l": {"arm", "v5"},
+	"armv8":  {"arm64", "v8"},
+}
+
+// Parse parses a platform specifier like "linux/arm/v7", "arm/v7", "armv7l", or "windows(10.0.17763)/amd64",
+// and returns the normalized platform.
+//
+// Unlike platforms.Parse, Parse accepts "ARCH/VARIANT" without the OS component
+// and the architecture spellings that embed the variant (e.g., "armv7").
+func Parse(s string) (ocispec.Platform, error) {
+	// The OS version, e.g. "(10.0.17763)" in "windows(10.0.17763)/amd64", may contain "/"
+	// and is kept as is, it is not part of the OS to look up.
+	specifier, osVersion := s, ""
+	if i := strings.Index(specifier, "("); i >= 0 {
+		if j := strings.Index(specifier[i:], ")"); j >= 0 {
+			osVersion = specifier[i : i+j+1]
+			specifier = specifier[:i] + specifier[i+j+1:]
+		}
+	}
+	parts := strings.Split(strings.ToLower(specifier), "/")
+	if len(parts) > 0 && !strutil.InStringSlice(knownOSes, parts[0]) {
+		// "ARCH" or "ARCH/VARIANT": the OS is omitted
+		if len(parts) > 2 {
+			return ocispec.Platform{}, fmt.Errorf("invalid platform %q: unknown operating system %q", s, parts[0])
+		}
+		parts = append([]string{platforms.DefaultSpec().OS}, parts...)
+	}
+	if len(parts) >= 2 {
+		if alias, ok := archVariantAliases[parts[1]]; ok {
+			if len(parts) > 2 && parts[2] != alias[1] {
+				return ocispec.Platform{}, fmt.Errorf("invalid platform %q: variant %q conflicts with architecture %q", s, parts[2], parts[1])
+			}
+			parts = append([]string{parts[0]}, alias[:]...)
+		}
+	}
+	parts[0] += osVersion
+	p, err := platforms.Parse(strings.Join(parts, "/"))
+	if err != nil {
+		return ocispec.Platform{}, err
+	}
+	return platforms.Normalize(p), nil
 }
@@ -0,0 +1,78 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package platformutil
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/platforms"
+)
+
+func TestParse(t *testing.T) {
+	defaultOS := platforms.DefaultSpec().OS
+	tests := []struct {
+		platform  string
+		expected  string
+		osVersion string
+		err       string
+	}{
+		{platform: "linux/arm/v7", expected: "linux/arm/v7"},
+		{platform: "linux/arm", expected: "linux/arm/v7"},
+		{platform: "arm", expected: defaultOS + "/arm/v7"},
+		{platform: "arm/v7", expected: defaultOS + "/arm/v7"},
+		{platform: "armhf", expected: defaultOS + "/arm/v7"},
+		{platform: "armv7", expected: defaultOS + "/arm/v7"},
+		{platform: "linux/armv7l", expected: "linux/arm/v7"},
+		{platform: "linux/armv7/v7", expected: "linux/arm/v7"},
+		{platform: "armel", expected: defaultOS + "/arm/v6"},
+		{platform: "linux/armv6l", expected: "linux/arm/v6"},
+		{platform: "arm/v6", expected: defaultOS + "/arm/v6"},
+		{platform: "arm64", expected: defaultOS + "/arm64"},
+		{platform: "arm64/v8", expected: defaultOS + "/arm64"},
+		{platform: "linux/arm64/v8", expected: "linux/arm64"},
+		{platform: "aarch64", expected: defaultOS + "/arm64"},
+		{platform: "Linux/AMD64", expected: "linux/amd64"},
+		{platform: "x86_64", expected: defaultOS + "/amd64"},
+		{platform: "windows(10.0.17763)/amd64", expected: "windows/amd64", osVersion: "10.0.17763"},
+		{platform: "linux/armv7/v6", err: "conflicts with architecture"},
+		{platform: "foo/arm/v7", err: "unknown operating system"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.platform, func(t *testing.T) {
+			p, err := Parse(tc.platform)
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, platforms.Format(p), tc.expected)
+			assert.Equal(t, p.OSVersion, tc.osVersion)
+		})
+	}
+}
+
+func TestNormalizeString(t *testing.T) {
+	normalized, err := NormalizeString("armv7l")
+	assert.NilError(t, err)
+	assert.Equal(t, normalized, platforms.DefaultSpec().OS+"/arm/v7")
+
+	normalized, err = NormalizeString("")
+	assert.NilError(t, err)
+	assert.Equal(t, normalized, platforms.DefaultString())
+}
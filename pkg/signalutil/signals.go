@@ -18,15 +18,58 @@ package signalutil
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"os/signal"
+	"runtime"
+	"strconv"
+	"strings"
 	"syscall"
 
+	mobysignal "github.com/moby/sys/signal"
+
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/errdefs"
 	"github.com/containerd/log"
 )
 
+// linuxSignalNames are the names of the Linux signals, without the "SIG" prefix.
+// The real-time signals (RTMIN+n, RTMAX-n) are not listed.
+var linuxSignalNames = map[string]struct{}{
+	"ABRT": {}, "ALRM": {}, "BUS": {}, "CHLD": {}, "CLD": {}, "CONT": {}, "FPE": {}, "HUP": {},
+	"ILL": {}, "INT": {}, "IO": {}, "IOT": {}, "KILL": {}, "PIPE": {}, "POLL": {}, "PROF": {},
+	"PWR": {}, "QUIT": {}, "SEGV": {}, "STKFLT": {}, "STOP": {}, "SYS": {}, "TERM": {}, "TRAP": {},
+	"TSTP": {}, "TTIN": {}, "TTOU": {}, "URG": {}, "USR1": {}, "USR2": {}, "VTALRM": {}, "WINCH": {},
+	"XCPU": {}, "XFSZ": {},
+}
+
+// ParseSignal parses a signal name (e.g., "SIGTERM" or "TERM") or number, like signal.ParseSignal
+// of github.com/moby/sys/signal, and validates that the signal is supported by the containers of the
+// current platform.
+//
+// Linux signal names that are not supported by the current platform are rejected with an explicit error.
+func ParseSignal(rawSignal string) (syscall.Signal, error) {
+	sig, err := mobysignal.ParseSignal(rawSignal)
+	if err == nil && isSupportedSignal(sig) {
+		return sig, nil
+	}
+	if _, numErr := strconv.Atoi(rawSignal); numErr != nil && runtime.GOOS != "linux" && isLinuxSignalName(rawSignal) {
+		return -1, fmt.Errorf("signal %q is only supported on Linux", rawSignal)
+	}
+	if err != nil {
+		return -1, err
+	}
+	return -1, fmt.Errorf("signal %q is not supported on %s", rawSignal, runtime.GOOS)
+}
+
+func isLinuxSignalName(rawSignal string) bool {
+	name := strings.TrimPrefix(strings.ToUpper(rawSignal), "SIG")
+	if _, ok := linuxSignalNames[name]; ok {
+		return true
+	}
+	return strings.HasPrefix(name, "RTMIN") || strings.HasPrefix(name, "RTMAX")
+}
+
 // killer is from https://github.com/containerd/containerd/blob/v1.7.0-rc.2/cmd/ctr/commands/signals.go#L30-L32
 type killer interface {
 	Kill(context.Context, syscall.Signal, ...containerd.KillOpts) error
@@ -62,3 +105,22 @@ func StopCatch(sigc chan os.Signal) {
 	signal.Stop(sigc)
 	close(sigc)
 }
+
+// NotifyHangup returns a channel that receives SIGHUP, which is sent when the
+// controlling terminal is closed (e.g., when an SSH session is dropped).
+// Catching it replaces the default action of terminating the process, so that
+// the caller can still restore the console and clean up the IO.
+func NotifyHangup() chan os.Signal {
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGHUP)
+	return sigc
+}
+
+// NotifyInterrupt returns a channel that receives SIGINT and SIGTERM.
+// It is used when signals are not proxied to the container (`--sig-proxy=false`),
+// so that the caller can stop waiting for the container and exit on its own.
+func NotifyInterrupt() chan os.Signal {
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, os.Interrupt, syscall.SIGTERM)
+	return sigc
+}
@@ -18,7 +18,9 @@ package signalutil
 
 import (
 	"os"
+	"syscall"
 
+	mobysignal "github.com/moby/sys/signal"
 	"golang.org/x/sys/unix"
 )
 
@@ -26,3 +28,8 @@ import (
 func canIgnoreSignal(s os.Signal) bool {
 	return s == unix.SIGURG
 }
+
+// isSupportedSignal returns whether the signal is one of the Linux signals, including the real-time signals.
+func isSupportedSignal(sig syscall.Signal) bool {
+	return mobysignal.ValidSignalForPlatform(sig)
+}
@@ -0,0 +1,47 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package signalutil
+
+import (
+	"syscall"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseSignal(t *testing.T) {
+	t.Parallel()
+	for raw, expected := range map[string]syscall.Signal{
+		"SIGTERM":   syscall.SIGTERM,
+		"term":      syscall.SIGTERM,
+		"9":         syscall.SIGKILL,
+		"SIGSTKFLT": syscall.Signal(0x10),
+		"RTMIN+3":   syscall.Signal(0x25),
+		"64":        syscall.Signal(64),
+	} {
+		sig, err := ParseSignal(raw)
+		assert.NilError(t, err, raw)
+		assert.Equal(t, sig, expected, raw)
+	}
+
+	_, err := ParseSignal("0")
+	assert.ErrorContains(t, err, "invalid signal")
+	_, err = ParseSignal("SIGFOO")
+	assert.ErrorContains(t, err, "invalid signal")
+	_, err = ParseSignal("99")
+	assert.ErrorContains(t, err, `signal "99" is not supported on linux`)
+}
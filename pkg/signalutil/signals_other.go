@@ -1,4 +1,4 @@
-//go:build !linux
+//go:build !linux && !windows
 
 /*
    Copyright The containerd Authors.
@@ -18,9 +18,19 @@
 
 package signalutil
 
-import "os"
+import (
+	"os"
+	"syscall"
+
+	mobysignal "github.com/moby/sys/signal"
+)
 
 // canIgnoreSignal is from https://github.com/containerd/containerd/blob/v1.7.0-rc.2/cmd/ctr/commands/signals_notlinux.go#L23-L25
 func canIgnoreSignal(_ os.Signal) bool {
 	return false
 }
+
+// isSupportedSignal returns whether the signal is one of the signals of the current platform (e.g., FreeBSD).
+func isSupportedSignal(sig syscall.Signal) bool {
+	return mobysignal.ValidSignalForPlatform(sig)
+}
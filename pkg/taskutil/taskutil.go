@@ -216,24 +216,44 @@ type StdinCloser struct {
 	Stdin  *os.File
 	Closer func()
 	closed bool
+	// eof is set when Stdin has been read to the end (or failed) before Closer was set
+	eof bool
 }
 
 func (s *StdinCloser) Read(p []byte) (int, error) {
 	s.mu.Lock()
-	defer s.mu.Unlock()
-	if s.closed {
+	closed := s.closed
+	s.mu.Unlock()
+	if closed {
 		return 0, syscall.EBADF
 	}
+	// Stdin is read without holding the lock, so that SetCloser does not wait for the input
 	n, err := s.Stdin.Read(p)
 	if err != nil {
-		if s.Closer != nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.Closer != nil && !s.closed {
 			s.Closer()
 			s.closed = true
+		} else if s.Closer == nil {
+			s.eof = true
 		}
 	}
 	return n, err
 }
 
+// SetCloser sets Closer once the process is started, as the stdin of the process can only be closed then.
+// Closer is called immediately when Stdin has already reached EOF, e.g., for a short input piped to `exec -i`.
+func (s *StdinCloser) SetCloser(closer func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Closer = closer
+	if s.eof && !s.closed {
+		s.Closer()
+		s.closed = true
+	}
+}
+
 // Close implements Closer
 func (s *StdinCloser) Close() error {
 	s.mu.Lock()
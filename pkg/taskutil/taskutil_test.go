@@ -0,0 +1,62 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package taskutil
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestStdinCloserEOFBeforeSetCloser(t *testing.T) {
+	r, w, err := os.Pipe()
+	assert.NilError(t, err)
+	defer r.Close()
+	_, err = w.WriteString("foo")
+	assert.NilError(t, err)
+	assert.NilError(t, w.Close())
+
+	// The input is read to the end before the process is started
+	stdinC := &StdinCloser{Stdin: r}
+	b, err := io.ReadAll(stdinC)
+	assert.NilError(t, err)
+	assert.Equal(t, string(b), "foo")
+
+	closed := 0
+	stdinC.SetCloser(func() { closed++ })
+	assert.Equal(t, closed, 1, "the closer must be called when the stdin has already reached EOF")
+	assert.NilError(t, stdinC.Close())
+	assert.Equal(t, closed, 1, "the closer must be called only once")
+}
+
+func TestStdinCloserEOFAfterSetCloser(t *testing.T) {
+	r, w, err := os.Pipe()
+	assert.NilError(t, err)
+	defer r.Close()
+
+	closed := 0
+	stdinC := &StdinCloser{Stdin: r}
+	stdinC.SetCloser(func() { closed++ })
+	assert.Equal(t, closed, 0)
+
+	assert.NilError(t, w.Close())
+	_, err = io.ReadAll(stdinC)
+	assert.NilError(t, err)
+	assert.Equal(t, closed, 1)
+}
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os/exec"
+	"slices"
 	"strings"
 
 	"github.com/Masterminds/semver/v3"
@@ -34,6 +35,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/clientutil"
 	"github.com/containerd/nerdctl/v2/pkg/infoutil"
 	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/dockercompat"
+	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/native"
 	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
 	"github.com/containerd/nerdctl/v2/pkg/snapshotterutil"
 	"github.com/containerd/nerdctl/v2/pkg/testutil"
@@ -179,6 +181,17 @@ var CGroup = &test.Requirement{
 	},
 }
 
+// SystemdCgroup requires that the cgroup driver is systemd
+var SystemdCgroup = &test.Requirement{
+	Check: func(data test.Data, helpers test.Helpers) (ret bool, mess string) {
+		stdout := helpers.Capture("info", "--format", "{{ json . }}")
+		var dinf dockercompat.Info
+		err := json.Unmarshal([]byte(stdout), &dinf)
+		assert.NilError(helpers.T(), err, "failed to parse docker info")
+		return dinf.CgroupDriver == "systemd", fmt.Sprintf("cgroup driver is %q", dinf.CgroupDriver)
+	},
+}
+
 var CgroupsAccessible = require.All(
 	CGroup,
 	&test.Requirement{
@@ -447,3 +460,27 @@ func ContainerdVersion(v string) *test.Requirement {
 		},
 	}
 }
+
+// ContainerdPlugin requires the containerd plugin of the given type and ID, with the given capabilities.
+func ContainerdPlugin(pluginType, pluginID string, capabilities ...string) *test.Requirement {
+	return &test.Requirement{
+		Check: func(data test.Data, helpers test.Helpers) (bool, string) {
+			stdout := helpers.Capture("info", "--mode", "native", "--format", "{{ json . }}")
+			var info native.Info
+			err := json.Unmarshal([]byte(stdout), &info)
+			assert.NilError(helpers.T(), err, "failed to parse native info")
+			for _, p := range info.Daemon.Plugins.Plugins {
+				if p.Type != pluginType || p.ID != pluginID {
+					continue
+				}
+				for _, c := range capabilities {
+					if !slices.Contains(p.Capabilities, c) {
+						return false, fmt.Sprintf("containerd plugin \"%s.%s\" does not have the capability %q", pluginType, pluginID, c)
+					}
+				}
+				return true, fmt.Sprintf("containerd plugin \"%s.%s\" is available", pluginType, pluginID)
+			}
+			return false, fmt.Sprintf("containerd plugin \"%s.%s\" is not available", pluginType, pluginID)
+		},
+	}
+}
@@ -0,0 +1,167 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package volumedriver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+)
+
+const (
+	pluginContentType = "application/vnd.docker.plugins.v1.2+json"
+	pluginTimeout     = 60 * time.Second
+)
+
+// plugin is a Driver speaking the Docker volume plugin protocol over HTTP.
+// See https://docs.docker.com/engine/extend/plugins_volume/
+type plugin struct {
+	name   string
+	addr   string
+	client *http.Client
+	base   string
+}
+
+// parsePluginJSON returns the address in the JSON spec file (<name>.json) of a plugin.
+func parsePluginJSON(b []byte) (string, error) {
+	var spec struct {
+		Addr string
+	}
+	if err := json.Unmarshal(b, &spec); err != nil {
+		return "", err
+	}
+	return spec.Addr, nil
+}
+
+// newPlugin connects to the plugin name listening on addr (unix://, tcp:// or http://),
+// and activates it. An error is returned when the plugin does not implement VolumeDriver.
+func newPlugin(ctx context.Context, name, addr string) (*plugin, error) {
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address %q of volume plugin %q: %w", addr, name, err)
+	}
+	p := &plugin{name: name, addr: addr}
+	switch u.Scheme {
+	case "unix":
+		sock := u.Path
+		p.client = &http.Client{
+			Timeout: pluginTimeout,
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					return (&net.Dialer{}).DialContext(ctx, "unix", sock)
+				},
+			},
+		}
+		p.base = "http://plugin"
+	case "tcp", "http":
+		p.client = &http.Client{Timeout: pluginTimeout}
+		p.base = "http://" + u.Host
+	default:
+		return nil, fmt.Errorf("unsupported address %q of volume plugin %q", addr, name)
+	}
+
+	var activate struct {
+		Implements []string
+	}
+	if err := p.call(ctx, "/Plugin.Activate", nil, &activate); err != nil {
+		return nil, err
+	}
+	if !slices.Contains(activate.Implements, "VolumeDriver") {
+		return nil, fmt.Errorf("plugin %q does not implement VolumeDriver (implements %v)", name, activate.Implements)
+	}
+	return p, nil
+}
+
+// call posts req to the method of the plugin, and decodes the response into res.
+// A non-empty "Err" field in the response is returned as an error.
+func (p *plugin) call(ctx context.Context, method string, req, res any) error {
+	body := []byte("{}")
+	if req != nil {
+		var err error
+		if body, err = json.Marshal(req); err != nil {
+			return err
+		}
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.base+method, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Accept", pluginContentType)
+	httpReq.Header.Set("Content-Type", pluginContentType)
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("volume plugin %q: %s: %w", p.name, method, err)
+	}
+	defer resp.Body.Close()
+
+	var raw json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return fmt.Errorf("volume plugin %q: %s: failed to decode the response (status %d): %w", p.name, method, resp.StatusCode, err)
+	}
+	var pluginErr struct {
+		Err string
+	}
+	if err := json.Unmarshal(raw, &pluginErr); err == nil && pluginErr.Err != "" {
+		return fmt.Errorf("volume plugin %q: %s: %w", p.name, method, errors.New(pluginErr.Err))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("volume plugin %q: %s: unexpected status %d", p.name, method, resp.StatusCode)
+	}
+	if res != nil {
+		return json.Unmarshal(raw, res)
+	}
+	return nil
+}
+
+// Create implements Driver.Create with /VolumeDriver.Create.
+func (p *plugin) Create(ctx context.Context, name string, opts map[string]string) error {
+	return p.call(ctx, "/VolumeDriver.Create", map[string]any{"Name": name, "Opts": opts}, nil)
+}
+
+// Remove implements Driver.Remove with /VolumeDriver.Remove.
+// The plugin may refuse to remove a volume that is still mounted.
+func (p *plugin) Remove(ctx context.Context, name string) error {
+	return p.call(ctx, "/VolumeDriver.Remove", map[string]any{"Name": name}, nil)
+}
+
+// Mount implements Driver.Mount with /VolumeDriver.Mount, and returns the mountpoint on the host.
+// The plugin counts the mounts by id: each successful Mount must be released by exactly one Unmount
+// with the same name and id, or the plugin keeps the volume mounted.
+func (p *plugin) Mount(ctx context.Context, name, id string) (string, error) {
+	var res struct {
+		Mountpoint string
+	}
+	if err := p.call(ctx, "/VolumeDriver.Mount", map[string]any{"Name": name, "ID": id}, &res); err != nil {
+		return "", err
+	}
+	if res.Mountpoint == "" {
+		return "", fmt.Errorf("volume plugin %q did not return a mountpoint for volume %q", p.name, name)
+	}
+	return res.Mountpoint, nil
+}
+
+// Unmount implements Driver.Unmount with /VolumeDriver.Unmount, releasing the mount id of a previous Mount.
+func (p *plugin) Unmount(ctx context.Context, name, id string) error {
+	return p.call(ctx, "/VolumeDriver.Unmount", map[string]any{"Name": name, "ID": id}, nil)
+}
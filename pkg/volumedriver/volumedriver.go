@@ -0,0 +1,183 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package volumedriver resolves the drivers of the volumes that are not managed by nerdctl itself (the "local" driver).
+// A driver is either registered in-process with Register, or discovered as a Docker volume plugin
+// listening on a socket in one of PluginDirs.
+package volumedriver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/containerd/errdefs"
+)
+
+// LocalDriverName is the name of the driver of the volumes managed by the volume store of nerdctl.
+const LocalDriverName = "local"
+
+// Driver is implemented by the volume drivers.
+// The method set follows the Docker volume plugin protocol, so that Docker volume plugins can be used.
+type Driver interface {
+	// Create creates the volume name with the driver-specific options.
+	Create(ctx context.Context, name string, opts map[string]string) error
+	// Remove removes the volume name.
+	Remove(ctx context.Context, name string) error
+	// Mount makes the volume name available on the host, and returns the host path to bind mount.
+	// id is unique for each mount (e.g. the container ID), and the driver may count the mounts by id,
+	// so each successful Mount must be paired with exactly one Unmount with the same name and id,
+	// including when the creation of the container fails after the volume was mounted.
+	Mount(ctx context.Context, name, id string) (string, error)
+	// Unmount releases the mount id of the volume name, acquired by Mount.
+	Unmount(ctx context.Context, name, id string) error
+}
+
+// MountRecord records a mount of a volume by its driver, so that it can be unmounted later.
+type MountRecord struct {
+	// Volume is the name of the volume.
+	Volume string
+	// Driver is the name of the driver of the volume.
+	Driver string
+	// ID is the mount ID passed to Driver.Mount, to be passed again to Driver.Unmount.
+	ID string
+	// Mountpoint is the host path returned by the driver, the source of the bind mounts of the volume.
+	// It is empty for the records of the containers created by older versions of nerdctl.
+	Mountpoint string `json:",omitempty"`
+}
+
+// Unmount unmounts the recorded mount.
+func (r MountRecord) Unmount(ctx context.Context) error {
+	d, err := Get(ctx, r.Driver)
+	if err != nil {
+		return err
+	}
+	return d.Unmount(ctx, r.Volume, r.ID)
+}
+
+// Remount mounts the recorded mount again, and returns the mountpoint returned by the driver.
+// This is needed when the container is started again, as the driver may have lost the mount
+// (e.g. on reboot), or may mount the volume on another path.
+// The previous mount is released first so that the mount count of the driver stays balanced;
+// failing to release it is not an error, as it may be gone already.
+func (r MountRecord) Remount(ctx context.Context) (string, error) {
+	d, err := Get(ctx, r.Driver)
+	if err != nil {
+		return "", err
+	}
+	_ = d.Unmount(ctx, r.Volume, r.ID)
+	return d.Mount(ctx, r.Volume, r.ID)
+}
+
+// PluginDirs are the directories where the sockets (<name>.sock) and spec files (<name>.spec, containing the address)
+// of the volume plugins are discovered, as in Docker.
+var PluginDirs = []string{"/run/docker/plugins", "/etc/docker/plugins", "/usr/lib/docker/plugins"}
+
+var (
+	mu      sync.Mutex
+	drivers = map[string]Driver{}
+	// plugins caches the activated plugins by name, as Docker activates a plugin only once.
+	plugins = map[string]*plugin{}
+)
+
+// IsLocal returns whether the driver name refers to the local driver.
+func IsLocal(name string) bool {
+	return name == "" || name == LocalDriverName
+}
+
+// Register registers an in-process driver, which takes precedence over the plugins with the same name.
+func Register(name string, d Driver) error {
+	if IsLocal(name) {
+		return fmt.Errorf("volume driver %q is reserved: %w", LocalDriverName, errdefs.ErrInvalidArgument)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := drivers[name]; ok {
+		return fmt.Errorf("volume driver %q is already registered: %w", name, errdefs.ErrAlreadyExists)
+	}
+	drivers[name] = d
+	return nil
+}
+
+// Unregister removes an in-process driver.
+func Unregister(name string) {
+	mu.Lock()
+	defer mu.Unlock()
+	delete(drivers, name)
+}
+
+// Get returns the driver name, which must not be the local driver.
+// A plugin is activated on its first use, and again only when its address changes.
+func Get(ctx context.Context, name string) (Driver, error) {
+	if IsLocal(name) {
+		return nil, fmt.Errorf("volume driver %q is handled by the volume store: %w", LocalDriverName, errdefs.ErrInvalidArgument)
+	}
+	mu.Lock()
+	d, ok := drivers[name]
+	mu.Unlock()
+	if ok {
+		return d, nil
+	}
+	addr, err := lookupPlugin(name)
+	if err != nil {
+		return nil, err
+	}
+	mu.Lock()
+	p, ok := plugins[name]
+	mu.Unlock()
+	if ok && p.addr == addr {
+		return p, nil
+	}
+	p, err = newPlugin(ctx, name, addr)
+	if err != nil {
+		return nil, err
+	}
+	mu.Lock()
+	plugins[name] = p
+	mu.Unlock()
+	return p, nil
+}
+
+// lookupPlugin returns the address of the plugin name.
+func lookupPlugin(name string) (string, error) {
+	if strings.ContainsAny(name, "/\\") || name == "." || name == ".." {
+		return "", fmt.Errorf("invalid volume driver name %q: %w", name, errdefs.ErrInvalidArgument)
+	}
+	for _, dir := range PluginDirs {
+		sock := filepath.Join(dir, name+".sock")
+		if _, err := os.Stat(sock); err == nil {
+			return "unix://" + sock, nil
+		}
+		for _, ext := range []string{".spec", ".json"} {
+			b, err := os.ReadFile(filepath.Join(dir, name+ext))
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return "", err
+			}
+			if ext == ".json" {
+				return parsePluginJSON(b)
+			}
+			return strings.TrimSpace(string(b)), nil
+		}
+	}
+	return "", fmt.Errorf("volume driver %q not found in %v: %w", name, PluginDirs, errdefs.ErrNotFound)
+}
@@ -0,0 +1,226 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package volumedriver
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/errdefs"
+)
+
+// fakePlugin serves the Docker volume plugin protocol, keeping track of the mounts.
+type fakePlugin struct {
+	mu      sync.Mutex
+	root    string
+	volumes map[string]map[string]string
+	mounts  map[string]string
+	// activations counts the calls to /Plugin.Activate
+	activations int
+}
+
+func (fp *fakePlugin) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name string
+		ID   string
+		Opts map[string]string
+	}
+	_ = json.NewDecoder(r.Body).Decode(&req)
+
+	fp.mu.Lock()
+	defer fp.mu.Unlock()
+	res := map[string]any{}
+	switch r.URL.Path {
+	case "/Plugin.Activate":
+		fp.activations++
+		res["Implements"] = []string{"VolumeDriver"}
+	case "/VolumeDriver.Create":
+		fp.volumes[req.Name] = req.Opts
+	case "/VolumeDriver.Remove":
+		delete(fp.volumes, req.Name)
+	case "/VolumeDriver.Mount":
+		if _, ok := fp.volumes[req.Name]; !ok {
+			res["Err"] = "no such volume"
+			break
+		}
+		fp.mounts[req.ID] = req.Name
+		res["Mountpoint"] = filepath.Join(fp.root, req.Name)
+	case "/VolumeDriver.Unmount":
+		delete(fp.mounts, req.ID)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+func startFakePlugin(t *testing.T, name string) *fakePlugin {
+	dir := t.TempDir()
+	oldDirs := PluginDirs
+	PluginDirs = []string{dir}
+	t.Cleanup(func() { PluginDirs = oldDirs })
+
+	l, err := net.Listen("unix", filepath.Join(dir, name+".sock"))
+	assert.NilError(t, err)
+	fp := &fakePlugin{
+		root:    "/fake",
+		volumes: map[string]map[string]string{},
+		mounts:  map[string]string{},
+	}
+	srv := httptest.NewUnstartedServer(fp)
+	srv.Listener = l
+	srv.Start()
+	t.Cleanup(srv.Close)
+	return fp
+}
+
+func TestPlugin(t *testing.T) {
+	ctx := context.Background()
+	fp := startFakePlugin(t, "fake")
+
+	d, err := Get(ctx, "fake")
+	assert.NilError(t, err)
+
+	assert.NilError(t, d.Create(ctx, "vol", map[string]string{"size": "1G"}))
+	assert.DeepEqual(t, fp.volumes["vol"], map[string]string{"size": "1G"})
+
+	mountpoint, err := d.Mount(ctx, "vol", "id1")
+	assert.NilError(t, err)
+	assert.Equal(t, mountpoint, "/fake/vol")
+	assert.Equal(t, fp.mounts["id1"], "vol")
+
+	_, err = d.Mount(ctx, "nonexistent", "id2")
+	assert.ErrorContains(t, err, "no such volume")
+
+	assert.NilError(t, MountRecord{Volume: "vol", Driver: "fake", ID: "id1"}.Unmount(ctx))
+	assert.Equal(t, len(fp.mounts), 0)
+
+	assert.NilError(t, d.Remove(ctx, "vol"))
+	assert.Equal(t, len(fp.volumes), 0)
+}
+
+func TestPluginActivation(t *testing.T) {
+	ctx := context.Background()
+	fp := startFakePlugin(t, "fake")
+
+	d1, err := Get(ctx, "fake")
+	assert.NilError(t, err)
+	d2, err := Get(ctx, "fake")
+	assert.NilError(t, err)
+	assert.Equal(t, d1, d2)
+	assert.Equal(t, fp.activations, 1)
+
+	// the plugin is activated again when it listens on another address
+	fp2 := startFakePlugin(t, "fake")
+	d3, err := Get(ctx, "fake")
+	assert.NilError(t, err)
+	assert.Assert(t, d3 != d1)
+	assert.Equal(t, fp2.activations, 1)
+
+	// the requests are canceled with their context
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.ErrorIs(t, d3.Create(canceled, "vol", nil), context.Canceled)
+}
+
+func TestMountRecordRemount(t *testing.T) {
+	ctx := context.Background()
+	fp := startFakePlugin(t, "fake")
+	// The plugin lost the mount (e.g. after a reboot), and mounts the volume on another path
+	fp.mu.Lock()
+	fp.volumes["vol"] = nil
+	fp.root = "/fake-moved"
+	fp.mu.Unlock()
+
+	r := MountRecord{Volume: "vol", Driver: "fake", ID: "id1", Mountpoint: "/fake/vol"}
+	mountpoint, err := r.Remount(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, mountpoint, "/fake-moved/vol")
+	assert.DeepEqual(t, fp.mounts, map[string]string{"id1": "vol"})
+
+	// Remounting again keeps a single mount
+	_, err = r.Remount(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, len(fp.mounts), 1)
+
+	fp.mu.Lock()
+	delete(fp.volumes, "vol")
+	fp.mu.Unlock()
+	_, err = r.Remount(ctx)
+	assert.ErrorContains(t, err, "no such volume")
+}
+
+func TestPluginSpecFile(t *testing.T) {
+	ctx := context.Background()
+	fp := &fakePlugin{root: "/fake", volumes: map[string]map[string]string{"vol": nil}, mounts: map[string]string{}}
+	srv := httptest.NewServer(fp)
+	t.Cleanup(srv.Close)
+
+	dir := t.TempDir()
+	oldDirs := PluginDirs
+	PluginDirs = []string{dir}
+	t.Cleanup(func() { PluginDirs = oldDirs })
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "fake.spec"), []byte(srv.URL+"\n"), 0o644))
+
+	d, err := Get(ctx, "fake")
+	assert.NilError(t, err)
+	mountpoint, err := d.Mount(ctx, "vol", "id1")
+	assert.NilError(t, err)
+	assert.Equal(t, mountpoint, "/fake/vol")
+}
+
+func TestGetNotFound(t *testing.T) {
+	ctx := context.Background()
+	oldDirs := PluginDirs
+	PluginDirs = []string{t.TempDir()}
+	t.Cleanup(func() { PluginDirs = oldDirs })
+
+	_, err := Get(ctx, "nonexistent")
+	assert.Assert(t, errdefs.IsNotFound(err))
+
+	_, err = Get(ctx, "../escape")
+	assert.Assert(t, errdefs.IsInvalidArgument(err))
+
+	_, err = Get(ctx, LocalDriverName)
+	assert.Assert(t, errdefs.IsInvalidArgument(err))
+}
+
+type nopDriver struct {
+	Driver
+}
+
+func TestRegister(t *testing.T) {
+	ctx := context.Background()
+	d := &nopDriver{}
+	assert.NilError(t, Register("nop", d))
+	t.Cleanup(func() { Unregister("nop") })
+
+	assert.Assert(t, errdefs.IsAlreadyExists(Register("nop", d)))
+	assert.Assert(t, errdefs.IsInvalidArgument(Register(LocalDriverName, d)))
+
+	got, err := Get(ctx, "nop")
+	assert.NilError(t, err)
+	assert.Equal(t, got, Driver(d))
+}